@@ -3917,6 +3917,9 @@ var doc = `{
                 },
                 "net_topology": {
                     "type": "string"
+                },
+                "piece_signing_key": {
+                    "type": "string"
                 }
             }
         },
@@ -4291,6 +4294,12 @@ var doc = `{
             "properties": {
                 "load_limit": {
                     "type": "integer"
+                },
+                "piece_transport": {
+                    "type": "string"
+                },
+                "piece_verify_key": {
+                    "type": "string"
                 }
             }
         },
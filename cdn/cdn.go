@@ -18,6 +18,8 @@ package cdn
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 
 	"github.com/pkg/errors"
 	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
@@ -33,9 +35,12 @@ import (
 	"d7y.io/dragonfly/v2/cdn/supervisor/cdn/storage"
 	"d7y.io/dragonfly/v2/cdn/supervisor/progress"
 	"d7y.io/dragonfly/v2/cdn/supervisor/task"
+	"d7y.io/dragonfly/v2/cdn/upload"
 	logger "d7y.io/dragonfly/v2/internal/dflog"
+	"d7y.io/dragonfly/v2/manager/types"
 	"d7y.io/dragonfly/v2/pkg/rpc/manager"
 	managerClient "d7y.io/dragonfly/v2/pkg/rpc/manager/client"
+	"d7y.io/dragonfly/v2/pkg/signature"
 	"d7y.io/dragonfly/v2/pkg/util/hostutils"
 )
 
@@ -54,6 +59,9 @@ type Server struct {
 
 	// gc Server
 	gcServer *gc.Server
+
+	// Upload server, it serves download files when they are encrypted
+	uploadServer *upload.Server
 }
 
 // New creates a brand-new server instance.
@@ -112,6 +120,12 @@ func New(config *config.Config) (*Server, error) {
 		}
 	}
 
+	// Encrypted download files can not be served by static file server
+	var uploadServer *upload.Server
+	if config.Storage.Encryption.Enable {
+		uploadServer = upload.New(fmt.Sprintf(":%d", config.RPCServer.DownloadPort), storageManager)
+	}
+
 	// Initialize configServer
 	var configServer managerClient.Client
 	if config.Manager.Addr != "" {
@@ -126,6 +140,7 @@ func New(config *config.Config) (*Server, error) {
 		metricsServer: metricsServer,
 		configServer:  configServer,
 		gcServer:      gcServer,
+		uploadServer:  uploadServer,
 	}, nil
 }
 
@@ -146,6 +161,15 @@ func (s *Server) Serve() error {
 		}
 	}()
 
+	go func() {
+		if s.uploadServer != nil {
+			// Start upload server
+			if err := s.uploadServer.ListenAndServe(); err != nil {
+				logger.Fatalf("start upload server failed: %v", err)
+			}
+		}
+	}()
+
 	go func() {
 		if s.configServer != nil {
 			var rpcServerConfig = s.grpcServer.GetConfig()
@@ -162,6 +186,7 @@ func (s *Server) Serve() error {
 			if err != nil {
 				logger.Fatalf("update cdn instance failed: %v", err)
 			}
+			s.setPieceSigner()
 			// Serve Keepalive
 			logger.Infof("====starting keepalive cdn instance %s to manager %s====", CDNInstance, s.config.Manager.Addr)
 			s.configServer.KeepAlive(s.config.Manager.KeepAlive.Interval, &manager.KeepAliveRequest{
@@ -176,6 +201,37 @@ func (s *Server) Serve() error {
 	return s.grpcServer.ListenAndServe()
 }
 
+// setPieceSigner signs piece digest roots with the piece signing key of cdn cluster
+func (s *Server) setPieceSigner() {
+	cdnInstance, err := s.configServer.GetCDN(&manager.GetCDNRequest{
+		SourceType:   manager.SourceType_CDN_SOURCE,
+		HostName:     hostutils.FQDNHostname,
+		CdnClusterId: uint64(s.config.Manager.CDNClusterID),
+	})
+	if err != nil {
+		logger.Errorf("get cdn cluster config failed: %v", err)
+		return
+	}
+	if cdnInstance.CdnCluster == nil || len(cdnInstance.CdnCluster.Config) == 0 {
+		return
+	}
+	var clusterConfig types.CDNClusterConfig
+	if err := json.Unmarshal(cdnInstance.CdnCluster.Config, &clusterConfig); err != nil {
+		logger.Errorf("unmarshal cdn cluster config failed: %v", err)
+		return
+	}
+	if clusterConfig.PieceSigningKey == "" {
+		return
+	}
+	signer, err := signature.NewSigner(clusterConfig.PieceSigningKey)
+	if err != nil {
+		logger.Errorf("create piece signer failed: %v", err)
+		return
+	}
+	logger.Infof("sign piece digest roots with public key %s", signer.PublicKey())
+	s.grpcServer.SetPieceSigner(signer)
+}
+
 func (s *Server) Stop() error {
 	g, ctx := errgroup.WithContext(context.Background())
 
@@ -194,6 +250,13 @@ func (s *Server) Stop() error {
 		return s.metricsServer.Shutdown(ctx)
 	})
 
+	if s.uploadServer != nil {
+		g.Go(func() error {
+			// Stop upload server
+			return s.uploadServer.Shutdown(ctx)
+		})
+	}
+
 	g.Go(func() error {
 		// Stop grpc server
 		return s.grpcServer.Shutdown()
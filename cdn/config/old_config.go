@@ -35,6 +35,7 @@ import (
 	"d7y.io/dragonfly/v2/cdn/supervisor/task"
 	"d7y.io/dragonfly/v2/cmd/dependency/base"
 	"d7y.io/dragonfly/v2/pkg/basic"
+	"d7y.io/dragonfly/v2/pkg/encryption"
 	"d7y.io/dragonfly/v2/pkg/unit"
 	"d7y.io/dragonfly/v2/pkg/util/net/iputils"
 )
@@ -91,6 +92,7 @@ func (c DeprecatedConfig) Convert() *Config {
 		if err := decodeStorageManager(storageManager.Config, storageConfig); err == nil {
 			newConfig.Storage.GCInitialDelay = storageConfig.GCInitialDelay
 			newConfig.Storage.GCInterval = storageConfig.GCInterval
+			newConfig.Storage.Encryption = storageConfig.Encryption
 			for driverName, config := range storageConfig.DriverConfigs {
 				var baseDir string
 				for _, pluginProperties := range c.Plugins[plugins.StorageDriverPlugin] {
@@ -241,6 +243,7 @@ type StorageConfig struct {
 	GCInitialDelay time.Duration            `yaml:"gcInitialDelay"`
 	GCInterval     time.Duration            `yaml:"gcInterval"`
 	DriverConfigs  map[string]*DriverConfig `yaml:"driverConfigs"`
+	Encryption     encryption.Config        `yaml:"encryption"`
 }
 
 type DriverConfig struct {
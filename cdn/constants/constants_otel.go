@@ -19,24 +19,26 @@ package constants
 import "go.opentelemetry.io/otel/attribute"
 
 const (
-	AttributeObtainSeedsRequest   = attribute.Key("d7y.obtain.seeds.request")
-	AttributeGetPieceTasksRequest = attribute.Key("d7y.get.piece.tasks.request")
-	AttributePiecePacketResult    = attribute.Key("d7y.piece.packet.result")
-	AttributeTaskID               = attribute.Key("d7y.task.id")
-	AttributeTaskStatus           = attribute.Key("d7y.task.status")
-	AttributeTaskInfo             = attribute.Key("d7y.taskInfo")
-	AttributeIfReuseTask          = attribute.Key("d7y.task.already.exist")
-	AttributeSeedPiece            = attribute.Key("d7y.seed.piece")
-	AttributeSeedTask             = attribute.Key("d7y.seed.task")
-	AttributeCacheResult          = attribute.Key("d7y.cache.result")
-	AttributeWriteGoroutineCount  = attribute.Key("d7y.write.goroutine.count")
-	AttributeDownloadFileInfo     = attribute.Key("d7y.download.file.info")
-	AttributeDetectCacheResult    = attribute.Key("d7y.detect.cache.result")
+	AttributeObtainSeedsRequest    = attribute.Key("d7y.obtain.seeds.request")
+	AttributeGetPieceTasksRequest  = attribute.Key("d7y.get.piece.tasks.request")
+	AttributeSyncPieceTasksRequest = attribute.Key("d7y.sync.piece.tasks.request")
+	AttributePiecePacketResult     = attribute.Key("d7y.piece.packet.result")
+	AttributeTaskID                = attribute.Key("d7y.task.id")
+	AttributeTaskStatus            = attribute.Key("d7y.task.status")
+	AttributeTaskInfo              = attribute.Key("d7y.taskInfo")
+	AttributeIfReuseTask           = attribute.Key("d7y.task.already.exist")
+	AttributeSeedPiece             = attribute.Key("d7y.seed.piece")
+	AttributeSeedTask              = attribute.Key("d7y.seed.task")
+	AttributeCacheResult           = attribute.Key("d7y.cache.result")
+	AttributeWriteGoroutineCount   = attribute.Key("d7y.write.goroutine.count")
+	AttributeDownloadFileInfo      = attribute.Key("d7y.download.file.info")
+	AttributeDetectCacheResult     = attribute.Key("d7y.detect.cache.result")
 )
 
 const (
 	SpanObtainSeeds          = "cdn-obtain-seeds"
 	SpanGetPieceTasks        = "get-piece-tasks"
+	SpanSyncPieceTasks       = "sync-piece-tasks"
 	SpanTaskRegister         = "task-register"
 	SpanAndOrUpdateTask      = "add-or-update-task"
 	SpanTriggerCDNSyncAction = "trigger-cdn-sync-action"
@@ -20,6 +20,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"sync/atomic"
 	"time"
 
 	"go.opentelemetry.io/otel"
@@ -37,6 +38,8 @@ import (
 	"d7y.io/dragonfly/v2/pkg/rpc/base"
 	"d7y.io/dragonfly/v2/pkg/rpc/cdnsystem"
 	cdnserver "d7y.io/dragonfly/v2/pkg/rpc/cdnsystem/server"
+	"d7y.io/dragonfly/v2/pkg/signature"
+	"d7y.io/dragonfly/v2/pkg/util/digestutils"
 	"d7y.io/dragonfly/v2/pkg/util/hostutils"
 )
 
@@ -46,6 +49,8 @@ type Server struct {
 	*grpc.Server
 	config  Config
 	service supervisor.CDNService
+	// signer holds *signature.Signer, it is updated with the cdn cluster config from manager
+	signer atomic.Value
 }
 
 // New returns a new Manager Object.
@@ -58,6 +63,20 @@ func New(config Config, cdnService supervisor.CDNService, opts ...grpc.ServerOpt
 	return svr, nil
 }
 
+// SetPieceSigner sets the signer of piece digest roots, nil disables signing.
+func (css *Server) SetPieceSigner(signer *signature.Signer) {
+	css.signer.Store(signer)
+}
+
+// signPieceDigestRoot returns the signature of the piece digest root, it returns nil when signing is disabled
+func (css *Server) signPieceDigestRoot(seedTask *task.SeedTask, root string) []byte {
+	signer, _ := css.signer.Load().(*signature.Signer)
+	if signer == nil || root == "" {
+		return nil
+	}
+	return signer.Sign(seedTask.ID, root)
+}
+
 func (css *Server) ObtainSeeds(ctx context.Context, req *cdnsystem.SeedRequest, psc chan<- *cdnsystem.PieceSeed) (err error) {
 	clientAddr := "unknown"
 	if pe, ok := peer.FromContext(ctx); ok {
@@ -101,6 +120,7 @@ func (css *Server) ObtainSeeds(ctx context.Context, req *cdnsystem.SeedRequest,
 				PieceMd5:    piece.PieceMd5,
 				PieceOffset: piece.OriginRange.StartIndex,
 				PieceStyle:  piece.PieceStyle,
+				PieceDigest: piece.PieceDigest,
 			},
 			Done:            false,
 			ContentLength:   registeredTask.SourceFileLength,
@@ -137,6 +157,12 @@ func (css *Server) ObtainSeeds(ctx context.Context, req *cdnsystem.SeedRequest,
 		ContentLength:   seedTask.SourceFileLength,
 		TotalPieceCount: seedTask.TotalPieceCount,
 	}
+	if pieces, err := css.service.GetSeedPieces(req.TaskId); err == nil {
+		if tree := pieceDigestTree(seedTask, pieces); tree != nil {
+			pieceSeed.PieceDigestRoot = tree.Root()
+			pieceSeed.PieceDigestSignature = css.signPieceDigestRoot(seedTask, pieceSeed.PieceDigestRoot)
+		}
+	}
 	psc <- pieceSeed
 	jsonPiece, err := json.Marshal(pieceSeed)
 	if err != nil {
@@ -185,16 +211,13 @@ func (css *Server) GetPieceTasks(ctx context.Context, req *base.PieceTaskRequest
 		return nil, err
 	}
 	pieceInfos := make([]*base.PieceInfo, 0, len(pieces))
+	tree := pieceDigestTree(seedTask, pieces)
 	var count uint32 = 0
 	for _, piece := range pieces {
 		if piece.PieceNum >= req.StartNum && (count < req.Limit || req.Limit <= 0) {
-			p := &base.PieceInfo{
-				PieceNum:    int32(piece.PieceNum),
-				RangeStart:  piece.PieceRange.StartIndex,
-				RangeSize:   piece.PieceLen,
-				PieceMd5:    piece.PieceMd5,
-				PieceOffset: piece.OriginRange.StartIndex,
-				PieceStyle:  piece.PieceStyle,
+			p := toPieceInfo(piece)
+			if tree != nil {
+				p.PieceProof = tree.Proof(int(piece.PieceNum))
 			}
 			pieceInfos = append(pieceInfos, p)
 			count++
@@ -203,16 +226,134 @@ func (css *Server) GetPieceTasks(ctx context.Context, req *base.PieceTaskRequest
 	pp := &base.PiecePacket{
 		TaskId:        req.TaskId,
 		DstPid:        req.DstPid,
-		DstAddr:       fmt.Sprintf("%s:%d", css.config.AdvertiseIP, css.config.DownloadPort),
+		DstAddr:       css.downloadAddr(),
 		PieceInfos:    pieceInfos,
 		TotalPiece:    seedTask.TotalPieceCount,
 		ContentLength: seedTask.SourceFileLength,
 		PieceMd5Sign:  seedTask.PieceMd5Sign,
 	}
+	if tree != nil {
+		pp.PieceDigestRoot = tree.Root()
+		pp.PieceDigestSignature = css.signPieceDigestRoot(seedTask, pp.PieceDigestRoot)
+	}
 	span.SetAttributes(constants.AttributePiecePacketResult.String(pp.String()))
 	return pp, nil
 }
 
+// SyncPieceTasks sends pieces of the seed task to the peer, new pieces are pushed when they are ready,
+// the last piece packet with all pieces and the piece digest root is sent when the task is done.
+func (css *Server) SyncPieceTasks(req *base.PieceTaskRequest, stream cdnsystem.Seeder_SyncPieceTasksServer) (err error) {
+	ctx, span := tracer.Start(stream.Context(), constants.SpanSyncPieceTasks, trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+	span.SetAttributes(constants.AttributeSyncPieceTasksRequest.String(req.String()))
+	span.SetAttributes(constants.AttributeTaskID.String(req.TaskId))
+	clientAddr := "unknown"
+	if pe, ok := peer.FromContext(ctx); ok {
+		clientAddr = pe.Addr.String()
+	}
+	logger.Infof("sync piece tasks: %#v, client: %s", req, clientAddr)
+	defer func() {
+		if r := recover(); r != nil {
+			err = dferrors.Newf(base.Code_UnknownError, "sync task(%s) piece tasks encounter an panic: %v", req.TaskId, r)
+			span.RecordError(err)
+			logger.WithTaskID(req.TaskId).Errorf("sync piece tasks failed: %v", err)
+		}
+		logger.WithTaskID(req.TaskId).Infof("sync piece tasks result success: %t", err == nil)
+	}()
+	seedTask, err := css.service.GetSeedTask(req.TaskId)
+	if err != nil {
+		if task.IsTaskNotFound(err) {
+			err = dferrors.Newf(base.Code_CDNTaskNotFound, "failed to get task(%s): %v", req.TaskId, err)
+			span.RecordError(err)
+			return err
+		}
+		err = dferrors.Newf(base.Code_CDNError, "failed to get task(%s): %v", req.TaskId, err)
+		span.RecordError(err)
+		return err
+	}
+
+	if !seedTask.IsDone() {
+		watchCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		pieceChan, err := css.service.WatchSeedPieces(watchCtx, clientAddr, req.TaskId)
+		if err != nil {
+			err = dferrors.Newf(base.Code_CDNError, "failed to watch pieces of task(%s): %v", req.TaskId, err)
+			span.RecordError(err)
+			return err
+		}
+		// drain pieces to release the watcher when the stream is broken
+		defer func() {
+			go func() {
+				for range pieceChan {
+				}
+			}()
+		}()
+		for piece := range pieceChan {
+			if piece.PieceNum < req.StartNum {
+				continue
+			}
+			if err = stream.Send(&base.PiecePacket{
+				TaskId:        req.TaskId,
+				DstPid:        req.DstPid,
+				DstAddr:       css.downloadAddr(),
+				PieceInfos:    []*base.PieceInfo{toPieceInfo(piece)},
+				TotalPiece:    seedTask.TotalPieceCount,
+				ContentLength: seedTask.SourceFileLength,
+			}); err != nil {
+				span.RecordError(err)
+				return err
+			}
+		}
+		if err = ctx.Err(); err != nil {
+			return err
+		}
+	}
+
+	// task is done, send all pieces with total piece count and piece digest root
+	piecePacket, err := css.GetPieceTasks(ctx, &base.PieceTaskRequest{
+		TaskId:   req.TaskId,
+		SrcPid:   req.SrcPid,
+		DstPid:   req.DstPid,
+		StartNum: req.StartNum,
+	})
+	if err != nil {
+		return err
+	}
+	return stream.Send(piecePacket)
+}
+
+func (css *Server) downloadAddr() string {
+	return fmt.Sprintf("%s:%d", css.config.AdvertiseIP, css.config.DownloadPort)
+}
+
+func toPieceInfo(piece *task.PieceInfo) *base.PieceInfo {
+	return &base.PieceInfo{
+		PieceNum:    int32(piece.PieceNum),
+		RangeStart:  piece.PieceRange.StartIndex,
+		RangeSize:   piece.PieceLen,
+		PieceMd5:    piece.PieceMd5,
+		PieceOffset: piece.OriginRange.StartIndex,
+		PieceStyle:  piece.PieceStyle,
+		PieceDigest: piece.PieceDigest,
+	}
+}
+
+// pieceDigestTree returns the merkle tree of piece digests, it returns nil until all pieces of the task are ready
+func pieceDigestTree(seedTask *task.SeedTask, pieces []*task.PieceInfo) *digestutils.MerkleTree {
+	if !seedTask.IsSuccess() || int(seedTask.TotalPieceCount) != len(pieces) {
+		return nil
+	}
+	leaves := make([]string, 0, len(pieces))
+	for i, piece := range pieces {
+		// pieces cached by old versions have no digest
+		if piece.PieceDigest == "" || piece.PieceNum != uint32(i) {
+			return nil
+		}
+		leaves = append(leaves, piece.PieceDigest)
+	}
+	return digestutils.NewMerkleTree(leaves)
+}
+
 func (css *Server) ListenAndServe() error {
 	// Generate GRPC listener
 	lis, _, err := rpc.ListenWithPortRange(css.config.AdvertiseIP, css.config.ListenPort, css.config.ListenPort)
@@ -19,6 +19,7 @@ package cdn
 import (
 	"context"
 	"crypto/md5"
+	"crypto/sha256"
 	"fmt"
 	"hash"
 	"io"
@@ -267,7 +268,8 @@ func checkMetadata(seedTask *task.SeedTask, metadata *storage.FileMetadata) (boo
 func checkPieceContent(reader io.Reader, pieceRecord *storage.PieceMetaRecord, fileDigest hash.Hash) error {
 	// TODO Analyze the original data for the slice format to calculate fileMd5
 	pieceMd5 := md5.New()
-	tee := io.TeeReader(io.TeeReader(io.LimitReader(reader, int64(pieceRecord.PieceLen)), pieceMd5), fileDigest)
+	pieceSha256 := sha256.New()
+	tee := io.TeeReader(io.LimitReader(reader, int64(pieceRecord.PieceLen)), io.MultiWriter(pieceMd5, pieceSha256, fileDigest))
 	if n, err := io.Copy(io.Discard, tee); n != int64(pieceRecord.PieceLen) || err != nil {
 		return errors.Wrap(err, "read piece content")
 	}
@@ -276,5 +278,8 @@ func checkPieceContent(reader io.Reader, pieceRecord *storage.PieceMetaRecord, f
 	if realPieceMd5 != pieceRecord.Md5 {
 		return errors.Errorf("piece md5 sign is inconsistent, expected is %s, but got %s", pieceRecord.Md5, realPieceMd5)
 	}
+	if realPieceSha256 := digestutils.ToHashString(pieceSha256); pieceRecord.Sha256 != "" && realPieceSha256 != pieceRecord.Sha256 {
+		return errors.Errorf("piece sha256 is inconsistent, expected is %s, but got %s", pieceRecord.Sha256, realPieceSha256)
+	}
 	return nil
 }
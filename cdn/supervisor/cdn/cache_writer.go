@@ -20,6 +20,7 @@ import (
 	"bytes"
 	"context"
 	"crypto/md5"
+	"crypto/sha256"
 	"encoding/json"
 	"io"
 	"sync"
@@ -174,9 +175,10 @@ func (cw *cacheWriter) writerPool(ctx context.Context, g *errgroup.Group, routin
 					pieceLen := originPieceLen                 // the real length written to the storage driver after processed
 					pieceStyle := int32(base.PieceStyle_PLAIN.Number())
 					pieceMd5 := md5.New()
+					pieceSha256 := sha256.New()
 					err := cw.cacheStore.WriteDownloadFile(
 						p.taskID, int64(p.pieceNum)*int64(p.pieceSize), int64(waitToWriteContent.Len()),
-						io.TeeReader(io.LimitReader(p.pieceContent, int64(waitToWriteContent.Len())), pieceMd5))
+						io.TeeReader(io.LimitReader(p.pieceContent, int64(waitToWriteContent.Len())), io.MultiWriter(pieceMd5, pieceSha256)))
 					if err != nil {
 						return errors.Errorf("write taskID %s pieceNum %d to download file failed: %v", p.taskID, p.pieceNum, err)
 					}
@@ -198,6 +200,7 @@ func (cw *cacheWriter) writerPool(ctx context.Context, g *errgroup.Group, routin
 							EndIndex:   end,
 						},
 						PieceStyle: pieceStyle,
+						Sha256:     digestutils.ToHashString(pieceSha256),
 					}
 					// write piece meta to storage
 					if err = cw.metadataManager.appendPieceMetadata(p.taskID, pieceRecord); err != nil {
@@ -23,6 +23,7 @@ import (
 	"time"
 
 	"d7y.io/dragonfly/v2/pkg/basic"
+	"d7y.io/dragonfly/v2/pkg/encryption"
 	"d7y.io/dragonfly/v2/pkg/unit"
 )
 
@@ -31,6 +32,8 @@ type Config struct {
 	GCInitialDelay time.Duration            `yaml:"gcInitialDelay"`
 	GCInterval     time.Duration            `yaml:"gcInterval"`
 	DriverConfigs  map[string]*DriverConfig `yaml:"driverGCConfigs"`
+	// Encryption is at-rest encryption configuration of download files
+	Encryption encryption.Config `yaml:"encryption"`
 }
 
 func DefaultConfig() Config {
@@ -80,6 +83,9 @@ func (c Config) Validate() []error {
 		errors = append(errors, fmt.Errorf("storage StorageMode must in [%s]. but is: %s", storageModes, c.StorageMode))
 	}
 	errors = append(errors, builder.Validate(c.DriverConfigs)...)
+	if err := c.Encryption.Validate(); err != nil {
+		errors = append(errors, err)
+	}
 	return errors
 }
 
@@ -64,6 +64,9 @@ func (b *diskStorageBuilder) Build(config storage.Config, taskManager task.Manag
 	if err != nil {
 		return nil, err
 	}
+	if diskDriver, err = storage.NewEncryptedDriver(diskDriver, config.Encryption); err != nil {
+		return nil, err
+	}
 	storageManager := &diskStorageManager{
 		config:      config,
 		diskDriver:  diskDriver,
@@ -174,6 +177,13 @@ func (s *diskStorageManager) ReadDownloadFile(taskID string) (io.ReadCloser, err
 	return s.diskDriver.Get(storage.GetDownloadRaw(taskID))
 }
 
+func (s *diskStorageManager) ReadDownloadFileRange(taskID string, offset int64, length int64) (io.ReadCloser, error) {
+	raw := storage.GetDownloadRaw(taskID)
+	raw.Offset = offset
+	raw.Length = length
+	return s.diskDriver.Get(raw)
+}
+
 func (s *diskStorageManager) StatDownloadFile(taskID string) (*storedriver.StorageInfo, error) {
 	storageInfo, err := s.diskDriver.Stat(storage.GetDownloadRaw(taskID))
 	if err != nil && os.IsNotExist(err) {
@@ -0,0 +1,296 @@
+/*
+ *     Copyright 2020 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package storage
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"d7y.io/dragonfly/v2/cdn/storedriver"
+	"d7y.io/dragonfly/v2/pkg/encryption"
+)
+
+// encryptionSuffix is the suffix of the file which stores salt and extents of an encrypted download file
+const encryptionSuffix = ".enc"
+
+// encryptedDriver encrypts task download files on top of a local storedriver.Driver,
+// other files like task and piece metadata are passed through.
+type encryptedDriver struct {
+	storedriver.Driver
+	encryptor *encryption.Encryptor
+
+	mu    sync.Mutex
+	files map[string]*encryptedFile
+}
+
+type encryptedFile struct {
+	sync.Mutex
+	cipher  *encryption.Cipher
+	extents []encryption.Extent
+}
+
+type encryptionHeader struct {
+	Salt []byte `json:"salt"`
+}
+
+// NewEncryptedDriver wraps driver with at-rest encryption of download files, it returns driver itself when encryption is disabled.
+func NewEncryptedDriver(driver storedriver.Driver, config encryption.Config) (storedriver.Driver, error) {
+	encryptor, err := encryption.NewFromConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	if encryptor == nil {
+		return driver, nil
+	}
+	return &encryptedDriver{
+		Driver:    driver,
+		encryptor: encryptor,
+		files:     map[string]*encryptedFile{},
+	}, nil
+}
+
+// isDownloadRaw reports whether raw is a task download file, task ids never contain dot
+func isDownloadRaw(raw *storedriver.Raw) bool {
+	return raw.Bucket == DownloadHome && raw.Key != "" && !strings.Contains(path.Base(raw.Key), ".")
+}
+
+func encryptionRaw(raw *storedriver.Raw) *storedriver.Raw {
+	return &storedriver.Raw{
+		Bucket: raw.Bucket,
+		Key:    raw.Key + encryptionSuffix,
+	}
+}
+
+// file loads the encryption state of raw, a new state is created when create is true and raw is not encrypted yet.
+// nil is returned when raw is not encrypted and create is false.
+func (d *encryptedDriver) file(raw *storedriver.Raw, create bool) (*encryptedFile, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	key := path.Join(raw.Bucket, raw.Key)
+	if f, ok := d.files[key]; ok {
+		return f, nil
+	}
+
+	data, err := d.Driver.GetBytes(encryptionRaw(raw))
+	if err != nil && !os.IsNotExist(errors.Cause(err)) {
+		return nil, err
+	}
+	if err == nil {
+		f, err := d.parseFile(raw.Key, data)
+		if err != nil {
+			return nil, errors.Wrapf(err, "parse encryption file of %s", key)
+		}
+		d.files[key] = f
+		return f, nil
+	}
+	if !create {
+		return nil, nil
+	}
+
+	salt, err := encryption.NewSalt()
+	if err != nil {
+		return nil, err
+	}
+	c, err := d.encryptor.Cipher(raw.Key, salt)
+	if err != nil {
+		return nil, err
+	}
+	header, err := json.Marshal(encryptionHeader{Salt: salt})
+	if err != nil {
+		return nil, err
+	}
+	encRaw := encryptionRaw(raw)
+	encRaw.Trunc = true
+	if err := d.Driver.PutBytes(encRaw, append(header, '\n')); err != nil {
+		return nil, err
+	}
+	f := &encryptedFile{cipher: c}
+	d.files[key] = f
+	return f, nil
+}
+
+func (d *encryptedDriver) parseFile(id string, data []byte) (*encryptedFile, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	if !scanner.Scan() {
+		return nil, errors.New("empty encryption file")
+	}
+	var header encryptionHeader
+	if err := json.Unmarshal(scanner.Bytes(), &header); err != nil {
+		return nil, err
+	}
+	c, err := d.encryptor.Cipher(id, header.Salt)
+	if err != nil {
+		return nil, err
+	}
+	f := &encryptedFile{cipher: c}
+	for scanner.Scan() {
+		var extent encryption.Extent
+		if err := json.Unmarshal(scanner.Bytes(), &extent); err != nil {
+			return nil, err
+		}
+		f.extents = append(f.extents, extent)
+	}
+	return f, scanner.Err()
+}
+
+func (d *encryptedDriver) appendExtent(raw *storedriver.Raw, f *encryptedFile, extent encryption.Extent) error {
+	data, err := json.Marshal(extent)
+	if err != nil {
+		return err
+	}
+	f.Lock()
+	defer f.Unlock()
+	encRaw := encryptionRaw(raw)
+	encRaw.Append = true
+	if err := d.Driver.PutBytes(encRaw, append(data, '\n')); err != nil {
+		return err
+	}
+	f.extents = append(f.extents, extent)
+	return nil
+}
+
+func (d *encryptedDriver) Get(raw *storedriver.Raw) (io.ReadCloser, error) {
+	if !isDownloadRaw(raw) {
+		return d.Driver.Get(raw)
+	}
+	f, err := d.file(raw, false)
+	if err != nil {
+		return nil, err
+	}
+	// data written before encryption was enabled
+	if f == nil {
+		return d.Driver.Get(raw)
+	}
+	info, err := d.Driver.Stat(raw)
+	if err != nil {
+		return nil, err
+	}
+	if err := storedriver.CheckGetRaw(raw, info.Size); err != nil {
+		return nil, err
+	}
+	length := raw.Length
+	if length <= 0 {
+		length = info.Size - raw.Offset
+	}
+
+	file, err := os.Open(d.Driver.GetPath(raw))
+	if err != nil {
+		return nil, err
+	}
+	f.Lock()
+	extents := make([]encryption.Extent, len(f.extents))
+	copy(extents, f.extents)
+	f.Unlock()
+	return &readCloser{
+		Reader: f.cipher.NewReader(file, extents, raw.Offset, length),
+		Closer: file,
+	}, nil
+}
+
+func (d *encryptedDriver) GetBytes(raw *storedriver.Raw) ([]byte, error) {
+	if !isDownloadRaw(raw) {
+		return d.Driver.GetBytes(raw)
+	}
+	rc, err := d.Get(raw)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	return io.ReadAll(rc)
+}
+
+func (d *encryptedDriver) Put(raw *storedriver.Raw, data io.Reader) error {
+	if !isDownloadRaw(raw) || data == nil {
+		return d.Driver.Put(raw, data)
+	}
+	if raw.Append {
+		return errors.New("append is not supported by encrypted download file")
+	}
+	f, err := d.file(raw, true)
+	if err != nil {
+		return err
+	}
+
+	var (
+		pr, pw  = io.Pipe()
+		done    = make(chan struct{})
+		written int64
+		sum     []byte
+	)
+	ew, err := f.cipher.NewWriter(pw, raw.Offset)
+	if err != nil {
+		return err
+	}
+	go func() {
+		defer close(done)
+		src := data
+		if raw.Length > 0 {
+			src = io.LimitReader(data, raw.Length)
+		}
+		n, err := io.Copy(ew, src)
+		written, sum = n, ew.Sum()
+		pw.CloseWithError(err)
+	}()
+	err = d.Driver.Put(raw, pr)
+	pr.Close()
+	<-done
+	if err != nil {
+		return err
+	}
+	return d.appendExtent(raw, f, encryption.Extent{
+		Offset: raw.Offset,
+		Length: written,
+		Nonce:  ew.Nonce(),
+		MAC:    sum,
+	})
+}
+
+func (d *encryptedDriver) PutBytes(raw *storedriver.Raw, data []byte) error {
+	if !isDownloadRaw(raw) {
+		return d.Driver.PutBytes(raw, data)
+	}
+	if raw.Length > 0 {
+		data = data[:raw.Length]
+	}
+	return d.Put(raw, bytes.NewReader(data))
+}
+
+func (d *encryptedDriver) Remove(raw *storedriver.Raw) error {
+	if !isDownloadRaw(raw) {
+		return d.Driver.Remove(raw)
+	}
+	d.mu.Lock()
+	delete(d.files, path.Join(raw.Bucket, raw.Key))
+	d.mu.Unlock()
+	if err := d.Driver.Remove(encryptionRaw(raw)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return d.Driver.Remove(raw)
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
@@ -0,0 +1,96 @@
+/*
+ *     Copyright 2020 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package storage
+
+import (
+	"bytes"
+	"encoding/hex"
+	"io"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"d7y.io/dragonfly/v2/cdn/storedriver"
+	"d7y.io/dragonfly/v2/cdn/storedriver/local"
+	"d7y.io/dragonfly/v2/pkg/encryption"
+)
+
+func TestEncryptedDriver(t *testing.T) {
+	dir := t.TempDir()
+	keyFile := filepath.Join(dir, "storage.key")
+	assert.Nil(t, os.WriteFile(keyFile, []byte(hex.EncodeToString(bytes.Repeat([]byte{7}, encryption.KeySize))), 0600))
+	config := encryption.Config{
+		Enable:    true,
+		KeySource: encryption.KeySourceFile,
+		KeyFile:   keyFile,
+	}
+
+	localDriver, err := local.NewStorageDriver(&storedriver.Config{BaseDir: filepath.Join(dir, "data")})
+	assert.Nil(t, err)
+	driver, err := NewEncryptedDriver(localDriver, config)
+	assert.Nil(t, err)
+
+	var (
+		taskID    = "d4bb1c273a9889fea14abd4651994fe8"
+		pieceSize = 1000
+		content   = make([]byte, 3*pieceSize+10)
+	)
+	rand.Read(content)
+	for start := 0; start < len(content); start += pieceSize {
+		end := start + pieceSize
+		if end > len(content) {
+			end = len(content)
+		}
+		raw := GetDownloadRaw(taskID)
+		raw.Offset = int64(start)
+		raw.Length = int64(end - start)
+		assert.Nil(t, driver.Put(raw, bytes.NewReader(content[start:end])))
+	}
+
+	onDisk, err := os.ReadFile(driver.GetPath(GetDownloadRaw(taskID)))
+	assert.Nil(t, err)
+	assert.Equal(t, len(content), len(onDisk))
+	assert.NotEqual(t, content, onDisk, "download file must be encrypted")
+
+	data, err := driver.GetBytes(GetDownloadRaw(taskID))
+	assert.Nil(t, err)
+	assert.Equal(t, content, data)
+
+	// a new driver loads salt and extents from disk
+	driver, err = NewEncryptedDriver(localDriver, config)
+	assert.Nil(t, err)
+	raw := GetDownloadRaw(taskID)
+	raw.Offset, raw.Length = 900, 1200
+	rc, err := driver.Get(raw)
+	assert.Nil(t, err)
+	data, err = io.ReadAll(rc)
+	rc.Close()
+	assert.Nil(t, err)
+	assert.Equal(t, content[900:2100], data)
+
+	// metadata is not encrypted
+	assert.Nil(t, driver.PutBytes(GetTaskMetadataRaw(taskID), []byte("{}")))
+	meta, err := os.ReadFile(driver.GetPath(GetTaskMetadataRaw(taskID)))
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("{}"), meta)
+
+	assert.Nil(t, driver.Remove(GetDownloadRaw(taskID)))
+	assert.False(t, driver.Exits(encryptionRaw(GetDownloadRaw(taskID))))
+}
@@ -65,6 +65,9 @@ func (*hybridStorageBuilder) Build(config storage.Config, taskManager task.Manag
 	if err != nil {
 		return nil, err
 	}
+	if diskDriver, err = storage.NewEncryptedDriver(diskDriver, config.Encryption); err != nil {
+		return nil, err
+	}
 	memoryDriverBuilder := storedriver.Get(local.MemoryDriverName)
 	if memoryDriverBuilder == nil {
 		return nil, fmt.Errorf("can not find memory driver for hybrid storage manager")
@@ -145,6 +148,13 @@ func (h *hybridStorageManager) ReadDownloadFile(taskID string) (io.ReadCloser, e
 	return h.diskDriver.Get(storage.GetDownloadRaw(taskID))
 }
 
+func (h *hybridStorageManager) ReadDownloadFileRange(taskID string, offset int64, length int64) (io.ReadCloser, error) {
+	raw := storage.GetDownloadRaw(taskID)
+	raw.Offset = offset
+	raw.Length = length
+	return h.diskDriver.Get(raw)
+}
+
 func (h *hybridStorageManager) ReadPieceMetaRecords(taskID string) ([]*storage.PieceMetaRecord, error) {
 	readBytes, err := h.diskDriver.GetBytes(storage.GetPieceMetadataRaw(taskID))
 	if err != nil {
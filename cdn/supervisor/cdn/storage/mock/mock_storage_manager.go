@@ -80,6 +80,21 @@ func (mr *MockManagerMockRecorder) ReadDownloadFile(arg0 interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadDownloadFile", reflect.TypeOf((*MockManager)(nil).ReadDownloadFile), arg0)
 }
 
+// ReadDownloadFileRange mocks base method.
+func (m *MockManager) ReadDownloadFileRange(arg0 string, arg1, arg2 int64) (io.ReadCloser, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadDownloadFileRange", arg0, arg1, arg2)
+	ret0, _ := ret[0].(io.ReadCloser)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadDownloadFileRange indicates an expected call of ReadDownloadFileRange.
+func (mr *MockManagerMockRecorder) ReadDownloadFileRange(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadDownloadFileRange", reflect.TypeOf((*MockManager)(nil).ReadDownloadFileRange), arg0, arg1, arg2)
+}
+
 // ReadFileMetadata mocks base method.
 func (m *MockManager) ReadFileMetadata(arg0 string) (*storage.FileMetadata, error) {
 	m.ctrl.T.Helper()
@@ -28,6 +28,7 @@ import (
 
 	"d7y.io/dragonfly/v2/cdn/storedriver"
 	"d7y.io/dragonfly/v2/cdn/supervisor/task"
+	"d7y.io/dragonfly/v2/pkg/util/digestutils"
 	"d7y.io/dragonfly/v2/pkg/util/rangeutils"
 )
 
@@ -54,6 +55,9 @@ type Manager interface {
 	// ReadDownloadFile return reader of download file
 	ReadDownloadFile(taskID string) (io.ReadCloser, error)
 
+	// ReadDownloadFileRange return reader of the range [offset, offset+length) of download file
+	ReadDownloadFileRange(taskID string, offset int64, length int64) (io.ReadCloser, error)
+
 	// ReadFileMetadata return meta data of download file
 	ReadFileMetadata(taskID string) (*FileMetadata, error)
 
@@ -111,13 +115,23 @@ type PieceMetaRecord struct {
 	OriginRange *rangeutils.Range `json:"originRange"`
 	// 0: PlainUnspecified
 	PieceStyle int32 `json:"pieceStyle"`
+	// sha256 of transported piece content, it is empty for records written by old versions
+	Sha256 string `json:"sha256,omitempty"`
 }
 
 const fieldSeparator = ":"
 
 func (record PieceMetaRecord) String() string {
 	return fmt.Sprint(record.PieceNum, fieldSeparator, record.PieceLen, fieldSeparator, record.Md5, fieldSeparator, record.Range, fieldSeparator,
-		record.OriginRange, fieldSeparator, record.PieceStyle)
+		record.OriginRange, fieldSeparator, record.PieceStyle, fieldSeparator, record.Sha256)
+}
+
+// Digest returns the piece digest with algorithm, it is empty when sha256 of piece is unknown
+func (record PieceMetaRecord) Digest() string {
+	if record.Sha256 == "" {
+		return ""
+	}
+	return digestutils.Sha256Hash.String() + ":" + record.Sha256
 }
 
 func ParsePieceMetaRecord(value string) (record *PieceMetaRecord, err error) {
@@ -148,6 +162,10 @@ func ParsePieceMetaRecord(value string) (record *PieceMetaRecord, err error) {
 	if err != nil {
 		return nil, errors.Wrapf(err, "invalid pieceStyle: %s", fields[5])
 	}
+	var sha256 string
+	if len(fields) > 6 {
+		sha256 = fields[6]
+	}
 	return &PieceMetaRecord{
 		PieceNum:    uint32(pieceNum),
 		PieceLen:    uint32(pieceLen),
@@ -155,6 +173,7 @@ func ParsePieceMetaRecord(value string) (record *PieceMetaRecord, err error) {
 		Range:       pieceRange,
 		OriginRange: originRange,
 		PieceStyle:  int32(pieceStyle),
+		Sha256:      sha256,
 	}, nil
 }
 
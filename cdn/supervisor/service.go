@@ -47,6 +47,10 @@ type CDNService interface {
 
 	// GetSeedTask returns seed task associated with taskID
 	GetSeedTask(taskID string) (seedTask *task.SeedTask, err error)
+
+	// WatchSeedPieces returns pieces of the seed task, pieces are sent when they are ready
+	// and the channel is closed when the task is done
+	WatchSeedPieces(ctx context.Context, clientAddr string, taskID string) (<-chan *task.PieceInfo, error)
 }
 
 type cdnService struct {
@@ -139,3 +143,7 @@ func (service *cdnService) GetSeedPieces(taskID string) ([]*task.PieceInfo, erro
 func (service *cdnService) GetSeedTask(taskID string) (*task.SeedTask, error) {
 	return service.taskManager.Get(taskID)
 }
+
+func (service *cdnService) WatchSeedPieces(ctx context.Context, clientAddr string, taskID string) (<-chan *task.PieceInfo, error) {
+	return service.progressManager.WatchSeedProgress(ctx, clientAddr, taskID)
+}
@@ -95,6 +95,7 @@ type PieceInfo struct {
 	OriginRange *rangeutils.Range `json:"origin_range"`
 	PieceLen    uint32            `json:"piece_len"`
 	PieceStyle  base.PieceStyle   `json:"piece_style"`
+	PieceDigest string            `json:"piece_digest,omitempty"`
 }
 
 const (
@@ -0,0 +1,112 @@
+/*
+ *     Copyright 2020 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package upload serves cdn download files to peers, it replaces the static file server
+// in front of the upload directory when download files are encrypted at rest.
+package upload
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/go-http-utils/headers"
+	"github.com/gorilla/mux"
+
+	"d7y.io/dragonfly/v2/cdn/supervisor/cdn/storage"
+	logger "d7y.io/dragonfly/v2/internal/dflog"
+	"d7y.io/dragonfly/v2/pkg/util/rangeutils"
+)
+
+const (
+	// DownloadHTTPPathPrefix is same with the path of the static file server
+	DownloadHTTPPathPrefix = "/download/"
+)
+
+type Server struct {
+	*http.Server
+	storageManager storage.Manager
+}
+
+// New returns a new upload server listening on addr.
+func New(addr string, storageManager storage.Manager) *Server {
+	s := &Server{
+		Server:         &http.Server{Addr: addr},
+		storageManager: storageManager,
+	}
+	r := mux.NewRouter()
+	r.HandleFunc(DownloadHTTPPathPrefix+"{taskPrefix}/{task}", s.handleUpload).Methods("GET")
+	s.Server.Handler = r
+	return s
+}
+
+func (s *Server) ListenAndServe() error {
+	logger.Infof("====starting upload server at %s====", s.Server.Addr)
+	if err := s.Server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		return err
+	}
+	return nil
+}
+
+func (s *Server) Shutdown(ctx context.Context) error {
+	defer logger.Infof("====stopped upload server====")
+	return s.Server.Shutdown(ctx)
+}
+
+func (s *Server) handleUpload(w http.ResponseWriter, r *http.Request) {
+	taskID := mux.Vars(r)["task"]
+	log := logger.WithTaskID(taskID)
+
+	info, err := s.storageManager.StatDownloadFile(taskID)
+	if err != nil {
+		log.Errorf("stat download file failed: %v", err)
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	var (
+		offset int64
+		length = info.Size
+		status = http.StatusOK
+	)
+	if rangeHeader := r.Header.Get(headers.Range); rangeHeader != "" {
+		rg, err := rangeutils.ParseRange(strings.TrimPrefix(rangeHeader, "bytes="), uint64(info.Size))
+		if err != nil {
+			log.Errorf("parse range %q failed: %v", rangeHeader, err)
+			http.Error(w, err.Error(), http.StatusRequestedRangeNotSatisfiable)
+			return
+		}
+		offset, length = int64(rg.StartIndex), int64(rg.Length())
+		status = http.StatusPartialContent
+		w.Header().Set(headers.ContentRange, fmt.Sprintf("bytes %d-%d/%d", rg.StartIndex, rg.EndIndex, info.Size))
+	}
+
+	rc, err := s.storageManager.ReadDownloadFileRange(taskID, offset, length)
+	if err != nil {
+		log.Errorf("read download file failed: %v", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer rc.Close()
+
+	w.Header().Set(headers.ContentLength, fmt.Sprintf("%d", length))
+	w.WriteHeader(status)
+	if n, err := io.Copy(w, rc); err != nil {
+		log.Errorf("transfer data failed after %d bytes: %v", n, err)
+	}
+}
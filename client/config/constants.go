@@ -38,6 +38,18 @@ const (
 	ForceNotBackSourceAddition    = 1000
 )
 
+/* piece transport between peers */
+const (
+	PieceTransportHTTP = "http"
+	PieceTransportGRPC = "grpc"
+)
+
+/* piece selection strategies of peer tasks */
+const (
+	PieceSelectionSequential  = "sequential"
+	PieceSelectionRarestFirst = "rarest-first"
+)
+
 /* download pattern */
 const (
 	PatternP2P    = "p2p"
@@ -45,6 +57,14 @@ const (
 	PatternSource = "source"
 )
 
+/* dfcache sub commands */
+const (
+	CmdStat   = "stat"
+	CmdImport = "import"
+	CmdExport = "export"
+	CmdDelete = "delete"
+)
+
 const (
 	DefaultPerPeerDownloadLimit = 20 * unit.MB
 	DefaultTotalDownloadLimit   = 100 * unit.MB
@@ -60,17 +80,20 @@ const (
 	ServerPortLowerLimit = 15000
 	ServerPortUpperLimit = 65000
 
-	DefaultTaskExpireTime  = 3 * time.Minute
-	DefaultGCInterval      = 1 * time.Minute
-	DefaultDaemonAliveTime = 5 * time.Minute
-	DefaultScheduleTimeout = 5 * time.Minute
-	DefaultDownloadTimeout = 5 * time.Minute
+	DefaultTaskExpireTime   = 3 * time.Minute
+	DefaultGCInterval       = 1 * time.Minute
+	DefaultDaemonAliveTime  = 5 * time.Minute
+	DefaultScheduleTimeout  = 5 * time.Minute
+	DefaultDownloadTimeout  = 5 * time.Minute
+	DefaultLeaveHostTimeout = 10 * time.Second
 
 	DefaultSchedulerSchema = "http"
 	DefaultSchedulerIP     = "127.0.0.1"
 	DefaultSchedulerPort   = 8002
 
 	DefaultPieceChanSize = 16
+
+	DefaultDfgetParallelism = 4
 )
 
 const (
@@ -47,6 +47,8 @@ const (
 	SpanFilePeerTask      = "file-peer-task"
 	SpanStreamPeerTask    = "stream-peer-task"
 	SpanReusePeerTask     = "reuse-peer-task"
+	SpanImportTask        = "import-task"
+	SpanAnnounceTask      = "announce-task"
 	SpanRegisterTask      = "register"
 	SpanReportPeerResult  = "report-peer-result"
 	SpanReportPieceResult = "report-piece-result"
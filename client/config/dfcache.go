@@ -0,0 +1,155 @@
+/*
+ *     Copyright 2020 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"d7y.io/dragonfly/v2/cmd/dependency/base"
+	"d7y.io/dragonfly/v2/internal/dferrors"
+	"d7y.io/dragonfly/v2/pkg/basic"
+)
+
+type DfcacheConfig = CacheOption
+
+// CacheOption holds all the runtime config information of dfcache.
+type CacheOption struct {
+	base.Options `yaml:",inline" mapstructure:",squash"`
+
+	// Cid is the task id of the cache, it takes precedence over URL.
+	Cid string `yaml:"cid,omitempty" mapstructure:"cid,omitempty"`
+
+	// URL identifies the cache together with Tag, Filter and Digest like dfget.
+	URL string `yaml:"url,omitempty" mapstructure:"url,omitempty"`
+
+	// Tag identify the cache, it is available merely when digest param not exist.
+	Tag string `yaml:"tag,omitempty" mapstructure:"tag,omitempty"`
+
+	// Filter filter some query params of url, use char '&' to separate different params.
+	Filter string `yaml:"filter,omitempty" mapstructure:"filter,omitempty"`
+
+	// Digest of the cache, in format of md5:xxx or sha256:yyy.
+	Digest string `yaml:"digest,omitempty" mapstructure:"digest,omitempty"`
+
+	// Timeout of the operation.
+	Timeout time.Duration `yaml:"timeout,omitempty" mapstructure:"timeout,omitempty"`
+
+	// LocalOnly indicates only check or export the cache in local storage, but not in the cluster.
+	LocalOnly bool `yaml:"localOnly,omitempty" mapstructure:"local,omitempty"`
+
+	// Path is the absolute path of the file to import.
+	Path string `yaml:"path,omitempty" mapstructure:"path,omitempty"`
+
+	// Output is the absolute path of the exported file.
+	Output string `yaml:"output,omitempty" mapstructure:"output,omitempty"`
+
+	// LogDir is log directory of dfcache.
+	LogDir string `yaml:"logDir,omitempty" mapstructure:"logDir,omitempty"`
+
+	// WorkHome is working directory of dfcache.
+	WorkHome string `yaml:"workHome,omitempty" mapstructure:"workHome,omitempty"`
+}
+
+func NewDfcacheConfig() *CacheOption {
+	return &CacheOption{}
+}
+
+// Convert fills the config with positional arguments, the first argument is the cid of cache,
+// except import command, which is the path of the imported file.
+func (cfg *CacheOption) Convert(cmd string, args []string) error {
+	if len(args) > 0 {
+		if cmd == CmdImport {
+			cfg.Path = args[0]
+		} else {
+			cfg.Cid = args[0]
+		}
+	}
+
+	if cfg.Digest != "" {
+		cfg.Tag = ""
+	}
+
+	var err error
+	if cfg.Path != "" {
+		if cfg.Path, err = filepath.Abs(cfg.Path); err != nil {
+			return err
+		}
+	}
+	if cfg.Output != "" {
+		if cfg.Output, err = filepath.Abs(cfg.Output); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Validate checks the config for the dfcache sub command.
+func (cfg *CacheOption) Validate(cmd string) error {
+	if cfg == nil {
+		return errors.Wrap(dferrors.ErrInvalidArgument, "runtime config")
+	}
+
+	switch cmd {
+	case CmdStat, CmdDelete:
+		if cfg.Cid == "" && cfg.URL == "" {
+			return errors.Wrap(dferrors.ErrInvalidArgument, "cid or url is required")
+		}
+	case CmdImport:
+		if cfg.URL == "" {
+			return errors.Wrap(dferrors.ErrInvalidArgument, "url is required")
+		}
+		if cfg.Path == "" {
+			return errors.Wrap(dferrors.ErrInvalidArgument, "path is required")
+		}
+	case CmdExport:
+		if cfg.Cid == "" && cfg.URL == "" {
+			return errors.Wrap(dferrors.ErrInvalidArgument, "cid or url is required")
+		}
+		if err := cfg.checkOutput(); err != nil {
+			return errors.Wrapf(dferrors.ErrInvalidArgument, "output: %v", err)
+		}
+	default:
+		return errors.Wrapf(dferrors.ErrInvalidArgument, "unknown command: %s", cmd)
+	}
+	return nil
+}
+
+func (cfg *CacheOption) String() string {
+	js, _ := json.Marshal(cfg)
+	return string(js)
+}
+
+func (cfg *CacheOption) checkOutput() error {
+	if cfg.Output == "" {
+		return errors.New("output is required")
+	}
+
+	if err := MkdirAll(filepath.Dir(cfg.Output), 0777, basic.UserID, basic.UserGroup); err != nil {
+		return err
+	}
+
+	if f, err := os.Stat(cfg.Output); err == nil && f.IsDir() {
+		return fmt.Errorf("path[%s] is directory but requires file path", cfg.Output)
+	}
+	return nil
+}
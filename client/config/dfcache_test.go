@@ -0,0 +1,130 @@
+/*
+ *     Copyright 2020 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	testifyassert "github.com/stretchr/testify/assert"
+)
+
+func TestCacheOption_ConvertAndValidate(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		name   string
+		cmd    string
+		args   []string
+		cfg    CacheOption
+		expect func(assert *testifyassert.Assertions, cfg *CacheOption, err error)
+	}{
+		{
+			name: "stat with cid",
+			cmd:  CmdStat,
+			args: []string{"cid"},
+			expect: func(assert *testifyassert.Assertions, cfg *CacheOption, err error) {
+				assert.Nil(err)
+				assert.Equal("cid", cfg.Cid)
+			},
+		},
+		{
+			name: "stat with url and digest",
+			cmd:  CmdStat,
+			cfg:  CacheOption{URL: "d7y:/cache", Tag: "tag", Digest: "md5:xxx"},
+			expect: func(assert *testifyassert.Assertions, cfg *CacheOption, err error) {
+				assert.Nil(err)
+				assert.Equal("", cfg.Tag, "tag conflicts with digest")
+			},
+		},
+		{
+			name: "delete without cid and url",
+			cmd:  CmdDelete,
+			expect: func(assert *testifyassert.Assertions, cfg *CacheOption, err error) {
+				assert.NotNil(err)
+			},
+		},
+		{
+			name: "import with relative path",
+			cmd:  CmdImport,
+			args: []string{"file"},
+			cfg:  CacheOption{URL: "d7y:/cache"},
+			expect: func(assert *testifyassert.Assertions, cfg *CacheOption, err error) {
+				assert.Nil(err)
+				assert.Equal("d7y:/cache", cfg.URL)
+				assert.True(filepath.IsAbs(cfg.Path))
+				assert.Equal("", cfg.Cid)
+			},
+		},
+		{
+			name: "import without url",
+			cmd:  CmdImport,
+			args: []string{"file"},
+			expect: func(assert *testifyassert.Assertions, cfg *CacheOption, err error) {
+				assert.NotNil(err)
+			},
+		},
+		{
+			name: "export to new file",
+			cmd:  CmdExport,
+			args: []string{"cid"},
+			cfg:  CacheOption{Output: filepath.Join(dir, "x", "y", "exported")},
+			expect: func(assert *testifyassert.Assertions, cfg *CacheOption, err error) {
+				assert.Nil(err)
+				info, err := os.Stat(filepath.Join(dir, "x", "y"))
+				assert.Nil(err)
+				assert.True(info.IsDir(), "parent directory of output should be created")
+			},
+		},
+		{
+			name: "export to directory",
+			cmd:  CmdExport,
+			args: []string{"cid"},
+			cfg:  CacheOption{Output: dir},
+			expect: func(assert *testifyassert.Assertions, cfg *CacheOption, err error) {
+				assert.NotNil(err)
+			},
+		},
+		{
+			name: "export without output",
+			cmd:  CmdExport,
+			args: []string{"cid"},
+			expect: func(assert *testifyassert.Assertions, cfg *CacheOption, err error) {
+				assert.NotNil(err)
+			},
+		},
+		{
+			name: "unknown command",
+			cmd:  "unknown",
+			args: []string{"cid"},
+			expect: func(assert *testifyassert.Assertions, cfg *CacheOption, err error) {
+				assert.NotNil(err)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := tc.cfg
+			err := cfg.Convert(tc.cmd, tc.args)
+			if err == nil {
+				err = cfg.Validate(tc.cmd)
+			}
+			tc.expect(testifyassert.New(t), &cfg, err)
+		})
+	}
+}
@@ -42,6 +42,15 @@ import (
 
 type DfgetConfig = ClientOption
 
+// StdoutOutput is the output path which writes the content to stdout
+const StdoutOutput = "-"
+
+// output formats of dfget messages
+const (
+	OutputFormatText = "text"
+	OutputFormatJSON = "json"
+)
+
 // ClientOption holds all the runtime config information.
 type ClientOption struct {
 	base.Options `yaml:",inline" mapstructure:",squash"`
@@ -91,6 +100,12 @@ type ClientOption struct {
 	// DisableBackSource indicates whether to not back source to download when p2p fails.
 	DisableBackSource bool `yaml:"disableBackSource,omitempty" mapstructure:"disableBackSource,omitempty"`
 
+	// Continue indicates whether to resume the unfinished download of the same task in daemon.
+	Continue bool `yaml:"continue,omitempty" mapstructure:"continue,omitempty"`
+
+	// Stdout indicates to write the content to stdout instead of the output file, `-O -` is the same.
+	Stdout bool `yaml:"stdout,omitempty" mapstructure:"stdout,omitempty"`
+
 	// Insecure indicates whether skip secure verify when supernode interact with the source.
 	Insecure bool `yaml:"insecure,omitempty" mapstructure:"insecure,omitempty"`
 
@@ -127,6 +142,22 @@ type ClientOption struct {
 	RecursiveAcceptRegex string `yaml:"acceptRegex,omitempty" mapstructure:"accept-regex,omitempty"`
 
 	RecursiveRejectRegex string `yaml:"rejectRegex,omitempty" mapstructure:"reject-regex,omitempty"`
+
+	// RecursiveSync indicates to skip the files which are up to date with the source in recursive download
+	RecursiveSync bool `yaml:"sync,omitempty" mapstructure:"sync,omitempty"`
+
+	// RecursiveDelete indicates to delete the local files which pass the recursive filters but are not in the source in recursive download
+	RecursiveDelete bool `yaml:"delete,omitempty" mapstructure:"delete,omitempty"`
+
+	// InputFile is the manifest file which lists the urls to download, one entry per line or in yaml
+	InputFile string `yaml:"inputFile,omitempty" mapstructure:"input-file,omitempty"`
+
+	// Parallelism is the maximum number of files downloaded concurrently
+	Parallelism int `yaml:"parallelism,omitempty" mapstructure:"parallelism,omitempty"`
+
+	// OutputFormat is the format of progress and result, text or json,
+	// json writes newline-delimited events to stdout and other messages to stderr.
+	OutputFormat string `yaml:"outputFormat,omitempty" mapstructure:"output-format,omitempty"`
 }
 
 func NewDfgetConfig() *ClientOption {
@@ -138,6 +169,20 @@ func (cfg *ClientOption) Validate() error {
 		return errors.Wrap(dferrors.ErrInvalidArgument, "runtime config")
 	}
 
+	switch cfg.OutputFormat {
+	case "", OutputFormatText:
+	case OutputFormatJSON:
+		if cfg.Stdout {
+			return errors.Wrap(dferrors.ErrInvalidArgument, "output-format: json conflicts with stdout")
+		}
+	default:
+		return errors.Wrapf(dferrors.ErrInvalidArgument, "output-format: %s", cfg.OutputFormat)
+	}
+
+	if cfg.InputFile != "" {
+		return cfg.checkInputFile()
+	}
+
 	if !urlutils.IsValidURL(cfg.URL) {
 		return errors.Wrapf(dferrors.ErrInvalidArgument, "url: %v", cfg.URL)
 	}
@@ -150,17 +195,51 @@ func (cfg *ClientOption) Validate() error {
 		return err
 	}
 
+	if cfg.Recursive && cfg.Parallelism <= 0 {
+		return errors.Wrapf(dferrors.ErrInvalidArgument, "parallelism: %d", cfg.Parallelism)
+	}
+
+	if cfg.Stdout {
+		if cfg.Recursive {
+			return errors.Wrap(dferrors.ErrInvalidArgument, "stdout: recursive download can not write to stdout")
+		}
+		return nil
+	}
+
 	if err := cfg.checkOutput(); err != nil {
 		return errors.Wrapf(dferrors.ErrInvalidArgument, "output: %v", err)
 	}
 
+	if cfg.Recursive && cfg.RecursiveDelete {
+		return cfg.checkDeleteOutput()
+	}
+
+	return nil
+}
+
+// checkDeleteOutput refuses to delete the extraneous files in the filesystem root or home directory,
+// which are never a download target in practice.
+func (cfg *ClientOption) checkDeleteOutput() error {
+	output := filepath.Clean(cfg.Output)
+	if output == filepath.Dir(output) {
+		return errors.Wrapf(dferrors.ErrInvalidArgument, "delete: output %s is the filesystem root", cfg.Output)
+	}
+	if home, err := os.UserHomeDir(); err == nil && output == filepath.Clean(home) {
+		return errors.Wrapf(dferrors.ErrInvalidArgument, "delete: output %s is the home directory", cfg.Output)
+	}
 	return nil
 }
 
 func (cfg *ClientOption) Convert(args []string) error {
 	var err error
 
-	if cfg.Output, err = filepath.Abs(cfg.Output); err != nil {
+	if cfg.Output == StdoutOutput {
+		cfg.Stdout = true
+	}
+
+	if cfg.Stdout {
+		cfg.Output = ""
+	} else if cfg.Output, err = filepath.Abs(cfg.Output); err != nil {
 		return err
 	}
 
@@ -172,13 +251,42 @@ func (cfg *ClientOption) Convert(args []string) error {
 		cfg.Tag = ""
 	}
 
-	if cfg.Console {
+	if cfg.Console || cfg.OutputFormat == OutputFormatJSON {
 		cfg.ShowProgress = false
 	}
 
 	return nil
 }
 
+// checkInputFile checks the options of downloading from manifest file,
+// the entries are validated one by one when they are loaded.
+func (cfg *ClientOption) checkInputFile() error {
+	if cfg.URL != "" {
+		return errors.Wrap(dferrors.ErrInvalidArgument, "input-file: conflicts with url")
+	}
+
+	if cfg.Recursive || cfg.Stdout {
+		return errors.Wrap(dferrors.ErrInvalidArgument, "input-file: conflicts with recursive and stdout")
+	}
+
+	if cfg.Parallelism <= 0 {
+		return errors.Wrapf(dferrors.ErrInvalidArgument, "parallelism: %d", cfg.Parallelism)
+	}
+
+	if f, err := os.Stat(cfg.InputFile); err != nil {
+		return errors.Wrapf(dferrors.ErrInvalidArgument, "input-file: %v", err)
+	} else if f.IsDir() {
+		return errors.Wrapf(dferrors.ErrInvalidArgument, "input-file: %s is a directory", cfg.InputFile)
+	}
+
+	// output is the base directory of the entries
+	if f, err := os.Stat(cfg.Output); err == nil && !f.IsDir() {
+		return errors.Wrapf(dferrors.ErrInvalidArgument, "output: path[%s] is file but requires directory path", cfg.Output)
+	}
+
+	return nil
+}
+
 func (cfg *ClientOption) String() string {
 	js, _ := json.Marshal(cfg)
 	return string(js)
@@ -138,3 +138,16 @@ func TestMkdirAll(t *testing.T) {
 		})
 	}
 }
+
+func TestClientOption_checkDeleteOutput(t *testing.T) {
+	assert := testifyassert.New(t)
+	home, err := os.UserHomeDir()
+	assert.Nil(err)
+
+	for _, output := range []string{"/", home, home + "/"} {
+		cfg := &ClientOption{Output: output}
+		assert.NotNil(cfg.checkDeleteOutput(), "delete in %s should be refused", output)
+	}
+	cfg := &ClientOption{Output: t.TempDir()}
+	assert.Nil(cfg.checkDeleteOutput())
+}
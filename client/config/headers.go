@@ -23,4 +23,6 @@ const (
 	HeaderDragonflyBiz    = "X-Dragonfly-Biz"
 	// HeaderDragonflyRegistry is used for dynamic registry mirrors
 	HeaderDragonflyRegistry = "X-Dragonfly-Registry"
+	// HeaderDragonflyPieceSelection overrides the piece selection strategy of the task
+	HeaderDragonflyPieceSelection = "X-Dragonfly-Piece-Selection"
 )
@@ -49,6 +49,21 @@ func (mr *MockClientMockRecorder) Close() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockClient)(nil).Close))
 }
 
+// GetCDN mocks base method.
+func (m *MockClient) GetCDN(arg0 *manager.GetCDNRequest) (*manager.CDN, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCDN", arg0)
+	ret0, _ := ret[0].(*manager.CDN)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCDN indicates an expected call of GetCDN.
+func (mr *MockClientMockRecorder) GetCDN(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCDN", reflect.TypeOf((*MockClient)(nil).GetCDN), arg0)
+}
+
 // GetScheduler mocks base method.
 func (m *MockClient) GetScheduler(arg0 *manager.GetSchedulerRequest) (*manager.Scheduler, error) {
 	m.ctrl.T.Helper()
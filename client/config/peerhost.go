@@ -36,6 +36,7 @@ import (
 	"d7y.io/dragonfly/v2/client/clientutil"
 	"d7y.io/dragonfly/v2/cmd/dependency/base"
 	"d7y.io/dragonfly/v2/internal/dfnet"
+	"d7y.io/dragonfly/v2/pkg/encryption"
 	"d7y.io/dragonfly/v2/pkg/unit"
 	"d7y.io/dragonfly/v2/pkg/util/net/iputils"
 	"d7y.io/dragonfly/v2/pkg/util/stringutils"
@@ -66,7 +67,8 @@ type DaemonOption struct {
 }
 
 func NewDaemonConfig() *DaemonOption {
-	return &peerHostConfig
+	cfg := peerHostConfig()
+	return &cfg
 }
 
 func (p *DaemonOption) Load(path string) error {
@@ -125,6 +127,30 @@ func (p *DaemonOption) Validate() error {
 		}
 	}
 
+	if err := p.Storage.Encryption.Validate(); err != nil {
+		return err
+	}
+
+	switch p.Download.PieceTransport {
+	case "", PieceTransportHTTP, PieceTransportGRPC:
+	default:
+		return errors.Errorf("invalid piece transport %q", p.Download.PieceTransport)
+	}
+
+	if err := p.Download.PieceSelection.Validate(); err != nil {
+		return err
+	}
+
+	if p.Upload.ConcurrentLimit < 0 {
+		return errors.New("upload concurrentLimit can not be negative")
+	}
+
+	if p.Proxy != nil {
+		if err := p.Proxy.validateRegistryMirrors(); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -174,6 +200,40 @@ type DownloadOption struct {
 	CalculateDigest      bool                 `mapstructure:"calculateDigest" yaml:"calculateDigest"`
 	TransportOption      *TransportOption     `mapstructure:"transportOption" yaml:"transportOption"`
 	GetPiecesMaxRetry    int                  `mapstructure:"getPiecesMaxRetry" yaml:"getPiecesMaxRetry"`
+	// PieceTransport is the transport of pieces between peers, http or grpc,
+	// it is overridden by the scheduler cluster client config
+	PieceTransport string `mapstructure:"pieceTransport" yaml:"pieceTransport"`
+	// Prefetch downloads the whole file in background when ranges of it are requested,
+	// so later ranges of the file are served locally
+	Prefetch bool `mapstructure:"prefetch" yaml:"prefetch"`
+	// PieceSelection is the piece selection strategy of peer tasks
+	PieceSelection PieceSelectionOption `mapstructure:"pieceSelection" yaml:"pieceSelection"`
+}
+
+type PieceSelectionOption struct {
+	// Strategy is sequential or rarest-first, it is overridden by the X-Dragonfly-Piece-Selection header of proxy requests
+	Strategy string `mapstructure:"strategy" yaml:"strategy"`
+	// RandomFirstPieces is the count of first pieces which are selected randomly with rarest-first strategy,
+	// so peers started at the same time request different pieces
+	RandomFirstPieces int `mapstructure:"randomFirstPieces" yaml:"randomFirstPieces"`
+	// EndgamePieces is the count of last pieces which are requested from multiple parents at the same time,
+	// the slower requests are canceled after the piece is downloaded, 0 means disabled
+	EndgamePieces int `mapstructure:"endgamePieces" yaml:"endgamePieces"`
+}
+
+func (p *PieceSelectionOption) Validate() error {
+	switch p.Strategy {
+	case "", PieceSelectionSequential, PieceSelectionRarestFirst:
+	default:
+		return errors.Errorf("invalid piece selection strategy %q", p.Strategy)
+	}
+	if p.RandomFirstPieces < 0 {
+		return errors.New("piece selection randomFirstPieces can not be negative")
+	}
+	if p.EndgamePieces < 0 {
+		return errors.New("piece selection endgamePieces can not be negative")
+	}
+	return nil
 }
 
 type TransportOption struct {
@@ -189,14 +249,15 @@ type TransportOption struct {
 type ProxyOption struct {
 	// WARNING: when add more option, please update ProxyOption.unmarshal function
 	ListenOption    `mapstructure:",squash" yaml:",inline"`
-	BasicAuth       *BasicAuth      `mapstructure:"basicAuth" yaml:"basicAuth"`
-	DefaultFilter   string          `mapstructure:"defaultFilter" yaml:"defaultFilter"`
-	MaxConcurrency  int64           `mapstructure:"maxConcurrency" yaml:"maxConcurrency"`
-	RegistryMirror  *RegistryMirror `mapstructure:"registryMirror" yaml:"registryMirror"`
-	WhiteList       []*WhiteList    `mapstructure:"whiteList" yaml:"whiteList"`
-	Proxies         []*Proxy        `mapstructure:"proxies" yaml:"proxies"`
-	HijackHTTPS     *HijackConfig   `mapstructure:"hijackHTTPS" yaml:"hijackHTTPS"`
-	DumpHTTPContent bool            `mapstructure:"dumpHTTPContent" yaml:"dumpHTTPContent"`
+	BasicAuth       *BasicAuth        `mapstructure:"basicAuth" yaml:"basicAuth"`
+	DefaultFilter   string            `mapstructure:"defaultFilter" yaml:"defaultFilter"`
+	MaxConcurrency  int64             `mapstructure:"maxConcurrency" yaml:"maxConcurrency"`
+	RegistryMirror  *RegistryMirror   `mapstructure:"registryMirror" yaml:"registryMirror"`
+	RegistryMirrors []*RegistryMirror `mapstructure:"registryMirrors" yaml:"registryMirrors"`
+	WhiteList       []*WhiteList      `mapstructure:"whiteList" yaml:"whiteList"`
+	Proxies         []*Proxy          `mapstructure:"proxies" yaml:"proxies"`
+	HijackHTTPS     *HijackConfig     `mapstructure:"hijackHTTPS" yaml:"hijackHTTPS"`
+	DumpHTTPContent bool              `mapstructure:"dumpHTTPContent" yaml:"dumpHTTPContent"`
 }
 
 func (p *ProxyOption) UnmarshalJSON(b []byte) error {
@@ -274,14 +335,15 @@ func (p *ProxyOption) UnmarshalYAML(node *yaml.Node) error {
 func (p *ProxyOption) unmarshal(unmarshal func(in []byte, out interface{}) (err error), b []byte) error {
 	pt := struct {
 		ListenOption    `mapstructure:",squash" yaml:",inline"`
-		BasicAuth       *BasicAuth      `mapstructure:"basicAuth" yaml:"basicAuth"`
-		DefaultFilter   string          `mapstructure:"defaultFilter" yaml:"defaultFilter"`
-		MaxConcurrency  int64           `mapstructure:"maxConcurrency" yaml:"maxConcurrency"`
-		RegistryMirror  *RegistryMirror `mapstructure:"registryMirror" yaml:"registryMirror"`
-		WhiteList       []*WhiteList    `mapstructure:"whiteList" yaml:"whiteList"`
-		Proxies         []*Proxy        `mapstructure:"proxies" yaml:"proxies"`
-		HijackHTTPS     *HijackConfig   `mapstructure:"hijackHTTPS" yaml:"hijackHTTPS"`
-		DumpHTTPContent bool            `mapstructure:"dumpHTTPContent" yaml:"dumpHTTPContent"`
+		BasicAuth       *BasicAuth        `mapstructure:"basicAuth" yaml:"basicAuth"`
+		DefaultFilter   string            `mapstructure:"defaultFilter" yaml:"defaultFilter"`
+		MaxConcurrency  int64             `mapstructure:"maxConcurrency" yaml:"maxConcurrency"`
+		RegistryMirror  *RegistryMirror   `mapstructure:"registryMirror" yaml:"registryMirror"`
+		RegistryMirrors []*RegistryMirror `mapstructure:"registryMirrors" yaml:"registryMirrors"`
+		WhiteList       []*WhiteList      `mapstructure:"whiteList" yaml:"whiteList"`
+		Proxies         []*Proxy          `mapstructure:"proxies" yaml:"proxies"`
+		HijackHTTPS     *HijackConfig     `mapstructure:"hijackHTTPS" yaml:"hijackHTTPS"`
+		DumpHTTPContent bool              `mapstructure:"dumpHTTPContent" yaml:"dumpHTTPContent"`
 	}{}
 
 	if err := unmarshal(b, &pt); err != nil {
@@ -290,6 +352,7 @@ func (p *ProxyOption) unmarshal(unmarshal func(in []byte, out interface{}) (err
 
 	p.ListenOption = pt.ListenOption
 	p.RegistryMirror = pt.RegistryMirror
+	p.RegistryMirrors = pt.RegistryMirrors
 	p.Proxies = pt.Proxies
 	p.HijackHTTPS = pt.HijackHTTPS
 	p.WhiteList = pt.WhiteList
@@ -304,6 +367,8 @@ func (p *ProxyOption) unmarshal(unmarshal func(in []byte, out interface{}) (err
 type UploadOption struct {
 	ListenOption `yaml:",inline" mapstructure:",squash"`
 	RateLimit    clientutil.RateLimit `mapstructure:"rateLimit" yaml:"rateLimit"`
+	// ConcurrentLimit is the max concurrent uploads, 0 means using the load limit of scheduler cluster
+	ConcurrentLimit int `mapstructure:"concurrentLimit" yaml:"concurrentLimit"`
 }
 
 type ListenOption struct {
@@ -436,6 +501,8 @@ type StorageOption struct {
 	// Multiplex indicates reusing underlying storage for same task id
 	Multiplex     bool          `mapstructure:"multiplex" yaml:"multiplex"`
 	StoreStrategy StoreStrategy `mapstructure:"strategy" yaml:"strategy"`
+	// Encryption indicates at-rest encryption of task data files
+	Encryption encryption.Config `mapstructure:"encryption" yaml:"encryption"`
 }
 
 type StoreStrategy string
@@ -509,9 +576,45 @@ func (t *TLSConfig) UnmarshalJSON(b []byte) error {
 	return nil
 }
 
+// validateRegistryMirrors checks the registry mirrors and fills the default remote url.
+func (p *ProxyOption) validateRegistryMirrors() error {
+	hosts := map[string]struct{}{}
+	for i, mirror := range p.RegistryMirrors {
+		if mirror == nil || mirror.Host == "" {
+			return errors.Errorf("registry mirror %d: host is not specified", i)
+		}
+		if _, ok := hosts[mirror.Host]; ok {
+			return errors.Errorf("registry mirror %d: duplicated host %s", i, mirror.Host)
+		}
+		hosts[mirror.Host] = struct{}{}
+
+		if mirror.Remote == nil || mirror.Remote.URL == nil {
+			remote := mirror.Host
+			if remote == DockerRegistryHost {
+				remote = DockerRegistryRemoteHost
+			}
+			mirror.Remote = &URL{&url.URL{Scheme: "https", Host: remote}}
+		}
+	}
+	return nil
+}
+
+// DockerRegistryHost is the registry host of docker hub in image references,
+// DockerRegistryRemoteHost is the host serving its registry api.
+const (
+	DockerRegistryHost       = "docker.io"
+	DockerRegistryRemoteHost = "registry-1.docker.io"
+)
+
 // RegistryMirror configures the mirror of the official docker registry
 type RegistryMirror struct {
-	// Remote url for the registry mirror, default is https://index.docker.io
+	// Host is the upstream registry host which the mirror serves, like docker.io or ghcr.io,
+	// it is only used in RegistryMirrors and matched with the "ns" query of containerd
+	// or the host of header "X-Dragonfly-Registry".
+	Host string `yaml:"host" mapstructure:"host"`
+
+	// Remote url for the registry mirror, default is https://index.docker.io,
+	// in RegistryMirrors, default is https://<Host>
 	Remote *URL `yaml:"url" mapstructure:"url"`
 
 	// DynamicRemote indicates using header "X-Dragonfly-Registry" for remote instead of Remote
@@ -526,6 +629,10 @@ type RegistryMirror struct {
 
 	// Request the remote registry directly.
 	Direct bool `yaml:"direct" mapstructure:"direct"`
+
+	// Credentials are used to authenticate with the remote registry, including requesting bearer tokens,
+	// when the request carries no authorization
+	Credentials *BasicAuth `yaml:"credentials" mapstructure:"credentials"`
 }
 
 // TLSConfig returns the tls.Config used to communicate with the mirror.
@@ -31,96 +31,103 @@ import (
 	"d7y.io/dragonfly/v2/pkg/util/net/iputils"
 )
 
-var peerHostConfig = DaemonOption{
-	AliveTime:   clientutil.Duration{Duration: DefaultDaemonAliveTime},
-	GCInterval:  clientutil.Duration{Duration: DefaultGCInterval},
-	KeepStorage: false,
-	Scheduler: SchedulerOption{
-		Manager: ManagerOption{
-			Enable:          false,
-			RefreshInterval: 5 * time.Minute,
-		},
-		NetAddrs: []dfnet.NetAddr{
-			{
-				Type: dfnet.TCP,
-				Addr: "127.0.0.1:8002",
+func peerHostConfig() DaemonOption {
+	return DaemonOption{
+		AliveTime:   clientutil.Duration{Duration: DefaultDaemonAliveTime},
+		GCInterval:  clientutil.Duration{Duration: DefaultGCInterval},
+		KeepStorage: false,
+		Scheduler: SchedulerOption{
+			Manager: ManagerOption{
+				Enable:          false,
+				RefreshInterval: 5 * time.Minute,
 			},
+			NetAddrs: []dfnet.NetAddr{
+				{
+					Type: dfnet.TCP,
+					Addr: "127.0.0.1:8002",
+				},
+			},
+			ScheduleTimeout: clientutil.Duration{Duration: DefaultScheduleTimeout},
 		},
-		ScheduleTimeout: clientutil.Duration{Duration: DefaultScheduleTimeout},
-	},
-	Host: HostOption{
-		Hostname:       hostutils.Hostname,
-		ListenIP:       net.IPv4zero.String(),
-		AdvertiseIP:    iputils.IPv4,
-		SecurityDomain: "",
-		Location:       "",
-		IDC:            "",
-		NetTopology:    "",
-	},
-	Download: DownloadOption{
-		CalculateDigest:      true,
-		PieceDownloadTimeout: 30 * time.Second,
-		GetPiecesMaxRetry:    100,
-		TotalRateLimit: clientutil.RateLimit{
-			Limit: rate.Limit(DefaultTotalDownloadLimit),
-		},
-		PerPeerRateLimit: clientutil.RateLimit{
-			Limit: rate.Limit(DefaultPerPeerDownloadLimit),
+		Host: HostOption{
+			Hostname:       hostutils.Hostname,
+			ListenIP:       net.IPv4zero.String(),
+			AdvertiseIP:    iputils.IPv4,
+			SecurityDomain: "",
+			Location:       "",
+			IDC:            "",
+			NetTopology:    "",
 		},
-		DownloadGRPC: ListenOption{
-			Security: SecurityOption{
-				Insecure: true,
+		Download: DownloadOption{
+			CalculateDigest:      true,
+			PieceDownloadTimeout: 30 * time.Second,
+			GetPiecesMaxRetry:    100,
+			PieceTransport:       PieceTransportHTTP,
+			PieceSelection: PieceSelectionOption{
+				Strategy:          PieceSelectionSequential,
+				RandomFirstPieces: 4,
 			},
-			UnixListen: &UnixListenOption{
-				Socket: "/tmp/dfdaemon.sock",
+			TotalRateLimit: clientutil.RateLimit{
+				Limit: rate.Limit(DefaultTotalDownloadLimit),
 			},
-		},
-		PeerGRPC: ListenOption{
-			Security: SecurityOption{
-				Insecure: true,
+			PerPeerRateLimit: clientutil.RateLimit{
+				Limit: rate.Limit(DefaultPerPeerDownloadLimit),
 			},
-			TCPListen: &TCPListenOption{
-				Listen: net.IPv4zero.String(),
-				PortRange: TCPListenPortRange{
-					Start: 65000,
-					End:   65535,
+			DownloadGRPC: ListenOption{
+				Security: SecurityOption{
+					Insecure: true,
+				},
+				UnixListen: &UnixListenOption{
+					Socket: "/tmp/dfdaemon.sock",
+				},
+			},
+			PeerGRPC: ListenOption{
+				Security: SecurityOption{
+					Insecure: true,
+				},
+				TCPListen: &TCPListenOption{
+					Listen: net.IPv4zero.String(),
+					PortRange: TCPListenPortRange{
+						Start: 65000,
+						End:   65535,
+					},
 				},
 			},
 		},
-	},
-	Upload: UploadOption{
-		RateLimit: clientutil.RateLimit{
-			Limit: rate.Limit(DefaultUploadLimit),
-		},
-		ListenOption: ListenOption{
-			Security: SecurityOption{
-				Insecure: true,
+		Upload: UploadOption{
+			RateLimit: clientutil.RateLimit{
+				Limit: rate.Limit(DefaultUploadLimit),
 			},
-			TCPListen: &TCPListenOption{
-				Listen: net.IPv4zero.String(),
-				PortRange: TCPListenPortRange{
-					Start: 65002,
-					End:   65535,
+			ListenOption: ListenOption{
+				Security: SecurityOption{
+					Insecure: true,
+				},
+				TCPListen: &TCPListenOption{
+					Listen: net.IPv4zero.String(),
+					PortRange: TCPListenPortRange{
+						Start: 65002,
+						End:   65535,
+					},
 				},
 			},
 		},
-	},
-	Proxy: &ProxyOption{
-		ListenOption: ListenOption{
-			Security: SecurityOption{
-				Insecure: true,
-			},
-			TCPListen: &TCPListenOption{
-				Listen:    net.IPv4zero.String(),
-				PortRange: TCPListenPortRange{},
+		Proxy: &ProxyOption{
+			ListenOption: ListenOption{
+				Security: SecurityOption{
+					Insecure: true,
+				},
+				TCPListen: &TCPListenOption{
+					Listen:    net.IPv4zero.String(),
+					PortRange: TCPListenPortRange{},
+				},
 			},
 		},
-	},
-	Storage: StorageOption{
-		TaskExpireTime: clientutil.Duration{
-			Duration: DefaultTaskExpireTime,
+		Storage: StorageOption{
+			TaskExpireTime: clientutil.Duration{
+				Duration: DefaultTaskExpireTime,
+			},
+			StoreStrategy: AdvanceLocalTaskStoreStrategy,
+			Multiplex:     false,
 		},
-		StoreStrategy: AdvanceLocalTaskStoreStrategy,
-		Multiplex:     false,
-	},
+	}
 }
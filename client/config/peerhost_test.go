@@ -27,6 +27,7 @@ import (
 
 	"d7y.io/dragonfly/v2/client/clientutil"
 	"d7y.io/dragonfly/v2/internal/dfnet"
+	"d7y.io/dragonfly/v2/pkg/encryption"
 	"d7y.io/dragonfly/v2/pkg/unit"
 )
 
@@ -320,6 +321,11 @@ func TestPeerHostOption_Load(t *testing.T) {
 				Duration: 180000000000,
 			},
 			StoreStrategy: StoreStrategy("io.d7y.storage.v2.simple"),
+			Encryption: encryption.Config{
+				Enable:    true,
+				KeySource: encryption.KeySourceFile,
+				KeyFile:   "/etc/dragonfly/storage.key",
+			},
 		},
 		Proxy: &ProxyOption{
 			ListenOption: ListenOption{
@@ -347,6 +353,22 @@ func TestPeerHostOption_Load(t *testing.T) {
 				Insecure: true,
 				Direct:   false,
 			},
+			RegistryMirrors: []*RegistryMirror{
+				{
+					Host: "ghcr.io",
+					Remote: &URL{
+						&url.URL{
+							Host:   "ghcr.io",
+							Scheme: "https",
+						},
+					},
+					Direct: true,
+					Credentials: &BasicAuth{
+						Username: "user",
+						Password: "pass",
+					},
+				},
+			},
 			Proxies: []*Proxy{
 				{
 					Regx:     proxyExp,
@@ -375,3 +397,35 @@ func TestPeerHostOption_Load(t *testing.T) {
 
 	assert.EqualValues(peerHostOption, peerHostOptionYAML)
 }
+
+func TestProxyOption_ValidateRegistryMirrors(t *testing.T) {
+	assert := testifyassert.New(t)
+
+	p := &ProxyOption{
+		RegistryMirrors: []*RegistryMirror{
+			{Host: DockerRegistryHost},
+			{Host: "ghcr.io"},
+			{Host: "quay.io", Remote: &URL{&url.URL{Scheme: "http", Host: "127.0.0.1:5000"}}},
+		},
+	}
+	assert.Nil(p.validateRegistryMirrors())
+	assert.Equal("https://registry-1.docker.io", p.RegistryMirrors[0].Remote.String())
+	assert.Equal("https://ghcr.io", p.RegistryMirrors[1].Remote.String())
+	assert.Equal("http://127.0.0.1:5000", p.RegistryMirrors[2].Remote.String())
+
+	p = &ProxyOption{RegistryMirrors: []*RegistryMirror{{Remote: p.RegistryMirrors[0].Remote}}}
+	assert.NotNil(p.validateRegistryMirrors())
+
+	p = &ProxyOption{RegistryMirrors: []*RegistryMirror{{Host: "ghcr.io"}, {Host: "ghcr.io"}}}
+	assert.NotNil(p.validateRegistryMirrors())
+}
+
+func TestPieceSelectionOption_Validate(t *testing.T) {
+	assert := testifyassert.New(t)
+
+	assert.Nil((&PieceSelectionOption{}).Validate())
+	assert.Nil((&PieceSelectionOption{Strategy: PieceSelectionRarestFirst, RandomFirstPieces: 4, EndgamePieces: 8}).Validate())
+	assert.NotNil((&PieceSelectionOption{Strategy: "random"}).Validate())
+	assert.NotNil((&PieceSelectionOption{RandomFirstPieces: -1}).Validate())
+	assert.NotNil((&PieceSelectionOption{EndgamePieces: -1}).Validate())
+}
@@ -20,6 +20,7 @@ import (
 	"context"
 	"crypto/tls"
 	"crypto/x509"
+	"encoding/json"
 	"fmt"
 	"net"
 	"net/http"
@@ -46,7 +47,9 @@ import (
 	"d7y.io/dragonfly/v2/client/daemon/upload"
 	logger "d7y.io/dragonfly/v2/internal/dflog"
 	"d7y.io/dragonfly/v2/internal/dfnet"
+	"d7y.io/dragonfly/v2/manager/types"
 	"d7y.io/dragonfly/v2/pkg/dfpath"
+	"d7y.io/dragonfly/v2/pkg/encryption"
 	"d7y.io/dragonfly/v2/pkg/idgen"
 	"d7y.io/dragonfly/v2/pkg/reachable"
 	"d7y.io/dragonfly/v2/pkg/rpc"
@@ -54,6 +57,7 @@ import (
 	managerclient "d7y.io/dragonfly/v2/pkg/rpc/manager/client"
 	"d7y.io/dragonfly/v2/pkg/rpc/scheduler"
 	schedulerclient "d7y.io/dragonfly/v2/pkg/rpc/scheduler/client"
+	"d7y.io/dragonfly/v2/pkg/signature"
 	"d7y.io/dragonfly/v2/pkg/source"
 )
 
@@ -61,6 +65,9 @@ type Daemon interface {
 	Serve() error
 	Stop()
 
+	// Reload applies the reloadable part of option without restarting daemon
+	Reload(opt *config.DaemonOption) error
+
 	// ExportTaskManager returns the underlay peer.TaskManager for downloading when embed dragonfly in custom binary
 	ExportTaskManager() peer.TaskManager
 	// ExportPeerHost returns the underlay scheduler.PeerHost for scheduling
@@ -88,6 +95,16 @@ type clientDaemon struct {
 	dfpath          dfpath.Dfpath
 	schedulers      []*manager.Scheduler
 	schedulerClient schedulerclient.SchedulerClient
+	pieceVerifier   *signature.Verifier
+	pieceDownloader peer.GRPCPieceDownloader
+	uploadAdmission *upload.Admission
+	downloadLimiter *rate.Limiter
+	uploadLimiter   *rate.Limiter
+
+	reloadLock          sync.Mutex
+	localOption         reloadableOption
+	appliedOption       reloadableOption
+	clusterClientConfig types.SchedulerClusterClientConfig
 }
 
 func New(opt *config.DaemonOption, d dfpath.Dfpath) (Daemon, error) {
@@ -153,22 +170,41 @@ func New(opt *config.DaemonOption, d dfpath.Dfpath) (Daemon, error) {
 			logger.Infof("step 4:leave task %s/%s state ok", request.TaskID, request.PeerID)
 		}
 	}
+	if opt.Storage.Encryption.PluginDir == "" {
+		opt.Storage.Encryption.PluginDir = d.PluginDir()
+	}
+	encryptor, err := encryption.NewFromConfig(opt.Storage.Encryption)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to init storage encryption")
+	}
+	pieceVerifier := signature.NewVerifier()
 	storageManager, err := storage.NewStorageManager(opt.Storage.StoreStrategy, &opt.Storage,
-		gcCallback, storage.WithGCInterval(opt.GCInterval.Duration))
+		gcCallback, storage.WithGCInterval(opt.GCInterval.Duration), storage.WithEncryptor(encryptor),
+		storage.WithPieceVerifier(pieceVerifier))
 	if err != nil {
 		return nil, err
 	}
 
+	pieceDownloader, err := newPieceDownloader(opt)
+	if err != nil {
+		return nil, err
+	}
+	// download limiter is shared by all peer tasks
+	downloadLimiter := rate.NewLimiter(opt.Download.TotalRateLimit.Limit, int(opt.Download.TotalRateLimit.Limit))
 	pieceManager, err := peer.NewPieceManager(storageManager,
 		opt.Download.PieceDownloadTimeout,
-		peer.WithLimiter(rate.NewLimiter(opt.Download.TotalRateLimit.Limit, int(opt.Download.TotalRateLimit.Limit))),
+		peer.WithLimiter(downloadLimiter),
 		peer.WithCalculateDigest(opt.Download.CalculateDigest), peer.WithTransportOption(opt.Download.TransportOption),
+		peer.WithPieceDownloader(pieceDownloader),
 	)
 	if err != nil {
 		return nil, err
 	}
+	uploadAdmission := upload.NewAdmission(opt.Upload.ConcurrentLimit)
+	clientConfig := updateClusterClientConfig(opt, schedulers, pieceVerifier, pieceDownloader, uploadAdmission)
 	peerTaskManager, err := peer.NewPeerTaskManager(host, pieceManager, storageManager, sched, opt.Scheduler,
-		opt.Download.PerPeerRateLimit.Limit, opt.Storage.Multiplex, opt.Download.CalculateDigest, opt.Download.GetPiecesMaxRetry)
+		opt.Download.PerPeerRateLimit.Limit, opt.Storage.Multiplex, opt.Download.Prefetch, opt.Download.PieceSelection, opt.Download.CalculateDigest, opt.Download.GetPiecesMaxRetry,
+		pieceVerifier)
 	if err != nil {
 		return nil, err
 	}
@@ -190,7 +226,10 @@ func New(opt *config.DaemonOption, d dfpath.Dfpath) (Daemon, error) {
 		}
 		peerServerOption = append(peerServerOption, grpc.Creds(tlsCredentials))
 	}
-	rpcManager, err := rpcserver.New(host, peerTaskManager, storageManager, downloadServerOption, peerServerOption)
+	// upload limiter is shared by http and grpc piece transport
+	uploadLimiter := rate.NewLimiter(opt.Upload.RateLimit.Limit, int(opt.Upload.RateLimit.Limit))
+	rpcManager, err := rpcserver.New(host, peerTaskManager, storageManager, downloadServerOption, peerServerOption,
+		rpcserver.WithUploadLimiter(uploadLimiter), rpcserver.WithUploadAdmission(uploadAdmission))
 	if err != nil {
 		return nil, err
 	}
@@ -202,12 +241,12 @@ func New(opt *config.DaemonOption, d dfpath.Dfpath) (Daemon, error) {
 	}
 
 	uploadManager, err := upload.NewUploadManager(storageManager,
-		upload.WithLimiter(rate.NewLimiter(opt.Upload.RateLimit.Limit, int(opt.Upload.RateLimit.Limit))))
+		upload.WithLimiter(uploadLimiter), upload.WithAdmission(uploadAdmission))
 	if err != nil {
 		return nil, err
 	}
 
-	return &clientDaemon{
+	cd := &clientDaemon{
 		once:          &sync.Once{},
 		done:          make(chan bool),
 		schedPeerHost: host,
@@ -223,8 +262,22 @@ func New(opt *config.DaemonOption, d dfpath.Dfpath) (Daemon, error) {
 		dynconfig:       dynconfig,
 		dfpath:          d,
 		schedulers:      schedulers,
+		pieceVerifier:   pieceVerifier,
+		pieceDownloader: pieceDownloader,
+		uploadAdmission: uploadAdmission,
 		schedulerClient: sched,
-	}, nil
+		downloadLimiter: downloadLimiter,
+		uploadLimiter:   uploadLimiter,
+
+		localOption:         newReloadableOption(opt),
+		appliedOption:       newReloadableOption(opt),
+		clusterClientConfig: clientConfig,
+	}
+	// overrides from scheduler cluster are not fatal, keep the local option when they are invalid
+	if err := cd.applyReloadableOption(); err != nil {
+		logger.Errorf("apply scheduler cluster client config failed: %v", err)
+	}
+	return cd, nil
 }
 
 func loadGPRCTLSCredentials(opt config.SecurityOption) (credentials.TransportCredentials, error) {
@@ -261,6 +314,49 @@ func loadGPRCTLSCredentials(opt config.SecurityOption) (credentials.TransportCre
 	return credentials.NewTLS(opt.TLSConfig), nil
 }
 
+func loadGRPCClientTLSCredentials(opt config.SecurityOption) (credentials.TransportCredentials, error) {
+	// Load certificate of the CA who signed server's certificate
+	pemServerCA, err := os.ReadFile(opt.CACert)
+	if err != nil {
+		return nil, err
+	}
+
+	certPool := x509.NewCertPool()
+	if !certPool.AppendCertsFromPEM(pemServerCA) {
+		return nil, fmt.Errorf("failed to add server CA's certificate")
+	}
+
+	// Load client's certificate and private key, peers use the same certificate as server and client
+	clientCert, err := tls.LoadX509KeyPair(opt.Cert, opt.Key)
+	if err != nil {
+		return nil, err
+	}
+
+	return credentials.NewTLS(&tls.Config{
+		Certificates: []tls.Certificate{clientCert},
+		RootCAs:      certPool,
+	}), nil
+}
+
+// newPieceDownloader returns the piece downloader which uses grpc piece transport when it is enabled,
+// grpc piece transport uses the security option of peer grpc for mTLS.
+func newPieceDownloader(opt *config.DaemonOption) (peer.GRPCPieceDownloader, error) {
+	httpDownloader, err := peer.NewPieceDownloader(opt.Download.PieceDownloadTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	dialOption := grpc.WithInsecure()
+	if !opt.Download.PeerGRPC.Security.Insecure {
+		tlsCredentials, err := loadGRPCClientTLSCredentials(opt.Download.PeerGRPC.Security)
+		if err != nil {
+			return nil, err
+		}
+		dialOption = grpc.WithTransportCredentials(tlsCredentials)
+	}
+	return peer.NewGRPCPieceDownloader(httpDownloader, opt.Download.PieceDownloadTimeout, dialOption), nil
+}
+
 func (*clientDaemon) prepareTCPListener(opt config.ListenOption, withTLS bool) (net.Listener, int, error) {
 	if len(opt.TCPListen.Namespace) > 0 {
 		runtime.LockOSThread()
@@ -485,6 +581,9 @@ func (cd *clientDaemon) Serve() error {
 		})
 	}
 
+	// announce reloaded tasks after the upload port is known, so that other peers can download from them
+	go cd.PeerTaskManager.AnnounceTasks(context.Background())
+
 	werr := g.Wait()
 	cd.Stop()
 	return werr
@@ -493,6 +592,7 @@ func (cd *clientDaemon) Serve() error {
 func (cd *clientDaemon) Stop() {
 	cd.once.Do(func() {
 		close(cd.done)
+		cd.leaveHost()
 		cd.GCManager.Stop()
 		cd.RPCManager.Stop()
 		if err := cd.UploadManager.Stop(); err != nil {
@@ -519,6 +619,19 @@ func (cd *clientDaemon) Stop() {
 	})
 }
 
+// leaveHost notifies schedulers that the host is leaving, then children of its peers are rescheduled
+func (cd *clientDaemon) leaveHost() {
+	ctx, cancel := context.WithTimeout(context.Background(), config.DefaultLeaveHostTimeout)
+	defer cancel()
+	if err := cd.schedulerClient.LeaveHost(ctx, &scheduler.LeaveHostRequest{
+		HostUuid: cd.schedPeerHost.Uuid,
+	}); err != nil {
+		logger.Errorf("leave host %s error: %v", cd.schedPeerHost.Uuid, err)
+		return
+	}
+	logger.Infof("leave host %s ok", cd.schedPeerHost.Uuid)
+}
+
 func (cd *clientDaemon) OnNotify(data *config.DynconfigData) {
 	ips := getSchedulerIPs(data.Schedulers)
 	if reflect.DeepEqual(cd.schedulers, data.Schedulers) {
@@ -532,10 +645,54 @@ func (cd *clientDaemon) OnNotify(data *config.DynconfigData) {
 	// Update scheduler client addresses
 	cd.schedulerClient.UpdateState(addrs)
 	cd.schedulers = data.Schedulers
+	clientConfig := updateClusterClientConfig(&cd.Option, data.Schedulers, cd.pieceVerifier, cd.pieceDownloader, cd.uploadAdmission)
+
+	cd.reloadLock.Lock()
+	cd.clusterClientConfig = clientConfig
+	if err := cd.applyReloadableOption(); err != nil {
+		logger.Errorf("apply scheduler cluster client config failed: %v", err)
+	}
+	cd.reloadLock.Unlock()
 
 	logger.Infof("scheduler addresses have been updated: %v", ips)
 }
 
+// updateClusterClientConfig applies the client config of scheduler cluster and returns it,
+// the reloadable part is applied by applyReloadableOption.
+func updateClusterClientConfig(opt *config.DaemonOption, schedulers []*manager.Scheduler,
+	verifier *signature.Verifier, pieceDownloader peer.GRPCPieceDownloader, uploadAdmission *upload.Admission) types.SchedulerClusterClientConfig {
+	var clientConfig types.SchedulerClusterClientConfig
+	for _, scheduler := range schedulers {
+		if scheduler.SchedulerCluster == nil || len(scheduler.SchedulerCluster.ClientConfig) == 0 {
+			continue
+		}
+		if err := json.Unmarshal(scheduler.SchedulerCluster.ClientConfig, &clientConfig); err != nil {
+			logger.Errorf("unmarshal scheduler cluster client config failed: %v", err)
+			return types.SchedulerClusterClientConfig{}
+		}
+		break
+	}
+
+	if err := verifier.SetKey(clientConfig.PieceVerifyKey); err != nil {
+		logger.Errorf("update piece verify key failed: %v", err)
+	} else {
+		logger.Infof("piece digest signature verification enabled: %t", verifier.Enabled())
+	}
+
+	// piece transport of scheduler cluster overrides the local one
+	pieceTransport := opt.Download.PieceTransport
+	if clientConfig.PieceTransport != "" {
+		pieceTransport = clientConfig.PieceTransport
+	}
+	pieceDownloader.SetEnabled(pieceTransport == config.PieceTransportGRPC)
+
+	// upload concurrency is the same with the load limit of host in scheduler when it is not configured
+	if opt.Upload.ConcurrentLimit == 0 {
+		uploadAdmission.SetLimit(int(clientConfig.LoadLimit))
+	}
+	return clientConfig
+}
+
 // getSchedulerIPs get ips by schedulers.
 func getSchedulerIPs(schedulers []*manager.Scheduler) []string {
 	ips := []string{}
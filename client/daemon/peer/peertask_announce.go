@@ -0,0 +1,90 @@
+/*
+ *     Copyright 2020 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package peer
+
+import (
+	"context"
+	"fmt"
+
+	"d7y.io/dragonfly/v2/client/config"
+	"d7y.io/dragonfly/v2/client/daemon/storage"
+	logger "d7y.io/dragonfly/v2/internal/dflog"
+	"d7y.io/dragonfly/v2/pkg/rpc/base"
+	"d7y.io/dragonfly/v2/pkg/rpc/scheduler"
+)
+
+// AnnounceTasks announces all completed tasks in local storage to scheduler,
+// it is called after the daemon restarts, so that reloaded tasks become parents of other peers immediately.
+func (ptm *peerTaskManager) AnnounceTasks(ctx context.Context) {
+	var announced, failed int
+	for _, info := range ptm.storageManager.ListTasks() {
+		if !info.Done {
+			continue
+		}
+		// tasks without url, like tiny tasks, can not be downloaded by other peers with the same task id
+		if info.URL == "" {
+			logger.With("peer", info.PeerID, "task", info.TaskID).Debugf("task has no url, skip announcing")
+			continue
+		}
+		if err := ptm.announceTask(ctx, info); err != nil {
+			failed++
+			logger.With("peer", info.PeerID, "task", info.TaskID).Warnf("announce task to scheduler error: %s", err)
+			continue
+		}
+		announced++
+	}
+	logger.Infof("announce tasks to scheduler done, announced: %d, failed: %d", announced, failed)
+}
+
+// announceTask registers the peer to scheduler as a finished peer of the task with all pieces,
+// then other peers will be scheduled to download pieces from it.
+func (ptm *peerTaskManager) announceTask(ctx context.Context, info *storage.TaskInfo) error {
+	ctx, span := tracer.Start(ctx, config.SpanAnnounceTask)
+	defer span.End()
+	span.SetAttributes(config.AttributeTaskID.String(info.TaskID))
+	span.SetAttributes(config.AttributePeerID.String(info.PeerID))
+
+	if ptm.schedulerOption.ScheduleTimeout.Duration > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, ptm.schedulerOption.ScheduleTimeout.Duration)
+		defer cancel()
+	}
+
+	packet, err := ptm.storageManager.GetPieces(ctx, &base.PieceTaskRequest{
+		TaskId:   info.TaskID,
+		DstPid:   info.PeerID,
+		StartNum: 0,
+		Limit:    uint32(info.TotalPieces),
+	})
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+	packet.DstAddr = fmt.Sprintf("%s:%d", ptm.host.Ip, ptm.host.DownPort)
+
+	err = ptm.schedulerClient.AnnounceTask(ctx, &scheduler.AnnounceTaskRequest{
+		TaskId:      info.TaskID,
+		Url:         info.URL,
+		UrlMeta:     info.URLMeta,
+		PeerHost:    ptm.host,
+		PiecePacket: packet,
+	})
+	if err != nil {
+		span.RecordError(err)
+	}
+	return err
+}
@@ -0,0 +1,152 @@
+/*
+ *     Copyright 2020 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package peer
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	testifyassert "github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+
+	"d7y.io/dragonfly/v2/client/clientutil"
+	"d7y.io/dragonfly/v2/client/config"
+	"d7y.io/dragonfly/v2/client/daemon/storage"
+	"d7y.io/dragonfly/v2/client/daemon/test"
+	mock_scheduler "d7y.io/dragonfly/v2/client/daemon/test/mock/scheduler"
+	logger "d7y.io/dragonfly/v2/internal/dflog"
+	"d7y.io/dragonfly/v2/pkg/idgen"
+	"d7y.io/dragonfly/v2/pkg/rpc/base"
+	"d7y.io/dragonfly/v2/pkg/rpc/scheduler"
+	"d7y.io/dragonfly/v2/pkg/signature"
+)
+
+func TestPeerTaskManager_AnnounceTasks(t *testing.T) {
+	assert := testifyassert.New(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var (
+		lock      sync.Mutex
+		announces []*scheduler.AnnounceTaskRequest
+	)
+	sched := mock_scheduler.NewMockSchedulerClient(ctrl)
+	sched.EXPECT().AnnounceTask(gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(
+		func(ctx context.Context, req *scheduler.AnnounceTaskRequest, opts ...grpc.CallOption) error {
+			lock.Lock()
+			defer lock.Unlock()
+			announces = append(announces, req)
+			return nil
+		})
+
+	tempDir, _ := os.MkdirTemp("", "d7y-test-*")
+	defer os.RemoveAll(tempDir)
+	storageOption := &config.StorageOption{
+		DataPath: tempDir,
+		TaskExpireTime: clientutil.Duration{
+			Duration: time.Hour,
+		},
+	}
+	newPeerTaskManager := func(verifier *signature.Verifier) *peerTaskManager {
+		storageManager, err := storage.NewStorageManager(config.SimpleLocalTaskStoreStrategy, storageOption,
+			func(request storage.CommonTaskRequest) {}, storage.WithPieceVerifier(verifier))
+		assert.Nil(err)
+		return &peerTaskManager{
+			host: &scheduler.PeerHost{
+				Ip:       "127.0.0.1",
+				DownPort: 65002,
+			},
+			runningPeerTasks: sync.Map{},
+			pieceManager: &pieceManager{
+				storageManager: storageManager,
+				computePieceSize: func(int64) uint32 {
+					return 1024
+				},
+			},
+			storageManager:  storageManager,
+			schedulerClient: sched,
+		}
+	}
+
+	// prepare a completed task with url and an unfinished task
+	ptm := newPeerTaskManager(nil)
+	meta := &base.UrlMeta{Tag: "d7y-test"}
+	taskID := idgen.TaskID("d7y:/test-announce", meta)
+	req := &ImportTaskRequest{
+		PeerTaskRequest: scheduler.PeerTaskRequest{
+			Url:      "d7y:/test-announce",
+			UrlMeta:  meta,
+			PeerId:   "peer-0",
+			PeerHost: ptm.host,
+		},
+		Path: test.File,
+	}
+	info, err := ptm.importFile(context.Background(), taskID, req, logger.With("test", t.Name()))
+	assert.Nil(err, "import file")
+	assert.Nil(ptm.storageManager.RegisterTask(context.Background(), storage.RegisterTaskRequest{
+		CommonTaskRequest: storage.CommonTaskRequest{
+			PeerID: "peer-1",
+			TaskID: idgen.TaskID("d7y:/test-running", nil),
+		},
+		ContentLength: 1024,
+		TotalPieces:   1,
+		URL:           "d7y:/test-running",
+	}))
+
+	// tasks are reloaded from disk after restart
+	ptm = newPeerTaskManager(nil)
+	defer ptm.storageManager.CleanUp()
+	ptm.AnnounceTasks(context.Background())
+
+	lock.Lock()
+	defer lock.Unlock()
+	assert.Len(announces, 1, "only completed task is announced")
+	announce := announces[0]
+	assert.Equal(taskID, announce.TaskId)
+	assert.Equal("d7y:/test-announce", announce.Url)
+	assert.Equal("d7y-test", announce.UrlMeta.Tag)
+	assert.Equal(ptm.host, announce.PeerHost)
+	assert.Equal("peer-0", announce.PiecePacket.DstPid)
+	assert.Equal("127.0.0.1:65002", announce.PiecePacket.DstAddr)
+	assert.Equal(info.ContentLength, announce.PiecePacket.ContentLength)
+	assert.Equal(info.TotalPieces, announce.PiecePacket.TotalPiece)
+	assert.Len(announce.PiecePacket.PieceInfos, int(info.TotalPieces))
+	// the root generated when importing is published without verifier
+	assert.NotEmpty(announce.PiecePacket.PieceDigestRoot)
+
+	// the unsigned root is not published when the cluster requires signed roots
+	signer, err := signature.NewSigner(base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{1}, 32)))
+	assert.Nil(err)
+	verifier := signature.NewVerifier()
+	assert.Nil(verifier.SetKey(signer.PublicKey()))
+	verifiedPtm := newPeerTaskManager(verifier)
+	lock.Unlock()
+	verifiedPtm.AnnounceTasks(context.Background())
+	lock.Lock()
+	assert.Len(announces, 2)
+	announce = announces[1]
+	assert.Equal(taskID, announce.TaskId)
+	assert.Empty(announce.PiecePacket.PieceDigestRoot)
+	assert.Empty(announce.PiecePacket.PieceDigestSignature)
+	assert.Len(announce.PiecePacket.PieceInfos, int(info.TotalPieces))
+}
@@ -32,6 +32,7 @@ import (
 	"google.golang.org/grpc/status"
 
 	"d7y.io/dragonfly/v2/client/config"
+	"d7y.io/dragonfly/v2/client/daemon/storage"
 	"d7y.io/dragonfly/v2/internal/dferrors"
 	logger "d7y.io/dragonfly/v2/internal/dflog"
 	"d7y.io/dragonfly/v2/pkg/retry"
@@ -39,6 +40,7 @@ import (
 	dfclient "d7y.io/dragonfly/v2/pkg/rpc/dfdaemon/client"
 	"d7y.io/dragonfly/v2/pkg/rpc/scheduler"
 	schedulerclient "d7y.io/dragonfly/v2/pkg/rpc/scheduler/client"
+	"d7y.io/dragonfly/v2/pkg/signature"
 	"d7y.io/dragonfly/v2/pkg/util/digestutils"
 )
 
@@ -55,6 +57,16 @@ const (
 
 var errPeerPacketChanged = errors.New("peer packet changed")
 
+// errPieceTaskSyncUnavailable is returned when dest peer can not push pieces, pieces will be polled from it
+var errPieceTaskSyncUnavailable = errors.New("piece task sync unavailable")
+
+const (
+	// pieceTaskSyncWaitTimeout is the max duration to wait dest peer pushing new pieces
+	pieceTaskSyncWaitTimeout = 10 * time.Second
+	// pieceTaskSyncCheckInterval is the interval to check peer packet changes when waiting pushed pieces
+	pieceTaskSyncCheckInterval = 200 * time.Millisecond
+)
+
 var _ Task = (*peerTask)(nil)
 
 type peerTask struct {
@@ -91,6 +103,24 @@ type peerTask struct {
 	contentLength   *atomic.Int64
 	completedLength *atomic.Int64
 	usedTraffic     *atomic.Uint64
+	// peerTraffic and cdnTraffic are the traffic of pieces downloaded from normal peers and cdn peers
+	peerTraffic atomic.Uint64
+	cdnTraffic  atomic.Uint64
+
+	// pieceDigestRoot is the merkle root of piece digests, pieces are verified with it
+	pieceDigestRoot string
+	// pieceDigestSignature is the cdn cluster signature of pieceDigestRoot
+	pieceDigestSignature []byte
+	// pieceVerifier verifies pieceDigestSignature, when it is enabled, only signed root is trusted
+	pieceVerifier *signature.Verifier
+	// pieceDigestLock protects pieceDigestRoot and pieceDigestSignature
+	pieceDigestLock sync.RWMutex
+
+	// peerRPCAddrs holds grpc addresses of dst peers, key is peer id
+	peerRPCAddrs sync.Map
+	// pieceTaskSynchronizers holds subscriptions of dst peers, key is peer id,
+	// it is only accessed in pullPiecesFromPeers
+	pieceTaskSynchronizers map[string]*pieceTaskSynchronizer
 
 	//sizeScope   base.SizeScope
 	singlePiece *scheduler.SinglePiece
@@ -128,6 +158,20 @@ type peerTask struct {
 	readyPieces *Bitmap
 	// requestedPieces stands all pieces requested from peers
 	requestedPieces *Bitmap
+	// priorityPieceCh receives the pieces which should be requested before the others, eg: the pieces being read
+	priorityPieceCh chan int32
+	// pieceSize is the size of all pieces except the last one, it is recorded with the first ready piece
+	pieceSize atomic.Uint32
+	// pieceSelector selects the pieces to request, nil means requesting pieces in order
+	pieceSelector pieceSelector
+	// pieceAvailability records the pieces of parents, it is nil when neither rarest-first nor endgame is enabled
+	pieceAvailability *pieceAvailability
+	// endgamePieces is the count of last pieces requested from multiple parents, 0 means endgame is disabled
+	endgamePieces int32
+	// pieceRequests tracks the requests of pieces in endgame mode
+	pieceRequests *pieceRequests
+	// resumedPieces are the validated pieces of the unfinished task when the peer task is resumed
+	resumedPieces []storage.PieceMetadata
 	// lock used by piece result manage, when update readyPieces, lock first
 	lock sync.RWMutex
 	// limiter will be used when enable per peer task rate limit
@@ -189,6 +233,40 @@ func (pt *peerTask) GetPieceMd5Sign() string {
 	return pt.md5
 }
 
+func (pt *peerTask) SetPieceDigestRoot(root string, signature []byte) {
+	pt.pieceDigestLock.Lock()
+	defer pt.pieceDigestLock.Unlock()
+	pt.pieceDigestRoot = root
+	pt.pieceDigestSignature = signature
+}
+
+func (pt *peerTask) GetPieceDigestRoot() (string, []byte) {
+	pt.pieceDigestLock.RLock()
+	defer pt.pieceDigestLock.RUnlock()
+	return pt.pieceDigestRoot, pt.pieceDigestSignature
+}
+
+// acceptPieceDigestRoot checks the piece digest root from parents or scheduler,
+// the first acceptable root is used to verify all pieces of the task.
+// Without piece verifier, any root is acceptable, otherwise only the root with valid signature is acceptable,
+// and an empty root is not acceptable until a signed root is accepted, so pieces are never dispatched unverified.
+func (pt *peerTask) acceptPieceDigestRoot(root string, sig []byte) bool {
+	pt.pieceDigestLock.Lock()
+	defer pt.pieceDigestLock.Unlock()
+	if root == "" {
+		return !pt.pieceVerifier.Enabled() || pt.pieceDigestRoot != ""
+	}
+	if pt.pieceDigestRoot != "" {
+		return !pt.pieceVerifier.Enabled() || root == pt.pieceDigestRoot
+	}
+	if pt.pieceVerifier.Enabled() && !pt.pieceVerifier.Verify(pt.taskID, root, sig) {
+		return false
+	}
+	pt.pieceDigestRoot = root
+	pt.pieceDigestSignature = sig
+	return true
+}
+
 func (pt *peerTask) Context() context.Context {
 	return pt.ctx
 }
@@ -202,8 +280,9 @@ func (pt *peerTask) backSource() {
 }
 
 func (pt *peerTask) pullPieces(cleanUnfinishedFunc func()) {
-	// when there is a single piece, try to download first
-	if pt.singlePiece != nil {
+	// when there is a single piece, try to download first,
+	// the single piece from scheduler has no signed root, so it is skipped when piece verifier is enabled
+	if pt.singlePiece != nil && !pt.pieceVerifier.Enabled() {
 		go pt.pullSinglePiece(cleanUnfinishedFunc)
 	} else {
 		go pt.receivePeerPacket()
@@ -301,6 +380,11 @@ loop:
 			firstPeerSpan.End()
 		}
 
+		if peerPacket.PieceDigestRoot != "" &&
+			!pt.acceptPieceDigestRoot(peerPacket.PieceDigestRoot, peerPacket.PieceDigestSignature) {
+			pt.Warnf("receive peer packet with invalid piece digest signature, ignore the piece digest root")
+		}
+
 		pt.peerPacket.Store(peerPacket)
 		pt.pieceParallelCount.Store(peerPacket.ParallelCount)
 		select {
@@ -363,6 +447,7 @@ func (pt *peerTask) pullSinglePiece(cleanUnfinishedFunc func()) {
 
 	request := &DownloadPieceRequest{
 		TaskID:  pt.GetTaskID(),
+		SrcPid:  pt.GetPeerID(),
 		DstPid:  pt.singlePiece.DstPid,
 		DstAddr: pt.singlePiece.DstAddr,
 		piece:   pt.singlePiece.PieceInfo,
@@ -384,6 +469,7 @@ func (pt *peerTask) pullSinglePiece(cleanUnfinishedFunc func()) {
 
 func (pt *peerTask) pullPiecesFromPeers(cleanUnfinishedFunc func()) {
 	defer func() {
+		pt.closePieceTaskSynchronizers(nil)
 		cleanUnfinishedFunc()
 	}()
 	if ok, backSource := pt.waitFirstPeerPacket(); !ok {
@@ -399,10 +485,19 @@ func (pt *peerTask) pullPiecesFromPeers(cleanUnfinishedFunc func()) {
 		limit          uint32
 		initialized    bool
 		pieceRequestCh chan *DownloadPieceRequest
+		// priorityRequestCh is used for the priority piece, workers process it before others
+		priorityRequestCh chan *DownloadPieceRequest
+		priorityPiece     int32 = -1
+		// pieceDigestRoot is the piece digest root saved in storage
+		pieceDigestRoot string
 		// keep same size with pt.failedPieceCh for avoiding dead-lock
 		pieceBufferSize = uint32(config.DefaultPieceChanSize)
 	)
 	limit = pieceBufferSize
+	// skip the downloaded pieces of the resumed peer task
+	if next := pt.getNextPieceNum(0); next > 0 {
+		num = next
+	}
 loop:
 	for {
 		// 1, check whether catch exit signal or get a failed piece
@@ -431,6 +526,14 @@ loop:
 			pt.Warnf("download piece %d failed, retry", failed)
 			num = failed
 			limit = 1
+		case prior := <-pt.priorityPieceCh:
+			// the other pieces continue from the priority one
+			if !pt.requestedPieces.IsSet(prior) && (pt.totalPiece <= 0 || prior < pt.totalPiece) {
+				pt.Debugf("request priority piece %d", prior)
+				num = prior
+				priorityPiece = prior
+				limit = pieceBufferSize
+			}
 		default:
 		}
 
@@ -455,7 +558,7 @@ loop:
 
 		if !initialized {
 			initialized = true
-			if pieceRequestCh, ok = pt.init(piecePacket, pieceBufferSize); !ok {
+			if pieceRequestCh, priorityRequestCh, ok = pt.init(piecePacket, pieceBufferSize); !ok {
 				break loop
 			}
 		}
@@ -474,6 +577,13 @@ loop:
 			pt.Debugf("update digest: %s", pt.md5)
 		}
 
+		// update piece digest root, pieces from all peers are verified with the accepted one
+		if root, _ := pt.GetPieceDigestRoot(); root != pieceDigestRoot {
+			pieceDigestRoot = root
+			_ = pt.callback.Update(pt)
+			pt.Debugf("update piece digest root: %s", pieceDigestRoot)
+		}
+
 		// update content length
 		if piecePacket.ContentLength > 0 {
 			_ = pt.SetContentLength(piecePacket.ContentLength)
@@ -482,10 +592,11 @@ loop:
 		}
 
 		// 3. dispatch piece request to all workers
-		pt.dispatchPieceRequest(pieceRequestCh, piecePacket)
+		pt.dispatchPieceRequest(pieceRequestCh, priorityRequestCh, priorityPiece, piecePacket)
+		priorityPiece = -1
 
 		// 4. get next piece
-		num = pt.getNextPieceNum(num)
+		num = pt.nextPieceNum(num)
 		if num != -1 {
 			// get next piece success
 			limit = pieceBufferSize
@@ -495,14 +606,14 @@ loop:
 		// just need one piece
 		limit = 1
 		// get failed piece
-		if num, ok = pt.waitFailedPiece(); !ok {
+		if num, ok = pt.waitFailedPiece(priorityRequestCh); !ok {
 			// when ok == false, indicates than need break loop
 			break loop
 		}
 	}
 }
 
-func (pt *peerTask) init(piecePacket *base.PiecePacket, pieceBufferSize uint32) (chan *DownloadPieceRequest, bool) {
+func (pt *peerTask) init(piecePacket *base.PiecePacket, pieceBufferSize uint32) (chan *DownloadPieceRequest, chan *DownloadPieceRequest, bool) {
 	pt.contentLength.Store(piecePacket.ContentLength)
 	if piecePacket.ContentLength > 0 {
 		pt.span.SetAttributes(config.AttributeTaskContentLength.Int64(piecePacket.ContentLength))
@@ -511,14 +622,15 @@ func (pt *peerTask) init(piecePacket *base.PiecePacket, pieceBufferSize uint32)
 		pt.span.RecordError(err)
 		pt.failedReason = err.Error()
 		pt.failedCode = base.Code_ClientError
-		return nil, false
+		return nil, nil, false
 	}
 	pc := pt.peerPacket.Load().(*scheduler.PeerPacket).ParallelCount
 	pieceRequestCh := make(chan *DownloadPieceRequest, pieceBufferSize)
+	priorityRequestCh := make(chan *DownloadPieceRequest, pieceBufferSize)
 	for i := int32(0); i < pc; i++ {
-		go pt.downloadPieceWorker(i, pt, pieceRequestCh)
+		go pt.downloadPieceWorker(i, pt, pieceRequestCh, priorityRequestCh)
 	}
-	return pieceRequestCh, true
+	return pieceRequestCh, priorityRequestCh, true
 }
 
 func (pt *peerTask) waitFirstPeerPacket() (done bool, backSource bool) {
@@ -606,23 +718,29 @@ func (pt *peerTask) waitAvailablePeerPacket() (int32, bool) {
 	return -1, false
 }
 
-func (pt *peerTask) dispatchPieceRequest(pieceRequestCh chan *DownloadPieceRequest, piecePacket *base.PiecePacket) {
+func (pt *peerTask) dispatchPieceRequest(pieceRequestCh, priorityRequestCh chan *DownloadPieceRequest,
+	priorityPiece int32, piecePacket *base.PiecePacket) {
 	pt.Debugf("dispatch piece request, piece count: %d", len(piecePacket.PieceInfos))
+	digestRoot, _ := pt.GetPieceDigestRoot()
+	if pt.pieceSelector != nil {
+		pt.pieceSelector.sort(pt, piecePacket.PieceInfos)
+	}
 	for _, piece := range piecePacket.PieceInfos {
 		pt.Infof("get piece %d from %s/%s, md5: %s, start: %d, size: %d",
 			piece.PieceNum, piecePacket.DstAddr, piecePacket.DstPid, piece.PieceMd5, piece.RangeStart, piece.RangeSize)
 		if !pt.requestedPieces.IsSet(piece.PieceNum) {
 			pt.requestedPieces.Set(piece.PieceNum)
 		}
-		req := &DownloadPieceRequest{
-			TaskID:  pt.GetTaskID(),
-			DstPid:  piecePacket.DstPid,
-			DstAddr: piecePacket.DstAddr,
-			piece:   piece,
-			log:     pt.Log(),
+		if pt.pieceRequests != nil {
+			pt.pieceRequests.dispatch(piece.PieceNum, piecePacket.DstPid)
+		}
+		req := pt.newDownloadPieceRequest(piecePacket.DstPid, piecePacket.DstAddr, piece, digestRoot)
+		requestCh := pieceRequestCh
+		if piece.PieceNum == priorityPiece {
+			requestCh = priorityRequestCh
 		}
 		select {
-		case pieceRequestCh <- req:
+		case requestCh <- req:
 		case <-pt.done:
 			pt.Warnf("peer task done, but still some piece request not process")
 		case <-pt.ctx.Done():
@@ -637,85 +755,138 @@ func (pt *peerTask) dispatchPieceRequest(pieceRequestCh chan *DownloadPieceReque
 	}
 }
 
-func (pt *peerTask) waitFailedPiece() (int32, bool) {
+func (pt *peerTask) newDownloadPieceRequest(dstPid, dstAddr string, piece *base.PieceInfo, digestRoot string) *DownloadPieceRequest {
+	var rpcAddr string
+	if addr, ok := pt.peerRPCAddrs.Load(dstPid); ok {
+		rpcAddr = addr.(string)
+	}
+	return &DownloadPieceRequest{
+		TaskID:     pt.GetTaskID(),
+		SrcPid:     pt.GetPeerID(),
+		DstPid:     dstPid,
+		DstAddr:    dstAddr,
+		DstRPCAddr: rpcAddr,
+		piece:      piece,
+		digestRoot: digestRoot,
+		// a trusted root is only guaranteed by signature, otherwise the proofs are optional
+		proofRequired: pt.pieceVerifier.Enabled(),
+		totalPiece:    pt.totalPiece,
+		log:           pt.Log(),
+	}
+}
+
+// waitFailedPiece waits a failed piece to retry, the last pieces are requested
+// from multiple parents with endgameRequestCh in endgame mode.
+func (pt *peerTask) waitFailedPiece(endgameRequestCh chan *DownloadPieceRequest) (int32, bool) {
 	if pt.isCompleted() {
 		return -1, false
 	}
-	// use no default branch select to wait failed piece or exit
-	select {
-	case <-pt.done:
-		pt.Infof("peer task done, stop to wait failed piece")
-		return -1, false
-	case <-pt.ctx.Done():
-		pt.Debugf("context done due to %s, stop to wait failed piece", pt.ctx.Err())
-		return -1, false
-	case failed := <-pt.failedPieceCh:
-		pt.Warnf("download piece/%d failed, retry", failed)
-		return failed, true
+	var endgameCheck <-chan time.Time
+	if pt.pieceRequests != nil {
+		pt.endgame(endgameRequestCh)
+		ticker := time.NewTicker(endgameCheckInterval)
+		defer ticker.Stop()
+		endgameCheck = ticker.C
+	}
+	for {
+		// use no default branch select to wait failed piece or exit
+		select {
+		case <-pt.done:
+			pt.Infof("peer task done, stop to wait failed piece")
+			return -1, false
+		case <-pt.ctx.Done():
+			pt.Debugf("context done due to %s, stop to wait failed piece", pt.ctx.Err())
+			return -1, false
+		case failed := <-pt.failedPieceCh:
+			pt.Warnf("download piece/%d failed, retry", failed)
+			return failed, true
+		case <-endgameCheck:
+			pt.endgame(endgameRequestCh)
+		}
 	}
 }
 
-func (pt *peerTask) downloadPieceWorker(id int32, pti Task, requests chan *DownloadPieceRequest) {
+func (pt *peerTask) downloadPieceWorker(id int32, pti Task, requests, priorityRequests chan *DownloadPieceRequest) {
 	for {
+		var request *DownloadPieceRequest
+		// the priority pieces are downloaded before the others
 		select {
-		case request := <-requests:
-			pt.lock.RLock()
-			if pt.readyPieces.IsSet(request.piece.PieceNum) {
-				pt.lock.RUnlock()
-				pt.Log().Debugf("piece %d is already downloaded, skip", request.piece.PieceNum)
-				continue
+		case request = <-priorityRequests:
+		default:
+			select {
+			case request = <-priorityRequests:
+			case request = <-requests:
+			case <-pt.done:
+				pt.Debugf("peer task done, peer download worker #%d exit", id)
+				return
+			case <-pt.ctx.Done():
+				pt.Debugf("peer task context done, peer download worker #%d exit", id)
+				return
 			}
+		}
+		pt.lock.RLock()
+		if pt.readyPieces.IsSet(request.piece.PieceNum) {
 			pt.lock.RUnlock()
-			ctx, span := tracer.Start(pt.ctx, fmt.Sprintf(config.SpanDownloadPiece, request.piece.PieceNum))
-			span.SetAttributes(config.AttributePiece.Int(int(request.piece.PieceNum)))
-			span.SetAttributes(config.AttributePieceWorker.Int(int(id)))
-			if pt.limiter != nil {
-				_, waitSpan := tracer.Start(ctx, config.SpanWaitPieceLimit)
-				if err := pt.limiter.WaitN(pt.ctx, int(request.piece.RangeSize)); err != nil {
-					pt.Errorf("request limiter error: %s", err)
-					waitSpan.RecordError(err)
-					waitSpan.End()
-					if err := pti.ReportPieceResult(&pieceTaskResult{
-						piece: request.piece,
-						pieceResult: &scheduler.PieceResult{
-							TaskId:        pt.GetTaskID(),
-							SrcPid:        pt.GetPeerID(),
-							DstPid:        request.DstPid,
-							PieceInfo:     request.piece,
-							Success:       false,
-							Code:          base.Code_ClientRequestLimitFail,
-							HostLoad:      nil,
-							FinishedCount: 0, // update by peer task
-						},
-						err: err,
-					}); err != nil {
-						pt.Errorf("report piece result failed %s", err)
-					}
-
-					pt.failedReason = err.Error()
-					pt.failedCode = base.Code_ClientRequestLimitFail
-					pt.cancel()
-					span.SetAttributes(config.AttributePieceSuccess.Bool(false))
-					span.End()
-					return
-				}
+			pt.Log().Debugf("piece %d is already downloaded, skip", request.piece.PieceNum)
+			continue
+		}
+		pt.lock.RUnlock()
+		ctx, span := tracer.Start(pt.ctx, fmt.Sprintf(config.SpanDownloadPiece, request.piece.PieceNum))
+		span.SetAttributes(config.AttributePiece.Int(int(request.piece.PieceNum)))
+		span.SetAttributes(config.AttributePieceWorker.Int(int(id)))
+		if pt.limiter != nil {
+			_, waitSpan := tracer.Start(ctx, config.SpanWaitPieceLimit)
+			if err := pt.limiter.WaitN(pt.ctx, int(request.piece.RangeSize)); err != nil {
+				pt.Errorf("request limiter error: %s", err)
+				waitSpan.RecordError(err)
 				waitSpan.End()
-			}
-			pt.Debugf("peer download worker #%d receive piece task, "+
-				"dest peer id: %s, piece num: %d, range start: %d, range size: %d",
-				id, request.DstPid, request.piece.PieceNum, request.piece.RangeStart, request.piece.RangeSize)
-			success := pt.pieceManager.DownloadPiece(ctx, pti, request)
+				if err := pti.ReportPieceResult(&pieceTaskResult{
+					piece: request.piece,
+					pieceResult: &scheduler.PieceResult{
+						TaskId:        pt.GetTaskID(),
+						SrcPid:        pt.GetPeerID(),
+						DstPid:        request.DstPid,
+						PieceInfo:     request.piece,
+						Success:       false,
+						Code:          base.Code_ClientRequestLimitFail,
+						HostLoad:      nil,
+						FinishedCount: 0, // update by peer task
+					},
+					err: err,
+				}); err != nil {
+					pt.Errorf("report piece result failed %s", err)
+				}
 
-			span.SetAttributes(config.AttributePieceSuccess.Bool(success))
-			span.End()
-		case <-pt.done:
-			pt.Debugf("peer task done, peer download worker #%d exit", id)
-			return
-		case <-pt.ctx.Done():
-			pt.Debugf("peer task context done, peer download worker #%d exit", id)
-			return
+				pt.failedReason = err.Error()
+				pt.failedCode = base.Code_ClientRequestLimitFail
+				pt.cancel()
+				span.SetAttributes(config.AttributePieceSuccess.Bool(false))
+				span.End()
+				return
+			}
+			waitSpan.End()
 		}
+		pt.Debugf("peer download worker #%d receive piece task, "+
+			"dest peer id: %s, piece num: %d, range start: %d, range size: %d",
+			id, request.DstPid, request.piece.PieceNum, request.piece.RangeStart, request.piece.RangeSize)
+		success := pt.downloadPiece(ctx, pti, request)
+
+		span.SetAttributes(config.AttributePieceSuccess.Bool(success))
+		span.End()
+	}
+}
+
+// recordPieceSize records the piece size with a ready piece, all pieces except the last one have the same size.
+func (pt *peerTask) recordPieceSize(piece *base.PieceInfo) {
+	if pt.pieceSize.Load() > 0 {
+		return
+	}
+	if piece.PieceNum > 0 {
+		pt.pieceSize.Store(uint32(piece.RangeStart / uint64(piece.PieceNum)))
+		return
 	}
+	// when the first piece is also the last one, there is only one piece
+	pt.pieceSize.Store(piece.RangeSize)
 }
 
 func (pt *peerTask) isCompleted() bool {
@@ -729,6 +900,10 @@ prepare:
 	retryCount++
 	peerPacket := pt.peerPacket.Load().(*scheduler.PeerPacket)
 	pt.pieceParallelCount.Store(peerPacket.ParallelCount)
+	pt.closePieceTaskSynchronizers(peerPacket)
+	if pt.pieceAvailability != nil {
+		pt.pieceAvailability.retain(peerPacket)
+	}
 	request.DstPid = peerPacket.MainPeer.PeerId
 	p, err = pt.preparePieceTasksByPeer(peerPacket, peerPacket.MainPeer, request)
 	if err == nil {
@@ -775,6 +950,8 @@ retry:
 	p, err := pt.getPieceTasks(span, curPeerPacket, peer, request)
 	if err == nil {
 		pt.Infof("got piece task from peer %s ok, pieces length: %d", peer.PeerId, len(p.PieceInfos))
+		pt.peerRPCAddrs.Store(peer.PeerId, fmt.Sprintf("%s:%d", peer.Ip, peer.RpcPort))
+		pt.recordAvailablePieces(peer.PeerId, p)
 		span.SetAttributes(config.AttributeGetPieceCount.Int(len(p.PieceInfos)))
 		return p, nil
 	}
@@ -823,7 +1000,122 @@ retry:
 	return nil, err
 }
 
+// getPieceTasks gets pieces from dest peer, pieces pushed by dest peer are preferred,
+// when dest peer does not support SyncPieceTasks, pieces are polled with GetPieceTasks.
 func (pt *peerTask) getPieceTasks(span trace.Span, curPeerPacket *scheduler.PeerPacket, peer *scheduler.PeerPacket_DestPeer, request *base.PieceTaskRequest) (*base.PiecePacket, error) {
+	p, err := pt.syncPieceTasks(span, curPeerPacket, peer, request)
+	if err != errPieceTaskSyncUnavailable {
+		return p, err
+	}
+	return pt.pollPieceTasks(span, curPeerPacket, peer, request)
+}
+
+// syncPieceTasks waits pieces pushed by dest peer, it returns errPieceTaskSyncUnavailable when dest peer can not push pieces
+func (pt *peerTask) syncPieceTasks(span trace.Span, curPeerPacket *scheduler.PeerPacket, peer *scheduler.PeerPacket_DestPeer, request *base.PieceTaskRequest) (*base.PiecePacket, error) {
+	if pt.pieceTaskSynchronizers == nil {
+		pt.pieceTaskSynchronizers = map[string]*pieceTaskSynchronizer{}
+	}
+	s, ok := pt.pieceTaskSynchronizers[peer.PeerId]
+	if !ok {
+		pt.Debugf("subscribe piece tasks from peer %s", peer.PeerId)
+		s = newPieceTaskSynchronizer(pt.ctx, pt.Log(), peer, &base.PieceTaskRequest{
+			TaskId:   pt.taskID,
+			SrcPid:   pt.peerID,
+			DstPid:   peer.PeerId,
+			StartNum: 0,
+			Limit:    pieceTaskSyncLimit,
+		}, func(packet *base.PiecePacket) {
+			pt.recordAvailablePieces(peer.PeerId, packet)
+		})
+		pt.pieceTaskSynchronizers[peer.PeerId] = s
+	}
+
+	timeout := time.NewTimer(pieceTaskSyncWaitTimeout)
+	defer timeout.Stop()
+	for {
+		piecePacket, finished, changed, err := s.pieceTasks(request)
+		if err != nil {
+			// dest peer returns df error, subscribe again in next time
+			if _, ok := err.(*dferrors.DfError); ok {
+				s.close()
+				delete(pt.pieceTaskSynchronizers, peer.PeerId)
+				span.RecordError(err)
+				return nil, err
+			}
+			// dest peer does not support SyncPieceTasks or the broken stream can not be subscribed again
+			return nil, errPieceTaskSyncUnavailable
+		}
+		if piecePacket != nil {
+			// piece metadata which does not match the signed piece digest root is poisoned, report the peer
+			if !pt.acceptPieceDigestRoot(piecePacket.PieceDigestRoot, piecePacket.PieceDigestSignature) {
+				span.AddEvent("invalid piece digest root")
+				return nil, dferrors.Newf(base.Code_ClientPieceVerifyFail,
+					"peer %s returns untrusted piece digest root %q", peer.PeerId, piecePacket.PieceDigestRoot)
+			}
+			// got any pieces or need update metadata
+			if len(piecePacket.PieceInfos) > 0 ||
+				piecePacket.ContentLength > pt.contentLength.Load() || piecePacket.TotalPiece > pt.totalPiece {
+				return piecePacket, nil
+			}
+			// invalid request num
+			if piecePacket.TotalPiece > -1 && uint32(piecePacket.TotalPiece) <= request.StartNum {
+				pt.Warnf("invalid start num: %d, total piece: %d", request.StartNum, piecePacket.TotalPiece)
+				return piecePacket, nil
+			}
+		}
+		if finished {
+			if piecePacket == nil {
+				return nil, errPieceTaskSyncUnavailable
+			}
+			// dest peer pushed all pieces, there is no more pieces to wait
+			return piecePacket, nil
+		}
+
+		lastPeerPacket := pt.peerPacket.Load().(*scheduler.PeerPacket)
+		if curPeerPacket.MainPeer.PeerId != lastPeerPacket.MainPeer.PeerId {
+			pt.Warnf("wait pushed pieces but peer packet changed, switch to new peer packet, current destPeer %s, new destPeer %s",
+				curPeerPacket.MainPeer.PeerId, lastPeerPacket.MainPeer.PeerId)
+			return nil, errPeerPacketChanged
+		}
+		select {
+		case <-changed:
+		case <-time.After(pieceTaskSyncCheckInterval):
+		case <-timeout.C:
+			span.AddEvent("wait pushed pieces timeout")
+			pt.Infof("peer %s does not push pieces in %s", peer.PeerId, pieceTaskSyncWaitTimeout)
+			return nil, dferrors.ErrEmptyValue
+		case <-pt.ctx.Done():
+			return nil, pt.ctx.Err()
+		}
+	}
+}
+
+// closePieceTaskSynchronizers closes subscriptions of dest peers which are not in peerPacket,
+// all subscriptions are closed when peerPacket is nil.
+func (pt *peerTask) closePieceTaskSynchronizers(peerPacket *scheduler.PeerPacket) {
+	for peerID, s := range pt.pieceTaskSynchronizers {
+		if peerPacket != nil && containsDestPeer(peerPacket, peerID) {
+			continue
+		}
+		s.close()
+		delete(pt.pieceTaskSynchronizers, peerID)
+	}
+}
+
+func containsDestPeer(peerPacket *scheduler.PeerPacket, peerID string) bool {
+	if peerPacket.MainPeer != nil && peerPacket.MainPeer.PeerId == peerID {
+		return true
+	}
+	for _, peer := range peerPacket.StealPeers {
+		if peer.PeerId == peerID {
+			return true
+		}
+	}
+	return false
+}
+
+// pollPieceTasks polls pieces from dest peer with GetPieceTasks until any pieces are ready
+func (pt *peerTask) pollPieceTasks(span trace.Span, curPeerPacket *scheduler.PeerPacket, peer *scheduler.PeerPacket_DestPeer, request *base.PieceTaskRequest) (*base.PiecePacket, error) {
 	var (
 		peerPacketChanged bool
 		count             int
@@ -856,6 +1148,12 @@ func (pt *peerTask) getPieceTasks(span trace.Span, curPeerPacket *scheduler.Peer
 			}
 			return nil, true, getError
 		}
+		// piece metadata which does not match the signed piece digest root is poisoned, report the peer
+		if !pt.acceptPieceDigestRoot(piecePacket.PieceDigestRoot, piecePacket.PieceDigestSignature) {
+			span.AddEvent("invalid piece digest root")
+			return nil, true, dferrors.Newf(base.Code_ClientPieceVerifyFail,
+				"peer %s returns untrusted piece digest root %q", peer.PeerId, piecePacket.PieceDigestRoot)
+		}
 		// got any pieces
 		if len(piecePacket.PieceInfos) > 0 {
 			return piecePacket, false, nil
@@ -41,6 +41,8 @@ type FilePeerTaskRequest struct {
 	DisableBackSource bool
 	Pattern           string
 	Callsystem        string
+	// Resume indicates to continue the unfinished local task of the same task id
+	Resume bool
 }
 
 // FilePeerTask represents a peer task to download a file
@@ -78,6 +80,8 @@ type FilePeerTaskProgress struct {
 	CompletedLength int64
 	PeerTaskDone    bool
 	DoneCallback    func()
+	// Stat is the statistics of peer task when the progress is sent
+	Stat *PeerTaskStat
 }
 
 func newFilePeerTask(ctx context.Context,
@@ -217,6 +221,13 @@ func newFilePeerTask(ctx context.Context,
 func (pt *filePeerTask) Start(ctx context.Context) (chan *FilePeerTaskProgress, error) {
 	pt.ctx, pt.cancel = context.WithCancel(ctx)
 
+	// all pieces of the resumed peer task are already downloaded
+	if !pt.needBackSource && len(pt.resumedPieces) > 0 && pt.isCompleted() {
+		pt.Infof("all pieces of resumed peer task are downloaded")
+		go pt.finish()
+		return pt.progressCh, nil
+	}
+
 	if pt.needBackSource {
 		pt.contentLength.Store(-1)
 		_ = pt.callback.Init(pt)
@@ -237,6 +248,10 @@ func (pt *filePeerTask) ReportPieceResult(result *pieceTaskResult) error {
 
 	// retry failed piece
 	if !result.pieceResult.Success {
+		if pt.isPieceReady(result.piece.PieceNum) {
+			pt.Debugf("piece %d is downloaded from other parent, skip failed result", result.piece.PieceNum)
+			return nil
+		}
 		result.pieceResult.FinishedCount = pt.readyPieces.Settled()
 		_ = pt.peerPacketStream.Send(result.pieceResult)
 		if result.notRetry {
@@ -264,6 +279,7 @@ func (pt *filePeerTask) ReportPieceResult(result *pieceTaskResult) error {
 	// mark piece processed
 	pt.readyPieces.Set(result.pieceResult.PieceInfo.PieceNum)
 	pt.completedLength.Add(int64(result.piece.RangeSize))
+	pt.addPieceTraffic(result.pieceResult, uint64(result.piece.RangeSize))
 	pt.lock.Unlock()
 
 	result.pieceResult.FinishedCount = pt.readyPieces.Settled()
@@ -280,6 +296,7 @@ func (pt *filePeerTask) ReportPieceResult(result *pieceTaskResult) error {
 		ContentLength:   pt.contentLength.Load(),
 		CompletedLength: pt.completedLength.Load(),
 		PeerTaskDone:    false,
+		Stat:            pt.stat(),
 	}
 
 	select {
@@ -341,6 +358,7 @@ func (pt *filePeerTask) finish() error {
 			ContentLength:   pt.contentLength.Load(),
 			CompletedLength: pt.completedLength.Load(),
 			PeerTaskDone:    true,
+			Stat:            pt.stat(),
 			DoneCallback: func() {
 				progressDone = true
 				close(pt.progressStopCh)
@@ -403,6 +421,7 @@ func (pt *filePeerTask) cleanUnfinished() {
 			ContentLength:   pt.contentLength.Load(),
 			CompletedLength: pt.completedLength.Load(),
 			PeerTaskDone:    true,
+			Stat:            pt.stat(),
 			DoneCallback: func() {
 				progressDone = true
 				close(pt.progressStopCh)
@@ -53,6 +53,8 @@ func (p *filePeerTaskCallback) Init(pt Task) error {
 			ContentLength: pt.GetContentLength(),
 			TotalPieces:   int32(pt.GetTotalPieces()),
 			PieceMd5Sign:  pt.GetPieceMd5Sign(),
+			URL:           p.req.Url,
+			URLMeta:       p.req.UrlMeta,
 		})
 	if err != nil {
 		pt.Log().Errorf("register task to storage manager failed: %s", err)
@@ -61,6 +63,7 @@ func (p *filePeerTaskCallback) Init(pt Task) error {
 }
 
 func (p *filePeerTaskCallback) Update(pt Task) error {
+	root, signature := pt.GetPieceDigestRoot()
 	// update storage
 	err := p.ptm.storageManager.UpdateTask(p.pt.ctx,
 		&storage.UpdateTaskRequest{
@@ -68,9 +71,11 @@ func (p *filePeerTaskCallback) Update(pt Task) error {
 				PeerID: pt.GetPeerID(),
 				TaskID: pt.GetTaskID(),
 			},
-			ContentLength: pt.GetContentLength(),
-			TotalPieces:   int32(pt.GetTotalPieces()),
-			PieceMd5Sign:  pt.GetPieceMd5Sign(),
+			ContentLength:        pt.GetContentLength(),
+			TotalPieces:          int32(pt.GetTotalPieces()),
+			PieceMd5Sign:         pt.GetPieceMd5Sign(),
+			PieceDigestRoot:      root,
+			PieceDigestSignature: signature,
 		})
 	if err != nil {
 		pt.Log().Errorf("update task to storage manager failed: %s", err)
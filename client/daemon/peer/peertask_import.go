@@ -0,0 +1,164 @@
+/*
+ *     Copyright 2020 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package peer
+
+import (
+	"context"
+	"io"
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"d7y.io/dragonfly/v2/client/config"
+	"d7y.io/dragonfly/v2/client/daemon/storage"
+	logger "d7y.io/dragonfly/v2/internal/dflog"
+	"d7y.io/dragonfly/v2/pkg/idgen"
+	"d7y.io/dragonfly/v2/pkg/rpc/scheduler"
+	"d7y.io/dragonfly/v2/pkg/util/digestutils"
+)
+
+var (
+	// ErrTaskExists is returned when importing a task which is already completed in local storage
+	ErrTaskExists = errors.New("task already exists")
+	// ErrEmptyFile is returned when importing an empty file
+	ErrEmptyFile = errors.New("empty file")
+)
+
+// ImportTaskRequest imports a local file as a completed task
+type ImportTaskRequest struct {
+	scheduler.PeerTaskRequest
+	// Path is the absolute path of the local file
+	Path string
+}
+
+func (ptm *peerTaskManager) ImportTask(ctx context.Context, req *ImportTaskRequest) (*storage.TaskInfo, error) {
+	ctx, span := tracer.Start(ctx, config.SpanImportTask)
+	defer span.End()
+
+	taskID := idgen.TaskID(req.Url, req.UrlMeta)
+	span.SetAttributes(config.AttributeTaskID.String(taskID))
+	span.SetAttributes(config.AttributePeerID.String(req.PeerId))
+	log := logger.With("peer", req.PeerId, "task", taskID, "component", "peerTaskManager")
+
+	if reuse := ptm.storageManager.FindCompletedTask(taskID); reuse != nil {
+		log.Warnf("task is already completed by peer %s, skip import", reuse.PeerID)
+		return nil, ErrTaskExists
+	}
+
+	ptm.runningPeerTasks.Store(req.PeerId, req)
+	defer ptm.PeerTaskDone(req.PeerId)
+
+	log.Infof("start to import file %s", req.Path)
+	start := time.Now()
+	info, err := ptm.importFile(ctx, taskID, req, log)
+	if err != nil {
+		span.RecordError(err)
+		log.Errorf("import file %s error: %s", req.Path, err)
+		return nil, err
+	}
+	log.Infof("import file %s done, content length: %d, total pieces: %d, cost: %dms",
+		req.Path, info.ContentLength, info.TotalPieces, time.Now().Sub(start).Milliseconds())
+
+	// imported task is kept in local storage when announce failed
+	if err = ptm.announceTask(ctx, info); err != nil {
+		span.RecordError(err)
+		log.Errorf("announce task to scheduler error: %s", err)
+		return info, err
+	}
+	log.Infof("announce task to scheduler ok")
+	return info, nil
+}
+
+// importFile writes the local file to storage and marks the task done
+func (ptm *peerTaskManager) importFile(ctx context.Context, taskID string, req *ImportTaskRequest, log *logger.SugaredLoggerOnWith) (*storage.TaskInfo, error) {
+	file, err := os.Open(req.Path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	stat, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if stat.IsDir() {
+		return nil, errors.Errorf("%s is a directory", req.Path)
+	}
+	if stat.Size() == 0 {
+		return nil, ErrEmptyFile
+	}
+
+	ptMeta := storage.PeerTaskMetadata{
+		PeerID: req.PeerId,
+		TaskID: taskID,
+	}
+	err = ptm.storageManager.RegisterTask(ctx,
+		storage.RegisterTaskRequest{
+			CommonTaskRequest: storage.CommonTaskRequest{
+				PeerID: req.PeerId,
+				TaskID: taskID,
+			},
+			ContentLength: stat.Size(),
+			URL:           req.Url,
+			URLMeta:       req.UrlMeta,
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	var reader io.Reader = file
+	if req.UrlMeta != nil && req.UrlMeta.Digest != "" {
+		reader = digestutils.NewDigestReader(log, file, req.UrlMeta.Digest)
+	}
+	if err = ptm.pieceManager.Import(ctx, ptMeta, stat.Size(), reader); err != nil {
+		return nil, err
+	}
+	// read to the end, digest of whole file is checked here, and the file must not be changed during importing
+	if n, err := reader.Read(make([]byte, 1)); err != io.EOF {
+		if err == nil || n > 0 {
+			err = errors.Errorf("file %s is changed during importing", req.Path)
+		}
+		return nil, err
+	}
+
+	if err = ptm.storageManager.ValidateDigest(&ptMeta); err != nil {
+		return nil, err
+	}
+	err = ptm.storageManager.Store(ctx,
+		&storage.StoreRequest{
+			CommonTaskRequest: storage.CommonTaskRequest{
+				PeerID: req.PeerId,
+				TaskID: taskID,
+			},
+			MetadataOnly: true,
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	tasks, err := ptm.storageManager.StatTask(taskID)
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range tasks {
+		if t.PeerID == req.PeerId {
+			return t, nil
+		}
+	}
+	return nil, storage.ErrTaskNotFound
+}
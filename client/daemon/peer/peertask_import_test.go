@@ -0,0 +1,147 @@
+/*
+ *     Copyright 2020 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package peer
+
+import (
+	"context"
+	"io"
+	"math"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	testifyassert "github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+
+	"d7y.io/dragonfly/v2/client/clientutil"
+	"d7y.io/dragonfly/v2/client/config"
+	"d7y.io/dragonfly/v2/client/daemon/storage"
+	"d7y.io/dragonfly/v2/client/daemon/test"
+	mock_scheduler "d7y.io/dragonfly/v2/client/daemon/test/mock/scheduler"
+	"d7y.io/dragonfly/v2/pkg/idgen"
+	"d7y.io/dragonfly/v2/pkg/rpc/base"
+	"d7y.io/dragonfly/v2/pkg/rpc/scheduler"
+	"d7y.io/dragonfly/v2/pkg/util/digestutils"
+)
+
+func TestPeerTaskManager_ImportTask(t *testing.T) {
+	assert := testifyassert.New(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	testBytes, err := os.ReadFile(test.File)
+	assert.Nil(err, "load test file")
+
+	var (
+		lock      sync.Mutex
+		announces []*scheduler.AnnounceTaskRequest
+	)
+	sched := mock_scheduler.NewMockSchedulerClient(ctrl)
+	sched.EXPECT().AnnounceTask(gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(
+		func(ctx context.Context, req *scheduler.AnnounceTaskRequest, opts ...grpc.CallOption) error {
+			lock.Lock()
+			defer lock.Unlock()
+			announces = append(announces, req)
+			return nil
+		})
+
+	tempDir, _ := os.MkdirTemp("", "d7y-test-*")
+	defer os.RemoveAll(tempDir)
+	storageManager, _ := storage.NewStorageManager(
+		config.SimpleLocalTaskStoreStrategy,
+		&config.StorageOption{
+			DataPath: tempDir,
+			TaskExpireTime: clientutil.Duration{
+				Duration: -1 * time.Second,
+			},
+		}, func(request storage.CommonTaskRequest) {})
+	defer storageManager.CleanUp()
+
+	ptm := &peerTaskManager{
+		host: &scheduler.PeerHost{
+			Ip: "127.0.0.1",
+		},
+		runningPeerTasks: sync.Map{},
+		pieceManager: &pieceManager{
+			storageManager: storageManager,
+			computePieceSize: func(int64) uint32 {
+				return 1024
+			},
+		},
+		storageManager:  storageManager,
+		schedulerClient: sched,
+	}
+
+	newRequest := func(url string, meta *base.UrlMeta, peerID string) *ImportTaskRequest {
+		return &ImportTaskRequest{
+			PeerTaskRequest: scheduler.PeerTaskRequest{
+				Url:      url,
+				UrlMeta:  meta,
+				PeerId:   peerID,
+				PeerHost: ptm.host,
+			},
+			Path: test.File,
+		}
+	}
+
+	// import file with digest
+	meta := &base.UrlMeta{Digest: "md5:" + digestutils.Md5Bytes(testBytes)}
+	info, err := ptm.ImportTask(context.Background(), newRequest("d7y:/test-import", meta, "peer-0"))
+	assert.Nil(err, "import task")
+	taskID := idgen.TaskID("d7y:/test-import", meta)
+	assert.Equal(taskID, info.TaskID)
+	assert.Equal("peer-0", info.PeerID)
+	assert.Equal(int64(len(testBytes)), info.ContentLength)
+	assert.Equal(int32(math.Ceil(float64(len(testBytes))/1024)), info.TotalPieces)
+	assert.True(info.Done)
+	assert.False(ptm.IsPeerTaskRunning("peer-0"))
+
+	reuse := storageManager.FindCompletedTask(taskID)
+	assert.NotNil(reuse, "imported task must be completed")
+	ptMeta := &storage.PeerTaskMetadata{TaskID: taskID, PeerID: "peer-0"}
+	assert.Nil(storageManager.ValidateDigest(ptMeta), "piece digests must be generated")
+	rc, err := storageManager.ReadAllPieces(context.Background(), ptMeta)
+	assert.Nil(err)
+	data, err := io.ReadAll(rc)
+	rc.Close()
+	assert.Nil(err)
+	assert.Equal(testBytes, data, "imported data must match")
+
+	lock.Lock()
+	assert.Len(announces, 1)
+	assert.Equal(taskID, announces[0].TaskId)
+	assert.Equal("d7y:/test-import", announces[0].Url)
+	assert.Equal(meta.Digest, announces[0].UrlMeta.Digest)
+	assert.Equal("peer-0", announces[0].PiecePacket.DstPid)
+	assert.Equal("127.0.0.1:0", announces[0].PiecePacket.DstAddr)
+	assert.Equal(info.ContentLength, announces[0].PiecePacket.ContentLength)
+	assert.Equal(info.TotalPieces, announces[0].PiecePacket.TotalPiece)
+	assert.Len(announces[0].PiecePacket.PieceInfos, int(info.TotalPieces))
+	lock.Unlock()
+
+	// import again
+	_, err = ptm.ImportTask(context.Background(), newRequest("d7y:/test-import", meta, "peer-1"))
+	assert.Equal(ErrTaskExists, err)
+
+	// digest not match
+	meta = &base.UrlMeta{Digest: "md5:" + digestutils.Md5Bytes([]byte("mismatch"))}
+	_, err = ptm.ImportTask(context.Background(), newRequest("d7y:/test-import-mismatch", meta, "peer-2"))
+	assert.Equal(digestutils.ErrDigestNotMatch, err)
+	assert.Nil(storageManager.FindCompletedTask(idgen.TaskID("d7y:/test-import-mismatch", meta)))
+}
@@ -28,6 +28,7 @@ import (
 	"github.com/go-http-utils/headers"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/atomic"
 	"golang.org/x/time/rate"
 
 	"d7y.io/dragonfly/v2/client/clientutil"
@@ -37,6 +38,7 @@ import (
 	"d7y.io/dragonfly/v2/pkg/rpc/base"
 	"d7y.io/dragonfly/v2/pkg/rpc/scheduler"
 	schedulerclient "d7y.io/dragonfly/v2/pkg/rpc/scheduler/client"
+	"d7y.io/dragonfly/v2/pkg/signature"
 )
 
 // TaskManager processes all peer tasks request
@@ -50,9 +52,25 @@ type TaskManager interface {
 	// tiny stands task file is tiny and task is done
 	StartStreamPeerTask(ctx context.Context, req *scheduler.PeerTaskRequest) (
 		readCloser io.ReadCloser, attribute map[string]string, err error)
+	// StartSeekableStreamPeerTask starts a peer task or joins the running one, the returned reader
+	// can read at any offset, and the pieces being read are downloaded before the others
+	StartSeekableStreamPeerTask(ctx context.Context, req *scheduler.PeerTaskRequest) (
+		reader SeekableReader, attribute map[string]string, err error)
 
 	IsPeerTaskRunning(pid string) bool
 
+	// ImportTask imports a local file as a completed task and announces it to scheduler
+	ImportTask(ctx context.Context, req *ImportTaskRequest) (*storage.TaskInfo, error)
+
+	// StatTask stats the task in scheduler cluster
+	StatTask(ctx context.Context, taskID string) (*scheduler.Task, error)
+
+	// AnnounceTasks announces all completed tasks in local storage to scheduler
+	AnnounceTasks(ctx context.Context)
+
+	// SetPerPeerRateLimit updates the default rate limit of new peer tasks, running ones keep their limits
+	SetPerPeerRateLimit(limit rate.Limit)
+
 	// Stop stops the PeerTaskManager
 	Stop(ctx context.Context) error
 }
@@ -74,6 +92,8 @@ type Task interface {
 	GetTraffic() uint64
 	SetPieceMd5Sign(string)
 	GetPieceMd5Sign() string
+	SetPieceDigestRoot(root string, signature []byte)
+	GetPieceDigestRoot() (root string, signature []byte)
 }
 
 // TaskCallback inserts some operations for peer task download lifecycle
@@ -109,16 +129,30 @@ type peerTaskManager struct {
 
 	runningPeerTasks sync.Map
 
-	perPeerRateLimit rate.Limit
+	// perPeerRateLimit is the default rate limit of peer tasks, it can be reloaded
+	perPeerRateLimit atomic.Float64
 
 	// enableMultiplex indicates reusing completed peer task storage
 	// currently, only check completed peer task after register to scheduler
 	// TODO multiplex the running peer task
 	enableMultiplex bool
 
+	// enablePrefetch indicates downloading the whole file in background when ranges of it are requested
+	enablePrefetch bool
+
+	// pieceSelection is the default piece selection strategy of peer tasks
+	pieceSelection config.PieceSelectionOption
+
+	// seekableTasks are the running stream peer tasks shared by seekable readers, key is task id
+	seekableTasks map[string]*seekableStreamTask
+	seekableLock  sync.Mutex
+
 	calculateDigest bool
 
 	getPiecesMaxRetry int
+
+	// pieceVerifier verifies the signature of piece digest root, it is updated with the scheduler cluster config
+	pieceVerifier *signature.Verifier
 }
 
 func NewPeerTaskManager(
@@ -129,8 +163,11 @@ func NewPeerTaskManager(
 	schedulerOption config.SchedulerOption,
 	perPeerRateLimit rate.Limit,
 	multiplex bool,
+	prefetch bool,
+	pieceSelection config.PieceSelectionOption,
 	calculateDigest bool,
-	getPiecesMaxRetry int) (TaskManager, error) {
+	getPiecesMaxRetry int,
+	pieceVerifier *signature.Verifier) (TaskManager, error) {
 
 	ptm := &peerTaskManager{
 		host:              host,
@@ -139,16 +176,28 @@ func NewPeerTaskManager(
 		storageManager:    storageManager,
 		schedulerClient:   schedulerClient,
 		schedulerOption:   schedulerOption,
-		perPeerRateLimit:  perPeerRateLimit,
 		enableMultiplex:   multiplex,
+		enablePrefetch:    prefetch,
+		pieceSelection:    pieceSelection,
+		seekableTasks:     map[string]*seekableStreamTask{},
 		calculateDigest:   calculateDigest,
 		getPiecesMaxRetry: getPiecesMaxRetry,
+		pieceVerifier:     pieceVerifier,
 	}
+	ptm.perPeerRateLimit.Store(float64(perPeerRateLimit))
 	return ptm, nil
 }
 
 var _ TaskManager = (*peerTaskManager)(nil)
 
+func (ptm *peerTaskManager) SetPerPeerRateLimit(limit rate.Limit) {
+	ptm.perPeerRateLimit.Store(float64(limit))
+}
+
+func (ptm *peerTaskManager) getPerPeerRateLimit() rate.Limit {
+	return rate.Limit(ptm.perPeerRateLimit.Load())
+}
+
 func (ptm *peerTaskManager) StartFilePeerTask(ctx context.Context, req *FilePeerTaskRequest) (chan *FilePeerTaskProgress, *TinyData, error) {
 	if ptm.enableMultiplex {
 		progress, ok := ptm.tryReuseFilePeerTask(ctx, req)
@@ -158,15 +207,30 @@ func (ptm *peerTaskManager) StartFilePeerTask(ctx context.Context, req *FilePeer
 	}
 	// TODO ensure scheduler is ok first
 	start := time.Now()
-	limit := ptm.perPeerRateLimit
+	limit := ptm.getPerPeerRateLimit()
 	if req.Limit > 0 {
 		limit = rate.Limit(req.Limit)
 	}
+	var unfinished *storage.UnfinishedPeerTask
+	if req.Resume {
+		unfinished = ptm.tryResumeFilePeerTask(ctx, req)
+	}
 	ctx, pt, tiny, err := newFilePeerTask(ctx, ptm.host, ptm.pieceManager,
 		req, ptm.schedulerClient, ptm.schedulerOption, limit, ptm.getPiecesMaxRetry)
+	if pt == nil && unfinished != nil {
+		// release the reserved peer id of the unfinished task
+		ptm.PeerTaskDone(req.PeerId)
+	}
 	if err != nil {
 		return nil, nil, err
 	}
+	if pt != nil {
+		pt.pieceVerifier = ptm.pieceVerifier
+		ptm.setPieceSelection(ctx, &pt.peerTask)
+		if unfinished != nil {
+			pt.resume(unfinished)
+		}
+	}
 	// tiny file content is returned by scheduler, just write to output
 	if tiny != nil {
 		ptm.storeTinyPeerTask(ctx, tiny)
@@ -220,6 +284,17 @@ func (ptm *peerTaskManager) StartStreamPeerTask(ctx context.Context, req *schedu
 		}
 	}
 
+	// a range is a different task, try to serve it with the whole file task
+	if req.UrlMeta != nil && req.UrlMeta.Range != "" {
+		r, attr, ok := ptm.tryStreamRangeFromWholeTask(ctx, req)
+		if ok {
+			return r, attr, nil
+		}
+		if ptm.enablePrefetch {
+			ptm.prefetch(req)
+		}
+	}
+
 	start := time.Now()
 	ctx, pt, tiny, err := newStreamPeerTask(ctx, ptm, req)
 	if err != nil {
@@ -264,6 +339,10 @@ func (ptm *peerTaskManager) IsPeerTaskRunning(peerID string) bool {
 	return ok
 }
 
+func (ptm *peerTaskManager) StatTask(ctx context.Context, taskID string) (*scheduler.Task, error) {
+	return ptm.schedulerClient.StatTask(ctx, &scheduler.StatTaskRequest{TaskId: taskID})
+}
+
 func (ptm *peerTaskManager) storeTinyPeerTask(ctx context.Context, tiny *TinyData) {
 	// TODO store tiny data asynchronous
 	l := int64(len(tiny.Content))
@@ -10,10 +10,12 @@ import (
 	reflect "reflect"
 	time "time"
 
+	storage "d7y.io/dragonfly/v2/client/daemon/storage"
 	dflog "d7y.io/dragonfly/v2/internal/dflog"
 	base "d7y.io/dragonfly/v2/pkg/rpc/base"
 	scheduler "d7y.io/dragonfly/v2/pkg/rpc/scheduler"
 	gomock "github.com/golang/mock/gomock"
+	rate "golang.org/x/time/rate"
 )
 
 // MockTaskManager is a mock of TaskManager interface.
@@ -39,6 +41,33 @@ func (m *MockTaskManager) EXPECT() *MockTaskManagerMockRecorder {
 	return m.recorder
 }
 
+// AnnounceTasks mocks base method.
+func (m *MockTaskManager) AnnounceTasks(ctx context.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "AnnounceTasks", ctx)
+}
+
+// AnnounceTasks indicates an expected call of AnnounceTasks.
+func (mr *MockTaskManagerMockRecorder) AnnounceTasks(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AnnounceTasks", reflect.TypeOf((*MockTaskManager)(nil).AnnounceTasks), ctx)
+}
+
+// ImportTask mocks base method.
+func (m *MockTaskManager) ImportTask(ctx context.Context, req *ImportTaskRequest) (*storage.TaskInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportTask", ctx, req)
+	ret0, _ := ret[0].(*storage.TaskInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImportTask indicates an expected call of ImportTask.
+func (mr *MockTaskManagerMockRecorder) ImportTask(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportTask", reflect.TypeOf((*MockTaskManager)(nil).ImportTask), ctx, req)
+}
+
 // IsPeerTaskRunning mocks base method.
 func (m *MockTaskManager) IsPeerTaskRunning(pid string) bool {
 	m.ctrl.T.Helper()
@@ -53,6 +82,18 @@ func (mr *MockTaskManagerMockRecorder) IsPeerTaskRunning(pid interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsPeerTaskRunning", reflect.TypeOf((*MockTaskManager)(nil).IsPeerTaskRunning), pid)
 }
 
+// SetPerPeerRateLimit mocks base method.
+func (m *MockTaskManager) SetPerPeerRateLimit(limit rate.Limit) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetPerPeerRateLimit", limit)
+}
+
+// SetPerPeerRateLimit indicates an expected call of SetPerPeerRateLimit.
+func (mr *MockTaskManagerMockRecorder) SetPerPeerRateLimit(limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetPerPeerRateLimit", reflect.TypeOf((*MockTaskManager)(nil).SetPerPeerRateLimit), limit)
+}
+
 // StartFilePeerTask mocks base method.
 func (m *MockTaskManager) StartFilePeerTask(ctx context.Context, req *FilePeerTaskRequest) (chan *FilePeerTaskProgress, *TinyData, error) {
 	m.ctrl.T.Helper()
@@ -69,6 +110,22 @@ func (mr *MockTaskManagerMockRecorder) StartFilePeerTask(ctx, req interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartFilePeerTask", reflect.TypeOf((*MockTaskManager)(nil).StartFilePeerTask), ctx, req)
 }
 
+// StartSeekableStreamPeerTask mocks base method.
+func (m *MockTaskManager) StartSeekableStreamPeerTask(ctx context.Context, req *scheduler.PeerTaskRequest) (SeekableReader, map[string]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StartSeekableStreamPeerTask", ctx, req)
+	ret0, _ := ret[0].(SeekableReader)
+	ret1, _ := ret[1].(map[string]string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// StartSeekableStreamPeerTask indicates an expected call of StartSeekableStreamPeerTask.
+func (mr *MockTaskManagerMockRecorder) StartSeekableStreamPeerTask(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartSeekableStreamPeerTask", reflect.TypeOf((*MockTaskManager)(nil).StartSeekableStreamPeerTask), ctx, req)
+}
+
 // StartStreamPeerTask mocks base method.
 func (m *MockTaskManager) StartStreamPeerTask(ctx context.Context, req *scheduler.PeerTaskRequest) (io.ReadCloser, map[string]string, error) {
 	m.ctrl.T.Helper()
@@ -85,6 +142,21 @@ func (mr *MockTaskManagerMockRecorder) StartStreamPeerTask(ctx, req interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartStreamPeerTask", reflect.TypeOf((*MockTaskManager)(nil).StartStreamPeerTask), ctx, req)
 }
 
+// StatTask mocks base method.
+func (m *MockTaskManager) StatTask(ctx context.Context, taskID string) (*scheduler.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StatTask", ctx, taskID)
+	ret0, _ := ret[0].(*scheduler.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StatTask indicates an expected call of StatTask.
+func (mr *MockTaskManagerMockRecorder) StatTask(ctx, taskID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StatTask", reflect.TypeOf((*MockTaskManager)(nil).StatTask), ctx, taskID)
+}
+
 // Stop mocks base method.
 func (m *MockTaskManager) Stop(ctx context.Context) error {
 	m.ctrl.T.Helper()
@@ -176,6 +248,21 @@ func (mr *MockTaskMockRecorder) GetPeerID() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPeerID", reflect.TypeOf((*MockTask)(nil).GetPeerID))
 }
 
+// GetPieceDigestRoot mocks base method.
+func (m *MockTask) GetPieceDigestRoot() (string, []byte) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPieceDigestRoot")
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].([]byte)
+	return ret0, ret1
+}
+
+// GetPieceDigestRoot indicates an expected call of GetPieceDigestRoot.
+func (mr *MockTaskMockRecorder) GetPieceDigestRoot() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPieceDigestRoot", reflect.TypeOf((*MockTask)(nil).GetPieceDigestRoot))
+}
+
 // GetPieceMd5Sign mocks base method.
 func (m *MockTask) GetPieceMd5Sign() string {
 	m.ctrl.T.Helper()
@@ -286,6 +373,18 @@ func (mr *MockTaskMockRecorder) SetContentLength(arg0 interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetContentLength", reflect.TypeOf((*MockTask)(nil).SetContentLength), arg0)
 }
 
+// SetPieceDigestRoot mocks base method.
+func (m *MockTask) SetPieceDigestRoot(root string, signature []byte) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetPieceDigestRoot", root, signature)
+}
+
+// SetPieceDigestRoot indicates an expected call of SetPieceDigestRoot.
+func (mr *MockTaskMockRecorder) SetPieceDigestRoot(root, signature interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetPieceDigestRoot", reflect.TypeOf((*MockTask)(nil).SetPieceDigestRoot), root, signature)
+}
+
 // SetPieceMd5Sign mocks base method.
 func (m *MockTask) SetPieceMd5Sign(arg0 string) {
 	m.ctrl.T.Helper()
@@ -402,3 +501,17 @@ func (mr *MockTaskCallbackMockRecorder) Update(pt interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockTaskCallback)(nil).Update), pt)
 }
+
+// ValidateDigest mocks base method.
+func (m *MockTaskCallback) ValidateDigest(pt Task) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ValidateDigest", pt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ValidateDigest indicates an expected call of ValidateDigest.
+func (mr *MockTaskCallbackMockRecorder) ValidateDigest(pt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateDigest", reflect.TypeOf((*MockTaskCallback)(nil).ValidateDigest), pt)
+}
@@ -44,6 +44,7 @@ import (
 	"d7y.io/dragonfly/v2/internal/util"
 	"d7y.io/dragonfly/v2/pkg/rpc"
 	"d7y.io/dragonfly/v2/pkg/rpc/base"
+	"d7y.io/dragonfly/v2/pkg/rpc/dfdaemon"
 	daemonserver "d7y.io/dragonfly/v2/pkg/rpc/dfdaemon/server"
 	"d7y.io/dragonfly/v2/pkg/rpc/scheduler"
 	schedulerclient "d7y.io/dragonfly/v2/pkg/rpc/scheduler/client"
@@ -64,7 +65,7 @@ func setupPeerTaskManagerComponents(ctrl *gomock.Controller, opt componentsOptio
 	port := int32(freeport.GetPort())
 	// 1. set up a mock daemon server for uploading pieces info
 	var daemon = mock_daemon.NewMockDaemonServer(ctrl)
-	daemon.EXPECT().GetPieceTasks(gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(func(ctx context.Context, request *base.PieceTaskRequest) (*base.PiecePacket, error) {
+	genPiecePacket := func(request *base.PieceTaskRequest) *base.PiecePacket {
 		var tasks []*base.PieceInfo
 		for i := uint32(0); i < request.Limit; i++ {
 			start := opt.pieceSize * (request.StartNum + i)
@@ -91,7 +92,22 @@ func setupPeerTaskManagerComponents(ctrl *gomock.Controller, opt componentsOptio
 			PieceInfos:    tasks,
 			ContentLength: opt.contentLength,
 			TotalPiece:    int32(math.Ceil(float64(opt.contentLength) / float64(opt.pieceSize))),
-		}, nil
+		}
+	}
+	daemon.EXPECT().GetPieceTasks(gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(func(ctx context.Context, request *base.PieceTaskRequest) (*base.PiecePacket, error) {
+		return genPiecePacket(request), nil
+	})
+	daemon.EXPECT().SyncPieceTasks(gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(func(request *base.PieceTaskRequest, stream dfdaemon.Daemon_SyncPieceTasksServer) error {
+		for {
+			packet := genPiecePacket(request)
+			if err := stream.Send(packet); err != nil {
+				return err
+			}
+			if len(packet.PieceInfos) < int(request.Limit) {
+				return nil
+			}
+			request.StartNum += request.Limit
+		}
 	})
 	ln, _ := rpc.Listen(dfnet.NetAddr{
 		Type: "tcp",
@@ -0,0 +1,151 @@
+/*
+ *     Copyright 2020 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package peer
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/go-http-utils/headers"
+	"github.com/pkg/errors"
+
+	"d7y.io/dragonfly/v2/client/clientutil"
+	"d7y.io/dragonfly/v2/client/config"
+	logger "d7y.io/dragonfly/v2/internal/dflog"
+	"d7y.io/dragonfly/v2/pkg/idgen"
+	"d7y.io/dragonfly/v2/pkg/rpc/base"
+	"d7y.io/dragonfly/v2/pkg/rpc/scheduler"
+)
+
+// wholeURLMeta returns the url meta of the whole file for a range request, the range header
+// of client request is removed, or the whole file task will download a part of file from source.
+func wholeURLMeta(meta *base.UrlMeta) *base.UrlMeta {
+	var header map[string]string
+	if meta.Header != nil {
+		header = make(map[string]string, len(meta.Header))
+		for k, v := range meta.Header {
+			if strings.EqualFold(k, headers.Range) {
+				continue
+			}
+			header[k] = v
+		}
+	}
+	return &base.UrlMeta{
+		Digest: meta.Digest,
+		Tag:    meta.Tag,
+		Filter: meta.Filter,
+		Header: header,
+	}
+}
+
+// parseSingleRange parses the range of the file with contentLength, multiple ranges are not supported.
+func parseSingleRange(rg string, contentLength int64) (*clientutil.Range, error) {
+	ranges, err := clientutil.ParseRange(rg, contentLength)
+	if err != nil {
+		return nil, err
+	}
+	if len(ranges) != 1 {
+		return nil, errors.Errorf("multiple ranges are not supported: %s", rg)
+	}
+	return &ranges[0], nil
+}
+
+// rangeReadCloser reads a range of the whole file.
+type rangeReadCloser struct {
+	io.Reader
+	close func() error
+}
+
+func (r *rangeReadCloser) Close() error {
+	return r.close()
+}
+
+// tryStreamRangeFromWholeTask serves the range of request with a seekable stream peer task of the
+// whole file when it is running or peers in scheduler cluster hold it. The pieces of the range are
+// downloaded before the others, and when prefetch is enabled, the rest of the file is downloaded in
+// background after the range is read, so later ranges of the file are served locally.
+func (ptm *peerTaskManager) tryStreamRangeFromWholeTask(ctx context.Context,
+	request *scheduler.PeerTaskRequest) (io.ReadCloser, map[string]string, bool) {
+	meta := wholeURLMeta(request.UrlMeta)
+	taskID := idgen.TaskID(request.Url, meta)
+	log := logger.With("peer", request.PeerId, "task", taskID, "component", "rangeStreamPeerTask")
+
+	if !ptm.isSeekableStreamPeerTaskRunning(taskID) {
+		task, err := ptm.StatTask(ctx, taskID)
+		if err != nil || !task.HasAvailablePeer || task.ContentLength <= 0 {
+			return nil, nil, false
+		}
+	}
+
+	req := &scheduler.PeerTaskRequest{
+		Url:      request.Url,
+		UrlMeta:  meta,
+		PeerId:   request.PeerId,
+		PeerHost: ptm.host,
+	}
+	r, _, err := ptm.StartSeekableStreamPeerTask(ctx, req)
+	if err != nil {
+		log.Warnf("start seekable stream peer task of the whole file error: %s", err)
+		return nil, nil, false
+	}
+	rg, err := parseSingleRange(request.UrlMeta.Range, r.Size())
+	if err != nil {
+		log.Warnf("parse range %q error: %s", request.UrlMeta.Range, err)
+		r.Close()
+		return nil, nil, false
+	}
+	log.Infof("read range %s from the whole file with %d bytes", request.UrlMeta.Range, r.Size())
+
+	attr := map[string]string{}
+	attr[headers.ContentLength] = fmt.Sprintf("%d", rg.Length)
+	attr[config.HeaderDragonflyTask] = taskID
+	attr[config.HeaderDragonflyPeer] = request.PeerId
+	return &rangeReadCloser{
+		Reader: io.NewSectionReader(r, rg.Start, rg.Length),
+		close:  r.Close,
+	}, attr, true
+}
+
+// prefetch downloads the whole file of the range request in background.
+func (ptm *peerTaskManager) prefetch(request *scheduler.PeerTaskRequest) {
+	meta := wholeURLMeta(request.UrlMeta)
+	taskID := idgen.TaskID(request.Url, meta)
+	if ptm.storageManager.FindCompletedTask(taskID) != nil || ptm.isSeekableStreamPeerTaskRunning(taskID) {
+		return
+	}
+
+	req := &scheduler.PeerTaskRequest{
+		Url:      request.Url,
+		UrlMeta:  meta,
+		PeerId:   idgen.PeerID(ptm.host.Ip),
+		PeerHost: ptm.host,
+	}
+	log := logger.With("peer", req.PeerId, "task", taskID, "component", "prefetchPeerTask")
+	log.Infof("prefetch the whole file for range %s", request.UrlMeta.Range)
+
+	go func() {
+		// the task keeps downloading after the reader is closed when prefetch is enabled
+		r, _, err := ptm.StartSeekableStreamPeerTask(context.Background(), req)
+		if err != nil {
+			log.Errorf("prefetch error: %s", err)
+			return
+		}
+		r.Close()
+	}()
+}
@@ -0,0 +1,272 @@
+/*
+ *     Copyright 2020 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package peer
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-http-utils/headers"
+	"github.com/golang/mock/gomock"
+	testifyassert "github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+
+	"d7y.io/dragonfly/v2/client/clientutil"
+	"d7y.io/dragonfly/v2/client/config"
+	"d7y.io/dragonfly/v2/client/daemon/storage"
+	"d7y.io/dragonfly/v2/client/daemon/test"
+	mock_scheduler "d7y.io/dragonfly/v2/client/daemon/test/mock/scheduler"
+	"d7y.io/dragonfly/v2/pkg/idgen"
+	"d7y.io/dragonfly/v2/pkg/rpc/base"
+	"d7y.io/dragonfly/v2/pkg/rpc/scheduler"
+)
+
+func TestPeerTaskManager_ReuseRangeStreamPeerTask(t *testing.T) {
+	assert := testifyassert.New(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	testBytes, err := os.ReadFile(test.File)
+	assert.Nil(err, "load test file")
+
+	sched := mock_scheduler.NewMockSchedulerClient(ctrl)
+	sched.EXPECT().AnnounceTask(gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(
+		func(ctx context.Context, req *scheduler.AnnounceTaskRequest, opts ...grpc.CallOption) error {
+			return nil
+		})
+
+	tempDir, _ := os.MkdirTemp("", "d7y-test-*")
+	defer os.RemoveAll(tempDir)
+	storageManager, _ := storage.NewStorageManager(
+		config.SimpleLocalTaskStoreStrategy,
+		&config.StorageOption{
+			DataPath: tempDir,
+			TaskExpireTime: clientutil.Duration{
+				Duration: -1 * time.Second,
+			},
+		}, func(request storage.CommonTaskRequest) {})
+	defer storageManager.CleanUp()
+
+	ptm := &peerTaskManager{
+		host: &scheduler.PeerHost{
+			Ip: "127.0.0.1",
+		},
+		runningPeerTasks: sync.Map{},
+		pieceManager: &pieceManager{
+			storageManager: storageManager,
+			computePieceSize: func(int64) uint32 {
+				return 1024
+			},
+		},
+		storageManager:  storageManager,
+		schedulerClient: sched,
+		enableMultiplex: true,
+	}
+
+	url := "http://localhost/test/data"
+	_, err = ptm.ImportTask(context.Background(), &ImportTaskRequest{
+		PeerTaskRequest: scheduler.PeerTaskRequest{
+			Url:      url,
+			UrlMeta:  &base.UrlMeta{Tag: "d7y-test"},
+			PeerId:   "peer-0",
+			PeerHost: ptm.host,
+		},
+		Path: test.File,
+	})
+	assert.Nil(err, "import whole file task")
+
+	size := len(testBytes)
+	tests := []struct {
+		name   string
+		rg     string
+		ok     bool
+		expect []byte
+	}{
+		{
+			name:   "range",
+			rg:     "bytes=100-1099",
+			ok:     true,
+			expect: testBytes[100:1100],
+		},
+		{
+			name:   "suffix range",
+			rg:     "bytes=-100",
+			ok:     true,
+			expect: testBytes[size-100:],
+		},
+		{
+			name:   "open range",
+			rg:     "bytes=1000-",
+			ok:     true,
+			expect: testBytes[1000:],
+		},
+		{
+			name: "multiple ranges",
+			rg:   "bytes=0-1,5-6",
+		},
+		{
+			name: "invalid range",
+			rg:   "bytes=x-y",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := testifyassert.New(t)
+			r, attr, ok := ptm.tryReuseStreamPeerTask(context.Background(), &scheduler.PeerTaskRequest{
+				Url:      url,
+				UrlMeta:  &base.UrlMeta{Tag: "d7y-test", Range: tc.rg},
+				PeerId:   "peer-1",
+				PeerHost: ptm.host,
+			})
+			assert.Equal(tc.ok, ok)
+			if !ok {
+				return
+			}
+			defer r.Close()
+
+			data, err := io.ReadAll(r)
+			assert.Nil(err)
+			assert.Equal(tc.expect, data)
+			assert.Equal(strconv.Itoa(len(tc.expect)), attr[headers.ContentLength])
+		})
+	}
+}
+
+func TestWholeURLMeta(t *testing.T) {
+	assert := testifyassert.New(t)
+
+	meta := &base.UrlMeta{
+		Tag:    "d7y-test",
+		Range:  "bytes=0-99",
+		Filter: "a&b",
+		Header: map[string]string{
+			headers.Range:         "bytes=0-99",
+			headers.Authorization: "Basic xxx",
+		},
+	}
+	whole := wholeURLMeta(meta)
+	assert.Equal("", whole.Range)
+	assert.Equal(map[string]string{headers.Authorization: "Basic xxx"}, whole.Header)
+	assert.Equal(meta.Tag, whole.Tag)
+	assert.Equal(meta.Filter, whole.Filter)
+	// the header of range request is untouched
+	assert.Equal("bytes=0-99", meta.Header[headers.Range])
+	assert.Nil(wholeURLMeta(&base.UrlMeta{}).Header)
+}
+
+func TestPeerTaskManager_StartStreamPeerTask_RangeFromWholeTask(t *testing.T) {
+	assert := testifyassert.New(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	testBytes, err := os.ReadFile(test.File)
+	assert.Nil(err, "load test file")
+
+	var (
+		url       = "http://localhost/test/data"
+		meta      = &base.UrlMeta{Tag: "d7y-test"}
+		taskID    = idgen.TaskID(url, meta)
+		pieceSize = 1024
+	)
+	sched, storageManager := setupPeerTaskManagerComponents(
+		ctrl,
+		componentsOption{
+			taskID:             taskID,
+			contentLength:      int64(len(testBytes)),
+			pieceSize:          uint32(pieceSize),
+			pieceParallelCount: 4,
+		})
+	defer storageManager.CleanUp()
+	sched.(*mock_scheduler.MockSchedulerClient).EXPECT().StatTask(gomock.Any(), gomock.Any()).Times(1).DoAndReturn(
+		func(ctx context.Context, req *scheduler.StatTaskRequest, opts ...grpc.CallOption) (*scheduler.Task, error) {
+			assert.Equal(taskID, req.TaskId)
+			return &scheduler.Task{
+				Id:               taskID,
+				ContentLength:    int64(len(testBytes)),
+				HasAvailablePeer: true,
+			}, nil
+		})
+
+	downloader := NewMockPieceDownloader(ctrl)
+	downloader.EXPECT().DownloadPiece(gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(
+		func(ctx context.Context, task *DownloadPieceRequest) (io.Reader, io.Closer, error) {
+			rc := io.NopCloser(
+				bytes.NewBuffer(
+					testBytes[task.piece.RangeStart : task.piece.RangeStart+uint64(task.piece.RangeSize)],
+				))
+			return rc, rc, nil
+		})
+
+	ptm := &peerTaskManager{
+		host: &scheduler.PeerHost{
+			Ip: "127.0.0.1",
+		},
+		runningPeerTasks: sync.Map{},
+		pieceManager: &pieceManager{
+			storageManager:  storageManager,
+			pieceDownloader: downloader,
+		},
+		storageManager:  storageManager,
+		schedulerClient: sched,
+		schedulerOption: config.SchedulerOption{
+			ScheduleTimeout: clientutil.Duration{Duration: 10 * time.Minute},
+		},
+		enableMultiplex: true,
+		enablePrefetch:  true,
+		seekableTasks:   map[string]*seekableStreamTask{},
+	}
+
+	newRequest := func(rg string, peerID string) *scheduler.PeerTaskRequest {
+		return &scheduler.PeerTaskRequest{
+			Url: url,
+			// proxy keeps the range header of client request
+			UrlMeta:  &base.UrlMeta{Tag: meta.Tag, Range: rg, Header: map[string]string{headers.Range: rg}},
+			PeerId:   peerID,
+			PeerHost: &scheduler.PeerHost{},
+		}
+	}
+
+	// the first range is read from the whole file task downloaded from peers
+	r, attr, err := ptm.StartStreamPeerTask(context.Background(), newRequest("bytes=100-1099", "peer-0"))
+	assert.Nil(err, "start stream peer task")
+	data, err := io.ReadAll(r)
+	assert.Nil(err)
+	assert.Equal(testBytes[100:1100], data)
+	assert.Equal("1000", attr[headers.ContentLength])
+	assert.Equal(taskID, attr[config.HeaderDragonflyTask])
+	r.Close()
+
+	// the rest of the whole file is prefetched in background
+	assert.Eventually(func() bool {
+		return !ptm.isSeekableStreamPeerTaskRunning(taskID) && storageManager.FindCompletedTask(taskID) != nil
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// later ranges are served locally
+	r, _, err = ptm.StartStreamPeerTask(context.Background(), newRequest("bytes=-100", "peer-1"))
+	assert.Nil(err, "start stream peer task")
+	data, err = io.ReadAll(r)
+	assert.Nil(err)
+	assert.Equal(testBytes[len(testBytes)-100:], data)
+	r.Close()
+}
@@ -0,0 +1,76 @@
+/*
+ *     Copyright 2020 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package peer
+
+import (
+	"context"
+
+	"d7y.io/dragonfly/v2/client/daemon/storage"
+	logger "d7y.io/dragonfly/v2/internal/dflog"
+	"d7y.io/dragonfly/v2/pkg/idgen"
+)
+
+// tryResumeFilePeerTask finds the unfinished local task of the request and reuses its peer id,
+// the peer id is reserved in running peer tasks until the peer task is done
+func (ptm *peerTaskManager) tryResumeFilePeerTask(ctx context.Context, req *FilePeerTaskRequest) *storage.UnfinishedPeerTask {
+	taskID := idgen.TaskID(req.Url, req.UrlMeta)
+	log := logger.With("task", taskID, "component", "peerTaskManager")
+
+	var running []string
+	ptm.runningPeerTasks.Range(func(key, value interface{}) bool {
+		running = append(running, key.(string))
+		return true
+	})
+	unfinished := ptm.storageManager.FindUnfinishedTask(ctx, taskID, running...)
+	if unfinished == nil {
+		log.Infof("unfinished task not found, start a new peer task")
+		return nil
+	}
+	if _, loaded := ptm.runningPeerTasks.LoadOrStore(unfinished.PeerID, req); loaded {
+		log.Warnf("unfinished peer task %s is resumed by others, start a new peer task", unfinished.PeerID)
+		return nil
+	}
+	log.Infof("resume unfinished peer task %s, downloaded pieces: %d", unfinished.PeerID, len(unfinished.Pieces))
+	req.PeerId = unfinished.PeerID
+	return unfinished
+}
+
+// resume marks the downloaded pieces of the unfinished task as ready, they will not be downloaded again
+func (pt *peerTask) resume(unfinished *storage.UnfinishedPeerTask) {
+	pt.lock.Lock()
+	defer pt.lock.Unlock()
+	if unfinished.ContentLength > 0 {
+		pt.contentLength.Store(unfinished.ContentLength)
+	}
+	if unfinished.TotalPieces > 0 {
+		pt.totalPiece = unfinished.TotalPieces
+	}
+	for _, piece := range unfinished.Pieces {
+		pt.readyPieces.Set(piece.Num)
+		pt.requestedPieces.Set(piece.Num)
+		pt.completedLength.Add(piece.Range.Length)
+	}
+	pt.resumedPieces = unfinished.Pieces
+}
+
+// resumedPieces returns the downloaded pieces when the peer task is resumed
+func resumedPieces(pt Task) []storage.PieceMetadata {
+	if ft, ok := pt.(*filePeerTask); ok {
+		return ft.resumedPieces
+	}
+	return nil
+}
@@ -0,0 +1,270 @@
+/*
+ *     Copyright 2020 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package peer
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	testifyassert "github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/atomic"
+	"google.golang.org/grpc"
+
+	"d7y.io/dragonfly/v2/client/clientutil"
+	"d7y.io/dragonfly/v2/client/config"
+	"d7y.io/dragonfly/v2/client/daemon/storage"
+	"d7y.io/dragonfly/v2/client/daemon/test"
+	mock_scheduler "d7y.io/dragonfly/v2/client/daemon/test/mock/scheduler"
+	"d7y.io/dragonfly/v2/pkg/idgen"
+	"d7y.io/dragonfly/v2/pkg/rpc/base"
+	"d7y.io/dragonfly/v2/pkg/rpc/scheduler"
+	"d7y.io/dragonfly/v2/pkg/source"
+	"d7y.io/dragonfly/v2/pkg/source/httpprotocol"
+	sourceMock "d7y.io/dragonfly/v2/pkg/source/mock"
+	"d7y.io/dragonfly/v2/pkg/util/digestutils"
+)
+
+// completeCountingStorageManager counts the updates which complete the task
+type completeCountingStorageManager struct {
+	storage.Manager
+	completed atomic.Int32
+}
+
+func (m *completeCountingStorageManager) UpdateTask(ctx context.Context, req *storage.UpdateTaskRequest) error {
+	if req.GenPieceDigest {
+		m.completed.Inc()
+	}
+	return m.Manager.UpdateTask(ctx, req)
+}
+
+func TestPeerTaskManager_ResumeFromSource(t *testing.T) {
+	assert := testifyassert.New(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	testBytes, err := os.ReadFile(test.File)
+	require.Nil(t, err, "load test file")
+
+	var (
+		pieceSize = 1024
+		url       = "http://localhost/test/resume"
+		meta      = &base.UrlMeta{
+			Tag:    "d7y-test",
+			Digest: digestutils.Md5Bytes(testBytes),
+		}
+		taskID     = idgen.TaskID(url, meta)
+		peerID     = "peer-resume-0"
+		totalPiece = (len(testBytes) + pieceSize - 1) / pieceSize
+	)
+
+	tempDir, _ := os.MkdirTemp("", "d7y-test-*")
+	defer os.RemoveAll(tempDir)
+	output := path.Join(tempDir, "output")
+	storageManager, err := storage.NewStorageManager(config.SimpleLocalTaskStoreStrategy,
+		&config.StorageOption{
+			DataPath: path.Join(tempDir, "data"),
+			TaskExpireTime: clientutil.Duration{
+				Duration: time.Hour,
+			},
+		}, func(request storage.CommonTaskRequest) {})
+	require.Nil(t, err)
+	defer storageManager.CleanUp()
+
+	// prepare an unfinished task with even pieces
+	require.Nil(t, storageManager.RegisterTask(context.Background(), storage.RegisterTaskRequest{
+		CommonTaskRequest: storage.CommonTaskRequest{
+			PeerID:      peerID,
+			TaskID:      taskID,
+			Destination: output,
+		},
+		ContentLength: int64(len(testBytes)),
+		TotalPieces:   int32(totalPiece),
+		URL:           url,
+		URLMeta:       meta,
+	}))
+	for i := 0; i < totalPiece; i += 2 {
+		start := i * pieceSize
+		end := start + pieceSize
+		if end > len(testBytes) {
+			end = len(testBytes)
+		}
+		_, err = storageManager.WritePiece(context.Background(), &storage.WritePieceRequest{
+			PeerTaskMetadata: storage.PeerTaskMetadata{
+				PeerID: peerID,
+				TaskID: taskID,
+			},
+			PieceMetadata: storage.PieceMetadata{
+				Num:    int32(i),
+				Md5:    digestutils.Md5Bytes(testBytes[start:end]),
+				Offset: uint64(start),
+				Range: clientutil.Range{
+					Start:  int64(start),
+					Length: int64(end - start),
+				},
+			},
+			Reader: bytes.NewBuffer(testBytes[start:end]),
+		})
+		require.Nil(t, err)
+	}
+
+	sched := mock_scheduler.NewMockSchedulerClient(ctrl)
+	sched.EXPECT().RegisterPeerTask(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, ptr *scheduler.PeerTaskRequest, opts ...grpc.CallOption) (*scheduler.RegisterResult, error) {
+			assert.Equal(peerID, ptr.PeerId, "peer id of unfinished task should be reused")
+			return nil, errors.New("scheduler is not available")
+		})
+
+	var (
+		lock   sync.Mutex
+		ranges []string
+	)
+	sourceClient := sourceMock.NewMockResourceClient(ctrl)
+	source.UnRegister("http")
+	require.Nil(t, source.Register("http", sourceClient, httpprotocol.Adapter))
+	defer source.UnRegister("http")
+	sourceClient.EXPECT().GetContentLength(gomock.Any()).DoAndReturn(
+		func(request *source.Request) (int64, error) {
+			return int64(len(testBytes)), nil
+		})
+	sourceClient.EXPECT().Download(gomock.Any()).AnyTimes().DoAndReturn(
+		func(request *source.Request) (*source.Response, error) {
+			var start, end int
+			rg := request.Header.Get("Range")
+			if _, err := fmt.Sscanf(rg, "bytes=%d-%d", &start, &end); err != nil {
+				return nil, err
+			}
+			lock.Lock()
+			ranges = append(ranges, rg)
+			lock.Unlock()
+			return source.NewResponse(io.NopCloser(bytes.NewBuffer(testBytes[start:end+1])),
+				source.WithStatus(http.StatusPartialContent, "Partial Content"),
+				source.WithContentLength(int64(end+1-start))), nil
+		})
+
+	pieceStorageManager := &completeCountingStorageManager{Manager: storageManager}
+	ptm := &peerTaskManager{
+		calculateDigest: true,
+		host: &scheduler.PeerHost{
+			Ip: "127.0.0.1",
+		},
+		runningPeerTasks: sync.Map{},
+		pieceManager: &pieceManager{
+			calculateDigest: true,
+			storageManager:  pieceStorageManager,
+			computePieceSize: func(contentLength int64) uint32 {
+				return uint32(pieceSize)
+			},
+		},
+		storageManager:  storageManager,
+		schedulerClient: sched,
+		schedulerOption: config.SchedulerOption{
+			ScheduleTimeout: clientutil.Duration{Duration: 10 * time.Minute},
+		},
+	}
+	progress, _, err := ptm.StartFilePeerTask(context.Background(), &FilePeerTaskRequest{
+		PeerTaskRequest: scheduler.PeerTaskRequest{
+			Url:      url,
+			UrlMeta:  meta,
+			PeerId:   "peer-resume-1",
+			PeerHost: &scheduler.PeerHost{},
+		},
+		Output: output,
+		Resume: true,
+	})
+	require.Nil(t, err, "start file peer task")
+
+	var p *FilePeerTaskProgress
+	for p = range progress {
+		assert.True(p.State.Success, p.State.Msg)
+		if p.PeerTaskDone {
+			p.DoneCallback()
+			break
+		}
+	}
+	require.NotNil(t, p)
+	assert.True(p.PeerTaskDone)
+	assert.Equal(peerID, p.PeerID)
+	assert.Equal(int64(len(testBytes)), p.CompletedLength)
+
+	outputBytes, err := os.ReadFile(output)
+	assert.Nil(err, "load output file")
+	assert.Equal(testBytes, outputBytes, "output and desired output must match")
+	assert.Equal(int32(1), pieceStorageManager.completed.Load(), "resumed task should be completed once")
+
+	// only odd pieces are downloaded from source
+	lock.Lock()
+	defer lock.Unlock()
+	assert.Len(ranges, totalPiece/2)
+	for _, rg := range ranges {
+		var start, end int
+		_, err = fmt.Sscanf(rg, "bytes=%d-%d", &start, &end)
+		assert.Nil(err)
+		assert.Equal(1, start/pieceSize%2, "range %s should be an odd piece", rg)
+	}
+}
+
+func TestResumedPieceSize(t *testing.T) {
+	assert := testifyassert.New(t)
+	piece := func(num int32, start, length int64) storage.PieceMetadata {
+		return storage.PieceMetadata{Num: num, Range: clientutil.Range{Start: start, Length: length}}
+	}
+	tests := []struct {
+		name   string
+		pieces []storage.PieceMetadata
+		size   uint32
+		ok     bool
+	}{
+		{
+			name:   "piece size of downloaded pieces",
+			pieces: []storage.PieceMetadata{piece(1, 2048, 2048), piece(2, 4096, 904)},
+			size:   2048,
+			ok:     true,
+		},
+		{
+			name:   "only last piece",
+			pieces: []storage.PieceMetadata{piece(4, 4096, 904)},
+			size:   1024,
+			ok:     true,
+		},
+		{
+			name:   "not aligned",
+			pieces: []storage.PieceMetadata{piece(1, 1024, 1000)},
+			ok:     false,
+		},
+		{
+			name:   "last piece not match",
+			pieces: []storage.PieceMetadata{piece(0, 0, 1024), piece(4, 4096, 1000)},
+			ok:     false,
+		},
+	}
+	for _, tc := range tests {
+		size, ok := resumedPieceSize(5000, 1024, tc.pieces)
+		assert.Equal(tc.ok, ok, tc.name)
+		assert.Equal(tc.size, size, tc.name)
+	}
+}
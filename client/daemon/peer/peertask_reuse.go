@@ -92,6 +92,12 @@ func (ptm *peerTaskManager) tryReuseFilePeerTask(ctx context.Context,
 		CompletedLength: reuse.ContentLength,
 		PeerTaskDone:    true,
 		DoneCallback:    func() {},
+		Stat: &PeerTaskStat{
+			TotalPieces:    reuse.TotalPieces,
+			FinishedPieces: reuse.TotalPieces,
+			Source:         PeerTaskSourceLocal,
+			PieceMd5Sign:   reuse.PieceMd5Sign,
+		},
 	}
 
 	// make a new buffered channel, because we did not need to call newFilePeerTask
@@ -108,6 +114,9 @@ func (ptm *peerTaskManager) tryReuseStreamPeerTask(ctx context.Context,
 	taskID := idgen.TaskID(request.Url, request.UrlMeta)
 	reuse := ptm.storageManager.FindCompletedTask(taskID)
 	if reuse == nil {
+		if request.UrlMeta != nil && request.UrlMeta.Range != "" {
+			return ptm.tryReuseRangeStreamPeerTask(ctx, request)
+		}
 		return nil, nil, false
 	}
 
@@ -140,3 +149,52 @@ func (ptm *peerTaskManager) tryReuseStreamPeerTask(ctx context.Context,
 	span.SetAttributes(config.AttributePeerTaskSuccess.Bool(true))
 	return rc, attr, true
 }
+
+// tryReuseRangeStreamPeerTask serves the range of request from the completed task of the whole file.
+func (ptm *peerTaskManager) tryReuseRangeStreamPeerTask(ctx context.Context,
+	request *scheduler.PeerTaskRequest) (io.ReadCloser, map[string]string, bool) {
+	taskID := idgen.TaskID(request.Url, wholeURLMeta(request.UrlMeta))
+	reuse := ptm.storageManager.FindCompletedTask(taskID)
+	if reuse == nil {
+		return nil, nil, false
+	}
+
+	log := logger.With("peer", request.PeerId, "task", taskID, "component", "reuseRangeStreamPeerTask")
+	rg, err := parseSingleRange(request.UrlMeta.Range, reuse.ContentLength)
+	if err != nil {
+		log.Warnf("parse range %q error: %s", request.UrlMeta.Range, err)
+		return nil, nil, false
+	}
+	log.Infof("reuse range %s from peer task: %s, size: %d", request.UrlMeta.Range, reuse.PeerID, reuse.ContentLength)
+
+	ctx, span := tracer.Start(ctx, config.SpanStreamPeerTask, trace.WithSpanKind(trace.SpanKindClient))
+	span.SetAttributes(config.AttributePeerHost.String(ptm.host.Uuid))
+	span.SetAttributes(semconv.NetHostIPKey.String(ptm.host.Ip))
+	span.SetAttributes(config.AttributeTaskID.String(taskID))
+	span.SetAttributes(config.AttributePeerID.String(request.PeerId))
+	span.SetAttributes(config.AttributeReusePeerID.String(reuse.PeerID))
+	span.SetAttributes(semconv.HTTPURLKey.String(request.Url))
+	defer span.End()
+
+	r, c, err := ptm.storageManager.ReadPiece(ctx, &storage.ReadPieceRequest{
+		PeerTaskMetadata: reuse.PeerTaskMetadata,
+		PieceMetadata: storage.PieceMetadata{
+			Num:   -1,
+			Range: *rg,
+		},
+	})
+	if err != nil {
+		log.Errorf("read range error when reuse peer task: %s", err)
+		span.SetAttributes(config.AttributePeerTaskSuccess.Bool(false))
+		span.RecordError(err)
+		return nil, nil, false
+	}
+
+	attr := map[string]string{}
+	attr[headers.ContentLength] = fmt.Sprintf("%d", rg.Length)
+	attr[config.HeaderDragonflyTask] = taskID
+	attr[config.HeaderDragonflyPeer] = request.PeerId
+
+	span.SetAttributes(config.AttributePeerTaskSuccess.Bool(true))
+	return &rangeReadCloser{Reader: r, close: c.Close}, attr, true
+}
@@ -0,0 +1,446 @@
+/*
+ *     Copyright 2020 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package peer
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/go-http-utils/headers"
+	"github.com/pkg/errors"
+
+	"d7y.io/dragonfly/v2/client/clientutil"
+	"d7y.io/dragonfly/v2/client/config"
+	"d7y.io/dragonfly/v2/client/daemon/storage"
+	logger "d7y.io/dragonfly/v2/internal/dflog"
+	"d7y.io/dragonfly/v2/pkg/idgen"
+	"d7y.io/dragonfly/v2/pkg/rpc/scheduler"
+)
+
+// SeekableReader reads the content of a peer task at any offset, the pieces being read
+// are downloaded before the others, and the others continue in background.
+type SeekableReader interface {
+	io.ReaderAt
+	io.ReadSeekCloser
+	// Size returns the content length of the task, -1 means unknown before the task is done
+	Size() int64
+}
+
+type seekableReader struct {
+	*io.SectionReader
+	size  func() int64
+	close func()
+	once  sync.Once
+}
+
+var _ SeekableReader = (*seekableReader)(nil)
+
+func newSeekableReader(r io.ReaderAt, size func() int64, close func()) *seekableReader {
+	n := size()
+	if n < 0 {
+		n = math.MaxInt64
+	}
+	return &seekableReader{
+		SectionReader: io.NewSectionReader(r, 0, n),
+		size:          size,
+		close:         close,
+	}
+}
+
+func (r *seekableReader) Size() int64 {
+	return r.size()
+}
+
+func (r *seekableReader) Close() error {
+	r.once.Do(r.close)
+	return nil
+}
+
+// seekableStreamTask shares a stream peer task with all readers of the same task.
+type seekableStreamTask struct {
+	*streamPeerTask
+	ptm *peerTaskManager
+	id  string
+	// tiny is the content of tiny task, it is returned by scheduler without stream peer task
+	tiny []byte
+	// keep indicates downloading the rest of the task after all readers are closed
+	keep bool
+
+	// started is closed after the first piece is ready or the task failed to start
+	started  chan struct{}
+	startErr error
+	attr     map[string]string
+
+	lock sync.Mutex
+	// stop cancels the stream peer task, it is set before the task starts
+	stop context.CancelFunc
+	// ready is closed and replaced when pieces are ready or the task is finished
+	ready    chan struct{}
+	finished bool
+	err      error
+	readers  int
+}
+
+// StartSeekableStreamPeerTask starts a stream peer task or joins the running one of the same task,
+// the returned reader can be read at any offset.
+func (ptm *peerTaskManager) StartSeekableStreamPeerTask(ctx context.Context, req *scheduler.PeerTaskRequest) (SeekableReader, map[string]string, error) {
+	taskID := idgen.TaskID(req.Url, req.UrlMeta)
+	if ptm.enableMultiplex {
+		if r, attr, ok := ptm.tryReuseSeekableStreamPeerTask(ctx, req, taskID); ok {
+			return r, attr, nil
+		}
+	}
+
+	ptm.seekableLock.Lock()
+	t, ok := ptm.seekableTasks[taskID]
+	if !ok {
+		t = &seekableStreamTask{
+			ptm:     ptm,
+			id:      taskID,
+			keep:    ptm.enablePrefetch,
+			started: make(chan struct{}),
+			ready:   make(chan struct{}),
+		}
+		ptm.seekableTasks[taskID] = t
+	}
+	t.lock.Lock()
+	t.readers++
+	t.lock.Unlock()
+	ptm.seekableLock.Unlock()
+
+	if !ok {
+		go t.start(req)
+	}
+	select {
+	case <-t.started:
+	case <-ctx.Done():
+		t.release()
+		return nil, nil, ctx.Err()
+	}
+	t.lock.Lock()
+	attr, err := t.attr, t.startErr
+	t.lock.Unlock()
+	if err != nil {
+		t.release()
+		return nil, attr, err
+	}
+
+	r := &taskReaderAt{task: t, ctx: ctx}
+	return newSeekableReader(r, t.size, t.release), attr, nil
+}
+
+// isSeekableStreamPeerTaskRunning returns whether the seekable stream peer task is running.
+func (ptm *peerTaskManager) isSeekableStreamPeerTaskRunning(taskID string) bool {
+	ptm.seekableLock.Lock()
+	defer ptm.seekableLock.Unlock()
+	_, ok := ptm.seekableTasks[taskID]
+	return ok
+}
+
+func (ptm *peerTaskManager) tryReuseSeekableStreamPeerTask(ctx context.Context,
+	req *scheduler.PeerTaskRequest, taskID string) (SeekableReader, map[string]string, bool) {
+	reuse := ptm.storageManager.FindCompletedTask(taskID)
+	if reuse == nil {
+		return nil, nil, false
+	}
+	logger.With("peer", req.PeerId, "task", taskID, "component", "reuseSeekableStreamPeerTask").
+		Infof("reuse from peer task: %s, size: %d", reuse.PeerID, reuse.ContentLength)
+
+	attr := map[string]string{}
+	attr[headers.ContentLength] = fmt.Sprintf("%d", reuse.ContentLength)
+	attr[config.HeaderDragonflyTask] = taskID
+	attr[config.HeaderDragonflyPeer] = req.PeerId
+	r := &storageReaderAt{ptm: ptm, ctx: ctx, meta: reuse.PeerTaskMetadata}
+	size := func() int64 { return reuse.ContentLength }
+	return newSeekableReader(r, size, func() {}), attr, true
+}
+
+// start starts the stream peer task and watches its pieces, the caller must be the first reader.
+func (t *seekableStreamTask) start(req *scheduler.PeerTaskRequest) {
+	defer close(t.started)
+
+	start := time.Now()
+	// the task is shared by readers, it is canceled by the last reader
+	ctx, pt, tiny, err := newStreamPeerTask(context.Background(), t.ptm, req)
+	if err != nil {
+		t.lock.Lock()
+		t.startErr = err
+		t.lock.Unlock()
+		t.forget()
+		return
+	}
+	if tiny != nil {
+		// tiny task is stored in local storage, read it as a completed task
+		t.ptm.storeTinyPeerTask(ctx, tiny)
+		tiny.span.End()
+		t.lock.Lock()
+		t.attr = map[string]string{
+			headers.ContentLength:      fmt.Sprintf("%d", len(tiny.Content)),
+			config.HeaderDragonflyTask: tiny.TaskID,
+			config.HeaderDragonflyPeer: tiny.PeerID,
+		}
+		t.tiny = tiny.Content
+		t.lock.Unlock()
+		t.finish(nil)
+		return
+	}
+
+	pt.SetCallback(
+		&streamPeerTaskCallback{
+			ptm:   t.ptm,
+			pt:    pt,
+			req:   req,
+			start: start,
+		})
+	t.ptm.runningPeerTasks.Store(req.PeerId, pt)
+
+	ctx, stop := context.WithCancel(ctx)
+	t.lock.Lock()
+	t.stop = stop
+	// all readers gave up before the task starts
+	abandoned := t.readers == 0 && !t.keep
+	t.lock.Unlock()
+	if abandoned {
+		pt.Infof("all readers are closed before started, cancel seekable stream peer task")
+		stop()
+	}
+
+	_, attr, err := pt.start(ctx)
+	t.lock.Lock()
+	t.streamPeerTask, t.attr, t.startErr = pt, attr, err
+	t.lock.Unlock()
+	if err != nil {
+		stop()
+		t.forget()
+		return
+	}
+	go t.watchPieces()
+}
+
+// watchPieces wakes up the readers when pieces are ready.
+func (t *seekableStreamTask) watchPieces() {
+	defer func() {
+		t.cancel()
+		t.stop()
+		t.span.End()
+	}()
+	for {
+		select {
+		case <-t.successPieceCh:
+			t.notify()
+		case <-t.streamDone:
+			var err error
+			if t.success {
+				err = t.callback.ValidateDigest(t)
+			} else if t.failedReason != failedReasonNotSet {
+				err = errors.New(t.failedReason)
+			} else {
+				err = errors.New("stream peer task failed")
+			}
+			if err != nil {
+				t.Errorf("seekable stream peer task failed: %s", err)
+			}
+			t.finish(err)
+			return
+		}
+	}
+}
+
+func (t *seekableStreamTask) notify() {
+	t.lock.Lock()
+	close(t.ready)
+	t.ready = make(chan struct{})
+	t.lock.Unlock()
+}
+
+func (t *seekableStreamTask) finish(err error) {
+	t.lock.Lock()
+	t.finished, t.err = true, err
+	close(t.ready)
+	t.lock.Unlock()
+
+	// later readers reuse the completed task in storage or start a new one
+	t.forget()
+}
+
+// forget removes the task from running seekable tasks, later readers start a new one.
+func (t *seekableStreamTask) forget() {
+	t.ptm.seekableLock.Lock()
+	defer t.ptm.seekableLock.Unlock()
+	if t.ptm.seekableTasks[t.id] == t {
+		delete(t.ptm.seekableTasks, t.id)
+	}
+}
+
+// release is called when a reader is closed, the task is canceled after the last reader is closed
+// unless it is kept downloading. the task which is not started yet is canceled by start.
+func (t *seekableStreamTask) release() {
+	t.ptm.seekableLock.Lock()
+	t.lock.Lock()
+	t.readers--
+	stop := t.stop
+	last := t.readers == 0 && !t.finished && (!t.keep || t.startErr != nil)
+	t.lock.Unlock()
+	if last && t.ptm.seekableTasks[t.id] == t {
+		delete(t.ptm.seekableTasks, t.id)
+	}
+	t.ptm.seekableLock.Unlock()
+
+	if last && stop != nil {
+		logger.With("task", t.id).Infof("all readers are closed, cancel seekable stream peer task")
+		stop()
+	}
+}
+
+// size returns the content length of the task, -1 means unknown.
+func (t *seekableStreamTask) size() int64 {
+	if t.tiny != nil {
+		return int64(len(t.tiny))
+	}
+	return t.GetContentLength()
+}
+
+// waitPieces waits the pieces from first to last are ready, it returns nil when the task is done
+// and the pieces are out of the content.
+func (t *seekableStreamTask) waitPieces(ctx context.Context, first, last int32) error {
+	for {
+		t.lock.Lock()
+		ready, finished, err := t.ready, t.finished, t.err
+		t.lock.Unlock()
+
+		missing := t.missingPiece(first, last)
+		if missing < 0 {
+			return nil
+		}
+		if finished {
+			if err != nil {
+				return err
+			}
+			if total := t.GetTotalPieces(); total >= 0 && missing >= total {
+				return nil
+			}
+			return errors.Errorf("piece %d is not downloaded", missing)
+		}
+
+		select {
+		case t.priorityPieceCh <- missing:
+		default:
+		}
+		select {
+		case <-ready:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// missingPiece returns the first piece not ready from first to last, -1 means all are ready.
+func (t *seekableStreamTask) missingPiece(first, last int32) int32 {
+	if t.tiny != nil {
+		return -1
+	}
+	t.peerTask.lock.RLock()
+	defer t.peerTask.lock.RUnlock()
+	for i := first; i <= last; i++ {
+		if !t.readyPieces.IsSet(i) {
+			return i
+		}
+	}
+	return -1
+}
+
+// taskReaderAt reads the pieces of seekable stream peer task after they are ready.
+type taskReaderAt struct {
+	task *seekableStreamTask
+	ctx  context.Context
+}
+
+func (r *taskReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	t := r.task
+	if len(p) == 0 {
+		return 0, nil
+	}
+	if size := t.size(); size >= 0 && off >= size {
+		return 0, io.EOF
+	}
+
+	end := off + int64(len(p))
+	if size := t.size(); size >= 0 && end > size {
+		end = size
+	}
+	if t.tiny == nil {
+		pieceSize := int64(t.pieceSize.Load())
+		if err := t.waitPieces(r.ctx, int32(off/pieceSize), int32((end-1)/pieceSize)); err != nil {
+			return 0, err
+		}
+	}
+
+	// the content length is known after the task is done when it is unknown at first
+	size := t.size()
+	if size >= 0 && off >= size {
+		return 0, io.EOF
+	}
+	if size >= 0 && end > size {
+		end = size
+	}
+
+	var n int
+	if t.tiny != nil {
+		n = copy(p, t.tiny[off:end])
+	} else {
+		sr := &storageReaderAt{ptm: t.ptm, ctx: r.ctx, meta: storage.PeerTaskMetadata{PeerID: t.peerID, TaskID: t.taskID}}
+		var err error
+		if n, err = sr.ReadAt(p[:end-off], off); err != nil {
+			return n, err
+		}
+	}
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+// storageReaderAt reads the peer task in local storage.
+type storageReaderAt struct {
+	ptm  *peerTaskManager
+	ctx  context.Context
+	meta storage.PeerTaskMetadata
+}
+
+func (r *storageReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	rd, c, err := r.ptm.storageManager.ReadPiece(r.ctx, &storage.ReadPieceRequest{
+		PeerTaskMetadata: r.meta,
+		PieceMetadata: storage.PieceMetadata{
+			Num:   -1,
+			Range: clientutil.Range{Start: off, Length: int64(len(p))},
+		},
+	})
+	if err != nil {
+		return 0, err
+	}
+	defer c.Close()
+
+	n, err := io.ReadFull(rd, p)
+	if err == io.ErrUnexpectedEOF {
+		err = io.EOF
+	}
+	return n, err
+}
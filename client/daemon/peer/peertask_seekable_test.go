@@ -0,0 +1,232 @@
+/*
+ *     Copyright 2020 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package peer
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	testifyassert "github.com/stretchr/testify/assert"
+
+	"d7y.io/dragonfly/v2/client/clientutil"
+	"d7y.io/dragonfly/v2/client/config"
+	"d7y.io/dragonfly/v2/client/daemon/test"
+	logger "d7y.io/dragonfly/v2/internal/dflog"
+	"d7y.io/dragonfly/v2/pkg/idgen"
+	"d7y.io/dragonfly/v2/pkg/rpc/base"
+	"d7y.io/dragonfly/v2/pkg/rpc/scheduler"
+)
+
+func TestPeerTaskManager_StartSeekableStreamPeerTask(t *testing.T) {
+	assert := testifyassert.New(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	testBytes, err := os.ReadFile(test.File)
+	assert.Nil(err, "load test file")
+
+	var (
+		url       = "http://localhost/test/data"
+		meta      = &base.UrlMeta{Tag: "d7y-test"}
+		taskID    = idgen.TaskID(url, meta)
+		pieceSize = 1024
+	)
+	sched, storageManager := setupPeerTaskManagerComponents(
+		ctrl,
+		componentsOption{
+			taskID:             taskID,
+			contentLength:      int64(len(testBytes)),
+			pieceSize:          uint32(pieceSize),
+			pieceParallelCount: 4,
+		})
+	defer storageManager.CleanUp()
+
+	downloader := NewMockPieceDownloader(ctrl)
+	downloader.EXPECT().DownloadPiece(gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(
+		func(ctx context.Context, task *DownloadPieceRequest) (io.Reader, io.Closer, error) {
+			rc := io.NopCloser(
+				bytes.NewBuffer(
+					testBytes[task.piece.RangeStart : task.piece.RangeStart+uint64(task.piece.RangeSize)],
+				))
+			return rc, rc, nil
+		})
+
+	ptm := &peerTaskManager{
+		host: &scheduler.PeerHost{
+			Ip: "127.0.0.1",
+		},
+		runningPeerTasks: sync.Map{},
+		pieceManager: &pieceManager{
+			storageManager:  storageManager,
+			pieceDownloader: downloader,
+		},
+		storageManager:  storageManager,
+		schedulerClient: sched,
+		schedulerOption: config.SchedulerOption{
+			ScheduleTimeout: clientutil.Duration{Duration: 10 * time.Minute},
+		},
+		enableMultiplex: true,
+		// the task is completed even if the reader is closed before the task is done
+		enablePrefetch: true,
+		seekableTasks:  map[string]*seekableStreamTask{},
+	}
+
+	r, attr, err := ptm.StartSeekableStreamPeerTask(context.Background(), &scheduler.PeerTaskRequest{
+		Url:      url,
+		UrlMeta:  meta,
+		PeerId:   "peer-0",
+		PeerHost: &scheduler.PeerHost{},
+	})
+	assert.Nil(err, "start seekable stream peer task")
+	assert.Equal(taskID, attr[config.HeaderDragonflyTask])
+	assert.Equal(int64(len(testBytes)), r.Size())
+
+	// read the tail first, it crosses the last two pieces
+	buf := make([]byte, 2000)
+	off := int64(len(testBytes) - 1500)
+	n, err := r.ReadAt(buf, off)
+	assert.Equal(io.EOF, err)
+	assert.Equal(1500, n)
+	assert.Equal(testBytes[off:], buf[:n])
+
+	// read the middle
+	n, err = r.ReadAt(buf[:100], 3000)
+	assert.Nil(err)
+	assert.Equal(100, n)
+	assert.Equal(testBytes[3000:3100], buf[:n])
+
+	// seek and read the rest
+	_, err = r.Seek(100, io.SeekStart)
+	assert.Nil(err)
+	data, err := io.ReadAll(r)
+	assert.Nil(err)
+	assert.Equal(testBytes[100:], data)
+	assert.Nil(r.Close())
+
+	assert.Eventually(func() bool {
+		return !ptm.isSeekableStreamPeerTaskRunning(taskID) && storageManager.FindCompletedTask(taskID) != nil
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// the completed task is read from local storage
+	r, _, err = ptm.StartSeekableStreamPeerTask(context.Background(), &scheduler.PeerTaskRequest{
+		Url:      url,
+		UrlMeta:  meta,
+		PeerId:   "peer-1",
+		PeerHost: &scheduler.PeerHost{},
+	})
+	assert.Nil(err, "start seekable stream peer task")
+	n, err = r.ReadAt(buf[:100], 5000)
+	assert.Nil(err)
+	assert.Equal(testBytes[5000:5100], buf[:n])
+	assert.Nil(r.Close())
+}
+
+func TestPeerTaskManager_StartSeekableStreamPeerTask_Abandoned(t *testing.T) {
+	assert := testifyassert.New(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var (
+		url    = "http://localhost/test/abandoned"
+		meta   = &base.UrlMeta{Tag: "d7y-test"}
+		taskID = idgen.TaskID(url, meta)
+	)
+	sched, storageManager := setupPeerTaskManagerComponents(
+		ctrl,
+		componentsOption{
+			taskID:             taskID,
+			contentLength:      4096,
+			pieceSize:          1024,
+			pieceParallelCount: 4,
+			peerPacketDelay:    []time.Duration{time.Second},
+		})
+	defer storageManager.CleanUp()
+
+	downloader := NewMockPieceDownloader(ctrl)
+	downloader.EXPECT().DownloadPiece(gomock.Any(), gomock.Any()).AnyTimes().Return(nil, nil, context.Canceled)
+
+	ptm := &peerTaskManager{
+		host: &scheduler.PeerHost{
+			Ip: "127.0.0.1",
+		},
+		runningPeerTasks: sync.Map{},
+		pieceManager: &pieceManager{
+			storageManager:  storageManager,
+			pieceDownloader: downloader,
+		},
+		storageManager:  storageManager,
+		schedulerClient: sched,
+		schedulerOption: config.SchedulerOption{
+			ScheduleTimeout: clientutil.Duration{Duration: 10 * time.Minute},
+		},
+		enableMultiplex: true,
+		seekableTasks:   map[string]*seekableStreamTask{},
+	}
+
+	// the only reader gives up before the first piece is ready
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, _, err := ptm.StartSeekableStreamPeerTask(ctx, &scheduler.PeerTaskRequest{
+		Url:      url,
+		UrlMeta:  meta,
+		PeerId:   "peer-0",
+		PeerHost: &scheduler.PeerHost{},
+	})
+	assert.Equal(context.DeadlineExceeded, err)
+	assert.False(ptm.isSeekableStreamPeerTaskRunning(taskID))
+
+	// the task is canceled without readers
+	assert.Eventually(func() bool {
+		return !ptm.IsPeerTaskRunning("peer-0")
+	}, 500*time.Millisecond, 10*time.Millisecond)
+}
+
+func TestPeerTask_DispatchPriorityPieceRequest(t *testing.T) {
+	assert := testifyassert.New(t)
+
+	pt := &peerTask{
+		SugaredLoggerOnWith: logger.With("test", t.Name()),
+		taskID:              "task-0",
+		peerID:              "peer-0",
+		done:                make(chan struct{}),
+		requestedPieces:     NewBitmap(),
+	}
+	pt.ctx, pt.cancel = context.WithCancel(context.Background())
+	defer pt.cancel()
+
+	var pieces []*base.PieceInfo
+	for i := int32(0); i < 4; i++ {
+		pieces = append(pieces, &base.PieceInfo{PieceNum: i + 8})
+	}
+	requestCh := make(chan *DownloadPieceRequest, len(pieces))
+	priorityRequestCh := make(chan *DownloadPieceRequest, len(pieces))
+	pt.dispatchPieceRequest(requestCh, priorityRequestCh, 8, &base.PiecePacket{PieceInfos: pieces})
+
+	assert.Len(priorityRequestCh, 1)
+	assert.Equal(int32(8), (<-priorityRequestCh).piece.PieceNum)
+	assert.Len(requestCh, 3)
+	for i := int32(9); i < 12; i++ {
+		assert.Equal(i, (<-requestCh).piece.PieceNum)
+		assert.True(pt.requestedPieces.IsSet(i))
+	}
+}
@@ -0,0 +1,97 @@
+/*
+ *     Copyright 2020 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package peer
+
+import (
+	"strings"
+
+	"d7y.io/dragonfly/v2/pkg/rpc/scheduler"
+)
+
+// where the content of peer task comes from
+const (
+	PeerTaskSourceP2P        = "p2p"
+	PeerTaskSourceCDN        = "cdn"
+	PeerTaskSourceBackSource = "back_source"
+	PeerTaskSourceLocal      = "local"
+)
+
+// cdnPeerIDSuffix is the suffix of cdn peer id, see idgen.CDNPeerID
+const cdnPeerIDSuffix = "_CDN"
+
+// PeerTaskStat is the statistics of peer task for progress report
+type PeerTaskStat struct {
+	TotalPieces    int32
+	FinishedPieces int32
+	// Source is where the content comes from, one of PeerTaskSourceXXX
+	Source string
+	// Parents are the peer ids of current parents
+	Parents []string
+	// PeerTraffic, CDNTraffic and SourceTraffic split the used traffic by where the content comes from
+	PeerTraffic   uint64
+	CDNTraffic    uint64
+	SourceTraffic uint64
+	PieceMd5Sign  string
+}
+
+func isCDNPeer(peerID string) bool {
+	return strings.HasSuffix(peerID, cdnPeerIDSuffix)
+}
+
+// addPieceTraffic records the traffic of a piece downloaded from parent
+func (pt *peerTask) addPieceTraffic(result *scheduler.PieceResult, size uint64) {
+	if result.DstPid == "" {
+		return
+	}
+	if isCDNPeer(result.DstPid) {
+		pt.cdnTraffic.Add(size)
+		return
+	}
+	pt.peerTraffic.Add(size)
+}
+
+// stat collects the statistics of peer task, when back source, the traffic which is not from parents is from source
+func (pt *peerTask) stat() *PeerTaskStat {
+	stat := &PeerTaskStat{
+		TotalPieces:    pt.GetTotalPieces(),
+		FinishedPieces: pt.readyPieces.Settled(),
+		Source:         PeerTaskSourceP2P,
+		PeerTraffic:    pt.peerTraffic.Load(),
+		CDNTraffic:     pt.cdnTraffic.Load(),
+		PieceMd5Sign:   pt.GetPieceMd5Sign(),
+	}
+	if pt.needBackSource {
+		stat.Source = PeerTaskSourceBackSource
+		if used := pt.GetTraffic(); used > stat.PeerTraffic+stat.CDNTraffic {
+			stat.SourceTraffic = used - stat.PeerTraffic - stat.CDNTraffic
+		}
+		return stat
+	}
+
+	if packet, ok := pt.peerPacket.Load().(*scheduler.PeerPacket); ok && packet != nil {
+		if packet.MainPeer != nil {
+			stat.Parents = append(stat.Parents, packet.MainPeer.PeerId)
+			if isCDNPeer(packet.MainPeer.PeerId) {
+				stat.Source = PeerTaskSourceCDN
+			}
+		}
+		for _, peer := range packet.StealPeers {
+			stat.Parents = append(stat.Parents, peer.PeerId)
+		}
+	}
+	return stat
+}
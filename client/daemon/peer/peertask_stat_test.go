@@ -0,0 +1,67 @@
+/*
+ *     Copyright 2020 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package peer
+
+import (
+	"testing"
+
+	testifyassert "github.com/stretchr/testify/assert"
+	"go.uber.org/atomic"
+
+	"d7y.io/dragonfly/v2/pkg/idgen"
+	"d7y.io/dragonfly/v2/pkg/rpc/scheduler"
+)
+
+func TestPeerTask_Stat(t *testing.T) {
+	assert := testifyassert.New(t)
+	cdnPeerID := idgen.CDNPeerID("127.0.0.1")
+
+	pt := &peerTask{
+		totalPiece:  3,
+		readyPieces: NewBitmap(),
+		usedTraffic: atomic.NewUint64(0),
+	}
+	pt.peerPacket.Store(&scheduler.PeerPacket{
+		MainPeer:   &scheduler.PeerPacket_DestPeer{PeerId: cdnPeerID},
+		StealPeers: []*scheduler.PeerPacket_DestPeer{{PeerId: "peer-1"}},
+	})
+
+	for i, dst := range []string{cdnPeerID, "peer-1"} {
+		pt.readyPieces.Set(int32(i))
+		pt.AddTraffic(100)
+		pt.addPieceTraffic(&scheduler.PieceResult{DstPid: dst}, 100)
+	}
+	// traffic of failed piece is not in the split
+	pt.AddTraffic(10)
+
+	stat := pt.stat()
+	assert.Equal(int32(3), stat.TotalPieces)
+	assert.Equal(int32(2), stat.FinishedPieces)
+	assert.Equal(PeerTaskSourceCDN, stat.Source)
+	assert.Equal([]string{cdnPeerID, "peer-1"}, stat.Parents)
+	assert.Equal(uint64(100), stat.CDNTraffic)
+	assert.Equal(uint64(100), stat.PeerTraffic)
+	assert.Equal(uint64(0), stat.SourceTraffic)
+
+	// the rest traffic is from source after back source
+	pt.needBackSource = true
+	pt.AddTraffic(90)
+	stat = pt.stat()
+	assert.Equal(PeerTaskSourceBackSource, stat.Source)
+	assert.Empty(stat.Parents)
+	assert.Equal(uint64(100), stat.SourceTraffic)
+}
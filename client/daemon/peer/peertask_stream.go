@@ -140,8 +140,8 @@ func newStreamPeerTask(ctx context.Context,
 		return ctx, nil, nil, err
 	}
 	var limiter *rate.Limiter
-	if ptm.perPeerRateLimit > 0 {
-		limiter = rate.NewLimiter(ptm.perPeerRateLimit, int(ptm.perPeerRateLimit))
+	if limit := ptm.getPerPeerRateLimit(); limit > 0 {
+		limiter = rate.NewLimiter(limit, int(limit))
 	}
 	pt := &streamPeerTask{
 		successPieceCh: make(chan int32),
@@ -161,6 +161,7 @@ func newStreamPeerTask(ctx context.Context,
 			span:                span,
 			readyPieces:         NewBitmap(),
 			requestedPieces:     NewBitmap(),
+			priorityPieceCh:     make(chan int32, config.DefaultPieceChanSize),
 			failedPieceCh:       make(chan int32, config.DefaultPieceChanSize),
 			failedReason:        failedReasonNotSet,
 			failedCode:          base.Code_UnknownError,
@@ -168,6 +169,7 @@ func newStreamPeerTask(ctx context.Context,
 			pieceParallelCount:  atomic.NewInt32(0),
 			totalPiece:          -1,
 			getPiecesMaxRetry:   ptm.getPiecesMaxRetry,
+			pieceVerifier:       ptm.pieceVerifier,
 			schedulerOption:     ptm.schedulerOption,
 			schedulerClient:     ptm.schedulerClient,
 			limiter:             limiter,
@@ -176,6 +178,7 @@ func newStreamPeerTask(ctx context.Context,
 			SugaredLoggerOnWith: logger.With("peer", request.PeerId, "task", result.TaskId, "component", "streamPeerTask"),
 		},
 	}
+	ptm.setPieceSelection(ctx, &pt.peerTask)
 	// bind func that base peer task did not implement
 	pt.backSourceFunc = pt.backSource
 	pt.setContentLengthFunc = pt.SetContentLength
@@ -188,6 +191,10 @@ func (s *streamPeerTask) ReportPieceResult(result *pieceTaskResult) error {
 	defer s.recoverFromPanic()
 	// retry failed piece
 	if !result.pieceResult.Success {
+		if s.isPieceReady(result.piece.PieceNum) {
+			s.Debugf("piece %d is downloaded from other parent, skip failed result", result.piece.PieceNum)
+			return nil
+		}
 		result.pieceResult.FinishedCount = s.readyPieces.Settled()
 		_ = s.peerPacketStream.Send(result.pieceResult)
 		if result.notRetry {
@@ -213,7 +220,9 @@ func (s *streamPeerTask) ReportPieceResult(result *pieceTaskResult) error {
 	}
 	// mark piece processed
 	s.readyPieces.Set(result.pieceResult.PieceInfo.PieceNum)
+	s.recordPieceSize(result.piece)
 	s.completedLength.Add(int64(result.piece.RangeSize))
+	s.addPieceTraffic(result.pieceResult, uint64(result.piece.RangeSize))
 	s.lock.Unlock()
 
 	result.pieceResult.FinishedCount = s.readyPieces.Settled()
@@ -235,6 +244,20 @@ func (s *streamPeerTask) ReportPieceResult(result *pieceTaskResult) error {
 }
 
 func (s *streamPeerTask) Start(ctx context.Context) (io.ReadCloser, map[string]string, error) {
+	firstPiece, attr, err := s.start(ctx)
+	if err != nil {
+		return nil, attr, err
+	}
+
+	pr, pw := io.Pipe()
+	var readCloser io.ReadCloser = pr
+	go s.writeToPipe(firstPiece, pw)
+
+	return readCloser, attr, nil
+}
+
+// start starts to download pieces and waits the first piece
+func (s *streamPeerTask) start(ctx context.Context) (int32, map[string]string, error) {
 	s.ctx, s.cancel = context.WithCancel(ctx)
 	if s.needBackSource {
 		go s.backSource()
@@ -258,7 +281,7 @@ func (s *streamPeerTask) Start(ctx context.Context) (io.ReadCloser, map[string]s
 		attr := map[string]string{}
 		attr[config.HeaderDragonflyTask] = s.taskID
 		attr[config.HeaderDragonflyPeer] = s.peerID
-		return nil, attr, err
+		return -1, attr, err
 	case <-s.done:
 		var err error
 		if s.failedReason != failedReasonNotSet {
@@ -272,7 +295,7 @@ func (s *streamPeerTask) Start(ctx context.Context) (io.ReadCloser, map[string]s
 		attr := map[string]string{}
 		attr[config.HeaderDragonflyTask] = s.taskID
 		attr[config.HeaderDragonflyPeer] = s.peerID
-		return nil, attr, err
+		return -1, attr, err
 	case first := <-s.successPieceCh:
 		firstPiece = first
 	}
@@ -285,12 +308,7 @@ func (s *streamPeerTask) Start(ctx context.Context) (io.ReadCloser, map[string]s
 	}
 	attr[config.HeaderDragonflyTask] = s.taskID
 	attr[config.HeaderDragonflyPeer] = s.peerID
-
-	pr, pw := io.Pipe()
-	var readCloser io.ReadCloser = pr
-	go s.writeToPipe(firstPiece, pw)
-
-	return readCloser, attr, nil
+	return firstPiece, attr, nil
 }
 
 func (s *streamPeerTask) finish() error {
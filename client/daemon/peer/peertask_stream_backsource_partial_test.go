@@ -34,6 +34,8 @@ import (
 	testifyassert "github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	"d7y.io/dragonfly/v2/client/clientutil"
 	"d7y.io/dragonfly/v2/client/config"
@@ -92,6 +94,8 @@ func setupBackSourcePartialComponents(ctrl *gomock.Controller, testBytes []byte,
 			TotalPiece:    pieceCount,
 		}, nil
 	})
+	// dest peer does not support pushing pieces, pieces are polled
+	daemon.EXPECT().SyncPieceTasks(gomock.Any(), gomock.Any()).AnyTimes().Return(status.Error(codes.Unimplemented, "unimplemented"))
 	ln, _ := rpc.Listen(dfnet.NetAddr{
 		Type: "tcp",
 		Addr: fmt.Sprintf("0.0.0.0:%d", port),
@@ -52,6 +52,8 @@ func (p *streamPeerTaskCallback) Init(pt Task) error {
 			ContentLength: pt.GetContentLength(),
 			TotalPieces:   pt.GetTotalPieces(),
 			PieceMd5Sign:  pt.GetPieceMd5Sign(),
+			URL:           p.req.Url,
+			URLMeta:       p.req.UrlMeta,
 		})
 	if err != nil {
 		pt.Log().Errorf("register task to storage manager failed: %s", err)
@@ -60,6 +62,7 @@ func (p *streamPeerTaskCallback) Init(pt Task) error {
 }
 
 func (p *streamPeerTaskCallback) Update(pt Task) error {
+	root, signature := pt.GetPieceDigestRoot()
 	// update storage
 	err := p.ptm.storageManager.UpdateTask(p.pt.ctx,
 		&storage.UpdateTaskRequest{
@@ -67,9 +70,11 @@ func (p *streamPeerTaskCallback) Update(pt Task) error {
 				PeerID: pt.GetPeerID(),
 				TaskID: pt.GetTaskID(),
 			},
-			ContentLength: pt.GetContentLength(),
-			TotalPieces:   pt.GetTotalPieces(),
-			PieceMd5Sign:  pt.GetPieceMd5Sign(),
+			ContentLength:        pt.GetContentLength(),
+			TotalPieces:          pt.GetTotalPieces(),
+			PieceMd5Sign:         pt.GetPieceMd5Sign(),
+			PieceDigestRoot:      root,
+			PieceDigestSignature: signature,
 		})
 	if err != nil {
 		pt.Log().Errorf("update task to storage manager failed: %s", err)
@@ -16,7 +16,15 @@
 
 package peer
 
-import "testing"
+import (
+	"bytes"
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"d7y.io/dragonfly/v2/pkg/signature"
+)
 
 func TestBitmap_Sets(t *testing.T) {
 	b := NewBitmap()
@@ -26,3 +34,33 @@ func TestBitmap_Sets(t *testing.T) {
 	b.Sets(2, 3, 3, 4)
 	//t.Logf("%s, %d", b.String(), b.Settled())
 }
+
+func TestPeerTask_acceptPieceDigestRoot(t *testing.T) {
+	signer, err := signature.NewSigner(base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{1}, 32)))
+	assert.Nil(t, err)
+
+	// without verifier, the first root is accepted
+	pt := &peerTask{taskID: "task"}
+	assert.True(t, pt.acceptPieceDigestRoot("", nil))
+	assert.True(t, pt.acceptPieceDigestRoot("root", nil))
+	assert.True(t, pt.acceptPieceDigestRoot("other", nil))
+	root, _ := pt.GetPieceDigestRoot()
+	assert.Equal(t, "root", root)
+
+	// with verifier, only the signed root is accepted
+	verifier := signature.NewVerifier()
+	assert.Nil(t, verifier.SetKey(signer.PublicKey()))
+	pt = &peerTask{taskID: "task", pieceVerifier: verifier}
+	assert.False(t, pt.acceptPieceDigestRoot("root", nil))
+	assert.False(t, pt.acceptPieceDigestRoot("root", signer.Sign("other", "root")))
+	// empty root can not downgrade the verification
+	assert.False(t, pt.acceptPieceDigestRoot("", nil))
+	assert.True(t, pt.acceptPieceDigestRoot("root", signer.Sign("task", "root")))
+	assert.True(t, pt.acceptPieceDigestRoot("root", nil))
+	// pieces are verified with the accepted root
+	assert.True(t, pt.acceptPieceDigestRoot("", nil))
+	assert.False(t, pt.acceptPieceDigestRoot("other", signer.Sign("task", "other")))
+	root, sig := pt.GetPieceDigestRoot()
+	assert.Equal(t, "root", root)
+	assert.Equal(t, signer.Sign("task", "root"), sig)
+}
@@ -25,6 +25,8 @@ import (
 	"strings"
 	"time"
 
+	"github.com/pkg/errors"
+
 	"d7y.io/dragonfly/v2/client/daemon/upload"
 	logger "d7y.io/dragonfly/v2/internal/dflog"
 	"d7y.io/dragonfly/v2/pkg/rpc/base"
@@ -32,12 +34,36 @@ import (
 )
 
 type DownloadPieceRequest struct {
-	TaskID     string
-	DstPid     string
-	DstAddr    string
+	TaskID  string
+	SrcPid  string
+	DstPid  string
+	DstAddr string
+	// DstRPCAddr is the grpc address of dst peer, it is used by grpc piece transport
+	DstRPCAddr string
 	CalcDigest bool
 	piece      *base.PieceInfo
-	log        *logger.SugaredLoggerOnWith
+	// digestRoot is the piece digest root of task, piece is verified with it when it is not empty
+	digestRoot string
+	// proofRequired is true when piece verifier is enabled, pieces without signed root or proof
+	// are rejected and piece content is always verified
+	proofRequired bool
+	totalPiece    int32
+	log           *logger.SugaredLoggerOnWith
+}
+
+// contentDigest returns the digest to verify the piece content with, the piece digest is preferred
+// when its algorithm is supported, otherwise the piece md5 is used.
+func (d *DownloadPieceRequest) contentDigest() string {
+	if d.hasPieceDigest() {
+		return d.piece.PieceDigest
+	}
+	return d.piece.PieceMd5
+}
+
+// hasPieceDigest returns whether the piece digest is present with a supported algorithm
+func (d *DownloadPieceRequest) hasPieceDigest() bool {
+	fields := digestutils.Parse(d.piece.PieceDigest)
+	return len(fields) == 2 && fields[1] != "" && digestutils.Algorithms[fields[0]] != ""
 }
 
 type PieceDownloader interface {
@@ -51,6 +77,9 @@ type pieceDownloader struct {
 
 var _ PieceDownloader = (*pieceDownloader)(nil)
 
+// errPeerBusy is returned when target peer is busy to upload
+var errPeerBusy = errors.New("peer is busy to upload")
+
 var defaultTransport http.RoundTripper = &http.Transport{
 	Proxy: http.ProxyFromEnvironment,
 	DialContext: (&net.Dialer{
@@ -99,6 +128,12 @@ func (p *pieceDownloader) DownloadPiece(ctx context.Context, d *DownloadPieceReq
 			d.TaskID, d.piece.PieceNum, d.DstAddr, err)
 		return nil, nil, err
 	}
+	// upload admission of target peer rejects the request
+	if resp.StatusCode == http.StatusServiceUnavailable {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+		return nil, nil, errPeerBusy
+	}
 	if resp.StatusCode > 299 {
 		_, _ = io.Copy(io.Discard, resp.Body)
 		_ = resp.Body.Close()
@@ -107,8 +142,9 @@ func (p *pieceDownloader) DownloadPiece(ctx context.Context, d *DownloadPieceReq
 	r := resp.Body.(io.Reader)
 	c := resp.Body.(io.Closer)
 	if d.CalcDigest {
-		d.log.Debugf("calculate digest for piece %d, md5: %s", d.piece.PieceNum, d.piece.PieceMd5)
-		r = digestutils.NewDigestReader(d.log, io.LimitReader(resp.Body, int64(d.piece.RangeSize)), d.piece.PieceMd5)
+		digest := d.contentDigest()
+		d.log.Debugf("calculate digest for piece %d, digest: %s", d.piece.PieceNum, digest)
+		r = digestutils.NewDigestReader(d.log, io.LimitReader(resp.Body, int64(d.piece.RangeSize)), digest)
 	}
 	return r, c, nil
 }
@@ -123,6 +159,10 @@ func buildDownloadPieceHTTPRequest(ctx context.Context, d *DownloadPieceRequest)
 	b.WriteString(d.TaskID)
 	b.Write([]byte("?peerId="))
 	b.WriteString(d.DstPid)
+	if d.SrcPid != "" {
+		b.WriteString("&" + upload.PeerDownloadHTTPSrcPeerQuery + "=")
+		b.WriteString(d.SrcPid)
+	}
 
 	u := b.String()
 	logger.Debugf("built request url: %s", u)
@@ -0,0 +1,320 @@
+/*
+ *     Copyright 2020 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package peer
+
+import (
+	"context"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/atomic"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	logger "d7y.io/dragonfly/v2/internal/dflog"
+	"d7y.io/dragonfly/v2/pkg/rpc/base"
+	"d7y.io/dragonfly/v2/pkg/rpc/base/common"
+	"d7y.io/dragonfly/v2/pkg/rpc/dfdaemon"
+	"d7y.io/dragonfly/v2/pkg/util/digestutils"
+)
+
+const (
+	// maxIdlePieceStreams is the max idle streams kept for one peer
+	maxIdlePieceStreams = 4
+	// pieceStreamIdleTimeout is the duration after which an idle stream is closed
+	pieceStreamIdleTimeout = 30 * time.Second
+)
+
+var errUnexpectedPieceData = errors.New("unexpected piece data")
+
+// GRPCPieceDownloader downloads pieces with the grpc streaming rpc of peers,
+// it downloads pieces with the fallback downloader when grpc transport is disabled
+// or the peer does not support it, like cdn and old daemons.
+type GRPCPieceDownloader interface {
+	PieceDownloader
+	// SetEnabled enables or disables grpc transport
+	SetEnabled(enabled bool)
+}
+
+type grpcPieceDownloader struct {
+	fallback    PieceDownloader
+	timeout     time.Duration
+	dialOptions []grpc.DialOption
+	enabled     *atomic.Bool
+
+	lock  sync.Mutex
+	conns map[string]*pieceConn
+	// unsupported holds addresses of peers without grpc piece transport
+	unsupported sync.Map
+}
+
+var _ GRPCPieceDownloader = (*grpcPieceDownloader)(nil)
+
+// pieceConn holds streams to one peer, streams are reused by pieces of any task
+type pieceConn struct {
+	conn   *grpc.ClientConn
+	active int
+	idle   []*pieceStream
+}
+
+type pieceStream struct {
+	addr   string
+	stream dfdaemon.Daemon_DownloadPiecesClient
+	cancel context.CancelFunc
+	// broken is set when stream is aborted, broken stream can not be reused
+	broken *atomic.Bool
+	timer  *time.Timer
+}
+
+func NewGRPCPieceDownloader(fallback PieceDownloader, timeout time.Duration, opts ...grpc.DialOption) GRPCPieceDownloader {
+	return &grpcPieceDownloader{
+		fallback:    fallback,
+		timeout:     timeout,
+		dialOptions: opts,
+		enabled:     atomic.NewBool(false),
+		conns:       map[string]*pieceConn{},
+	}
+}
+
+func (d *grpcPieceDownloader) SetEnabled(enabled bool) {
+	if d.enabled.Swap(enabled) != enabled {
+		logger.Infof("grpc piece transport enabled: %t", enabled)
+	}
+}
+
+func (d *grpcPieceDownloader) DownloadPiece(ctx context.Context, request *DownloadPieceRequest) (io.Reader, io.Closer, error) {
+	addr := request.DstRPCAddr
+	if !d.enabled.Load() || addr == "" || strings.HasSuffix(request.DstPid, common.CdnSuffix) {
+		return d.fallback.DownloadPiece(ctx, request)
+	}
+	if _, ok := d.unsupported.Load(addr); ok {
+		return d.fallback.DownloadPiece(ctx, request)
+	}
+
+	s, err := d.acquire(addr)
+	if err != nil {
+		return nil, nil, err
+	}
+	pieceCtx, cancel := context.WithTimeout(ctx, d.timeout)
+	r := &grpcPieceReader{
+		downloader: d,
+		stream:     s,
+		pieceNum:   request.piece.PieceNum,
+		cancel:     cancel,
+		stop:       make(chan struct{}),
+		exited:     make(chan struct{}),
+	}
+	// abort the stream when the piece is canceled or timeout
+	go func() {
+		defer close(r.exited)
+		select {
+		case <-pieceCtx.Done():
+			s.broken.Store(true)
+			s.cancel()
+		case <-r.stop:
+		}
+	}()
+
+	if err = s.stream.Send(&dfdaemon.PieceDownloadRequest{
+		TaskId:     request.TaskID,
+		SrcPid:     request.SrcPid,
+		DstPid:     request.DstPid,
+		PieceNum:   request.piece.PieceNum,
+		RangeStart: request.piece.RangeStart,
+		RangeSize:  request.piece.RangeSize,
+	}); err != nil {
+		r.Close()
+		return nil, nil, err
+	}
+	// receive the first chunk for detecting peers without grpc piece transport
+	if err = r.fill(); err != nil {
+		r.Close()
+		if status.Code(err) == codes.Unimplemented {
+			logger.Infof("peer %s does not support grpc piece transport, use fallback", addr)
+			d.unsupported.Store(addr, struct{}{})
+			return d.fallback.DownloadPiece(ctx, request)
+		}
+		if isPeerBusy(err) {
+			return nil, nil, errPeerBusy
+		}
+		return nil, nil, err
+	}
+
+	var reader io.Reader = r
+	if request.CalcDigest {
+		digest := request.contentDigest()
+		request.log.Debugf("calculate digest for piece %d, digest: %s", request.piece.PieceNum, digest)
+		reader = digestutils.NewDigestReader(request.log, io.LimitReader(r, int64(request.piece.RangeSize)), digest)
+	}
+	return reader, r, nil
+}
+
+// acquire returns an idle stream to the peer or creates a new one
+func (d *grpcPieceDownloader) acquire(addr string) (*pieceStream, error) {
+	d.lock.Lock()
+	pc, ok := d.conns[addr]
+	if !ok {
+		conn, err := grpc.Dial(addr, d.dialOptions...)
+		if err != nil {
+			d.lock.Unlock()
+			return nil, err
+		}
+		pc = &pieceConn{conn: conn}
+		d.conns[addr] = pc
+	}
+	pc.active++
+	if n := len(pc.idle); n > 0 {
+		s := pc.idle[n-1]
+		pc.idle = pc.idle[:n-1]
+		d.lock.Unlock()
+		s.timer.Stop()
+		return s, nil
+	}
+	d.lock.Unlock()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stream, err := dfdaemon.NewDaemonClient(pc.conn).DownloadPieces(ctx)
+	if err != nil {
+		cancel()
+		d.release(&pieceStream{addr: addr, cancel: cancel, broken: atomic.NewBool(true)})
+		return nil, err
+	}
+	return &pieceStream{addr: addr, stream: stream, cancel: cancel, broken: atomic.NewBool(false)}, nil
+}
+
+// release puts the stream back for next pieces, the connection is closed when no stream uses it
+func (d *grpcPieceDownloader) release(s *pieceStream) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	pc := d.conns[s.addr]
+	pc.active--
+	if !s.broken.Load() && len(pc.idle) < maxIdlePieceStreams {
+		s.timer = time.AfterFunc(pieceStreamIdleTimeout, func() {
+			d.expire(s)
+		})
+		pc.idle = append(pc.idle, s)
+		return
+	}
+	s.cancel()
+	d.closeConnIfUnused(s.addr, pc)
+}
+
+// expire closes the idle stream
+func (d *grpcPieceDownloader) expire(s *pieceStream) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	pc, ok := d.conns[s.addr]
+	if !ok {
+		return
+	}
+	for i, idle := range pc.idle {
+		if idle == s {
+			pc.idle = append(pc.idle[:i], pc.idle[i+1:]...)
+			s.cancel()
+			d.closeConnIfUnused(s.addr, pc)
+			return
+		}
+	}
+}
+
+func (d *grpcPieceDownloader) closeConnIfUnused(addr string, pc *pieceConn) {
+	if pc.active > 0 || len(pc.idle) > 0 {
+		return
+	}
+	delete(d.conns, addr)
+	if err := pc.conn.Close(); err != nil {
+		logger.Warnf("close piece connection to %s error: %s", addr, err)
+	}
+}
+
+// isPeerBusy checks whether the error is the busy response of upload admission
+func isPeerBusy(err error) bool {
+	for _, detail := range status.Convert(err).Details() {
+		if dfError, ok := detail.(*base.GrpcDfError); ok && dfError.Code == base.Code_ClientPeerBusy {
+			return true
+		}
+	}
+	return false
+}
+
+// grpcPieceReader reads chunks of one piece from the stream
+type grpcPieceReader struct {
+	downloader *grpcPieceDownloader
+	stream     *pieceStream
+	pieceNum   int32
+	buf        []byte
+	done       bool
+	err        error
+
+	cancel context.CancelFunc
+	stop   chan struct{}
+	exited chan struct{}
+	once   sync.Once
+}
+
+// fill receives next chunk of the piece
+func (r *grpcPieceReader) fill() error {
+	data, err := r.stream.stream.Recv()
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	if err == nil && data.PieceNum != r.pieceNum {
+		err = errUnexpectedPieceData
+	}
+	if err != nil {
+		r.err = err
+		return err
+	}
+	r.buf = data.Content
+	r.done = data.Done
+	return nil
+}
+
+func (r *grpcPieceReader) Read(p []byte) (int, error) {
+	for len(r.buf) == 0 {
+		if r.err != nil {
+			return 0, r.err
+		}
+		if r.done {
+			return 0, io.EOF
+		}
+		if err := r.fill(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+	return n, nil
+}
+
+// Close releases the stream, the stream is reused only when the whole piece is read
+func (r *grpcPieceReader) Close() error {
+	r.once.Do(func() {
+		close(r.stop)
+		<-r.exited
+		r.cancel()
+		if r.err != nil || !r.done || len(r.buf) > 0 {
+			r.stream.broken.Store(true)
+		}
+		r.downloader.release(r.stream)
+	})
+	return nil
+}
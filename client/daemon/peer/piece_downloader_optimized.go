@@ -20,6 +20,7 @@ import (
 	"context"
 	"io"
 	"net"
+	"net/http"
 	"net/http/httputil"
 	"time"
 
@@ -81,6 +82,11 @@ func (o optimizedPieceDownloader) DownloadPiece(ctx context.Context, request *Do
 		cancel()
 		return nil, nil, err
 	}
+	if resp.StatusCode == http.StatusServiceUnavailable {
+		cancel()
+		conn.Close()
+		return nil, nil, errPeerBusy
+	}
 	if resp.ContentLength <= 0 {
 		cancel()
 		logger.Errorf("can not get ContentLength, addr: %s, task: %s, peer: %s, piece: %d",
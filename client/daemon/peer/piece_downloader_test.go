@@ -23,6 +23,7 @@ import (
 	"fmt"
 	"io"
 	"math"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
@@ -31,14 +32,18 @@ import (
 	"time"
 
 	"github.com/go-http-utils/headers"
+	"github.com/golang/mock/gomock"
 	testifyassert "github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/atomic"
+	"google.golang.org/grpc"
 
 	"d7y.io/dragonfly/v2/client/clientutil"
 	"d7y.io/dragonfly/v2/client/daemon/test"
 	"d7y.io/dragonfly/v2/client/daemon/upload"
 	logger "d7y.io/dragonfly/v2/internal/dflog"
 	"d7y.io/dragonfly/v2/pkg/rpc/base"
+	"d7y.io/dragonfly/v2/pkg/rpc/dfdaemon"
 	"d7y.io/dragonfly/v2/pkg/source"
 	"d7y.io/dragonfly/v2/pkg/source/httpprotocol"
 )
@@ -162,3 +167,104 @@ func TestPieceDownloader_DownloadPiece(t *testing.T) {
 		server.Close()
 	}
 }
+
+type testPieceServer struct {
+	dfdaemon.UnimplementedDaemonServer
+	data    []byte
+	streams *atomic.Int32
+}
+
+func (s *testPieceServer) DownloadPieces(stream dfdaemon.Daemon_DownloadPiecesServer) error {
+	s.streams.Inc()
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		// send piece in two chunks
+		content := s.data[req.RangeStart : req.RangeStart+uint64(req.RangeSize)]
+		half := len(content) / 2
+		if err = stream.Send(&dfdaemon.PieceData{PieceNum: req.PieceNum, Content: content[:half]}); err != nil {
+			return err
+		}
+		if err = stream.Send(&dfdaemon.PieceData{PieceNum: req.PieceNum, Content: content[half:], Done: true}); err != nil {
+			return err
+		}
+	}
+}
+
+func TestGRPCPieceDownloader_DownloadPiece(t *testing.T) {
+	assert := testifyassert.New(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	testData, err := os.ReadFile(test.File)
+	assert.Nil(err, "load test file")
+
+	serve := func(daemonServer dfdaemon.DaemonServer) (string, func()) {
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		require.Nil(t, err)
+		server := grpc.NewServer()
+		dfdaemon.RegisterDaemonServer(server, daemonServer)
+		go server.Serve(ln)
+		return ln.Addr().String(), server.Stop
+	}
+
+	pieceServer := &testPieceServer{data: testData, streams: atomic.NewInt32(0)}
+	addr, stop := serve(pieceServer)
+	defer stop()
+	unimplementedAddr, stopUnimplemented := serve(&dfdaemon.UnimplementedDaemonServer{})
+	defer stopUnimplemented()
+
+	fallback := NewMockPieceDownloader(ctrl)
+	pd := NewGRPCPieceDownloader(fallback, 30*time.Second, grpc.WithInsecure())
+	pd.SetEnabled(true)
+
+	newRequest := func(addr string, num int32, start uint64, size uint32) *DownloadPieceRequest {
+		hash := md5.New()
+		hash.Write(testData[start : start+uint64(size)])
+		return &DownloadPieceRequest{
+			TaskID:     "task-0",
+			DstPid:     "peer-0",
+			DstRPCAddr: addr,
+			CalcDigest: true,
+			piece: &base.PieceInfo{
+				PieceNum:    num,
+				RangeStart:  start,
+				RangeSize:   size,
+				PieceMd5:    hex.EncodeToString(hash.Sum(nil)),
+				PieceOffset: start,
+				PieceStyle:  base.PieceStyle_PLAIN,
+			},
+			log: logger.With("test", "test"),
+		}
+	}
+
+	// pieces are downloaded by grpc and the stream is reused
+	for i := 0; i < 4; i++ {
+		start, size := uint64(i*100), uint32(100)
+		r, c, err := pd.DownloadPiece(context.Background(), newRequest(addr, int32(i), start, size))
+		assert.Nil(err, "downloaded piece should success")
+		data, err := io.ReadAll(r)
+		assert.Nil(err, "read piece data should success")
+		c.Close()
+		assert.Equal(testData[start:start+uint64(size)], data, "downloaded piece data should match")
+	}
+	assert.Equal(int32(1), pieceServer.streams.Load(), "pieces should be sent in one stream")
+
+	// peers without grpc piece transport use fallback
+	fallback.EXPECT().DownloadPiece(gomock.Any(), gomock.Any()).Return(nil, nil, nil).Times(2)
+	for i := 0; i < 2; i++ {
+		_, _, err = pd.DownloadPiece(context.Background(), newRequest(unimplementedAddr, int32(i), 0, 100))
+		assert.Nil(err)
+	}
+
+	// disabled grpc piece transport uses fallback
+	pd.SetEnabled(false)
+	fallback.EXPECT().DownloadPiece(gomock.Any(), gomock.Any()).Return(nil, nil, nil).Times(1)
+	_, _, err = pd.DownloadPiece(context.Background(), newRequest(addr, 0, 0, 100))
+	assert.Nil(err)
+}
@@ -18,12 +18,14 @@ package peer
 
 import (
 	"context"
+	"fmt"
 	"io"
 	"math"
 	"net"
 	"net/http"
 	"time"
 
+	"github.com/pkg/errors"
 	"golang.org/x/time/rate"
 
 	"d7y.io/dragonfly/v2/client/clientutil"
@@ -41,6 +43,8 @@ type PieceManager interface {
 	DownloadSource(ctx context.Context, pt Task, request *scheduler.PeerTaskRequest) error
 	DownloadPiece(ctx context.Context, peerTask Task, request *DownloadPieceRequest) bool
 	ReadPiece(ctx context.Context, req *storage.ReadPieceRequest) (io.Reader, io.Closer, error)
+	// Import writes the content of reader to storage as all pieces of the task, piece digests are computed like back source
+	Import(ctx context.Context, ptm storage.PeerTaskMetadata, contentLength int64, reader io.Reader) error
 }
 
 type pieceManager struct {
@@ -78,6 +82,13 @@ func WithCalculateDigest(enable bool) func(*pieceManager) {
 	}
 }
 
+// WithPieceDownloader sets the downloader which downloads pieces from other peers
+func WithPieceDownloader(downloader PieceDownloader) func(*pieceManager) {
+	return func(pm *pieceManager) {
+		pm.pieceDownloader = downloader
+	}
+}
+
 // WithLimiter sets upload rate limiter, the burst size must be bigger than piece size
 func WithLimiter(limiter *rate.Limiter) func(*pieceManager) {
 	return func(manager *pieceManager) {
@@ -141,8 +152,20 @@ func (pm *pieceManager) DownloadPiece(ctx context.Context, pt Task, request *Dow
 			return
 		}
 	}
+	if err = verifyPieceProof(request); err != nil {
+		pt.Log().Errorf("verify piece %d from peer %s failed: %s", request.piece.PieceNum, request.DstPid, err)
+		return
+	}
+
 	ctx, span := tracer.Start(ctx, config.SpanWritePiece)
-	request.CalcDigest = pm.calculateDigest && request.piece.PieceMd5 != ""
+	calculateDigest := pm.calculateDigest || request.proofRequired
+	request.CalcDigest = calculateDigest && request.contentDigest() != ""
+	// the advertised piece digest is stored only when the piece content is verified with it,
+	// otherwise storage generates the digest from the content when calculateDigest is enabled
+	var digest string
+	if request.CalcDigest && request.hasPieceDigest() {
+		digest = request.piece.PieceDigest
+	}
 	span.SetAttributes(config.AttributeTargetPeerID.String(request.DstPid))
 	span.SetAttributes(config.AttributeTargetPeerAddr.String(request.DstAddr))
 	span.SetAttributes(config.AttributePiece.Int(int(request.piece.PieceNum)))
@@ -176,8 +199,11 @@ func (pm *pieceManager) DownloadPiece(ctx context.Context, pt Task, request *Dow
 				Start:  int64(request.piece.RangeStart),
 				Length: int64(request.piece.RangeSize),
 			},
+			Digest: digest,
+			Proof:  request.piece.PieceProof,
 		},
-		Reader: r,
+		GenPieceDigest: calculateDigest,
+		Reader:         r,
 	})
 	end = time.Now().UnixNano()
 	span.RecordError(err)
@@ -194,6 +220,32 @@ func (pm *pieceManager) DownloadPiece(ctx context.Context, pt Task, request *Dow
 	return
 }
 
+var errInvalidPieceProof = errors.New("invalid piece proof")
+
+// verifyPieceProof checks the piece digest with the piece digest root of task before downloading,
+// pieces without proof are verified after all pieces are downloaded, unless the proof is required.
+func verifyPieceProof(request *DownloadPieceRequest) error {
+	if request.digestRoot == "" {
+		if request.proofRequired {
+			return errInvalidPieceProof
+		}
+		return nil
+	}
+	if len(request.piece.PieceProof) == 0 && request.totalPiece != 1 {
+		if request.proofRequired {
+			return errInvalidPieceProof
+		}
+		return nil
+	}
+	// the piece content is verified with the proven piece digest, so its algorithm must be supported
+	if !request.hasPieceDigest() ||
+		!digestutils.VerifyMerkleProof(request.digestRoot, request.piece.PieceDigest,
+			int(request.piece.PieceNum), int(request.totalPiece), request.piece.PieceProof) {
+		return errInvalidPieceProof
+	}
+	return nil
+}
+
 func (pm *pieceManager) pushSuccessResult(peerTask Task, dstPid string, piece *base.PieceInfo, start int64, end int64) {
 	err := peerTask.ReportPieceResult(
 		&pieceTaskResult{
@@ -219,6 +271,15 @@ func (pm *pieceManager) pushSuccessResult(peerTask Task, dstPid string, piece *b
 }
 
 func (pm *pieceManager) pushFailResult(peerTask Task, dstPid string, piece *base.PieceInfo, start int64, end int64, err error, notRetry bool) {
+	code := base.Code_ClientPieceDownloadFail
+	switch errors.Cause(err) {
+	case errInvalidPieceProof, digestutils.ErrDigestNotMatch:
+		// report the parent which serves poisoned piece
+		code = base.Code_ClientPieceVerifyFail
+	case errPeerBusy:
+		// report the busy parent, then scheduler will re-schedule parents immediately
+		code = base.Code_ClientPeerBusy
+	}
 	err = peerTask.ReportPieceResult(
 		&pieceTaskResult{
 			piece: piece,
@@ -230,7 +291,7 @@ func (pm *pieceManager) pushFailResult(peerTask Task, dstPid string, piece *base
 				BeginTime:     uint64(start),
 				EndTime:       uint64(end),
 				Success:       false,
-				Code:          base.Code_ClientPieceDownloadFail,
+				Code:          code,
 				HostLoad:      nil,
 				FinishedCount: 0, // update by peer task
 			},
@@ -248,7 +309,7 @@ func (pm *pieceManager) ReadPiece(ctx context.Context, req *storage.ReadPieceReq
 
 // callback will be invoked before report result, it's useful to update some metadata before a peer task finished.
 func (pm *pieceManager) processPieceFromSource(pt Task,
-	reader io.Reader, contentLength int64, pieceNum int32, pieceOffset uint64, pieceSize uint32, callback func(n int64)) (int64, error) {
+	reader io.Reader, contentLength int64, pieceNum int32, pieceOffset uint64, pieceSize uint32, callback func(n int64) error) (int64, error) {
 	var (
 		success bool
 		start   = time.Now().UnixNano()
@@ -315,7 +376,8 @@ func (pm *pieceManager) processPieceFromSource(pt Task,
 					Length: int64(size),
 				},
 			},
-			Reader: reader,
+			GenPieceDigest: pm.calculateDigest,
+			Reader:         reader,
 		})
 	if n != int64(size) && n > 0 {
 		size = uint32(n)
@@ -331,8 +393,11 @@ func (pm *pieceManager) processPieceFromSource(pt Task,
 	if pm.calculateDigest {
 		md5 = reader.(digestutils.DigestReader).Digest()
 	}
+	if err = callback(n); err != nil {
+		pt.Log().Errorf("piece %d callback failed: %s", pieceNum, err)
+		return n, err
+	}
 	success = true
-	callback(n)
 	return n, nil
 }
 
@@ -373,6 +438,23 @@ func (pm *pieceManager) DownloadSource(ctx context.Context, pt Task, request *sc
 		}
 	}
 	log.Debugf("get content length: %d", contentLength)
+	pieceSize := pm.computePieceSize(contentLength)
+
+	// continue the resumed peer task with range requests, only the missing pieces are downloaded
+	if pieces := resumedPieces(pt); len(pieces) > 0 && contentLength > 0 && request.UrlMeta.Range == "" {
+		if size, ok := resumedPieceSize(contentLength, pieceSize, pieces); ok {
+			// keep the piece size of the downloaded pieces
+			pieceSize = size
+			err = pm.downloadMissingSource(ctx, pt, request, contentLength, pieceSize, pieces)
+			if err != errRangeNotSupported {
+				return err
+			}
+		} else {
+			err = errors.New("downloaded pieces are not aligned")
+		}
+		log.Warnf("can not resume from source: %s, download the whole source", err)
+	}
+
 	// 1. download piece from source
 	downloadRequest, err := source.NewRequestWithContext(ctx, request.Url, request.UrlMeta.Header)
 	if err != nil {
@@ -391,7 +473,6 @@ func (pm *pieceManager) DownloadSource(ctx context.Context, pt Task, request *sc
 	}
 
 	// 2. save to storage
-	pieceSize := pm.computePieceSize(contentLength)
 	// handle resource which content length is unknown
 	if contentLength < 0 {
 		return pm.downloadUnknownLengthSource(ctx, pt, pieceSize, reader)
@@ -407,9 +488,9 @@ func (pm *pieceManager) DownloadSource(ctx context.Context, pt Task, request *sc
 		}
 
 		log.Debugf("download piece %d", pieceNum)
-		n, er := pm.processPieceFromSource(pt, reader, contentLength, pieceNum, offset, size, func(n int64) {
+		n, er := pm.processPieceFromSource(pt, reader, contentLength, pieceNum, offset, size, func(n int64) error {
 			if pieceNum != maxPieceNum-1 {
-				return
+				return nil
 			}
 			// last piece
 			err = pm.storageManager.UpdateTask(ctx,
@@ -425,6 +506,7 @@ func (pm *pieceManager) DownloadSource(ctx context.Context, pt Task, request *sc
 			if err != nil {
 				log.Errorf("update task failed %s", err)
 			}
+			return nil
 		})
 		if er != nil {
 			log.Errorf("download piece %d error: %s", pieceNum, er)
@@ -445,6 +527,165 @@ func (pm *pieceManager) DownloadSource(ctx context.Context, pt Task, request *sc
 	return nil
 }
 
+var errRangeNotSupported = errors.New("source does not support range request")
+
+// resumedPieceSize returns the piece size of the downloaded pieces, ok is false when the pieces are not aligned
+func resumedPieceSize(contentLength int64, defaultSize uint32, pieces []storage.PieceMetadata) (uint32, bool) {
+	size := int64(defaultSize)
+	// the size of the last piece may be smaller
+	for _, piece := range pieces {
+		if piece.Range.Start+piece.Range.Length < contentLength {
+			size = piece.Range.Length
+			break
+		}
+	}
+	if size <= 0 {
+		return 0, false
+	}
+	for _, piece := range pieces {
+		start := int64(piece.Num) * size
+		length := size
+		if start+length > contentLength {
+			length = contentLength - start
+		}
+		if piece.Range.Start != start || piece.Range.Length != length || length <= 0 {
+			return 0, false
+		}
+	}
+	return uint32(size), true
+}
+
+// downloadMissingSource downloads the pieces not in downloaded pieces from source with range requests,
+// errRangeNotSupported is returned before any piece is written when the source ignores the range
+func (pm *pieceManager) downloadMissingSource(ctx context.Context, pt Task, request *scheduler.PeerTaskRequest,
+	contentLength int64, pieceSize uint32, pieces []storage.PieceMetadata) error {
+	log := pt.Log()
+	maxPieceNum := int32(math.Ceil(float64(contentLength) / float64(pieceSize)))
+	downloaded := map[int32]bool{}
+	for _, piece := range pieces {
+		downloaded[piece.Num] = true
+	}
+	missing := maxPieceNum - int32(len(downloaded))
+	log.Infof("resume from source, downloaded pieces: %d, missing pieces: %d", len(downloaded), missing)
+
+	// the last missing piece completes the task, generate the piece digests and check the whole content
+	// before reporting it
+	complete := func(int64) error {
+		if missing--; missing > 0 {
+			return nil
+		}
+		return pm.completeResumedSource(ctx, pt, request, contentLength, maxPieceNum)
+	}
+	for start := int32(0); start < maxPieceNum; {
+		if downloaded[start] {
+			start++
+			continue
+		}
+		end := start
+		for end < maxPieceNum && !downloaded[end] {
+			end++
+		}
+		if err := pm.downloadSourceRange(ctx, pt, request, contentLength, pieceSize, start, end, complete); err != nil {
+			return err
+		}
+		start = end
+	}
+	// nothing is downloaded from source, complete the task here
+	if int(maxPieceNum) == len(downloaded) {
+		if err := pm.completeResumedSource(ctx, pt, request, contentLength, maxPieceNum); err != nil {
+			return err
+		}
+	}
+
+	if err := pt.SetContentLength(contentLength); err != nil {
+		log.Errorf("set content length failed %s", err)
+		return err
+	}
+	log.Infof("resume from source ok")
+	return nil
+}
+
+// downloadSourceRange downloads pieces in [start, end) with one range request
+func (pm *pieceManager) downloadSourceRange(ctx context.Context, pt Task, request *scheduler.PeerTaskRequest,
+	contentLength int64, pieceSize uint32, start, end int32, callback func(n int64) error) error {
+	log := pt.Log()
+	offset := int64(start) * int64(pieceSize)
+	length := int64(end)*int64(pieceSize) - offset
+	if offset+length > contentLength {
+		length = contentLength - offset
+	}
+	header := map[string]string{}
+	for k, v := range request.UrlMeta.Header {
+		header[k] = v
+	}
+	header["Range"] = fmt.Sprintf("bytes=%d-%d", offset, offset+length-1)
+	log.Debugf("download piece %d-%d from source, range: %s", start, end-1, header["Range"])
+
+	downloadRequest, err := source.NewRequestWithContext(ctx, request.Url, header)
+	if err != nil {
+		return err
+	}
+	response, err := source.Download(downloadRequest)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	// resource plugins may not report partial content status, trust the range when content length matches
+	if response.ContentLength != length &&
+		(response.StatusCode != http.StatusPartialContent || response.ContentLength >= 0) {
+		log.Warnf("range %s is not satisfied, status: %d, content length: %d",
+			header["Range"], response.StatusCode, response.ContentLength)
+		return errRangeNotSupported
+	}
+
+	for pieceNum := start; pieceNum < end; pieceNum++ {
+		pieceOffset := uint64(pieceNum) * uint64(pieceSize)
+		size := pieceSize
+		if int64(pieceOffset)+int64(size) > contentLength {
+			size = uint32(contentLength - int64(pieceOffset))
+		}
+		n, err := pm.processPieceFromSource(pt, response.Body, contentLength, pieceNum, pieceOffset, size, callback)
+		if err != nil {
+			log.Errorf("download piece %d error: %s", pieceNum, err)
+			return err
+		}
+		if n != int64(size) {
+			log.Errorf("download piece %d size not match, desired: %d, actual: %d", pieceNum, size, n)
+			return storage.ErrShortRead
+		}
+	}
+	return nil
+}
+
+// completeResumedSource generates the piece digests of the resumed task and checks the digest of the whole content
+func (pm *pieceManager) completeResumedSource(ctx context.Context, pt Task, request *scheduler.PeerTaskRequest,
+	contentLength int64, totalPieces int32) error {
+	meta := storage.PeerTaskMetadata{
+		PeerID: pt.GetPeerID(),
+		TaskID: pt.GetTaskID(),
+	}
+	err := pm.storageManager.UpdateTask(ctx,
+		&storage.UpdateTaskRequest{
+			PeerTaskMetadata: meta,
+			ContentLength:    contentLength,
+			TotalPieces:      totalPieces,
+			GenPieceDigest:   true,
+		})
+	if err != nil {
+		return err
+	}
+	if !pm.calculateDigest || request.UrlMeta.Digest == "" {
+		return nil
+	}
+	rc, err := pm.storageManager.ReadAllPieces(ctx, &meta)
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+	_, err = io.Copy(io.Discard, digestutils.NewDigestReader(pt.Log(), io.LimitReader(rc, contentLength), request.UrlMeta.Digest))
+	return err
+}
+
 func (pm *pieceManager) downloadUnknownLengthSource(ctx context.Context, pt Task, pieceSize uint32, reader io.Reader) error {
 	var contentLength int64 = -1
 	log := pt.Log()
@@ -453,9 +694,9 @@ func (pm *pieceManager) downloadUnknownLengthSource(ctx context.Context, pt Task
 		offset := uint64(pieceNum) * uint64(pieceSize)
 		log.Debugf("download piece %d", pieceNum)
 		n, err := pm.processPieceFromSource(pt, reader, contentLength, pieceNum, offset, size,
-			func(n int64) {
+			func(n int64) error {
 				if n == int64(size) {
-					return
+					return nil
 				}
 				// last piece
 				contentLength = int64(pieceNum)*int64(pieceSize) + n
@@ -473,6 +714,7 @@ func (pm *pieceManager) downloadUnknownLengthSource(ctx context.Context, pt Task
 				if er != nil {
 					log.Errorf("update task failed %s", er)
 				}
+				return nil
 			})
 		if err != nil {
 			log.Errorf("download piece %d error: %s", pieceNum, err)
@@ -492,3 +734,51 @@ func (pm *pieceManager) downloadUnknownLengthSource(ctx context.Context, pt Task
 	log.Infof("download from source ok")
 	return nil
 }
+
+func (pm *pieceManager) Import(ctx context.Context, ptm storage.PeerTaskMetadata, contentLength int64, reader io.Reader) error {
+	log := logger.With("peer", ptm.PeerID, "task", ptm.TaskID, "component", "pieceManager")
+	pieceSize := pm.computePieceSize(contentLength)
+	maxPieceNum := int32(math.Ceil(float64(contentLength) / float64(pieceSize)))
+	for pieceNum := int32(0); pieceNum < maxPieceNum; pieceNum++ {
+		size := pieceSize
+		offset := uint64(pieceNum) * uint64(pieceSize)
+		// calculate piece size for last piece
+		if int64(offset)+int64(size) > contentLength {
+			size = uint32(contentLength - int64(offset))
+		}
+
+		log.Debugf("import piece %d", pieceNum)
+		n, err := pm.storageManager.WritePiece(ctx,
+			&storage.WritePieceRequest{
+				PeerTaskMetadata: ptm,
+				PieceMetadata: storage.PieceMetadata{
+					Num: pieceNum,
+					// storage manager will get digest from DigestReader, keep empty here is ok
+					Md5:    "",
+					Offset: offset,
+					Range: clientutil.Range{
+						Start:  int64(offset),
+						Length: int64(size),
+					},
+				},
+				GenPieceDigest: true,
+				Reader:         digestutils.NewDigestReader(log, reader),
+			})
+		if err != nil {
+			log.Errorf("import piece %d error: %s", pieceNum, err)
+			return err
+		}
+		if n != int64(size) {
+			log.Errorf("import piece %d size not match, desired: %d, actual: %d", pieceNum, size, n)
+			return storage.ErrShortRead
+		}
+	}
+
+	return pm.storageManager.UpdateTask(ctx,
+		&storage.UpdateTaskRequest{
+			PeerTaskMetadata: ptm,
+			ContentLength:    contentLength,
+			TotalPieces:      maxPieceNum,
+			GenPieceDigest:   true,
+		})
+}
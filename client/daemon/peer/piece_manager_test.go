@@ -20,6 +20,7 @@ import (
 	"bytes"
 	"context"
 	"crypto/md5"
+	"encoding/base64"
 	"encoding/hex"
 	"fmt"
 	"io"
@@ -38,12 +39,15 @@ import (
 	"d7y.io/dragonfly/v2/client/config"
 	"d7y.io/dragonfly/v2/client/daemon/storage"
 	"d7y.io/dragonfly/v2/client/daemon/test"
+	mock_storage "d7y.io/dragonfly/v2/client/daemon/test/mock/storage"
 	logger "d7y.io/dragonfly/v2/internal/dflog"
 	"d7y.io/dragonfly/v2/pkg/rpc/base"
 	_ "d7y.io/dragonfly/v2/pkg/rpc/dfdaemon/server"
 	"d7y.io/dragonfly/v2/pkg/rpc/scheduler"
+	"d7y.io/dragonfly/v2/pkg/signature"
 	"d7y.io/dragonfly/v2/pkg/source"
 	"d7y.io/dragonfly/v2/pkg/source/httpprotocol"
+	"d7y.io/dragonfly/v2/pkg/util/digestutils"
 )
 
 func TestPieceManager_DownloadSource(t *testing.T) {
@@ -223,3 +227,165 @@ func TestPieceManager_DownloadSource(t *testing.T) {
 		})
 	}
 }
+
+func TestPieceManager_verifyPieceProof(t *testing.T) {
+	assert := testifyassert.New(t)
+
+	digests := []string{"sha256:" + digestutils.Sha256("a"), "sha256:" + digestutils.Sha256("b"), "sha256:" + digestutils.Sha256("c")}
+	tree := digestutils.NewMerkleTree(digests)
+	newRequest := func(num int32, digest string, proof []string) *DownloadPieceRequest {
+		return &DownloadPieceRequest{
+			piece: &base.PieceInfo{
+				PieceNum:    num,
+				PieceDigest: digest,
+				PieceProof:  proof,
+			},
+			digestRoot: tree.Root(),
+			totalPiece: int32(len(digests)),
+		}
+	}
+
+	for i := range digests {
+		assert.Nil(verifyPieceProof(newRequest(int32(i), digests[i], tree.Proof(i))))
+	}
+	// piece without proof is verified after download
+	assert.Nil(verifyPieceProof(newRequest(0, digests[0], nil)))
+	// no root, nothing to verify
+	assert.Nil(verifyPieceProof(&DownloadPieceRequest{piece: &base.PieceInfo{PieceDigest: "sha256:bad", PieceProof: []string{"bad"}}}))
+	// no root, but the proof is required
+	assert.Equal(errInvalidPieceProof, verifyPieceProof(&DownloadPieceRequest{piece: &base.PieceInfo{PieceDigest: digests[0]}, proofRequired: true}))
+	assert.Equal(errInvalidPieceProof, verifyPieceProof(newRequest(0, digests[1], tree.Proof(0))))
+	assert.Equal(errInvalidPieceProof, verifyPieceProof(newRequest(1, digests[0], tree.Proof(0))))
+	assert.Equal(errInvalidPieceProof, verifyPieceProof(newRequest(2, "", tree.Proof(2))))
+}
+
+func TestPieceManager_DownloadPieceWithoutProof(t *testing.T) {
+	assert := testifyassert.New(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	signer, err := signature.NewSigner(base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{1}, 32)))
+	assert.Nil(err)
+	verifier := signature.NewVerifier()
+	assert.Nil(verifier.SetKey(signer.PublicKey()))
+
+	digests := []string{"sha256:" + digestutils.Sha256("a"), "sha256:" + digestutils.Sha256("b"), "sha256:" + digestutils.Sha256("c")}
+	tree := digestutils.NewMerkleTree(digests)
+	pt := &peerTask{
+		taskID:              "task",
+		peerID:              "peer",
+		totalPiece:          int32(len(digests)),
+		pieceVerifier:       verifier,
+		SugaredLoggerOnWith: logger.With("test", t.Name()),
+	}
+	assert.True(pt.acceptPieceDigestRoot(tree.Root(), signer.Sign("task", tree.Root())))
+
+	var codes []base.Code
+	task := NewMockTask(ctrl)
+	task.EXPECT().GetPeerID().Return("peer").AnyTimes()
+	task.EXPECT().GetTaskID().Return("task").AnyTimes()
+	task.EXPECT().Log().Return(pt.Log()).AnyTimes()
+	task.EXPECT().ReportPieceResult(gomock.Any()).DoAndReturn(
+		func(result *pieceTaskResult) error {
+			codes = append(codes, result.pieceResult.Code)
+			return nil
+		}).AnyTimes()
+
+	downloader := NewMockPieceDownloader(ctrl)
+	downloader.EXPECT().DownloadPiece(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, request *DownloadPieceRequest) (io.Reader, io.Closer, error) {
+			// content must be verified even without calculateDigest
+			assert.True(request.CalcDigest)
+			return nil, nil, fmt.Errorf("connection reset")
+		}).Times(1)
+	pm := &pieceManager{
+		calculateDigest: false,
+		pieceDownloader: downloader,
+	}
+
+	// the poisoned parent strips the proofs, pieces are rejected before downloading
+	request := pt.newDownloadPieceRequest("parent", "127.0.0.1:0", &base.PieceInfo{PieceNum: 1, PieceDigest: "sha256:bad"}, tree.Root())
+	assert.False(pm.DownloadPiece(context.Background(), task, request))
+	// the piece with proof is downloaded with digest calculation
+	request = pt.newDownloadPieceRequest("parent", "127.0.0.1:0", &base.PieceInfo{PieceNum: 1, PieceDigest: digests[1], PieceProof: tree.Proof(1)}, tree.Root())
+	assert.False(pm.DownloadPiece(context.Background(), task, request))
+	assert.Equal([]base.Code{base.Code_ClientPieceVerifyFail, base.Code_ClientPieceDownloadFail}, codes)
+}
+
+func TestPieceManager_DownloadPieceDigest(t *testing.T) {
+	digest := "sha256:" + digestutils.Sha256("b")
+	tests := []struct {
+		name            string
+		calculateDigest bool
+		pieceDigest     string
+		calcDigest      bool
+		expectDigest    string
+	}{
+		{
+			name:            "unverified digest is not stored",
+			calculateDigest: false,
+			pieceDigest:     digest,
+			calcDigest:      false,
+			expectDigest:    "",
+		},
+		{
+			name:            "verified digest is stored",
+			calculateDigest: true,
+			pieceDigest:     digest,
+			calcDigest:      true,
+			expectDigest:    digest,
+		},
+		{
+			name:            "digest with unsupported algorithm is not stored",
+			calculateDigest: true,
+			pieceDigest:     "sha512:" + digestutils.Sha256("b"),
+			calcDigest:      true,
+			expectDigest:    "",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := testifyassert.New(t)
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			task := NewMockTask(ctrl)
+			task.EXPECT().GetPeerID().Return("peer").AnyTimes()
+			task.EXPECT().GetTaskID().Return("task").AnyTimes()
+			task.EXPECT().Log().Return(logger.With("test", t.Name())).AnyTimes()
+			task.EXPECT().AddTraffic(gomock.Any()).AnyTimes()
+			task.EXPECT().ReportPieceResult(gomock.Any()).Return(nil).AnyTimes()
+
+			downloader := NewMockPieceDownloader(ctrl)
+			downloader.EXPECT().DownloadPiece(gomock.Any(), gomock.Any()).DoAndReturn(
+				func(ctx context.Context, request *DownloadPieceRequest) (io.Reader, io.Closer, error) {
+					assert.Equal(tc.calcDigest, request.CalcDigest)
+					return bytes.NewBufferString("b"), io.NopCloser(nil), nil
+				})
+			storageManager := mock_storage.NewMockManager(ctrl)
+			storageManager.EXPECT().WritePiece(gomock.Any(), gomock.Any()).DoAndReturn(
+				func(ctx context.Context, req *storage.WritePieceRequest) (int64, error) {
+					assert.Equal(tc.expectDigest, req.PieceMetadata.Digest)
+					assert.Equal(tc.calculateDigest, req.GenPieceDigest)
+					return 1, nil
+				})
+			pm := &pieceManager{
+				calculateDigest: tc.calculateDigest,
+				pieceDownloader: downloader,
+				storageManager:  storageManager,
+			}
+
+			request := &DownloadPieceRequest{
+				DstPid: "parent",
+				piece: &base.PieceInfo{
+					PieceNum:    0,
+					RangeSize:   1,
+					PieceMd5:    "92eb5ffee6ae2fec3ad71c777531578f",
+					PieceDigest: tc.pieceDigest,
+				},
+				log: logger.With("test", t.Name()),
+			}
+			assert.True(pm.DownloadPiece(context.Background(), task, request))
+		})
+	}
+}
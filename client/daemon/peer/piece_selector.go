@@ -0,0 +1,400 @@
+/*
+ *     Copyright 2020 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package peer
+
+import (
+	"context"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	"d7y.io/dragonfly/v2/client/config"
+	logger "d7y.io/dragonfly/v2/internal/dflog"
+	"d7y.io/dragonfly/v2/pkg/rpc/base"
+	"d7y.io/dragonfly/v2/pkg/rpc/scheduler"
+)
+
+const (
+	// rarestFirstWindow is the max count of not requested pieces to find the rarest one
+	rarestFirstWindow = 256
+	// endgameCheckInterval is the interval to check whether the last pieces should be requested from multiple parents
+	endgameCheckInterval = 100 * time.Millisecond
+)
+
+// pieceSelector selects the pieces to request from parents
+type pieceSelector interface {
+	// next returns the next piece to request from cur, -1 means all pieces are requested
+	next(pt *peerTask, cur int32) int32
+	// sort sorts the pieces of a piece packet in the order to request
+	sort(pt *peerTask, pieces []*base.PieceInfo)
+}
+
+type pieceSelectionKey struct{}
+
+// WithPieceSelection returns a context with the piece selection strategy of peer tasks,
+// it overrides the strategy in config for the peer tasks started with the context.
+func WithPieceSelection(ctx context.Context, strategy string) context.Context {
+	return context.WithValue(ctx, pieceSelectionKey{}, strategy)
+}
+
+func newPieceSelector(ctx context.Context, opt config.PieceSelectionOption, log *logger.SugaredLoggerOnWith) pieceSelector {
+	strategy := opt.Strategy
+	if s, ok := ctx.Value(pieceSelectionKey{}).(string); ok && s != "" {
+		strategy = s
+	}
+	switch strategy {
+	case "", config.PieceSelectionSequential:
+		return sequentialPieceSelector{}
+	case config.PieceSelectionRarestFirst:
+		return &rarestFirstPieceSelector{randomFirst: opt.RandomFirstPieces}
+	default:
+		log.Warnf("unknown piece selection strategy %q, use %s", strategy, config.PieceSelectionSequential)
+		return sequentialPieceSelector{}
+	}
+}
+
+// sequentialPieceSelector requests pieces in order
+type sequentialPieceSelector struct{}
+
+func (sequentialPieceSelector) next(pt *peerTask, cur int32) int32 {
+	return pt.getNextPieceNum(cur)
+}
+
+func (sequentialPieceSelector) sort(*peerTask, []*base.PieceInfo) {
+}
+
+// rarestFirstPieceSelector requests the pieces owned by the fewest parents first, so the rare pieces
+// are spread in the cluster quickly, the first pieces are selected randomly, so peers started
+// at the same time request different pieces.
+type rarestFirstPieceSelector struct {
+	randomFirst int
+}
+
+func (s *rarestFirstPieceSelector) next(pt *peerTask, cur int32) int32 {
+	if pt.isCompleted() {
+		return -1
+	}
+	// pieces are requested from the main peer, so only its pieces are candidates
+	candidates := pt.pieceAvailability.candidates(pt.mainPeerID(), cur, pt.totalPiece, pt.requestedPieces, rarestFirstWindow)
+	if len(candidates) == 0 {
+		// nothing is known about the pieces of parents
+		return pt.getNextPieceNum(cur)
+	}
+
+	if s.randomFirst > 0 && pt.readyPieceCount() < int32(s.randomFirst) {
+		return candidates[rand.Intn(len(candidates))]
+	}
+
+	var (
+		rarest = candidates[0]
+		min    = pt.pieceAvailability.owners(rarest)
+	)
+	for _, num := range candidates[1:] {
+		if owners := pt.pieceAvailability.owners(num); owners < min {
+			rarest, min = num, owners
+		}
+	}
+	return rarest
+}
+
+func (s *rarestFirstPieceSelector) sort(pt *peerTask, pieces []*base.PieceInfo) {
+	if s.randomFirst > 0 && pt.readyPieceCount() < int32(s.randomFirst) {
+		rand.Shuffle(len(pieces), func(i, j int) {
+			pieces[i], pieces[j] = pieces[j], pieces[i]
+		})
+		return
+	}
+	sort.SliceStable(pieces, func(i, j int) bool {
+		return pt.pieceAvailability.owners(pieces[i].PieceNum) < pt.pieceAvailability.owners(pieces[j].PieceNum)
+	})
+}
+
+// pieceAvailability records the pieces of parents
+type pieceAvailability struct {
+	lock sync.RWMutex
+	// parents holds the pieces of parents, key is peer id
+	parents map[string]*parentPieces
+}
+
+type parentPieces struct {
+	addr   string
+	pieces map[int32]*base.PieceInfo
+	// max is the max piece number of the parent
+	max int32
+}
+
+func newPieceAvailability() *pieceAvailability {
+	return &pieceAvailability{
+		parents: map[string]*parentPieces{},
+	}
+}
+
+// add records the pieces of the parent
+func (a *pieceAvailability) add(peerID, addr string, pieces []*base.PieceInfo) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	p, ok := a.parents[peerID]
+	if !ok {
+		p = &parentPieces{pieces: map[int32]*base.PieceInfo{}, max: -1}
+		a.parents[peerID] = p
+	}
+	if addr != "" {
+		p.addr = addr
+	}
+	for _, piece := range pieces {
+		p.pieces[piece.PieceNum] = piece
+		if piece.PieceNum > p.max {
+			p.max = piece.PieceNum
+		}
+	}
+}
+
+// retain removes the parents which are not in peerPacket
+func (a *pieceAvailability) retain(peerPacket *scheduler.PeerPacket) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	for peerID := range a.parents {
+		if !containsDestPeer(peerPacket, peerID) {
+			delete(a.parents, peerID)
+		}
+	}
+}
+
+// owners returns the count of parents which own the piece
+func (a *pieceAvailability) owners(num int32) int {
+	a.lock.RLock()
+	defer a.lock.RUnlock()
+	var count int
+	for _, p := range a.parents {
+		if _, ok := p.pieces[num]; ok {
+			count++
+		}
+	}
+	return count
+}
+
+// candidates returns at most limit pieces of the parent which are not requested, from cur to the end,
+// then from 0 to cur.
+func (a *pieceAvailability) candidates(peerID string, cur, total int32, requested *Bitmap, limit int) []int32 {
+	a.lock.RLock()
+	defer a.lock.RUnlock()
+	p, ok := a.parents[peerID]
+	if !ok {
+		return nil
+	}
+	end := p.max + 1
+	if total > 0 && total < end {
+		end = total
+	}
+	var nums []int32
+	scan := func(from, to int32) {
+		for i := from; i < to && len(nums) < limit; i++ {
+			if _, ok := p.pieces[i]; ok && !requested.IsSet(i) {
+				nums = append(nums, i)
+			}
+		}
+	}
+	scan(cur, end)
+	scan(0, cur)
+	return nums
+}
+
+// pieceOwner is a parent which owns the piece
+type pieceOwner struct {
+	peerID string
+	addr   string
+	piece  *base.PieceInfo
+}
+
+// pieceOwners returns the parents which own the piece
+func (a *pieceAvailability) pieceOwners(num int32) []pieceOwner {
+	a.lock.RLock()
+	defer a.lock.RUnlock()
+	var owners []pieceOwner
+	for peerID, p := range a.parents {
+		if piece, ok := p.pieces[num]; ok {
+			owners = append(owners, pieceOwner{peerID: peerID, addr: p.addr, piece: piece})
+		}
+	}
+	sort.Slice(owners, func(i, j int) bool {
+		return owners[i].peerID < owners[j].peerID
+	})
+	return owners
+}
+
+// pieceRequests tracks the parents which pieces are requested from in endgame mode,
+// the slower requests of a piece are canceled after it is downloaded.
+type pieceRequests struct {
+	lock sync.Mutex
+	// dispatched holds the parents which pieces are dispatched to, key is piece number
+	dispatched map[int32]map[string]bool
+	// running holds cancel functions of the running requests, key is piece number and parent id
+	running map[int32]map[string]context.CancelFunc
+}
+
+func newPieceRequests() *pieceRequests {
+	return &pieceRequests{
+		dispatched: map[int32]map[string]bool{},
+		running:    map[int32]map[string]context.CancelFunc{},
+	}
+}
+
+// dispatch records the piece is dispatched to the parent, it returns false when it is already dispatched
+func (r *pieceRequests) dispatch(num int32, peerID string) bool {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	parents, ok := r.dispatched[num]
+	if !ok {
+		parents = map[string]bool{}
+		r.dispatched[num] = parents
+	}
+	if parents[peerID] {
+		return false
+	}
+	parents[peerID] = true
+	return true
+}
+
+// start records the running request of the piece
+func (r *pieceRequests) start(num int32, peerID string, cancel context.CancelFunc) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	running, ok := r.running[num]
+	if !ok {
+		running = map[string]context.CancelFunc{}
+		r.running[num] = running
+	}
+	running[peerID] = cancel
+}
+
+// finish removes the request of the piece, the other requests of the piece are canceled when it succeeds
+func (r *pieceRequests) finish(num int32, peerID string, success bool) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	running := r.running[num]
+	delete(running, peerID)
+	if !success {
+		// the piece may be requested from the parent again
+		delete(r.dispatched[num], peerID)
+		return
+	}
+	for _, cancel := range running {
+		cancel()
+	}
+	delete(r.running, num)
+	delete(r.dispatched, num)
+}
+
+// setPieceSelection sets the piece selection strategy of the peer task, the strategy in ctx overrides the one in config
+func (ptm *peerTaskManager) setPieceSelection(ctx context.Context, pt *peerTask) {
+	pt.pieceSelector = newPieceSelector(ctx, ptm.pieceSelection, pt.Log())
+	if _, ok := pt.pieceSelector.(*rarestFirstPieceSelector); ok || ptm.pieceSelection.EndgamePieces > 0 {
+		pt.pieceAvailability = newPieceAvailability()
+	}
+	if ptm.pieceSelection.EndgamePieces > 0 {
+		pt.endgamePieces = int32(ptm.pieceSelection.EndgamePieces)
+		pt.pieceRequests = newPieceRequests()
+	}
+}
+
+// nextPieceNum returns the next piece to request with the piece selector
+func (pt *peerTask) nextPieceNum(cur int32) int32 {
+	if pt.pieceSelector == nil {
+		return pt.getNextPieceNum(cur)
+	}
+	return pt.pieceSelector.next(pt, cur)
+}
+
+// recordAvailablePieces records the pieces of the parent in piece packet
+func (pt *peerTask) recordAvailablePieces(peerID string, packet *base.PiecePacket) {
+	if pt.pieceAvailability == nil {
+		return
+	}
+	pt.pieceAvailability.add(peerID, packet.DstAddr, packet.PieceInfos)
+}
+
+func (pt *peerTask) mainPeerID() string {
+	peerPacket, ok := pt.peerPacket.Load().(*scheduler.PeerPacket)
+	if !ok || peerPacket == nil || peerPacket.MainPeer == nil {
+		return ""
+	}
+	return peerPacket.MainPeer.PeerId
+}
+
+// isPieceReady returns whether the piece is downloaded, eg: by the other request of it in endgame mode
+func (pt *peerTask) isPieceReady(num int32) bool {
+	pt.lock.RLock()
+	defer pt.lock.RUnlock()
+	return pt.readyPieces.IsSet(num)
+}
+
+func (pt *peerTask) readyPieceCount() int32 {
+	pt.lock.RLock()
+	defer pt.lock.RUnlock()
+	return pt.readyPieces.Settled()
+}
+
+// downloadPiece downloads the piece, in endgame mode, the requests of the same piece
+// to other parents are canceled after the piece is downloaded.
+func (pt *peerTask) downloadPiece(ctx context.Context, pti Task, request *DownloadPieceRequest) bool {
+	if pt.pieceRequests == nil {
+		return pt.pieceManager.DownloadPiece(ctx, pti, request)
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	pt.pieceRequests.start(request.piece.PieceNum, request.DstPid, cancel)
+	success := pt.pieceManager.DownloadPiece(ctx, pti, request)
+	pt.pieceRequests.finish(request.piece.PieceNum, request.DstPid, success)
+	return success
+}
+
+// endgame requests the last pieces from all parents which own them, when the count of
+// requested but not ready pieces is not more than endgamePieces.
+func (pt *peerTask) endgame(requestCh chan *DownloadPieceRequest) {
+	if pt.pieceRequests == nil || pt.totalPiece <= 0 {
+		return
+	}
+	var pending []int32
+	pt.lock.RLock()
+	for i := int32(0); i < pt.totalPiece && len(pending) <= int(pt.endgamePieces); i++ {
+		if pt.requestedPieces.IsSet(i) && !pt.readyPieces.IsSet(i) {
+			pending = append(pending, i)
+		}
+	}
+	pt.lock.RUnlock()
+	if len(pending) == 0 || len(pending) > int(pt.endgamePieces) {
+		return
+	}
+
+	digestRoot, _ := pt.GetPieceDigestRoot()
+	for _, num := range pending {
+		for _, owner := range pt.pieceAvailability.pieceOwners(num) {
+			if !pt.pieceRequests.dispatch(num, owner.peerID) {
+				continue
+			}
+			pt.Debugf("endgame: request piece %d from %s", num, owner.peerID)
+			select {
+			case requestCh <- pt.newDownloadPieceRequest(owner.peerID, owner.addr, owner.piece, digestRoot):
+			case <-pt.done:
+				return
+			case <-pt.ctx.Done():
+				return
+			}
+		}
+	}
+}
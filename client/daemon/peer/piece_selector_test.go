@@ -0,0 +1,314 @@
+/*
+ *     Copyright 2020 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package peer
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	testifyassert "github.com/stretchr/testify/assert"
+	"go.uber.org/atomic"
+
+	"d7y.io/dragonfly/v2/client/clientutil"
+	"d7y.io/dragonfly/v2/client/config"
+	"d7y.io/dragonfly/v2/client/daemon/test"
+	logger "d7y.io/dragonfly/v2/internal/dflog"
+	"d7y.io/dragonfly/v2/pkg/rpc/base"
+	"d7y.io/dragonfly/v2/pkg/rpc/scheduler"
+)
+
+func newPieceInfos(from, to int32) []*base.PieceInfo {
+	var pieces []*base.PieceInfo
+	for i := from; i < to; i++ {
+		pieces = append(pieces, &base.PieceInfo{PieceNum: i})
+	}
+	return pieces
+}
+
+func newSelectorTestPeerTask(t *testing.T, mainPeer string) *peerTask {
+	pt := &peerTask{
+		SugaredLoggerOnWith: logger.With("test", t.Name()),
+		taskID:              "task-0",
+		peerID:              "peer-0",
+		done:                make(chan struct{}),
+		readyPieces:         NewBitmap(),
+		requestedPieces:     NewBitmap(),
+		contentLength:       atomic.NewInt64(-1),
+		completedLength:     atomic.NewInt64(0),
+		totalPiece:          -1,
+		pieceAvailability:   newPieceAvailability(),
+	}
+	pt.ctx, pt.cancel = context.WithCancel(context.Background())
+	pt.peerPacket.Store(&scheduler.PeerPacket{
+		MainPeer: &scheduler.PeerPacket_DestPeer{PeerId: mainPeer},
+	})
+	return pt
+}
+
+func TestNewPieceSelector(t *testing.T) {
+	assert := testifyassert.New(t)
+	log := logger.With("test", t.Name())
+
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		strategy string
+		expect   pieceSelector
+	}{
+		{
+			name:   "default",
+			ctx:    context.Background(),
+			expect: sequentialPieceSelector{},
+		},
+		{
+			name:     "config",
+			ctx:      context.Background(),
+			strategy: config.PieceSelectionRarestFirst,
+			expect:   &rarestFirstPieceSelector{randomFirst: 4},
+		},
+		{
+			name:     "override by context",
+			ctx:      WithPieceSelection(context.Background(), config.PieceSelectionSequential),
+			strategy: config.PieceSelectionRarestFirst,
+			expect:   sequentialPieceSelector{},
+		},
+		{
+			name:   "unknown strategy",
+			ctx:    WithPieceSelection(context.Background(), "unknown"),
+			expect: sequentialPieceSelector{},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			s := newPieceSelector(tc.ctx, config.PieceSelectionOption{Strategy: tc.strategy, RandomFirstPieces: 4}, log)
+			assert.Equal(tc.expect, s)
+		})
+	}
+}
+
+func TestPieceAvailability(t *testing.T) {
+	assert := testifyassert.New(t)
+
+	a := newPieceAvailability()
+	a.add("peer-1", "127.0.0.1:1", newPieceInfos(0, 8))
+	a.add("peer-2", "127.0.0.1:2", newPieceInfos(0, 4))
+	a.add("peer-3", "127.0.0.1:3", newPieceInfos(2, 4))
+
+	assert.Equal(2, a.owners(0))
+	assert.Equal(3, a.owners(2))
+	assert.Equal(1, a.owners(6))
+	assert.Equal(0, a.owners(8))
+
+	requested := NewBitmap()
+	requested.Set(1)
+	requested.Set(5)
+	assert.Equal([]int32{4, 6, 7, 0, 2, 3}, a.candidates("peer-1", 4, -1, requested, 10))
+	assert.Equal([]int32{4, 6}, a.candidates("peer-1", 4, 7, requested, 2))
+	assert.Nil(a.candidates("peer-4", 0, -1, requested, 10))
+
+	owners := a.pieceOwners(3)
+	assert.Len(owners, 3)
+	assert.Equal("peer-1", owners[0].peerID)
+	assert.Equal("127.0.0.1:2", owners[1].addr)
+
+	a.retain(&scheduler.PeerPacket{
+		MainPeer:   &scheduler.PeerPacket_DestPeer{PeerId: "peer-1"},
+		StealPeers: []*scheduler.PeerPacket_DestPeer{{PeerId: "peer-3"}},
+	})
+	assert.Equal(1, a.owners(0))
+	assert.Equal(2, a.owners(2))
+}
+
+func TestRarestFirstPieceSelector(t *testing.T) {
+	assert := testifyassert.New(t)
+
+	pt := newSelectorTestPeerTask(t, "peer-1")
+	defer pt.cancel()
+	pt.pieceAvailability.add("peer-1", "", newPieceInfos(0, 8))
+	pt.pieceAvailability.add("peer-2", "", newPieceInfos(0, 4))
+	pt.pieceAvailability.add("peer-3", "", newPieceInfos(2, 6))
+
+	s := &rarestFirstPieceSelector{}
+	// pieces 6 and 7 are only owned by the main peer
+	assert.Equal(int32(6), s.next(pt, 0))
+	pt.requestedPieces.Set(6)
+	assert.Equal(int32(7), s.next(pt, 0))
+	pt.requestedPieces.Set(7)
+	// pieces 0, 1, 4, 5 are owned by two parents
+	assert.Equal(int32(0), s.next(pt, 0))
+	assert.Equal(int32(4), s.next(pt, 4))
+
+	pieces := newPieceInfos(0, 8)
+	s.sort(pt, pieces)
+	var nums []int32
+	for _, p := range pieces {
+		nums = append(nums, p.PieceNum)
+	}
+	assert.Equal([]int32{6, 7, 0, 1, 4, 5, 2, 3}, nums)
+
+	// random first pieces are selected from the pieces of main peer
+	s = &rarestFirstPieceSelector{randomFirst: 4}
+	for i := 0; i < 10; i++ {
+		num := s.next(pt, 0)
+		assert.True(num >= 0 && num < 6, "piece %d", num)
+	}
+
+	// fallback to sequential when the pieces of main peer are unknown
+	pt.peerPacket.Store(&scheduler.PeerPacket{
+		MainPeer: &scheduler.PeerPacket_DestPeer{PeerId: "peer-4"},
+	})
+	assert.Equal(int32(0), s.next(pt, 0))
+}
+
+func TestPieceRequests(t *testing.T) {
+	assert := testifyassert.New(t)
+
+	r := newPieceRequests()
+	assert.True(r.dispatch(0, "peer-1"))
+	assert.False(r.dispatch(0, "peer-1"))
+	assert.True(r.dispatch(0, "peer-2"))
+
+	ctx1, cancel1 := context.WithCancel(context.Background())
+	ctx2, cancel2 := context.WithCancel(context.Background())
+	defer cancel1()
+	defer cancel2()
+	r.start(0, "peer-1", cancel1)
+	r.start(0, "peer-2", cancel2)
+
+	// failed parent can be requested again
+	r.finish(0, "peer-1", false)
+	assert.Nil(ctx2.Err())
+	assert.True(r.dispatch(0, "peer-1"))
+
+	r.start(0, "peer-1", cancel1)
+	r.finish(0, "peer-2", true)
+	assert.Equal(context.Canceled, ctx1.Err())
+	assert.Empty(r.running)
+	assert.Empty(r.dispatched)
+}
+
+func TestPeerTask_Endgame(t *testing.T) {
+	assert := testifyassert.New(t)
+
+	pt := newSelectorTestPeerTask(t, "peer-1")
+	defer pt.cancel()
+	pt.totalPiece = 4
+	pt.endgamePieces = 2
+	pt.pieceRequests = newPieceRequests()
+	pt.pieceAvailability.add("peer-1", "127.0.0.1:1", newPieceInfos(0, 4))
+	pt.pieceAvailability.add("peer-2", "127.0.0.1:2", newPieceInfos(0, 4))
+	for i := int32(0); i < 4; i++ {
+		pt.requestedPieces.Set(i)
+		pt.pieceRequests.dispatch(i, "peer-1")
+	}
+	pt.readyPieces.Set(0)
+
+	requestCh := make(chan *DownloadPieceRequest, 8)
+	// more pending pieces than endgame pieces
+	pt.endgame(requestCh)
+	assert.Len(requestCh, 0)
+
+	pt.readyPieces.Set(1)
+	pt.endgame(requestCh)
+	assert.Len(requestCh, 2)
+	for i := int32(2); i < 4; i++ {
+		req := <-requestCh
+		assert.Equal(i, req.piece.PieceNum)
+		assert.Equal("peer-2", req.DstPid)
+		assert.Equal("127.0.0.1:2", req.DstAddr)
+	}
+
+	// pieces are dispatched to all parents already
+	pt.endgame(requestCh)
+	assert.Len(requestCh, 0)
+}
+
+func TestPeerTaskManager_StartStreamPeerTask_PieceSelection(t *testing.T) {
+	assert := testifyassert.New(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	testBytes, err := os.ReadFile(test.File)
+	assert.Nil(err, "load test file")
+
+	var (
+		pieceSize = 1024
+		taskID    = "task-0"
+	)
+	sched, storageManager := setupPeerTaskManagerComponents(
+		ctrl,
+		componentsOption{
+			taskID:             taskID,
+			contentLength:      int64(len(testBytes)),
+			pieceSize:          uint32(pieceSize),
+			pieceParallelCount: 4,
+		})
+	defer storageManager.CleanUp()
+
+	downloader := NewMockPieceDownloader(ctrl)
+	downloader.EXPECT().DownloadPiece(gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(
+		func(ctx context.Context, task *DownloadPieceRequest) (io.Reader, io.Closer, error) {
+			rc := io.NopCloser(
+				bytes.NewBuffer(
+					testBytes[task.piece.RangeStart : task.piece.RangeStart+uint64(task.piece.RangeSize)],
+				))
+			return rc, rc, nil
+		})
+
+	ptm := &peerTaskManager{
+		host: &scheduler.PeerHost{
+			Ip: "127.0.0.1",
+		},
+		runningPeerTasks: sync.Map{},
+		pieceManager: &pieceManager{
+			storageManager:  storageManager,
+			pieceDownloader: downloader,
+		},
+		storageManager:  storageManager,
+		schedulerClient: sched,
+		schedulerOption: config.SchedulerOption{
+			ScheduleTimeout: clientutil.Duration{Duration: 10 * time.Minute},
+		},
+		pieceSelection: config.PieceSelectionOption{
+			Strategy:          config.PieceSelectionSequential,
+			RandomFirstPieces: 2,
+			EndgamePieces:     4,
+		},
+	}
+
+	ctx := WithPieceSelection(context.Background(), config.PieceSelectionRarestFirst)
+	r, _, err := ptm.StartStreamPeerTask(ctx, &scheduler.PeerTaskRequest{
+		Url: "http://localhost/test/data",
+		UrlMeta: &base.UrlMeta{
+			Tag: "d7y-test",
+		},
+		PeerId:   "peer-0",
+		PeerHost: &scheduler.PeerHost{},
+	})
+	assert.Nil(err, "start stream peer task")
+
+	outputBytes, err := io.ReadAll(r)
+	assert.Nil(err, "load read data")
+	assert.Equal(testBytes, outputBytes, "output and desired output must match")
+	r.Close()
+}
@@ -0,0 +1,216 @@
+/*
+ *     Copyright 2020 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package peer
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"d7y.io/dragonfly/v2/internal/dferrors"
+	logger "d7y.io/dragonfly/v2/internal/dflog"
+	"d7y.io/dragonfly/v2/pkg/rpc/base"
+	dfclient "d7y.io/dragonfly/v2/pkg/rpc/dfdaemon/client"
+	"d7y.io/dragonfly/v2/pkg/rpc/scheduler"
+	"d7y.io/dragonfly/v2/pkg/util/mathutils"
+)
+
+const (
+	// pieceTaskSyncLimit is the max pieces of one packet pushed by dest peer
+	pieceTaskSyncLimit = 16
+	// pieceTaskSyncMaxRetry is the max times to subscribe again without receiving any packet
+	pieceTaskSyncMaxRetry = 5
+	// pieceTaskSyncInitBackoff and pieceTaskSyncMaxBackoff are the backoff seconds to subscribe again
+	pieceTaskSyncInitBackoff = 0.2
+	pieceTaskSyncMaxBackoff  = 3.0
+)
+
+// pieceTaskSynchronizer subscribes pieces of a dest peer with SyncPieceTasks,
+// pushed pieces are kept in memory and waiters are woken up when pieces or metadata are changed.
+type pieceTaskSynchronizer struct {
+	*logger.SugaredLoggerOnWith
+	cancel context.CancelFunc
+
+	lock sync.Mutex
+	// packet holds the latest metadata pushed by dest peer
+	packet *base.PiecePacket
+	// pieces holds all pushed pieces, key is piece number
+	pieces map[int32]*base.PieceInfo
+	// changed is closed and reset when pieces or metadata are changed
+	changed chan struct{}
+	// finished is true after dest peer pushed all pieces
+	finished bool
+	// err is set when the stream is broken
+	err error
+	// onPieces is called with the packets pushed by dest peer, it can be nil
+	onPieces func(packet *base.PiecePacket)
+}
+
+func newPieceTaskSynchronizer(ctx context.Context, log *logger.SugaredLoggerOnWith,
+	peer *scheduler.PeerPacket_DestPeer, request *base.PieceTaskRequest, onPieces func(packet *base.PiecePacket)) *pieceTaskSynchronizer {
+	ctx, cancel := context.WithCancel(ctx)
+	s := &pieceTaskSynchronizer{
+		SugaredLoggerOnWith: log,
+		cancel:              cancel,
+		pieces:              map[int32]*base.PieceInfo{},
+		changed:             make(chan struct{}),
+		onPieces:            onPieces,
+	}
+	go s.receive(ctx, peer, request)
+	return s
+}
+
+// receive subscribes pieces from dest peer, the broken stream is subscribed again with backoff
+// from the first piece which is not received.
+func (s *pieceTaskSynchronizer) receive(ctx context.Context, peer *scheduler.PeerPacket_DestPeer, request *base.PieceTaskRequest) {
+	for retry := 0; ; retry++ {
+		if retry > 0 {
+			select {
+			case <-time.After(mathutils.RandBackoff(pieceTaskSyncInitBackoff, pieceTaskSyncMaxBackoff, 2.0, retry)):
+			case <-ctx.Done():
+				s.fail(ctx.Err())
+				return
+			}
+		}
+
+		received, err := s.subscribe(ctx, peer, &base.PieceTaskRequest{
+			TaskId:   request.TaskId,
+			SrcPid:   request.SrcPid,
+			DstPid:   request.DstPid,
+			StartNum: s.nextPieceNum(request.StartNum),
+			Limit:    request.Limit,
+		})
+		if err == nil {
+			return
+		}
+		if received {
+			retry = 0
+		}
+		if !canResubscribe(ctx, err) || retry >= pieceTaskSyncMaxRetry {
+			s.fail(err)
+			return
+		}
+		s.Warnf("sync piece tasks from peer %s error: %s, subscribe again", peer.PeerId, err)
+	}
+}
+
+// subscribe receives pieces until dest peer pushed all pieces or the stream is broken,
+// received reports whether any packet is received.
+func (s *pieceTaskSynchronizer) subscribe(ctx context.Context, peer *scheduler.PeerPacket_DestPeer, request *base.PieceTaskRequest) (received bool, err error) {
+	stream, err := dfclient.SyncPieceTasks(ctx, peer, request)
+	if err != nil {
+		return false, err
+	}
+	for {
+		packet, err := stream.Recv()
+		if err == io.EOF {
+			s.Debugf("sync piece tasks from peer %s finished", peer.PeerId)
+			s.lock.Lock()
+			s.finished = true
+			s.notify()
+			s.lock.Unlock()
+			return received, nil
+		}
+		if err != nil {
+			return received, err
+		}
+		received = true
+		s.lock.Lock()
+		for _, piece := range packet.PieceInfos {
+			s.pieces[piece.PieceNum] = piece
+		}
+		s.packet = packet
+		s.notify()
+		s.lock.Unlock()
+		if s.onPieces != nil {
+			s.onPieces(packet)
+		}
+	}
+}
+
+// nextPieceNum returns the first piece number from start which is not received
+func (s *pieceTaskSynchronizer) nextPieceNum(start uint32) uint32 {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	for s.pieces[int32(start)] != nil {
+		start++
+	}
+	return start
+}
+
+// canResubscribe reports whether the broken stream can be subscribed again,
+// dest peer which does not support SyncPieceTasks or returns df error is not subscribed again.
+func canResubscribe(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	if _, ok := err.(*dferrors.DfError); ok {
+		return false
+	}
+	return status.Code(err) != codes.Unimplemented
+}
+
+func (s *pieceTaskSynchronizer) fail(err error) {
+	s.Warnf("sync piece tasks error: %s", err)
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.err = err
+	s.notify()
+}
+
+// notify wakes up all waiters, caller must hold the lock
+func (s *pieceTaskSynchronizer) notify() {
+	close(s.changed)
+	s.changed = make(chan struct{})
+}
+
+// pieceTasks returns pushed pieces in the range of request, the returned packet is nil when dest peer pushed nothing.
+// changed will be closed when more pieces or metadata are pushed.
+func (s *pieceTaskSynchronizer) pieceTasks(request *base.PieceTaskRequest) (packet *base.PiecePacket, finished bool, changed <-chan struct{}, err error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.err != nil {
+		return nil, false, nil, s.err
+	}
+	if s.packet == nil {
+		return nil, s.finished, s.changed, nil
+	}
+	packet = &base.PiecePacket{
+		TaskId:               s.packet.TaskId,
+		DstPid:               s.packet.DstPid,
+		DstAddr:              s.packet.DstAddr,
+		TotalPiece:           s.packet.TotalPiece,
+		ContentLength:        s.packet.ContentLength,
+		PieceMd5Sign:         s.packet.PieceMd5Sign,
+		PieceDigestRoot:      s.packet.PieceDigestRoot,
+		PieceDigestSignature: s.packet.PieceDigestSignature,
+	}
+	for i := uint32(0); i < request.Limit; i++ {
+		if piece, ok := s.pieces[int32(request.StartNum+i)]; ok {
+			packet.PieceInfos = append(packet.PieceInfos, piece)
+		}
+	}
+	return packet, s.finished, s.changed, nil
+}
+
+func (s *pieceTaskSynchronizer) close() {
+	s.cancel()
+}
@@ -0,0 +1,142 @@
+/*
+ *     Copyright 2020 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package peer
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/phayes/freeport"
+	testifyassert "github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	mock_daemon "d7y.io/dragonfly/v2/client/daemon/test/mock/daemon"
+	logger "d7y.io/dragonfly/v2/internal/dflog"
+	"d7y.io/dragonfly/v2/internal/dfnet"
+	"d7y.io/dragonfly/v2/pkg/rpc"
+	"d7y.io/dragonfly/v2/pkg/rpc/base"
+	"d7y.io/dragonfly/v2/pkg/rpc/dfdaemon"
+	daemonserver "d7y.io/dragonfly/v2/pkg/rpc/dfdaemon/server"
+	"d7y.io/dragonfly/v2/pkg/rpc/scheduler"
+)
+
+func TestPieceTaskSynchronizer_Resubscribe(t *testing.T) {
+	assert := testifyassert.New(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	pushPieces := func(stream dfdaemon.Daemon_SyncPieceTasksServer, nums ...int32) error {
+		packet := &base.PiecePacket{TaskId: "task-0", DstPid: "peer-x", TotalPiece: 4}
+		for _, num := range nums {
+			packet.PieceInfos = append(packet.PieceInfos, &base.PieceInfo{PieceNum: num})
+		}
+		return stream.Send(packet)
+	}
+
+	var (
+		lock     sync.Mutex
+		requests []uint32
+	)
+	subscribed := func(request *base.PieceTaskRequest) {
+		lock.Lock()
+		defer lock.Unlock()
+		requests = append(requests, request.StartNum)
+	}
+	daemon := mock_daemon.NewMockDaemonServer(ctrl)
+	gomock.InOrder(
+		// the stream is broken after some pieces are pushed
+		daemon.EXPECT().SyncPieceTasks(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(request *base.PieceTaskRequest, stream dfdaemon.Daemon_SyncPieceTasksServer) error {
+				subscribed(request)
+				if err := pushPieces(stream, 0, 1); err != nil {
+					return err
+				}
+				return status.Error(codes.Unavailable, "connection reset")
+			}),
+		// the stream is broken again before any pieces are pushed
+		daemon.EXPECT().SyncPieceTasks(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(request *base.PieceTaskRequest, stream dfdaemon.Daemon_SyncPieceTasksServer) error {
+				subscribed(request)
+				return status.Error(codes.Unavailable, "connection reset")
+			}),
+		daemon.EXPECT().SyncPieceTasks(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(request *base.PieceTaskRequest, stream dfdaemon.Daemon_SyncPieceTasksServer) error {
+				subscribed(request)
+				return pushPieces(stream, 2, 3)
+			}),
+		// dest peer does not support pushing pieces
+		daemon.EXPECT().SyncPieceTasks(gomock.Any(), gomock.Any()).Return(status.Error(codes.Unimplemented, "unimplemented")),
+	)
+
+	port := int32(freeport.GetPort())
+	ln, _ := rpc.Listen(dfnet.NetAddr{
+		Type: "tcp",
+		Addr: fmt.Sprintf("0.0.0.0:%d", port),
+	})
+	server := daemonserver.New(daemon)
+	go func() {
+		if err := server.Serve(ln); err != nil {
+			log.Fatal(err)
+		}
+	}()
+	defer server.Stop()
+	time.Sleep(100 * time.Millisecond)
+
+	peer := &scheduler.PeerPacket_DestPeer{Ip: "127.0.0.1", RpcPort: port, PeerId: "peer-x"}
+	request := &base.PieceTaskRequest{
+		TaskId: "task-0",
+		SrcPid: "peer-0",
+		DstPid: "peer-x",
+		Limit:  pieceTaskSyncLimit,
+	}
+	wait := func(s *pieceTaskSynchronizer) (*base.PiecePacket, bool, error) {
+		for {
+			packet, finished, changed, err := s.pieceTasks(request)
+			if finished || err != nil {
+				return packet, finished, err
+			}
+			select {
+			case <-changed:
+			case <-time.After(5 * time.Second):
+				return packet, finished, context.DeadlineExceeded
+			}
+		}
+	}
+
+	// broken stream is subscribed again from the first piece which is not received
+	s := newPieceTaskSynchronizer(context.Background(), logger.With("test", t.Name()), peer, request, nil)
+	defer s.close()
+	packet, finished, err := wait(s)
+	assert.Nil(err)
+	assert.True(finished)
+	assert.Len(packet.PieceInfos, 4)
+	lock.Lock()
+	assert.Equal([]uint32{0, 2, 2}, requests)
+	lock.Unlock()
+
+	// unimplemented is not subscribed again
+	s = newPieceTaskSynchronizer(context.Background(), logger.With("test", t.Name()), peer, request, nil)
+	defer s.close()
+	_, _, err = wait(s)
+	assert.Equal(codes.Unimplemented, status.Code(err))
+}
@@ -24,6 +24,7 @@ import (
 	"net"
 	"net/http"
 	"net/http/httputil"
+	"net/url"
 	"strings"
 	"sync"
 	"time"
@@ -40,6 +41,7 @@ import (
 	"d7y.io/dragonfly/v2/client/daemon/peer"
 	"d7y.io/dragonfly/v2/client/daemon/transport"
 	logger "d7y.io/dragonfly/v2/internal/dflog"
+	"d7y.io/dragonfly/v2/pkg/registry"
 	"d7y.io/dragonfly/v2/pkg/rpc/scheduler"
 	"d7y.io/dragonfly/v2/pkg/util/stringutils"
 )
@@ -52,6 +54,9 @@ var (
 
 	schemaHTTPS = "https"
 
+	// registryNamespaceQuery is the query which containerd uses to pass the upstream registry host to mirrors
+	registryNamespaceQuery = "ns"
+
 	portHTTPS = 443
 )
 
@@ -61,6 +66,12 @@ type Proxy struct {
 	// reverse proxy upstream url for the default registry
 	registry *config.RegistryMirror
 
+	// registries are the registry mirrors matched by upstream registry host
+	registries []*config.RegistryMirror
+
+	// registryAuth resolves the token authentication of registries for requests without authorization
+	registryAuth *registry.Authenticator
+
 	// proxy rules
 	rules []*config.Proxy
 
@@ -98,6 +109,9 @@ type Proxy struct {
 
 	// dumpHTTPContent indicates to dump http request header and response header
 	dumpHTTPContent bool
+
+	// httpCache records the cache policies of resources downloaded with dragonfly, shared by transports
+	httpCache *transport.HTTPCache
 }
 
 // Option is a functional option for configuring the proxy
@@ -135,6 +149,14 @@ func WithRegistryMirror(r *config.RegistryMirror) Option {
 	}
 }
 
+// WithRegistryMirrors sets the registry mirrors matched by upstream registry host
+func WithRegistryMirrors(r ...*config.RegistryMirror) Option {
+	return func(p *Proxy) *Proxy {
+		p.registries = r
+		return p
+	}
+}
+
 // WithCert sets the certificate
 func WithCert(cert *tls.Certificate) Option {
 	return func(p *Proxy) *Proxy {
@@ -146,22 +168,25 @@ func WithCert(cert *tls.Certificate) Option {
 // WithDirectHandler sets the handler for non-proxy requests
 func WithDirectHandler(h *http.ServeMux) Option {
 	return func(p *Proxy) *Proxy {
-		if p.registry == nil || p.registry.Remote == nil || p.registry.Remote.URL == nil {
+		if !p.registryMirrorEnabled() {
 			logger.Warnf("registry mirror url is empty, registry mirror feature is disabled")
-			h.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
-				http.Error(w, fmt.Sprintf("registry mirror feature is disabled"), http.StatusNotFound)
-			})
-			p.directHandler = h
-			return p
 		}
 		// Make sure the root handler of the given server mux is the
 		// registry mirror reverse proxy
-		h.HandleFunc("/", p.mirrorRegistry)
+		h.HandleFunc("/", p.serveRegistryMirror)
 		p.directHandler = h
 		return p
 	}
 }
 
+// WithHTTPCache sets the http cache, it is shared by the proxies reloaded
+func WithHTTPCache(c *transport.HTTPCache) Option {
+	return func(p *Proxy) *Proxy {
+		p.httpCache = c
+		return p
+	}
+}
+
 // WithRules sets the proxy rules
 func WithRules(rules []*config.Proxy) Option {
 	return func(p *Proxy) *Proxy {
@@ -226,6 +251,10 @@ func NewProxyWithOptions(options ...Option) (*Proxy, error) {
 	for _, opt := range options {
 		opt(proxy)
 	}
+	if proxy.httpCache == nil {
+		proxy.httpCache = transport.NewHTTPCache(transport.DefaultHTTPCacheSize)
+	}
+	proxy.registryAuth = newRegistryAuthenticator(append([]*config.RegistryMirror{proxy.registry}, proxy.registries...))
 
 	return proxy, nil
 }
@@ -440,26 +469,56 @@ func (proxy *Proxy) newTransport(tlsConfig *tls.Config) http.RoundTripper {
 		transport.WithDefaultFilter(proxy.defaultFilter),
 		transport.WithDefaultBiz(bizTag),
 		transport.WithDumpHTTPContent(proxy.dumpHTTPContent),
+		transport.WithHTTPCache(proxy.httpCache),
 	)
 	return rt
 }
 
+func (proxy *Proxy) registryMirrorEnabled() bool {
+	return len(proxy.registries) > 0 || (proxy.registry != nil && proxy.registry.Remote != nil && proxy.registry.Remote.URL != nil)
+}
+
+// serveRegistryMirror handles the non-proxy requests with the registry mirror
+func (proxy *Proxy) serveRegistryMirror(w http.ResponseWriter, r *http.Request) {
+	if !proxy.registryMirrorEnabled() {
+		http.Error(w, fmt.Sprintf("registry mirror feature is disabled"), http.StatusNotFound)
+		return
+	}
+	proxy.mirrorRegistry(w, r)
+}
+
 func (proxy *Proxy) mirrorRegistry(w http.ResponseWriter, r *http.Request) {
-	reverseProxy := newReverseProxy(proxy.registry)
+	mirror := proxy.matchRegistry(r)
+	if mirror == nil || mirror.Remote == nil || mirror.Remote.URL == nil {
+		http.Error(w, fmt.Sprintf("no registry mirror for %s", r.URL.String()), http.StatusNotFound)
+		return
+	}
+
+	reverseProxy := newReverseProxy(mirror)
 	t, err := transport.New(
 		transport.WithPeerHost(proxy.peerHost),
 		transport.WithPeerTaskManager(proxy.peerTaskManager),
-		transport.WithTLS(proxy.registry.TLSConfig()),
-		transport.WithCondition(proxy.shouldUseDragonflyForMirror),
+		transport.WithTLS(mirror.TLSConfig()),
+		transport.WithCondition(func(req *http.Request) bool {
+			return useDragonflyForMirror(mirror, req)
+		}),
 		transport.WithDefaultFilter(proxy.defaultFilter),
 		transport.WithDefaultBiz(bizTag),
 		transport.WithDumpHTTPContent(proxy.dumpHTTPContent),
+		transport.WithHTTPCache(proxy.httpCache),
 	)
 	if err != nil {
 		http.Error(w, fmt.Sprintf("failed to get transport: %v", err), http.StatusInternalServerError)
+		return
 	}
 
-	reverseProxy.Transport = t
+	// layers downloaded with dragonfly fail instead of returning 401,
+	// so the challenge is resolved in advance by a HEAD request which is never downloaded with dragonfly
+	reverseProxy.Transport = &registry.Transport{
+		Base:          t,
+		Authenticator: proxy.registryAuth,
+		Challenger:    t,
+	}
 	reverseProxy.ErrorHandler = func(rw http.ResponseWriter, req *http.Request, err error) {
 		rw.WriteHeader(http.StatusInternalServerError)
 		// write error string to response body
@@ -470,6 +529,51 @@ func (proxy *Proxy) mirrorRegistry(w http.ResponseWriter, r *http.Request) {
 	reverseProxy.ServeHTTP(w, r)
 }
 
+// newRegistryAuthenticator returns the authenticator with the credentials of registry mirrors,
+// credentials are keyed by the remote host which the requests are sent to.
+func newRegistryAuthenticator(mirrors []*config.RegistryMirror) *registry.Authenticator {
+	var options []registry.AuthenticatorOption
+	for _, mirror := range mirrors {
+		if mirror == nil || mirror.Credentials == nil || mirror.Remote == nil || mirror.Remote.URL == nil {
+			continue
+		}
+		options = append(options, registry.WithCredentials(mirror.Remote.Host, &registry.Credentials{
+			Username: mirror.Credentials.Username,
+			Password: mirror.Credentials.Password,
+		}))
+	}
+	return registry.NewAuthenticator(options...)
+}
+
+// matchRegistry returns the registry mirror for the request, the upstream registry host is from
+// the "ns" query of containerd or the header "X-Dragonfly-Registry",
+// the default registry mirror is used when no one matches.
+func (proxy *Proxy) matchRegistry(r *http.Request) *config.RegistryMirror {
+	if len(proxy.registries) == 0 {
+		return proxy.registry
+	}
+
+	host := r.URL.Query().Get(registryNamespaceQuery)
+	if host == "" {
+		if reg := r.Header.Get(config.HeaderDragonflyRegistry); reg != "" {
+			if u, err := url.Parse(reg); err == nil {
+				host = u.Host
+			}
+		}
+	}
+	if host == "" {
+		return proxy.registry
+	}
+
+	for _, mirror := range proxy.registries {
+		if mirror.Host == host {
+			logger.Debugf("registry mirror %s matched for %s", mirror.Remote, r.URL.String())
+			return mirror
+		}
+	}
+	return proxy.registry
+}
+
 // remoteConfig returns the tls.Config used to connect to the given remote host.
 // If the host should not be hijacked, and it will return nil.
 func (proxy *Proxy) remoteConfig(host string) *tls.Config {
@@ -547,7 +651,12 @@ func (proxy *Proxy) shouldUseDragonfly(req *http.Request) bool {
 // shouldUseDragonflyForMirror returns whether we should use dragonfly to proxy a request
 // when we use registry mirror.
 func (proxy *Proxy) shouldUseDragonflyForMirror(req *http.Request) bool {
-	return proxy.registry != nil && !proxy.registry.Direct && transport.NeedUseDragonfly(req)
+	return useDragonflyForMirror(proxy.registry, req)
+}
+
+// useDragonflyForMirror returns whether we should use dragonfly to proxy a request for the registry mirror.
+func useDragonflyForMirror(mirror *config.RegistryMirror, req *http.Request) bool {
+	return mirror != nil && !mirror.Direct && transport.NeedUseDragonfly(req)
 }
 
 // tunnelHTTPS handles a CONNECT request and proxy an https request through an
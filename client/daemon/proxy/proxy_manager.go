@@ -26,12 +26,16 @@ import (
 	"net/http"
 	"os"
 	"reflect"
+	"sync"
+	"sync/atomic"
 
 	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/spf13/viper"
 
 	"d7y.io/dragonfly/v2/client/config"
 	"d7y.io/dragonfly/v2/client/daemon/peer"
+	"d7y.io/dragonfly/v2/client/daemon/transport"
 	logger "d7y.io/dragonfly/v2/internal/dflog"
 	"d7y.io/dragonfly/v2/pkg/rpc/scheduler"
 )
@@ -41,12 +45,27 @@ type Manager interface {
 	ServeSNI(net.Listener) error
 	Stop() error
 	IsEnabled() bool
+	// Reload rebuilds the proxy with the new option atomically, requests in flight keep using the old one,
+	// the listen options are not reloaded
+	Reload(*config.ProxyOption) error
 }
 
 type proxyManager struct {
 	*http.Server
-	*Proxy
 	config.ListenOption
+
+	peerHost        *scheduler.PeerHost
+	peerTaskManager peer.TaskManager
+
+	// httpCache is shared by the proxies reloaded
+	httpCache *transport.HTTPCache
+
+	// proxy is the current *Proxy
+	proxy atomic.Value
+
+	// lock protects directHandler and serializes reloading
+	lock          sync.Mutex
+	directHandler *http.ServeMux
 }
 
 var _ Manager = (*proxyManager)(nil)
@@ -57,20 +76,38 @@ func NewProxyManager(peerHost *scheduler.PeerHost, peerTaskManager peer.TaskMana
 		logger.Infof("proxy config is empty, disabled")
 		return &proxyManager{}, nil
 	}
+
+	pm := &proxyManager{
+		Server:          &http.Server{},
+		ListenOption:    opts.ListenOption,
+		peerHost:        peerHost,
+		peerTaskManager: peerTaskManager,
+		httpCache:       transport.NewHTTPCache(transport.DefaultHTTPCacheSize),
+	}
+	p, err := pm.newProxy(opts)
+	if err != nil {
+		return nil, err
+	}
+	pm.proxy.Store(p)
+	return pm, nil
+}
+
+func (pm *proxyManager) newProxy(opts *config.ProxyOption) (*Proxy, error) {
 	registry := opts.RegistryMirror
 	proxies := opts.Proxies
 	hijackHTTPS := opts.HijackHTTPS
 	whiteList := opts.WhiteList
 
 	options := []Option{
-		WithPeerHost(peerHost),
-		WithPeerTaskManager(peerTaskManager),
+		WithPeerHost(pm.peerHost),
+		WithPeerTaskManager(pm.peerTaskManager),
 		WithRules(proxies),
 		WithWhiteList(whiteList),
 		WithMaxConcurrency(opts.MaxConcurrency),
 		WithDefaultFilter(opts.DefaultFilter),
 		WithBasicAuth(opts.BasicAuth),
 		WithDumpHTTPContent(opts.DumpHTTPContent),
+		WithHTTPCache(pm.httpCache),
 	}
 
 	if registry != nil {
@@ -78,6 +115,13 @@ func NewProxyManager(peerHost *scheduler.PeerHost, peerTaskManager peer.TaskMana
 		options = append(options, WithRegistryMirror(registry))
 	}
 
+	if len(opts.RegistryMirrors) > 0 {
+		for i, mirror := range opts.RegistryMirrors {
+			logger.Infof("[%d] registry mirror for %s: %s", i+1, mirror.Host, mirror.Remote)
+		}
+		options = append(options, WithRegistryMirrors(opts.RegistryMirrors...))
+	}
+
 	if len(proxies) > 0 {
 		logger.Infof("load %d proxy rules", len(proxies))
 		for i, r := range proxies {
@@ -111,22 +155,31 @@ func NewProxyManager(peerHost *scheduler.PeerHost, peerTaskManager peer.TaskMana
 	if err != nil {
 		return nil, errors.Wrap(err, "create proxy")
 	}
+	return p, nil
+}
 
-	return &proxyManager{
-		Server:       &http.Server{},
-		Proxy:        p,
-		ListenOption: opts.ListenOption,
-	}, nil
+func (pm *proxyManager) current() *Proxy {
+	return pm.proxy.Load().(*Proxy)
 }
 
 func (pm *proxyManager) Serve(listener net.Listener) error {
-	_ = WithDirectHandler(newDirectHandler())(pm.Proxy)
-	pm.Server.Handler = pm.Proxy
+	pm.lock.Lock()
+	// the root handler always serves with the current proxy
+	pm.directHandler = newDirectHandler()
+	pm.directHandler.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		pm.current().serveRegistryMirror(w, r)
+	})
+	pm.current().directHandler = pm.directHandler
+	pm.lock.Unlock()
+
+	pm.Server.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		pm.current().ServeHTTP(w, r)
+	})
 	return pm.Server.Serve(listener)
 }
 
 func (pm *proxyManager) ServeSNI(listener net.Listener) error {
-	return pm.Proxy.ServeSNI(listener)
+	return serveSNI(listener, pm.current)
 }
 
 func (pm *proxyManager) Stop() error {
@@ -137,10 +190,37 @@ func (pm *proxyManager) IsEnabled() bool {
 	return pm.ListenOption.TCPListen != nil && pm.ListenOption.TCPListen.PortRange.Start != 0
 }
 
+func (pm *proxyManager) Reload(opts *config.ProxyOption) error {
+	if pm.Server == nil {
+		return errors.New("proxy is disabled")
+	}
+	if opts == nil {
+		return errors.New("empty proxy option")
+	}
+	if !reflect.DeepEqual(opts.TCPListen, pm.TCPListen) {
+		logger.Warnf("proxy listen option changed, it takes effect after restart")
+	}
+
+	pm.lock.Lock()
+	defer pm.lock.Unlock()
+
+	p, err := pm.newProxy(opts)
+	if err != nil {
+		return err
+	}
+	if pm.directHandler != nil {
+		p.directHandler = pm.directHandler
+	}
+	pm.proxy.Store(p)
+	logger.Infof("proxy reloaded")
+	return nil
+}
+
 func newDirectHandler() *http.ServeMux {
 	s := http.DefaultServeMux
 	s.HandleFunc("/args", getArgs)
 	s.HandleFunc("/env", getEnv)
+	s.Handle("/metrics", promhttp.Handler())
 	return s
 }
 
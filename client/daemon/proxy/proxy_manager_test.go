@@ -0,0 +1,83 @@
+/*
+ *     Copyright 2020 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package proxy
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"d7y.io/dragonfly/v2/client/config"
+	"d7y.io/dragonfly/v2/pkg/rpc/scheduler"
+)
+
+func TestProxyManager_Reload(t *testing.T) {
+	assert := assert.New(t)
+
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "ok")
+	}))
+	defer upstream.Close()
+
+	rule, err := config.NewProxy("blobs/sha256.*", false, true, "")
+	assert.Nil(err)
+	pm, err := NewProxyManager(&scheduler.PeerHost{}, nil, &config.ProxyOption{Proxies: []*config.Proxy{rule}})
+	assert.Nil(err)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(err)
+	go pm.Serve(listener)
+	defer pm.Stop()
+
+	proxyURL, _ := url.Parse("http://" + listener.Addr().String())
+	client := &http.Client{Transport: &http.Transport{Proxy: http.ProxyURL(proxyURL)}}
+	get := func() int {
+		resp, err := client.Get(upstream.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+	assert.Equal(http.StatusOK, get())
+
+	old := pm.(*proxyManager).current()
+	assert.Nil(pm.Reload(&config.ProxyOption{
+		WhiteList: []*config.WhiteList{{Host: "example.com"}},
+	}))
+	assert.Equal(http.StatusUnauthorized, get())
+
+	// the proxy in use is not modified by reloading
+	assert.Len(old.rules, 1)
+	assert.Empty(old.whiteList)
+	assert.Empty(pm.(*proxyManager).current().rules)
+	assert.Equal(old.directHandler, pm.(*proxyManager).current().directHandler)
+
+	assert.Nil(pm.Reload(&config.ProxyOption{}))
+	assert.Equal(http.StatusOK, get())
+}
+
+func TestProxyManager_ReloadDisabled(t *testing.T) {
+	pm, err := NewProxyManager(nil, nil, nil)
+	assert.Nil(t, err)
+	assert.Error(t, pm.Reload(&config.ProxyOption{}))
+}
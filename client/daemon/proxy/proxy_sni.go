@@ -32,6 +32,12 @@ import (
 )
 
 func (proxy *Proxy) ServeSNI(l net.Listener) error {
+	return serveSNI(l, func() *Proxy { return proxy })
+}
+
+// serveSNI serves the connections with the current proxy, so the proxy can be reloaded.
+func serveSNI(l net.Listener, current func() *Proxy) error {
+	proxy := current()
 	if proxy.cert == nil {
 		return errors.New("empty cert")
 	}
@@ -52,6 +58,11 @@ func (proxy *Proxy) ServeSNI(l net.Listener) error {
 			logger.Errorf("accept connection error: %s", err)
 			continue
 		}
+		if proxy = current(); proxy.cert == nil {
+			logger.Errorf("empty cert, close sni connection from %s", conn.RemoteAddr())
+			conn.Close()
+			continue
+		}
 		go proxy.handleTLSConn(conn, port)
 	}
 }
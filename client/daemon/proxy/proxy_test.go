@@ -18,7 +18,9 @@ package proxy
 
 import (
 	"fmt"
+	"io"
 	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"testing"
 
@@ -168,3 +170,98 @@ func TestMatch(t *testing.T) {
 		WithTest("http://index.docker.io/v2/blobs/sha256/xxx", true, false, "").
 		TestMirror(t)
 }
+
+func TestMirrorRegistry(t *testing.T) {
+	a := assert.New(t)
+
+	newUpstream := func(name string, requireAuth bool) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			user, pass, ok := r.BasicAuth()
+			if requireAuth && !ok {
+				w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Basic realm=%q`, name))
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			fmt.Fprintf(w, "%s %s %s:%s", name, r.URL.RequestURI(), user, pass)
+		}))
+	}
+	ghcr, quay, docker := newUpstream("ghcr", true), newUpstream("quay", false), newUpstream("docker", false)
+	defer ghcr.Close()
+	defer quay.Close()
+	defer docker.Close()
+
+	newMirror := func(host, rawURL string, credentials *config.BasicAuth) *config.RegistryMirror {
+		u, err := url.Parse(rawURL)
+		a.Nil(err)
+		return &config.RegistryMirror{
+			Host:        host,
+			Remote:      &config.URL{URL: u},
+			Direct:      true,
+			Credentials: credentials,
+		}
+	}
+
+	tp, err := NewProxy(
+		WithRegistryMirror(newMirror("", docker.URL, nil)),
+		WithRegistryMirrors(
+			newMirror("ghcr.io", ghcr.URL, &config.BasicAuth{Username: "user", Password: "pass"}),
+			newMirror("quay.io", quay.URL, nil),
+		),
+	)
+	a.Nil(err)
+
+	tests := []struct {
+		name     string
+		uri      string
+		registry string
+		auth     bool
+		expect   string
+	}{
+		{
+			name:   "containerd ns query",
+			uri:    "/v2/a/manifests/latest?ns=ghcr.io",
+			expect: "ghcr /v2/a/manifests/latest user:pass",
+		},
+		{
+			name:   "request authorization is kept",
+			uri:    "/v2/a/manifests/latest?ns=ghcr.io",
+			auth:   true,
+			expect: "ghcr /v2/a/manifests/latest u:p",
+		},
+		{
+			name:     "registry header",
+			uri:      "/v2/b/manifests/latest",
+			registry: "https://quay.io",
+			expect:   "quay /v2/b/manifests/latest :",
+		},
+		{
+			name:   "no matched mirror",
+			uri:    "/v2/c/manifests/latest?ns=k8s.gcr.io",
+			expect: "docker /v2/c/manifests/latest?ns=k8s.gcr.io :",
+		},
+		{
+			name:   "default mirror",
+			uri:    "/v2/d/manifests/latest",
+			expect: "docker /v2/d/manifests/latest :",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.uri, nil)
+			if tt.registry != "" {
+				req.Header.Set(config.HeaderDragonflyRegistry, tt.registry)
+			}
+			if tt.auth {
+				req.SetBasicAuth("u", "p")
+			}
+
+			w := httptest.NewRecorder()
+			tp.mirrorRegistry(w, req)
+			body, err := io.ReadAll(w.Result().Body)
+			assert.Nil(t, err)
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, tt.expect, string(body))
+		})
+	}
+}
@@ -31,9 +31,23 @@ func newReverseProxy(mirror *config.RegistryMirror) *httputil.ReverseProxy {
 	if mirror.DynamicRemote {
 		reverseProxy.Director = newDynamicDirector(mirror.Remote.URL)
 	}
+	if mirror.Host != "" {
+		reverseProxy.Director = newMirrorDirector(reverseProxy.Director)
+	}
 	return reverseProxy
 }
 
+// newMirrorDirector removes the "ns" query which is only for mirrors.
+func newMirrorDirector(director func(*http.Request)) func(*http.Request) {
+	return func(req *http.Request) {
+		director(req)
+		if query := req.URL.Query(); query.Get(registryNamespaceQuery) != "" {
+			query.Del(registryNamespaceQuery)
+			req.URL.RawQuery = query.Encode()
+		}
+	}
+}
+
 func newDynamicDirector(remote *url.URL) func(*http.Request) {
 	director := func(req *http.Request) {
 		var target = remote
@@ -0,0 +1,149 @@
+/*
+ *     Copyright 2020 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package daemon
+
+import (
+	"reflect"
+
+	"github.com/pkg/errors"
+	"golang.org/x/time/rate"
+
+	"d7y.io/dragonfly/v2/client/config"
+	logger "d7y.io/dragonfly/v2/internal/dflog"
+	"d7y.io/dragonfly/v2/manager/types"
+)
+
+// reloadableOption is the part of daemon option which can be changed without restarting daemon.
+type reloadableOption struct {
+	totalRateLimit   rate.Limit
+	perPeerRateLimit rate.Limit
+	uploadRateLimit  rate.Limit
+	proxy            *config.ProxyOption
+}
+
+func newReloadableOption(opt *config.DaemonOption) reloadableOption {
+	return reloadableOption{
+		totalRateLimit:   opt.Download.TotalRateLimit.Limit,
+		perPeerRateLimit: opt.Download.PerPeerRateLimit.Limit,
+		uploadRateLimit:  opt.Upload.RateLimit.Limit,
+		proxy:            opt.Proxy,
+	}
+}
+
+// override returns the option overridden by the client config of scheduler cluster.
+func (o reloadableOption) override(clientConfig *types.SchedulerClusterClientConfig) (reloadableOption, error) {
+	if clientConfig.TotalDownloadRateLimit > 0 {
+		o.totalRateLimit = rate.Limit(clientConfig.TotalDownloadRateLimit)
+	}
+	if clientConfig.PerPeerDownloadRateLimit > 0 {
+		o.perPeerRateLimit = rate.Limit(clientConfig.PerPeerDownloadRateLimit)
+	}
+	if clientConfig.UploadRateLimit > 0 {
+		o.uploadRateLimit = rate.Limit(clientConfig.UploadRateLimit)
+	}
+	if clientConfig.Proxy == nil || o.proxy == nil {
+		return o, nil
+	}
+
+	proxy := *o.proxy
+	if clientConfig.Proxy.Rules != nil {
+		proxy.Proxies = make([]*config.Proxy, 0, len(clientConfig.Proxy.Rules))
+		for _, r := range clientConfig.Proxy.Rules {
+			rule, err := config.NewProxy(r.Regx, r.UseHTTPS, r.Direct, r.Redirect)
+			if err != nil {
+				return o, errors.Wrapf(err, "proxy rule %q", r.Regx)
+			}
+			proxy.Proxies = append(proxy.Proxies, rule)
+		}
+	}
+	if clientConfig.Proxy.WhiteList != nil {
+		proxy.WhiteList = make([]*config.WhiteList, 0, len(clientConfig.Proxy.WhiteList))
+		for _, w := range clientConfig.Proxy.WhiteList {
+			whiteList := &config.WhiteList{Host: w.Host, Ports: w.Ports}
+			if w.Regx != "" {
+				regx, err := config.NewRegexp(w.Regx)
+				if err != nil {
+					return o, errors.Wrapf(err, "white list %q", w.Regx)
+				}
+				whiteList.Regx = regx
+			}
+			proxy.WhiteList = append(proxy.WhiteList, whiteList)
+		}
+	}
+	if clientConfig.Proxy.HijackHosts != nil {
+		var hijackHTTPS config.HijackConfig
+		if proxy.HijackHTTPS != nil {
+			hijackHTTPS = *proxy.HijackHTTPS
+		}
+		hijackHTTPS.Hosts = make([]*config.HijackHost, 0, len(clientConfig.Proxy.HijackHosts))
+		for _, h := range clientConfig.Proxy.HijackHosts {
+			regx, err := config.NewRegexp(h.Regx)
+			if err != nil {
+				return o, errors.Wrapf(err, "hijack host %q", h.Regx)
+			}
+			hijackHTTPS.Hosts = append(hijackHTTPS.Hosts, &config.HijackHost{Regx: regx, Insecure: h.Insecure})
+		}
+		proxy.HijackHTTPS = &hijackHTTPS
+	}
+	o.proxy = &proxy
+	return o, nil
+}
+
+// Reload applies the proxy rules, white list, hijack hosts, registry mirrors and rate limits of the new option,
+// the other options take effect after restart. Running tasks and proxy requests are not interrupted.
+func (cd *clientDaemon) Reload(opt *config.DaemonOption) error {
+	cd.reloadLock.Lock()
+	defer cd.reloadLock.Unlock()
+
+	cd.localOption = newReloadableOption(opt)
+	return cd.applyReloadableOption()
+}
+
+// applyReloadableOption applies the local option overridden by the client config of scheduler cluster,
+// the caller must hold reloadLock.
+func (cd *clientDaemon) applyReloadableOption() error {
+	opt, err := cd.localOption.override(&cd.clusterClientConfig)
+	if err != nil {
+		return errors.Wrap(err, "invalid scheduler cluster client config")
+	}
+
+	if opt.totalRateLimit != cd.appliedOption.totalRateLimit {
+		cd.downloadLimiter.SetLimit(opt.totalRateLimit)
+		cd.downloadLimiter.SetBurst(int(opt.totalRateLimit))
+		logger.Infof("total download rate limit is updated to %.0f", opt.totalRateLimit)
+	}
+	if opt.perPeerRateLimit != cd.appliedOption.perPeerRateLimit {
+		cd.PeerTaskManager.SetPerPeerRateLimit(opt.perPeerRateLimit)
+		logger.Infof("per peer download rate limit is updated to %.0f", opt.perPeerRateLimit)
+	}
+	if opt.uploadRateLimit != cd.appliedOption.uploadRateLimit {
+		cd.uploadLimiter.SetLimit(opt.uploadRateLimit)
+		cd.uploadLimiter.SetBurst(int(opt.uploadRateLimit))
+		logger.Infof("upload rate limit is updated to %.0f", opt.uploadRateLimit)
+	}
+	cd.appliedOption.totalRateLimit = opt.totalRateLimit
+	cd.appliedOption.perPeerRateLimit = opt.perPeerRateLimit
+	cd.appliedOption.uploadRateLimit = opt.uploadRateLimit
+
+	if cd.ProxyManager.IsEnabled() && opt.proxy != nil && !reflect.DeepEqual(opt.proxy, cd.appliedOption.proxy) {
+		if err := cd.ProxyManager.Reload(opt.proxy); err != nil {
+			return errors.Wrap(err, "reload proxy")
+		}
+		cd.appliedOption.proxy = opt.proxy
+	}
+	return nil
+}
@@ -0,0 +1,198 @@
+/*
+ *     Copyright 2020 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package daemon
+
+import (
+	"net"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/time/rate"
+
+	"d7y.io/dragonfly/v2/client/clientutil"
+	"d7y.io/dragonfly/v2/client/config"
+	mock_peer "d7y.io/dragonfly/v2/client/daemon/test/mock/peer"
+	"d7y.io/dragonfly/v2/manager/types"
+)
+
+type testProxyManager struct {
+	enabled bool
+	reloads []*config.ProxyOption
+}
+
+func (m *testProxyManager) Serve(net.Listener) error    { return nil }
+func (m *testProxyManager) ServeSNI(net.Listener) error { return nil }
+func (m *testProxyManager) Stop() error                 { return nil }
+func (m *testProxyManager) IsEnabled() bool             { return m.enabled }
+
+func (m *testProxyManager) Reload(opt *config.ProxyOption) error {
+	m.reloads = append(m.reloads, opt)
+	return nil
+}
+
+func testDaemonOption(limit rate.Limit, rules ...string) *config.DaemonOption {
+	opt := &config.DaemonOption{
+		Download: config.DownloadOption{
+			TotalRateLimit:   clientutil.RateLimit{Limit: limit},
+			PerPeerRateLimit: clientutil.RateLimit{Limit: limit},
+		},
+		Upload: config.UploadOption{
+			RateLimit: clientutil.RateLimit{Limit: limit},
+		},
+		Proxy: &config.ProxyOption{
+			HijackHTTPS: &config.HijackConfig{Cert: "cert", Key: "key"},
+		},
+	}
+	for _, rule := range rules {
+		proxy, _ := config.NewProxy(rule, false, false, "")
+		opt.Proxy.Proxies = append(opt.Proxy.Proxies, proxy)
+	}
+	return opt
+}
+
+func TestReloadableOptionOverride(t *testing.T) {
+	tests := []struct {
+		name         string
+		clientConfig types.SchedulerClusterClientConfig
+		expect       func(t *testing.T, local, opt reloadableOption, err error)
+	}{
+		{
+			name: "no overrides",
+			expect: func(t *testing.T, local, opt reloadableOption, err error) {
+				assert := assert.New(t)
+				assert.Nil(err)
+				assert.Equal(local, opt)
+			},
+		},
+		{
+			name: "override rate limits",
+			clientConfig: types.SchedulerClusterClientConfig{
+				TotalDownloadRateLimit:   300,
+				PerPeerDownloadRateLimit: 200,
+			},
+			expect: func(t *testing.T, local, opt reloadableOption, err error) {
+				assert := assert.New(t)
+				assert.Nil(err)
+				assert.Equal(rate.Limit(300), opt.totalRateLimit)
+				assert.Equal(rate.Limit(200), opt.perPeerRateLimit)
+				assert.Equal(local.uploadRateLimit, opt.uploadRateLimit)
+				assert.Equal(local.proxy, opt.proxy)
+			},
+		},
+		{
+			name: "override proxy",
+			clientConfig: types.SchedulerClusterClientConfig{
+				Proxy: &types.SchedulerClusterClientProxyConfig{
+					Rules:       []*types.ProxyRule{{Regx: "blobs/sha256.*", Direct: true}},
+					WhiteList:   []*types.ProxyWhiteList{{Host: "example.com", Ports: []string{"80"}}},
+					HijackHosts: []*types.ProxyHijackHost{{Regx: "index.docker.io", Insecure: true}},
+				},
+			},
+			expect: func(t *testing.T, local, opt reloadableOption, err error) {
+				assert := assert.New(t)
+				assert.Nil(err)
+				assert.Len(opt.proxy.Proxies, 1)
+				assert.True(opt.proxy.Proxies[0].Direct)
+				assert.True(opt.proxy.Proxies[0].Match("http://example.com/v2/blobs/sha256:foo"))
+				assert.Len(opt.proxy.WhiteList, 1)
+				assert.Equal("example.com", opt.proxy.WhiteList[0].Host)
+				assert.Len(opt.proxy.HijackHTTPS.Hosts, 1)
+				assert.Equal("cert", opt.proxy.HijackHTTPS.Cert)
+				assert.True(opt.proxy.HijackHTTPS.Hosts[0].Insecure)
+
+				// local option is not modified
+				assert.Len(local.proxy.Proxies, 1)
+				assert.False(local.proxy.Proxies[0].Direct)
+				assert.Nil(local.proxy.WhiteList)
+				assert.Nil(local.proxy.HijackHTTPS.Hosts)
+			},
+		},
+		{
+			name: "invalid proxy rule",
+			clientConfig: types.SchedulerClusterClientConfig{
+				Proxy: &types.SchedulerClusterClientProxyConfig{
+					Rules: []*types.ProxyRule{{Regx: "("}},
+				},
+			},
+			expect: func(t *testing.T, local, opt reloadableOption, err error) {
+				assert := assert.New(t)
+				assert.Error(err)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			local := newReloadableOption(testDaemonOption(100, "blobs/sha256.*"))
+			opt, err := local.override(&tc.clientConfig)
+			tc.expect(t, local, opt, err)
+		})
+	}
+}
+
+func TestClientDaemonReload(t *testing.T) {
+	assert := assert.New(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	opt := testDaemonOption(100, "blobs/sha256.*")
+	peerTaskManager := mock_peer.NewMockTaskManager(ctrl)
+	proxyManager := &testProxyManager{enabled: true}
+	cd := &clientDaemon{
+		PeerTaskManager: peerTaskManager,
+		ProxyManager:    proxyManager,
+		downloadLimiter: rate.NewLimiter(100, 100),
+		uploadLimiter:   rate.NewLimiter(100, 100),
+		localOption:     newReloadableOption(opt),
+		appliedOption:   newReloadableOption(opt),
+	}
+
+	// nothing changed
+	assert.Nil(cd.Reload(testDaemonOption(100, "blobs/sha256.*")))
+	assert.Empty(proxyManager.reloads)
+
+	// local rate limits and rules changed
+	peerTaskManager.EXPECT().SetPerPeerRateLimit(rate.Limit(200)).Times(1)
+	assert.Nil(cd.Reload(testDaemonOption(200, "manifests.*")))
+	assert.Equal(rate.Limit(200), cd.downloadLimiter.Limit())
+	assert.Equal(200, cd.downloadLimiter.Burst())
+	assert.Equal(rate.Limit(200), cd.uploadLimiter.Limit())
+	assert.Len(proxyManager.reloads, 1)
+	assert.True(proxyManager.reloads[0].Proxies[0].Match("manifests/latest"))
+
+	// scheduler cluster overrides the local option
+	peerTaskManager.EXPECT().SetPerPeerRateLimit(rate.Limit(50)).Times(1)
+	cd.reloadLock.Lock()
+	cd.clusterClientConfig = types.SchedulerClusterClientConfig{
+		PerPeerDownloadRateLimit: 50,
+		Proxy: &types.SchedulerClusterClientProxyConfig{
+			Rules: []*types.ProxyRule{{Regx: "blobs/sha256.*", Direct: true}},
+		},
+	}
+	assert.Nil(cd.applyReloadableOption())
+	cd.reloadLock.Unlock()
+	assert.Equal(rate.Limit(200), cd.downloadLimiter.Limit())
+	assert.Len(proxyManager.reloads, 2)
+	assert.True(proxyManager.reloads[1].Proxies[0].Direct)
+
+	// invalid scheduler cluster config keeps the applied option
+	cd.clusterClientConfig.Proxy.Rules = []*types.ProxyRule{{Regx: "("}}
+	assert.Error(cd.Reload(testDaemonOption(300)))
+	assert.Equal(rate.Limit(200), cd.downloadLimiter.Limit())
+	assert.Len(proxyManager.reloads, 2)
+}
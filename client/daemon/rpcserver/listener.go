@@ -0,0 +1,149 @@
+/*
+ *     Copyright 2020 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rpcserver
+
+import (
+	"bufio"
+	"net"
+	"sync"
+	"time"
+
+	"go.uber.org/atomic"
+
+	logger "d7y.io/dragonfly/v2/internal/dflog"
+)
+
+// sniffTimeout is the max duration to wait the first bytes of a new connection
+const sniffTimeout = 10 * time.Second
+
+// httpMethodPrefixes are the first bytes of http/1.x requests, other connections are served by grpc
+var httpMethodPrefixes = []string{"GET", "PUT", "POS", "DEL", "HEA", "OPT", "PAT"}
+
+// splitListener splits connections of listener by the first bytes,
+// http/1.x connections are accepted by httpListener, others like http2 and tls are accepted by grpcListener.
+// listener is closed when both of them are closed.
+func splitListener(listener net.Listener) (grpcListener net.Listener, httpListener net.Listener) {
+	parent := &sharedListener{Listener: listener, refs: atomic.NewInt32(2)}
+	grpcLn := newChanListener(parent)
+	httpLn := newChanListener(parent)
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				grpcLn.closeWithError(err)
+				httpLn.closeWithError(err)
+				return
+			}
+			go dispatchConn(conn, grpcLn, httpLn)
+		}
+	}()
+	return grpcLn, httpLn
+}
+
+func dispatchConn(conn net.Conn, grpcLn, httpLn *chanListener) {
+	reader := bufio.NewReader(conn)
+	_ = conn.SetReadDeadline(time.Now().Add(sniffTimeout))
+	prefix, err := reader.Peek(3)
+	_ = conn.SetReadDeadline(time.Time{})
+	if err != nil {
+		logger.Debugf("sniff connection from %s error: %s", conn.RemoteAddr(), err)
+		_ = conn.Close()
+		return
+	}
+
+	target := grpcLn
+	for _, method := range httpMethodPrefixes {
+		if string(prefix) == method {
+			target = httpLn
+			break
+		}
+	}
+	select {
+	case target.conns <- &sniffedConn{Conn: conn, reader: reader}:
+	case <-target.done:
+		_ = conn.Close()
+	}
+}
+
+// sniffedConn reads the sniffed bytes first
+type sniffedConn struct {
+	net.Conn
+	reader *bufio.Reader
+}
+
+func (c *sniffedConn) Read(b []byte) (int, error) {
+	return c.reader.Read(b)
+}
+
+// sharedListener is the listener shared by split listeners, it is closed by the last one
+type sharedListener struct {
+	net.Listener
+	refs *atomic.Int32
+}
+
+func (l *sharedListener) release() error {
+	if l.refs.Dec() == 0 {
+		return l.Listener.Close()
+	}
+	return nil
+}
+
+// chanListener accepts connections dispatched by splitListener
+type chanListener struct {
+	parent *sharedListener
+	conns  chan net.Conn
+	done   chan struct{}
+	once   sync.Once
+	// err is returned by Accept after done is closed
+	err error
+}
+
+func newChanListener(parent *sharedListener) *chanListener {
+	return &chanListener{
+		parent: parent,
+		conns:  make(chan net.Conn),
+		done:   make(chan struct{}),
+	}
+}
+
+func (l *chanListener) Accept() (net.Conn, error) {
+	select {
+	case conn := <-l.conns:
+		return conn, nil
+	case <-l.done:
+		return nil, l.err
+	}
+}
+
+func (l *chanListener) Close() error {
+	return l.closeWithError(net.ErrClosed)
+}
+
+// closeWithError stops accepting connections, the parent listener is closed with the last chanListener,
+// so that the accepting goroutine of splitListener exits.
+func (l *chanListener) closeWithError(err error) (closeErr error) {
+	l.once.Do(func() {
+		l.err = err
+		close(l.done)
+		closeErr = l.parent.release()
+	})
+	return
+}
+
+func (l *chanListener) Addr() net.Addr {
+	return l.parent.Addr()
+}
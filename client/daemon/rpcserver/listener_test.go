@@ -0,0 +1,89 @@
+/*
+ *     Copyright 2020 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rpcserver
+
+import (
+	"errors"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	testifyassert "github.com/stretchr/testify/assert"
+)
+
+// acceptRecordListener closes accepted when Accept returns error
+type acceptRecordListener struct {
+	net.Listener
+	accepted chan struct{}
+}
+
+func (l *acceptRecordListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		close(l.accepted)
+	}
+	return conn, err
+}
+
+func TestSplitListener(t *testing.T) {
+	assert := testifyassert.New(t)
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(err)
+	parent := &acceptRecordListener{Listener: ln, accepted: make(chan struct{})}
+	grpcLn, httpLn := splitListener(parent)
+
+	for _, tc := range []struct {
+		data     string
+		listener net.Listener
+	}{
+		{data: "GET / HTTP/1.1\r\n", listener: httpLn},
+		{data: "PRI * HTTP/2.0\r\n", listener: grpcLn},
+	} {
+		client, err := net.Dial("tcp", ln.Addr().String())
+		assert.Nil(err)
+		_, err = client.Write([]byte(tc.data))
+		assert.Nil(err)
+		conn, err := tc.listener.Accept()
+		assert.Nil(err)
+		buf := make([]byte, len(tc.data))
+		_, err = io.ReadFull(conn, buf)
+		assert.Nil(err)
+		assert.Equal(tc.data, string(buf), "sniffed bytes should be read again")
+		conn.Close()
+		client.Close()
+	}
+
+	// parent is kept until the last split listener is closed
+	assert.Nil(grpcLn.Close())
+	_, err = grpcLn.Accept()
+	assert.True(errors.Is(err, net.ErrClosed))
+	select {
+	case <-parent.accepted:
+		t.Fatal("parent listener is closed with one split listener")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	assert.Nil(httpLn.Close())
+	select {
+	case <-parent.accepted:
+	case <-time.After(5 * time.Second):
+		t.Fatal("accepting goroutine is not stopped")
+	}
+	_, err = net.Dial("tcp", ln.Addr().String())
+	assert.NotNil(err)
+}
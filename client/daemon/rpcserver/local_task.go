@@ -0,0 +1,445 @@
+/*
+ *     Copyright 2020 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rpcserver
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"google.golang.org/grpc/codes"
+	grpcpeer "google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"d7y.io/dragonfly/v2/client/daemon/peer"
+	"d7y.io/dragonfly/v2/client/daemon/storage"
+	"d7y.io/dragonfly/v2/internal/dferrors"
+	logger "d7y.io/dragonfly/v2/internal/dflog"
+	"d7y.io/dragonfly/v2/pkg/idgen"
+	"d7y.io/dragonfly/v2/pkg/rpc/base"
+	dfdaemongrpc "d7y.io/dragonfly/v2/pkg/rpc/dfdaemon"
+	"d7y.io/dragonfly/v2/pkg/rpc/scheduler"
+)
+
+const (
+	// LocalTasksHTTPPath lists all tasks in local storage
+	LocalTasksHTTPPath = "/api/v1/tasks"
+	// LocalTaskHTTPPath stats with GET and deletes with DELETE a task in local storage
+	LocalTaskHTTPPath = "/api/v1/task"
+	// LocalTaskPinHTTPPath pins with PUT and unpins with DELETE a task in local storage
+	LocalTaskPinHTTPPath = "/api/v1/task/pin"
+	// LocalTaskImportHTTPPath imports a local file as a completed task with POST
+	LocalTaskImportHTTPPath = "/api/v1/task/import"
+	// LocalTaskExportHTTPPath exports a cached task to a local file with POST
+	LocalTaskExportHTTPPath = "/api/v1/task/export"
+)
+
+func (m *server) ListTasks(ctx context.Context) (*dfdaemongrpc.LocalTasks, error) {
+	if err := checkLocalCaller(ctx); err != nil {
+		return nil, err
+	}
+	return m.listTasks(), nil
+}
+
+func (m *server) StatTask(ctx context.Context, req *dfdaemongrpc.TaskRequest) (*dfdaemongrpc.LocalTasks, error) {
+	if err := checkLocalCaller(ctx); err != nil {
+		return nil, err
+	}
+	return m.statTask(ctx, req)
+}
+
+func (m *server) DeleteTask(ctx context.Context, req *dfdaemongrpc.TaskRequest) error {
+	if err := checkLocalCaller(ctx); err != nil {
+		return err
+	}
+	return m.deleteTask(req)
+}
+
+func (m *server) PinTask(ctx context.Context, req *dfdaemongrpc.PinTaskRequest) error {
+	if err := checkLocalCaller(ctx); err != nil {
+		return err
+	}
+	return m.pinTask(req.Task, req.Pin)
+}
+
+func (m *server) ImportTask(ctx context.Context, req *dfdaemongrpc.ImportTaskRequest) (*dfdaemongrpc.LocalTask, error) {
+	if err := checkLocalCaller(ctx); err != nil {
+		return nil, err
+	}
+	return m.importTask(ctx, req)
+}
+
+func (m *server) ExportTask(ctx context.Context, req *dfdaemongrpc.ExportTaskRequest) (*dfdaemongrpc.LocalTask, error) {
+	if err := checkLocalCaller(ctx); err != nil {
+		return nil, err
+	}
+	return m.exportTask(ctx, req)
+}
+
+// checkLocalCaller rejects task management requests from other peers, they are only served on the unix socket
+func checkLocalCaller(ctx context.Context) error {
+	if p, ok := grpcpeer.FromContext(ctx); ok && p.Addr.Network() == "unix" {
+		return nil
+	}
+	return status.Error(codes.PermissionDenied, "local task management is only allowed on unix socket")
+}
+
+func (m *server) listTasks() *dfdaemongrpc.LocalTasks {
+	m.Keep()
+	return toLocalTasks(m.storageManager.ListTasks())
+}
+
+func (m *server) statTask(ctx context.Context, req *dfdaemongrpc.TaskRequest) (*dfdaemongrpc.LocalTasks, error) {
+	m.Keep()
+	taskID, err := localTaskID(req)
+	if err != nil {
+		return nil, err
+	}
+	tasks, err := m.storageManager.StatTask(taskID)
+	if err == storage.ErrTaskNotFound && req.Cluster {
+		return m.statClusterTask(ctx, taskID)
+	}
+	if err != nil {
+		return nil, convertStorageError(taskID, err)
+	}
+	return toLocalTasks(tasks), nil
+}
+
+// statClusterTask stats the task in scheduler cluster, the task is cached in cluster when any peer finished it
+func (m *server) statClusterTask(ctx context.Context, taskID string) (*dfdaemongrpc.LocalTasks, error) {
+	task, err := m.peerTaskManager.StatTask(ctx, taskID)
+	if err != nil {
+		if de, ok := err.(*dferrors.DfError); ok && de.Code == base.Code_PeerTaskNotFound {
+			return nil, dferrors.Newf(base.Code_PeerTaskNotFound, "task %s not found in local storage and cluster", taskID)
+		}
+		return nil, dferrors.Newf(base.Code_ClientError, "stat task %s in cluster error: %s", taskID, err)
+	}
+	if !task.HasAvailablePeer {
+		return nil, dferrors.Newf(base.Code_PeerTaskNotFound, "task %s is not cached by any peer in cluster, state: %s", taskID, task.State)
+	}
+	return &dfdaemongrpc.LocalTasks{Cluster: task}, nil
+}
+
+func (m *server) deleteTask(req *dfdaemongrpc.TaskRequest) error {
+	m.Keep()
+	taskID, err := localTaskID(req)
+	if err != nil {
+		return err
+	}
+	tasks, err := m.storageManager.StatTask(taskID)
+	if err != nil {
+		return convertStorageError(taskID, err)
+	}
+	for _, t := range tasks {
+		if m.peerTaskManager.IsPeerTaskRunning(t.PeerID) {
+			return dferrors.Newf(base.Code_BadRequest, "task %s is downloading by peer %s", taskID, t.PeerID)
+		}
+	}
+	if err = m.storageManager.DeleteTask(taskID); err != nil {
+		return convertStorageError(taskID, err)
+	}
+	logger.Infof("local task %s deleted", taskID)
+	return nil
+}
+
+func (m *server) pinTask(req *dfdaemongrpc.TaskRequest, pin bool) error {
+	m.Keep()
+	taskID, err := localTaskID(req)
+	if err != nil {
+		return err
+	}
+	if err = m.storageManager.PinTask(taskID, pin); err != nil {
+		return convertStorageError(taskID, err)
+	}
+	logger.Infof("local task %s pinned: %v", taskID, pin)
+	return nil
+}
+
+func (m *server) importTask(ctx context.Context, req *dfdaemongrpc.ImportTaskRequest) (*dfdaemongrpc.LocalTask, error) {
+	m.Keep()
+	if req.Url == "" {
+		return nil, dferrors.New(base.Code_BadRequest, "url is required")
+	}
+	if !filepath.IsAbs(req.Path) {
+		return nil, dferrors.Newf(base.Code_BadRequest, "path %q is not an absolute path", req.Path)
+	}
+	taskID := idgen.TaskID(req.Url, req.UrlMeta)
+	info, err := m.peerTaskManager.ImportTask(ctx, &peer.ImportTaskRequest{
+		PeerTaskRequest: scheduler.PeerTaskRequest{
+			Url:      req.Url,
+			UrlMeta:  req.UrlMeta,
+			PeerId:   idgen.PeerID(m.peerHost.Ip),
+			PeerHost: m.peerHost,
+		},
+		Path: req.Path,
+	})
+	switch {
+	case err == nil:
+	case info != nil:
+		return nil, dferrors.Newf(base.Code_ClientError, "task %s is imported, but announce to scheduler failed: %s", taskID, err)
+	case err == peer.ErrTaskExists:
+		return nil, dferrors.Newf(base.Code_BadRequest, "task %s already exists, delete it before importing", taskID)
+	case err == peer.ErrEmptyFile, os.IsNotExist(err):
+		return nil, dferrors.Newf(base.Code_BadRequest, "import %s error: %s", req.Path, err)
+	default:
+		return nil, dferrors.New(base.Code_ClientError, err.Error())
+	}
+	logger.Infof("local file %s imported as task %s", req.Path, taskID)
+	return toLocalTasks([]*storage.TaskInfo{info}).Tasks[0], nil
+}
+
+func (m *server) exportTask(ctx context.Context, req *dfdaemongrpc.ExportTaskRequest) (*dfdaemongrpc.LocalTask, error) {
+	m.Keep()
+	taskID, err := localTaskID(req.Task)
+	if err != nil {
+		return nil, err
+	}
+	if !filepath.IsAbs(req.Output) {
+		return nil, dferrors.Newf(base.Code_BadRequest, "output %q is not an absolute path", req.Output)
+	}
+
+	if reuse := m.storageManager.FindCompletedTask(taskID); reuse != nil {
+		err = m.storageManager.Store(ctx,
+			&storage.StoreRequest{
+				CommonTaskRequest: storage.CommonTaskRequest{
+					PeerID:      reuse.PeerID,
+					TaskID:      taskID,
+					Destination: req.Output,
+				},
+				StoreOnly:   true,
+				TotalPieces: reuse.TotalPieces,
+			})
+		if err != nil {
+			return nil, dferrors.Newf(base.Code_ClientError, "export task %s error: %s", taskID, err)
+		}
+		if req.Uid != 0 && req.Gid != 0 {
+			if err = os.Chown(req.Output, int(req.Uid), int(req.Gid)); err != nil {
+				return nil, dferrors.Newf(base.Code_ClientError, "change own of %s error: %s", req.Output, err)
+			}
+		}
+		logger.Infof("local task %s exported to %s", taskID, req.Output)
+		return &dfdaemongrpc.LocalTask{
+			TaskId:          taskID,
+			PeerId:          reuse.PeerID,
+			ContentLength:   reuse.ContentLength,
+			TotalPiece:      reuse.TotalPieces,
+			CompletedLength: reuse.ContentLength,
+			Done:            true,
+		}, nil
+	}
+
+	if req.LocalOnly {
+		return nil, dferrors.Newf(base.Code_PeerTaskNotFound, "task %s not found in local storage", taskID)
+	}
+	if req.Task.Url == "" {
+		return nil, dferrors.Newf(base.Code_BadRequest, "task %s not found in local storage, url is required to export it from other peers", taskID)
+	}
+
+	// download the task from other peers, back source is disabled, so only cached tasks in cluster can be exported
+	var (
+		last    *dfdaemongrpc.DownResult
+		results = make(chan *dfdaemongrpc.DownResult)
+		done    = make(chan struct{})
+	)
+	go func() {
+		defer close(done)
+		for result := range results {
+			last = result
+		}
+	}()
+	err = m.Download(ctx, &dfdaemongrpc.DownRequest{
+		Url:               req.Task.Url,
+		UrlMeta:           req.Task.UrlMeta,
+		Output:            req.Output,
+		Limit:             req.Limit,
+		DisableBackSource: true,
+		Uid:               req.Uid,
+		Gid:               req.Gid,
+	}, results)
+	close(results)
+	<-done
+	if err != nil {
+		if _, ok := err.(*dferrors.DfError); ok {
+			return nil, err
+		}
+		return nil, dferrors.Newf(base.Code_ClientError, "export task %s error: %s", taskID, err)
+	}
+	if last == nil || !last.Done {
+		return nil, dferrors.Newf(base.Code_ClientError, "export task %s error: download not done", taskID)
+	}
+	logger.Infof("task %s exported to %s from other peers", taskID, req.Output)
+	return &dfdaemongrpc.LocalTask{
+		TaskId:          last.TaskId,
+		PeerId:          last.PeerId,
+		ContentLength:   int64(last.CompletedLength),
+		CompletedLength: int64(last.CompletedLength),
+		Done:            true,
+	}, nil
+}
+
+// localTaskID returns task id in request, it is generated from url and url meta when it is empty
+func localTaskID(req *dfdaemongrpc.TaskRequest) (string, error) {
+	if req == nil {
+		return "", dferrors.New(base.Code_BadRequest, "empty task request")
+	}
+	if req.TaskId != "" {
+		return req.TaskId, nil
+	}
+	if req.Url == "" {
+		return "", dferrors.New(base.Code_BadRequest, "task id or url is required")
+	}
+	return idgen.TaskID(req.Url, req.UrlMeta), nil
+}
+
+func convertStorageError(taskID string, err error) error {
+	if err == storage.ErrTaskNotFound {
+		return dferrors.Newf(base.Code_PeerTaskNotFound, "task %s not found", taskID)
+	}
+	return dferrors.New(base.Code_ClientError, err.Error())
+}
+
+func toLocalTasks(tasks []*storage.TaskInfo) *dfdaemongrpc.LocalTasks {
+	result := &dfdaemongrpc.LocalTasks{}
+	for _, t := range tasks {
+		task := &dfdaemongrpc.LocalTask{
+			TaskId:          t.TaskID,
+			PeerId:          t.PeerID,
+			ContentLength:   t.ContentLength,
+			TotalPiece:      t.TotalPieces,
+			CompletedLength: t.CompletedLength,
+			Done:            t.Done,
+			Pinned:          t.Pinned,
+			LastAccessTime:  t.LastAccessTime.UnixNano(),
+		}
+		if !t.CreateTime.IsZero() {
+			task.CreateTime = t.CreateTime.UnixNano()
+		}
+		result.Tasks = append(result.Tasks, task)
+	}
+	return result
+}
+
+// localTaskHandler serves local task management http api, it is only served on the unix socket
+func (m *server) localTaskHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(LocalTasksHTTPPath, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		writeLocalTaskResult(w, m.listTasks(), nil)
+	})
+	mux.HandleFunc(LocalTaskHTTPPath, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			tasks, err := m.statTask(r.Context(), parseTaskRequest(r))
+			writeLocalTaskResult(w, tasks, err)
+		case http.MethodDelete:
+			writeLocalTaskResult(w, nil, m.deleteTask(parseTaskRequest(r)))
+		default:
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+	mux.HandleFunc(LocalTaskPinHTTPPath, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPut:
+			writeLocalTaskResult(w, nil, m.pinTask(parseTaskRequest(r), true))
+		case http.MethodDelete:
+			writeLocalTaskResult(w, nil, m.pinTask(parseTaskRequest(r), false))
+		default:
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+	mux.HandleFunc(LocalTaskImportHTTPPath, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		taskReq := parseTaskRequest(r)
+		task, err := m.importTask(r.Context(), &dfdaemongrpc.ImportTaskRequest{
+			Url:     taskReq.Url,
+			UrlMeta: taskReq.UrlMeta,
+			Path:    r.URL.Query().Get("path"),
+		})
+		var tasks *dfdaemongrpc.LocalTasks
+		if task != nil {
+			tasks = &dfdaemongrpc.LocalTasks{Tasks: []*dfdaemongrpc.LocalTask{task}}
+		}
+		writeLocalTaskResult(w, tasks, err)
+	})
+	mux.HandleFunc(LocalTaskExportHTTPPath, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		task, err := m.exportTask(r.Context(), &dfdaemongrpc.ExportTaskRequest{
+			Task:      parseTaskRequest(r),
+			Output:    r.URL.Query().Get("output"),
+			LocalOnly: r.URL.Query().Get("localOnly") == "true",
+		})
+		var tasks *dfdaemongrpc.LocalTasks
+		if task != nil {
+			tasks = &dfdaemongrpc.LocalTasks{Tasks: []*dfdaemongrpc.LocalTask{task}}
+		}
+		writeLocalTaskResult(w, tasks, err)
+	})
+	return mux
+}
+
+// parseTaskRequest parses task request from query, like ?taskId=xxx or ?url=xxx&tag=xxx&filter=xxx&digest=xxx&range=xxx,
+// stat request has an extra cluster parameter, import request has an extra path parameter,
+// and export request has extra output and localOnly parameters
+func parseTaskRequest(r *http.Request) *dfdaemongrpc.TaskRequest {
+	query := r.URL.Query()
+	return &dfdaemongrpc.TaskRequest{
+		TaskId: query.Get("taskId"),
+		Url:    query.Get("url"),
+		UrlMeta: &base.UrlMeta{
+			Digest: query.Get("digest"),
+			Tag:    query.Get("tag"),
+			Range:  query.Get("range"),
+			Filter: query.Get("filter"),
+		},
+		Cluster: query.Get("cluster") == "true",
+	}
+}
+
+func writeLocalTaskResult(w http.ResponseWriter, tasks *dfdaemongrpc.LocalTasks, err error) {
+	if err != nil {
+		code := http.StatusInternalServerError
+		if de, ok := err.(*dferrors.DfError); ok {
+			switch de.Code {
+			case base.Code_BadRequest:
+				code = http.StatusBadRequest
+			case base.Code_PeerTaskNotFound:
+				code = http.StatusNotFound
+			}
+		}
+		http.Error(w, err.Error(), code)
+		return
+	}
+	if tasks == nil {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err = json.NewEncoder(w).Encode(tasks); err != nil {
+		logger.Errorf("write local tasks error: %s", err)
+	}
+}
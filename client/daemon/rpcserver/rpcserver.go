@@ -19,17 +19,26 @@ package rpcserver
 import (
 	"context"
 	"fmt"
+	"io"
+	"math"
 	"net"
+	"net/http"
 	"os"
+	"strconv"
+	"time"
 
+	"github.com/go-http-utils/headers"
 	"github.com/pkg/errors"
+	"golang.org/x/time/rate"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
 	"d7y.io/dragonfly/v2/client/clientutil"
+	"d7y.io/dragonfly/v2/client/config"
 	"d7y.io/dragonfly/v2/client/daemon/peer"
 	"d7y.io/dragonfly/v2/client/daemon/storage"
+	"d7y.io/dragonfly/v2/client/daemon/upload"
 	"d7y.io/dragonfly/v2/internal/dferrors"
 	logger "d7y.io/dragonfly/v2/internal/dflog"
 	"d7y.io/dragonfly/v2/pkg/idgen"
@@ -53,24 +62,69 @@ type server struct {
 	storageManager  storage.Manager
 
 	downloadServer *grpc.Server
-	peerServer     *grpc.Server
-	uploadAddr     string
+	// downloadHTTPServer serves local task management api on the download unix socket
+	downloadHTTPServer *http.Server
+	peerServer         *grpc.Server
+	uploadAddr         string
+	// uploadLimiter limits pieces uploaded by grpc, it is shared with upload manager
+	uploadLimiter *rate.Limiter
+	// uploadAdmission limits concurrent pieces uploaded by grpc, it is shared with upload manager
+	uploadAdmission *upload.Admission
 }
 
-func New(peerHost *scheduler.PeerHost, peerTaskManager peer.TaskManager, storageManager storage.Manager, downloadOpts []grpc.ServerOption, peerOpts []grpc.ServerOption) (Server, error) {
+// pieceChunkSize is the max content size of one piece data message
+const pieceChunkSize = 128 * 1024
+
+const (
+	// defaultSyncPieceLimit is the max pieces of one piece packet pushed by SyncPieceTasks
+	defaultSyncPieceLimit = 16
+	// syncPieceWindow is the max pieces to scan in one round when total piece count is unknown
+	syncPieceWindow = 1024
+	// syncPieceTaskInterval is the interval to check whether the task storage is registered
+	syncPieceTaskInterval = 100 * time.Millisecond
+)
+
+type Option func(*server)
+
+// WithUploadLimiter sets upload rate limiter of pieces uploaded by grpc, the burst size must be bigger than piece size
+func WithUploadLimiter(limiter *rate.Limiter) Option {
+	return func(s *server) {
+		s.uploadLimiter = limiter
+	}
+}
+
+// WithUploadAdmission sets upload admission of pieces uploaded by grpc
+func WithUploadAdmission(admission *upload.Admission) Option {
+	return func(s *server) {
+		s.uploadAdmission = admission
+	}
+}
+
+func New(peerHost *scheduler.PeerHost, peerTaskManager peer.TaskManager, storageManager storage.Manager, downloadOpts []grpc.ServerOption, peerOpts []grpc.ServerOption, opts ...Option) (Server, error) {
 	svr := &server{
 		KeepAlive:       clientutil.NewKeepAlive("rpc server"),
 		peerHost:        peerHost,
 		peerTaskManager: peerTaskManager,
 		storageManager:  storageManager,
 	}
+	for _, opt := range opts {
+		opt(svr)
+	}
 	svr.downloadServer = dfdaemonserver.New(svr, downloadOpts...)
 	svr.peerServer = dfdaemonserver.New(svr, peerOpts...)
 	return svr, nil
 }
 
+// ServeDownload serves download grpc and local task management http api on the same listener
 func (m *server) ServeDownload(listener net.Listener) error {
-	return m.downloadServer.Serve(listener)
+	grpcListener, httpListener := splitListener(listener)
+	m.downloadHTTPServer = &http.Server{Handler: m.localTaskHandler()}
+	go func() {
+		if err := m.downloadHTTPServer.Serve(httpListener); err != nil && err != http.ErrServerClosed {
+			logger.Errorf("serve local task http api error: %s", err)
+		}
+	}()
+	return m.downloadServer.Serve(grpcListener)
 }
 
 func (m *server) ServePeer(listener net.Listener) error {
@@ -81,6 +135,9 @@ func (m *server) ServePeer(listener net.Listener) error {
 func (m *server) Stop() {
 	m.peerServer.GracefulStop()
 	m.downloadServer.GracefulStop()
+	if m.downloadHTTPServer != nil {
+		_ = m.downloadHTTPServer.Close()
+	}
 }
 
 func (m *server) GetPieceTasks(ctx context.Context, request *base.PieceTaskRequest) (*base.PiecePacket, error) {
@@ -127,6 +184,229 @@ func (m *server) GetPieceTasks(ctx context.Context, request *base.PieceTaskReque
 	return p, nil
 }
 
+// SyncPieceTasks pushes pieces from request.StartNum to the subscriber, new pieces are pushed as soon as
+// they are written to storage. The stream is finished after all pieces are sent.
+func (m *server) SyncPieceTasks(request *base.PieceTaskRequest, stream dfdaemongrpc.Daemon_SyncPieceTasksServer) error {
+	m.Keep()
+	var (
+		ctx   = stream.Context()
+		meta  = &storage.PeerTaskMetadata{TaskID: request.TaskId, PeerID: request.DstPid}
+		limit = request.Limit
+		// next is the lowest piece number which is not sent
+		next = request.StartNum
+		// sent holds the sent pieces which are greater than next
+		sent = map[int32]bool{}
+		// end is the piece number after the greatest sent piece
+		end  = next
+		last *base.PiecePacket
+	)
+	if limit == 0 {
+		limit = defaultSyncPieceLimit
+	}
+
+	for {
+		// watch before get pieces, so that pieces written in between will not be missed
+		changed, err := m.storageManager.WatchPieces(meta)
+		if err == storage.ErrTaskNotFound {
+			if !m.peerTaskManager.IsPeerTaskRunning(request.DstPid) {
+				logger.Errorf("sync piece tasks error: peer task not found, task id: %s, src peer: %s, dst peer: %s",
+					request.TaskId, request.SrcPid, request.DstPid)
+				return dferrors.New(base.Code_PeerTaskNotFound, err.Error())
+			}
+			// dst peer is initializing, wait for the task storage
+			select {
+			case <-time.After(syncPieceTaskInterval):
+				continue
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+		if err != nil {
+			return dferrors.New(base.Code_UnknownError, err.Error())
+		}
+
+		// scan beyond the sent pieces, pieces after a missing one are pushed before it is written
+		start, window := next, end-next+syncPieceWindow
+		if last != nil && last.TotalPiece > 0 {
+			window = uint32(last.TotalPiece) - next
+		}
+		p, err := m.storageManager.GetPieces(ctx, &base.PieceTaskRequest{
+			TaskId:   request.TaskId,
+			SrcPid:   request.SrcPid,
+			DstPid:   request.DstPid,
+			StartNum: start,
+			Limit:    window,
+		})
+		if err == storage.ErrTaskNotFound {
+			// task is reclaimed after watched, check it again
+			continue
+		}
+		if err != nil {
+			logger.Errorf("sync piece tasks error: %s, task id: %s, src peer: %s, dst peer: %s",
+				err, request.TaskId, request.SrcPid, request.DstPid)
+			return dferrors.New(base.Code_UnknownError, err.Error())
+		}
+
+		var pieces []*base.PieceInfo
+		for _, piece := range p.PieceInfos {
+			if !sent[piece.PieceNum] {
+				pieces = append(pieces, piece)
+			}
+		}
+		// metadata is sent even there is no new pieces
+		if len(pieces) > 0 || last == nil || !samePieceMetadata(last, p) {
+			if err = m.sendPiecePacket(stream, p, pieces, int(limit)); err != nil {
+				return err
+			}
+			last = p
+		}
+		for _, piece := range pieces {
+			sent[piece.PieceNum] = true
+			if uint32(piece.PieceNum) >= end {
+				end = uint32(piece.PieceNum) + 1
+			}
+		}
+		for sent[int32(next)] {
+			delete(sent, int32(next))
+			next++
+		}
+		if next > end {
+			end = next
+		}
+		if p.TotalPiece > 0 && int32(next) >= p.TotalPiece {
+			logger.Debugf("sync piece tasks done, task id: %s, src peer: %s, dst peer: %s, total piece: %d",
+				request.TaskId, request.SrcPid, request.DstPid, p.TotalPiece)
+			return nil
+		}
+		// the window is full, more pieces may be written after it
+		if len(pieces) > 0 && end >= start+window {
+			continue
+		}
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// sendPiecePacket sends pieces with metadata of packet, pieces are split into packets with at most limit pieces
+func (m *server) sendPiecePacket(stream dfdaemongrpc.Daemon_SyncPieceTasksServer, packet *base.PiecePacket, pieces []*base.PieceInfo, limit int) error {
+	for {
+		n := len(pieces)
+		if n > limit {
+			n = limit
+		}
+		if err := stream.Send(&base.PiecePacket{
+			TaskId:               packet.TaskId,
+			DstPid:               packet.DstPid,
+			DstAddr:              m.uploadAddr,
+			PieceInfos:           pieces[:n],
+			TotalPiece:           packet.TotalPiece,
+			ContentLength:        packet.ContentLength,
+			PieceMd5Sign:         packet.PieceMd5Sign,
+			PieceDigestRoot:      packet.PieceDigestRoot,
+			PieceDigestSignature: packet.PieceDigestSignature,
+		}); err != nil {
+			return err
+		}
+		pieces = pieces[n:]
+		if len(pieces) == 0 {
+			return nil
+		}
+	}
+}
+
+func samePieceMetadata(a, b *base.PiecePacket) bool {
+	return a.TotalPiece == b.TotalPiece &&
+		a.ContentLength == b.ContentLength &&
+		a.PieceMd5Sign == b.PieceMd5Sign &&
+		a.PieceDigestRoot == b.PieceDigestRoot
+}
+
+// DownloadPieces sends requested pieces one by one, every piece is split into chunks and
+// the stream flow control of grpc applies backpressure to slow receivers.
+func (m *server) DownloadPieces(stream dfdaemongrpc.Daemon_DownloadPiecesServer) error {
+	m.Keep()
+	buf := make([]byte, pieceChunkSize)
+	for {
+		request, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err = m.sendPiece(stream, request, buf); err != nil {
+			logger.Errorf("send piece error: %s, task id: %s, src peer: %s, dst peer: %s, piece num: %d",
+				err, request.TaskId, request.SrcPid, request.DstPid, request.PieceNum)
+			return err
+		}
+	}
+}
+
+func (m *server) sendPiece(stream dfdaemongrpc.Daemon_DownloadPiecesServer, request *dfdaemongrpc.PieceDownloadRequest, buf []byte) error {
+	if err := request.Validate(); err != nil {
+		return dferrors.New(base.Code_BadRequest, err.Error())
+	}
+	release, ok := m.uploadAdmission.Acquire(request.SrcPid)
+	if !ok {
+		return dferrors.New(base.Code_ClientPeerBusy, "upload is busy")
+	}
+	defer release()
+
+	ctx := stream.Context()
+	reader, closer, err := m.storageManager.ReadPiece(ctx,
+		&storage.ReadPieceRequest{
+			PeerTaskMetadata: storage.PeerTaskMetadata{
+				TaskID: request.TaskId,
+				PeerID: request.DstPid,
+			},
+			PieceMetadata: storage.PieceMetadata{
+				Num: -1,
+				Range: clientutil.Range{
+					Start:  int64(request.RangeStart),
+					Length: int64(request.RangeSize),
+				},
+			},
+		})
+	if err != nil {
+		if err == storage.ErrTaskNotFound {
+			return dferrors.New(base.Code_PeerTaskNotFound, err.Error())
+		}
+		return dferrors.New(base.Code_UnknownError, err.Error())
+	}
+	defer closer.Close()
+
+	if m.uploadLimiter != nil {
+		if err = m.uploadLimiter.WaitN(ctx, int(request.RangeSize)); err != nil {
+			return err
+		}
+	}
+
+	remaining := int(request.RangeSize)
+	for remaining > 0 {
+		size := len(buf)
+		if remaining < size {
+			size = remaining
+		}
+		n, err := io.ReadFull(reader, buf[:size])
+		if err != nil {
+			return errors.Wrapf(err, "read piece data, remaining %d", remaining)
+		}
+		remaining -= n
+		if err = stream.Send(&dfdaemongrpc.PieceData{
+			PieceNum: request.PieceNum,
+			Content:  buf[:n],
+			Done:     remaining == 0,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (m *server) CheckHealth(context.Context) error {
 	m.Keep()
 	return nil
@@ -148,6 +428,7 @@ func (m *server) Download(ctx context.Context,
 		DisableBackSource: req.DisableBackSource,
 		Pattern:           req.Pattern,
 		Callsystem:        req.Callsystem,
+		Resume:            req.Resume,
 	}
 	log := logger.With("peer", peerTask.PeerId, "component", "downloadService")
 
@@ -161,6 +442,10 @@ func (m *server) Download(ctx context.Context,
 			PeerId:          tiny.PeerID,
 			CompletedLength: uint64(len(tiny.Content)),
 			Done:            true,
+			ContentLength:   int64(len(tiny.Content)),
+			TotalPieces:     1,
+			FinishedPieces:  1,
+			Source:          peer.PeerTaskSourceCDN,
 		}
 		log.Infof("tiny file, wrote to output")
 		if req.Uid != 0 && req.Gid != 0 {
@@ -184,12 +469,7 @@ func (m *server) Download(ctx context.Context,
 				log.Errorf("task %s/%s failed: %d/%s", p.PeerID, p.TaskID, p.State.Code, p.State.Msg)
 				return dferrors.New(p.State.Code, p.State.Msg)
 			}
-			results <- &dfdaemongrpc.DownResult{
-				TaskId:          p.TaskID,
-				PeerId:          p.PeerID,
-				CompletedLength: uint64(p.CompletedLength),
-				Done:            p.PeerTaskDone,
-			}
+			results <- newDownResult(p)
 			// peer task sets PeerTaskDone to true only once
 			if p.PeerTaskDone {
 				p.DoneCallback()
@@ -213,3 +493,138 @@ func (m *server) Download(ctx context.Context,
 		}
 	}
 }
+
+func (m *server) DownloadStream(req *dfdaemongrpc.StreamDownRequest, stream dfdaemongrpc.Daemon_DownloadStreamServer) error {
+	m.Keep()
+	ctx := stream.Context()
+	peerTask := &scheduler.PeerTaskRequest{
+		Url:      req.Url,
+		UrlMeta:  req.UrlMeta,
+		PeerId:   idgen.PeerID(m.peerHost.Ip),
+		PeerHost: m.peerHost,
+	}
+	log := logger.With("peer", peerTask.PeerId, "component", "downloadService")
+
+	var (
+		reader        io.ReadCloser
+		attr          map[string]string
+		err           error
+		contentLength = int64(-1)
+	)
+	if req.Offset > 0 || req.Length > 0 {
+		// read part of the task, the pieces of it are downloaded before the others
+		reader, contentLength, attr, err = m.startSectionReader(ctx, peerTask, req.Offset, req.Length)
+	} else {
+		reader, attr, err = m.peerTaskManager.StartStreamPeerTask(ctx, peerTask)
+	}
+	if err != nil {
+		return dferrors.New(base.Code_UnknownError, fmt.Sprintf("%s", err))
+	}
+	defer reader.Close()
+
+	if cl, ok := attr[headers.ContentLength]; ok && contentLength == -1 {
+		if contentLength, err = strconv.ParseInt(cl, 10, 64); err != nil {
+			return dferrors.Newf(base.Code_UnknownError, "invalid content length %q", cl)
+		}
+	}
+	result := &dfdaemongrpc.StreamDownResult{
+		TaskId:        attr[config.HeaderDragonflyTask],
+		PeerId:        attr[config.HeaderDragonflyPeer],
+		ContentLength: contentLength,
+	}
+	if err = stream.Send(result); err != nil {
+		return err
+	}
+
+	var (
+		buf     = make([]byte, pieceChunkSize)
+		written int64
+	)
+	for {
+		n, er := io.ReadFull(reader, buf)
+		if n > 0 {
+			written += int64(n)
+			if err = stream.Send(&dfdaemongrpc.StreamDownResult{Content: buf[:n]}); err != nil {
+				log.Errorf("send content error: %s", err)
+				return err
+			}
+		}
+		if er == io.EOF || er == io.ErrUnexpectedEOF {
+			break
+		}
+		if er != nil {
+			log.Errorf("read content error: %s", er)
+			return dferrors.New(base.Code_UnknownError, er.Error())
+		}
+	}
+	// a truncated stream must not be taken as the whole content by client
+	if contentLength >= 0 && written != contentLength {
+		log.Errorf("task %s/%s streamed %d bytes, content length: %d", result.PeerId, result.TaskId, written, contentLength)
+		return dferrors.Newf(base.Code_UnknownError, "streamed %d bytes, but content length is %d", written, contentLength)
+	}
+	log.Infof("task %s/%s streamed, length: %d", result.PeerId, result.TaskId, written)
+	return nil
+}
+
+// startSectionReader starts a seekable stream peer task and reads length bytes from offset,
+// it returns the length of the section, -1 means unknown.
+func (m *server) startSectionReader(ctx context.Context, peerTask *scheduler.PeerTaskRequest,
+	offset, length int64) (io.ReadCloser, int64, map[string]string, error) {
+	reader, attr, err := m.peerTaskManager.StartSeekableStreamPeerTask(ctx, peerTask)
+	if err != nil {
+		return nil, -1, nil, err
+	}
+
+	size := reader.Size()
+	if size >= 0 && offset > size {
+		reader.Close()
+		return nil, -1, nil, errors.Errorf("offset %d exceeds content length %d", offset, size)
+	}
+	sectionLength := int64(-1)
+	switch {
+	case size >= 0 && (length == 0 || offset+length > size):
+		sectionLength = size - offset
+	case length > 0:
+		sectionLength = length
+	}
+	n := sectionLength
+	if n < 0 {
+		n = math.MaxInt64 - offset
+	}
+	return &sectionReadCloser{
+		SectionReader: io.NewSectionReader(reader, offset, n),
+		Closer:        reader,
+	}, sectionLength, attr, nil
+}
+
+// sectionReadCloser reads a section of seekable reader.
+type sectionReadCloser struct {
+	*io.SectionReader
+	io.Closer
+}
+
+// newDownResult converts the progress of file peer task to download result
+func newDownResult(p *peer.FilePeerTaskProgress) *dfdaemongrpc.DownResult {
+	result := &dfdaemongrpc.DownResult{
+		TaskId:          p.TaskID,
+		PeerId:          p.PeerID,
+		CompletedLength: uint64(p.CompletedLength),
+		Done:            p.PeerTaskDone,
+		ContentLength:   p.ContentLength,
+	}
+	if p.Stat == nil {
+		return result
+	}
+
+	result.TotalPieces = p.Stat.TotalPieces
+	result.FinishedPieces = p.Stat.FinishedPieces
+	result.Source = p.Stat.Source
+	result.Parents = p.Stat.Parents
+	if p.PeerTaskDone {
+		result.PeerTraffic = p.Stat.PeerTraffic
+		result.CdnTraffic = p.Stat.CDNTraffic
+		result.SourceTraffic = p.Stat.SourceTraffic
+		result.PieceMd5Sign = p.Stat.PieceMd5Sign
+	}
+	return result
+}
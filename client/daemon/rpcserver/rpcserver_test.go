@@ -17,23 +17,35 @@
 package rpcserver
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net"
+	"net/http"
 	"os"
+	"path"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/distribution/distribution/v3/uuid"
+	"github.com/go-http-utils/headers"
 	"github.com/golang/mock/gomock"
+	"github.com/golang/protobuf/ptypes/empty"
 	"github.com/phayes/freeport"
 	testifyassert "github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	"d7y.io/dragonfly/v2/client/clientutil"
 	"d7y.io/dragonfly/v2/client/daemon/peer"
+	"d7y.io/dragonfly/v2/client/daemon/storage"
 	mock_peer "d7y.io/dragonfly/v2/client/daemon/test/mock/peer"
 	mock_storage "d7y.io/dragonfly/v2/client/daemon/test/mock/storage"
+	"d7y.io/dragonfly/v2/internal/dferrors"
 	"d7y.io/dragonfly/v2/internal/dfnet"
 	"d7y.io/dragonfly/v2/pkg/idgen"
 	"d7y.io/dragonfly/v2/pkg/rpc/base"
@@ -254,3 +266,601 @@ func TestDownloadManager_ServePeer(t *testing.T) {
 		assert.Equal(tc.responsePieceSize, len(response.PieceInfos))
 	}
 }
+
+func TestDownloadManager_DownloadPieces(t *testing.T) {
+	assert := testifyassert.New(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	testData := make([]byte, 3*pieceChunkSize+100)
+	for i := range testData {
+		testData[i] = byte(i)
+	}
+	mockStorageManger := mock_storage.NewMockManager(ctrl)
+	mockStorageManger.EXPECT().ReadPiece(gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(func(ctx context.Context, req *storage.ReadPieceRequest) (io.Reader, io.Closer, error) {
+		if req.TaskID != "task-0" {
+			return nil, nil, storage.ErrTaskNotFound
+		}
+		r := bytes.NewReader(testData[req.Range.Start : req.Range.Start+req.Range.Length])
+		return r, io.NopCloser(r), nil
+	})
+	m := &server{
+		KeepAlive:      clientutil.NewKeepAlive("test"),
+		peerHost:       &scheduler.PeerHost{},
+		storageManager: mockStorageManger,
+	}
+	m.peerServer = dfdaemonserver.New(m)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(err, "listen should be ok")
+	go func() {
+		if err := m.ServePeer(ln); err != nil {
+			t.Error(err)
+		}
+	}()
+	time.Sleep(100 * time.Millisecond)
+
+	conn, err := grpc.Dial(ln.Addr().String(), grpc.WithInsecure())
+	assert.Nil(err, "grpc dial should be ok")
+	defer conn.Close()
+	stream, err := dfdaemongrpc.NewDaemonClient(conn).DownloadPieces(context.Background())
+	assert.Nil(err, "open stream should be ok")
+
+	var tests = []struct {
+		pieceNum   int32
+		rangeStart uint64
+		rangeSize  uint32
+	}{
+		{pieceNum: 0, rangeStart: 0, rangeSize: 100},
+		{pieceNum: 1, rangeStart: 100, rangeSize: 2*pieceChunkSize + 1},
+		{pieceNum: 2, rangeStart: 2*pieceChunkSize + 101, rangeSize: pieceChunkSize - 1},
+	}
+	// multiple pieces in one stream
+	for _, tt := range tests {
+		err = stream.Send(&dfdaemongrpc.PieceDownloadRequest{
+			TaskId:     "task-0",
+			SrcPid:     "peer-0",
+			DstPid:     "peer-1",
+			PieceNum:   tt.pieceNum,
+			RangeStart: tt.rangeStart,
+			RangeSize:  tt.rangeSize,
+		})
+		assert.Nil(err, "send request should be ok")
+
+		var data []byte
+		for {
+			pieceData, err := stream.Recv()
+			assert.Nil(err, "receive piece data should be ok")
+			assert.Equal(tt.pieceNum, pieceData.PieceNum)
+			assert.LessOrEqual(len(pieceData.Content), pieceChunkSize)
+			data = append(data, pieceData.Content...)
+			if pieceData.Done {
+				break
+			}
+		}
+		assert.Equal(testData[tt.rangeStart:tt.rangeStart+uint64(tt.rangeSize)], data)
+	}
+
+	// unknown task aborts the stream
+	err = stream.Send(&dfdaemongrpc.PieceDownloadRequest{
+		TaskId:    "task-1",
+		DstPid:    "peer-1",
+		RangeSize: 100,
+	})
+	assert.Nil(err, "send request should be ok")
+	_, err = stream.Recv()
+	assert.NotNil(err, "unknown task should fail")
+}
+
+func TestDownloadManager_SyncPieceTasks(t *testing.T) {
+	assert := testifyassert.New(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	const totalPiece = 5
+	var (
+		lock    sync.Mutex
+		pieces  = map[int32]bool{}
+		changed = make(chan struct{})
+	)
+	mockStorageManger := mock_storage.NewMockManager(ctrl)
+	mockStorageManger.EXPECT().WatchPieces(gomock.Any()).AnyTimes().DoAndReturn(func(req *storage.PeerTaskMetadata) (<-chan struct{}, error) {
+		if req.TaskID != "task-0" {
+			return nil, storage.ErrTaskNotFound
+		}
+		lock.Lock()
+		defer lock.Unlock()
+		return changed, nil
+	})
+	mockStorageManger.EXPECT().GetPieces(gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(func(ctx context.Context, req *base.PieceTaskRequest) (*base.PiecePacket, error) {
+		lock.Lock()
+		defer lock.Unlock()
+		p := &base.PiecePacket{
+			TaskId:        req.TaskId,
+			DstPid:        req.DstPid,
+			TotalPiece:    totalPiece,
+			ContentLength: totalPiece * 100,
+		}
+		for i := req.StartNum; i < req.StartNum+req.Limit; i++ {
+			if pieces[int32(i)] {
+				p.PieceInfos = append(p.PieceInfos, &base.PieceInfo{PieceNum: int32(i)})
+			}
+		}
+		return p, nil
+	})
+	mockPeerTaskManager := mock_peer.NewMockTaskManager(ctrl)
+	mockPeerTaskManager.EXPECT().IsPeerTaskRunning(gomock.Any()).AnyTimes().Return(false)
+	m := &server{
+		KeepAlive:       clientutil.NewKeepAlive("test"),
+		peerHost:        &scheduler.PeerHost{Ip: "127.0.0.1", DownPort: 65002},
+		peerTaskManager: mockPeerTaskManager,
+		storageManager:  mockStorageManger,
+	}
+	m.peerServer = dfdaemonserver.New(m)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(err, "listen should be ok")
+	go func() {
+		if err := m.ServePeer(ln); err != nil {
+			t.Error(err)
+		}
+	}()
+	time.Sleep(100 * time.Millisecond)
+
+	conn, err := grpc.Dial(ln.Addr().String(), grpc.WithInsecure())
+	assert.Nil(err, "grpc dial should be ok")
+	defer conn.Close()
+	client := dfdaemongrpc.NewDaemonClient(conn)
+
+	// pieces are written out of order after subscribed
+	go func() {
+		for _, num := range []int32{0, 2, 1, 4, 3} {
+			time.Sleep(10 * time.Millisecond)
+			lock.Lock()
+			pieces[num] = true
+			close(changed)
+			changed = make(chan struct{})
+			lock.Unlock()
+		}
+	}()
+
+	stream, err := client.SyncPieceTasks(context.Background(), &base.PieceTaskRequest{
+		TaskId:   "task-0",
+		SrcPid:   "peer-0",
+		DstPid:   "peer-1",
+		StartNum: 0,
+		Limit:    2,
+	})
+	assert.Nil(err, "open stream should be ok")
+	received := map[int32]int{}
+	for {
+		p, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		assert.Nil(err, "receive piece packet should be ok")
+		if err != nil {
+			return
+		}
+		assert.Equal(int32(totalPiece), p.TotalPiece)
+		assert.Equal("127.0.0.1:65002", p.DstAddr)
+		assert.LessOrEqual(len(p.PieceInfos), 2)
+		for _, piece := range p.PieceInfos {
+			received[piece.PieceNum]++
+		}
+	}
+	assert.Equal(map[int32]int{0: 1, 1: 1, 2: 1, 3: 1, 4: 1}, received, "every piece should be pushed once")
+
+	// peer task is not running
+	stream, err = client.SyncPieceTasks(context.Background(), &base.PieceTaskRequest{
+		TaskId: "task-1",
+		DstPid: "peer-1",
+	})
+	assert.Nil(err, "open stream should be ok")
+	_, err = stream.Recv()
+	assert.NotNil(err, "unknown task should fail")
+}
+
+func TestDownloadManager_SyncPieceTasks_Window(t *testing.T) {
+	assert := testifyassert.New(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// more pieces than a window are written before piece 0, total piece is unknown
+	const totalPiece = 3 * syncPieceWindow
+	var (
+		lock    sync.Mutex
+		total   = int32(-1)
+		pieces  = map[int32]bool{}
+		changed = make(chan struct{})
+	)
+	for i := int32(1); i < totalPiece; i++ {
+		pieces[i] = true
+	}
+	mockStorageManger := mock_storage.NewMockManager(ctrl)
+	mockStorageManger.EXPECT().WatchPieces(gomock.Any()).AnyTimes().DoAndReturn(func(req *storage.PeerTaskMetadata) (<-chan struct{}, error) {
+		lock.Lock()
+		defer lock.Unlock()
+		return changed, nil
+	})
+	mockStorageManger.EXPECT().GetPieces(gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(func(ctx context.Context, req *base.PieceTaskRequest) (*base.PiecePacket, error) {
+		lock.Lock()
+		defer lock.Unlock()
+		p := &base.PiecePacket{
+			TaskId:     req.TaskId,
+			DstPid:     req.DstPid,
+			TotalPiece: total,
+		}
+		for i := req.StartNum; i < req.StartNum+req.Limit; i++ {
+			if pieces[int32(i)] {
+				p.PieceInfos = append(p.PieceInfos, &base.PieceInfo{PieceNum: int32(i)})
+			}
+		}
+		return p, nil
+	})
+	m := &server{
+		KeepAlive:       clientutil.NewKeepAlive("test"),
+		peerHost:        &scheduler.PeerHost{Ip: "127.0.0.1", DownPort: 65002},
+		peerTaskManager: mock_peer.NewMockTaskManager(ctrl),
+		storageManager:  mockStorageManger,
+	}
+	m.peerServer = dfdaemonserver.New(m)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(err, "listen should be ok")
+	go func() {
+		if err := m.ServePeer(ln); err != nil {
+			t.Error(err)
+		}
+	}()
+	time.Sleep(100 * time.Millisecond)
+
+	conn, err := grpc.Dial(ln.Addr().String(), grpc.WithInsecure())
+	assert.Nil(err, "grpc dial should be ok")
+	defer conn.Close()
+	client := dfdaemongrpc.NewDaemonClient(conn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	stream, err := client.SyncPieceTasks(ctx, &base.PieceTaskRequest{
+		TaskId: "task-0",
+		SrcPid: "peer-0",
+		DstPid: "peer-1",
+	})
+	assert.Nil(err, "open stream should be ok")
+	received := map[int32]int{}
+	for {
+		p, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		assert.Nil(err, "receive piece packet should be ok")
+		if err != nil {
+			return
+		}
+		for _, piece := range p.PieceInfos {
+			received[piece.PieceNum]++
+		}
+		// piece 0 is written after all the others are pushed
+		if len(received) == totalPiece-1 {
+			lock.Lock()
+			pieces[0] = true
+			total = totalPiece
+			close(changed)
+			changed = make(chan struct{})
+			lock.Unlock()
+		}
+	}
+	assert.Len(received, totalPiece)
+	for num, count := range received {
+		assert.Equal(1, count, "piece %d should be pushed once", num)
+	}
+}
+
+func TestDownloadManager_DownloadStream(t *testing.T) {
+	assert := testifyassert.New(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	tests := []struct {
+		name          string
+		contentLength string
+		content       string
+		ok            bool
+	}{
+		{
+			name:          "content length matched",
+			contentLength: "5",
+			content:       "hello",
+			ok:            true,
+		},
+		{
+			name:          "stream truncated",
+			contentLength: "10",
+			content:       "hello",
+			ok:            false,
+		},
+	}
+
+	mockPeerTaskManager := mock_peer.NewMockTaskManager(ctrl)
+	m := &server{
+		KeepAlive:       clientutil.NewKeepAlive("test"),
+		peerHost:        &scheduler.PeerHost{Ip: "127.0.0.1"},
+		peerTaskManager: mockPeerTaskManager,
+	}
+	m.downloadServer = dfdaemonserver.New(m)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(err, "listen should be ok")
+	go func() {
+		if err := m.ServeDownload(ln); err != nil {
+			t.Error(err)
+		}
+	}()
+	time.Sleep(100 * time.Millisecond)
+
+	conn, err := grpc.Dial(ln.Addr().String(), grpc.WithInsecure())
+	assert.Nil(err, "grpc dial should be ok")
+	defer conn.Close()
+	client := dfdaemongrpc.NewDaemonClient(conn)
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := testifyassert.New(t)
+			mockPeerTaskManager.EXPECT().StartStreamPeerTask(gomock.Any(), gomock.Any()).Return(
+				io.NopCloser(bytes.NewBufferString(tc.content)),
+				map[string]string{headers.ContentLength: tc.contentLength}, nil)
+
+			stream, err := client.DownloadStream(context.Background(), &dfdaemongrpc.StreamDownRequest{
+				Url:     "http://localhost/test",
+				UrlMeta: &base.UrlMeta{Tag: "unit test"},
+			})
+			assert.Nil(err, "open stream should be ok")
+
+			var content []byte
+			for {
+				result, err := stream.Recv()
+				if err != nil {
+					assert.Equal(tc.ok, err == io.EOF, "stream error: %v", err)
+					break
+				}
+				content = append(content, result.Content...)
+			}
+			assert.Equal(tc.content, string(content))
+		})
+	}
+}
+
+func TestDownloadManager_LocalTasks(t *testing.T) {
+	assert := testifyassert.New(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var (
+		taskID = idgen.TaskID("http://localhost/test", &base.UrlMeta{Tag: "unit test"})
+		pinned bool
+	)
+	mockStorageManger := mock_storage.NewMockManager(ctrl)
+	mockStorageManger.EXPECT().ListTasks().AnyTimes().Return([]*storage.TaskInfo{
+		{
+			PeerTaskMetadata: storage.PeerTaskMetadata{TaskID: taskID, PeerID: "peer-0"},
+			ContentLength:    100,
+			CompletedLength:  100,
+			Done:             true,
+		},
+		{
+			PeerTaskMetadata: storage.PeerTaskMetadata{TaskID: "task-1", PeerID: "peer-1"},
+			ContentLength:    100,
+			CompletedLength:  10,
+		},
+	})
+	mockStorageManger.EXPECT().StatTask(gomock.Any()).AnyTimes().DoAndReturn(func(id string) ([]*storage.TaskInfo, error) {
+		switch id {
+		case taskID:
+			return []*storage.TaskInfo{{PeerTaskMetadata: storage.PeerTaskMetadata{TaskID: taskID, PeerID: "peer-0"}, Done: true}}, nil
+		case "task-1":
+			return []*storage.TaskInfo{{PeerTaskMetadata: storage.PeerTaskMetadata{TaskID: "task-1", PeerID: "peer-1"}}}, nil
+		}
+		return nil, storage.ErrTaskNotFound
+	})
+	mockStorageManger.EXPECT().PinTask(gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(func(id string, pin bool) error {
+		if id != taskID {
+			return storage.ErrTaskNotFound
+		}
+		pinned = pin
+		return nil
+	})
+	mockStorageManger.EXPECT().DeleteTask(taskID).Times(1).Return(nil)
+	mockStorageManger.EXPECT().FindCompletedTask(gomock.Any()).AnyTimes().DoAndReturn(func(id string) *storage.ReusePeerTask {
+		if id != taskID {
+			return nil
+		}
+		return &storage.ReusePeerTask{
+			PeerTaskMetadata: storage.PeerTaskMetadata{TaskID: taskID, PeerID: "peer-0"},
+			ContentLength:    100,
+			TotalPieces:      1,
+		}
+	})
+	var exported []string
+	mockStorageManger.EXPECT().Store(gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(func(ctx context.Context, req *storage.StoreRequest) error {
+		exported = append(exported, req.Destination)
+		return nil
+	})
+	mockPeerTaskManager := mock_peer.NewMockTaskManager(ctrl)
+	mockPeerTaskManager.EXPECT().IsPeerTaskRunning(gomock.Any()).AnyTimes().DoAndReturn(func(peerID string) bool {
+		return peerID == "peer-1"
+	})
+	mockPeerTaskManager.EXPECT().ImportTask(gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(func(ctx context.Context, req *peer.ImportTaskRequest) (*storage.TaskInfo, error) {
+		if req.Path != "/tmp/imported" {
+			return nil, peer.ErrTaskExists
+		}
+		return &storage.TaskInfo{
+			PeerTaskMetadata: storage.PeerTaskMetadata{TaskID: idgen.TaskID(req.Url, req.UrlMeta), PeerID: req.PeerId},
+			ContentLength:    100,
+			TotalPieces:      1,
+			CompletedLength:  100,
+			Done:             true,
+		}, nil
+	})
+	mockPeerTaskManager.EXPECT().StatTask(gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(func(ctx context.Context, id string) (*scheduler.Task, error) {
+		switch id {
+		case "task-3":
+			return &scheduler.Task{Id: id, ContentLength: 100, State: "Success", PeerCount: 2, HasAvailablePeer: true}, nil
+		case "task-4":
+			return &scheduler.Task{Id: id, State: "Running", PeerCount: 1}, nil
+		}
+		return nil, dferrors.Newf(base.Code_PeerTaskNotFound, "task %s not found", id)
+	})
+	m := &server{
+		KeepAlive:       clientutil.NewKeepAlive("test"),
+		peerHost:        &scheduler.PeerHost{},
+		peerTaskManager: mockPeerTaskManager,
+		storageManager:  mockStorageManger,
+	}
+	m.downloadServer = dfdaemonserver.New(m)
+	m.peerServer = dfdaemonserver.New(m)
+
+	sock := path.Join(t.TempDir(), "dfdaemon.sock")
+	ln, err := net.Listen("unix", sock)
+	assert.Nil(err, "listen unix socket should be ok")
+	go func() {
+		if err := m.ServeDownload(ln); err != nil {
+			t.Error(err)
+		}
+	}()
+	peerLn, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(err, "listen tcp should be ok")
+	go func() {
+		if err := m.ServePeer(peerLn); err != nil {
+			t.Error(err)
+		}
+	}()
+	defer m.Stop()
+	time.Sleep(100 * time.Millisecond)
+
+	// grpc on unix socket
+	target := dfnet.NetAddr{Type: dfnet.UNIX, Addr: sock}
+	client, err := dfclient.GetClientByAddr([]dfnet.NetAddr{target})
+	assert.Nil(err, "grpc dial should be ok")
+	ctx := context.Background()
+
+	tasks, err := client.ListTasks(ctx, target)
+	assert.Nil(err, "list tasks should be ok")
+	assert.Len(tasks.Tasks, 2)
+
+	tasks, err = client.StatTask(ctx, target, &dfdaemongrpc.TaskRequest{Url: "http://localhost/test", UrlMeta: &base.UrlMeta{Tag: "unit test"}})
+	assert.Nil(err, "stat task by url should be ok")
+	assert.Equal(taskID, tasks.Tasks[0].TaskId)
+
+	_, err = client.StatTask(ctx, target, &dfdaemongrpc.TaskRequest{TaskId: "task-2"})
+	assert.True(dferrors.CheckError(err, base.Code_PeerTaskNotFound), "stat unknown task should fail")
+
+	tasks, err = client.StatTask(ctx, target, &dfdaemongrpc.TaskRequest{TaskId: "task-3", Cluster: true})
+	assert.Nil(err, "stat task cached in cluster should be ok")
+	assert.Len(tasks.Tasks, 0)
+	assert.Equal("task-3", tasks.Cluster.Id)
+	assert.True(tasks.Cluster.HasAvailablePeer)
+
+	_, err = client.StatTask(ctx, target, &dfdaemongrpc.TaskRequest{TaskId: "task-3"})
+	assert.True(dferrors.CheckError(err, base.Code_PeerTaskNotFound), "stat task only in cluster should fail without cluster")
+
+	_, err = client.StatTask(ctx, target, &dfdaemongrpc.TaskRequest{TaskId: "task-4", Cluster: true})
+	assert.True(dferrors.CheckError(err, base.Code_PeerTaskNotFound), "stat task without available peer should fail")
+
+	_, err = client.StatTask(ctx, target, &dfdaemongrpc.TaskRequest{TaskId: "task-2", Cluster: true})
+	assert.True(dferrors.CheckError(err, base.Code_PeerTaskNotFound), "stat unknown task in cluster should fail")
+
+	task, err := client.ExportTask(ctx, target, &dfdaemongrpc.ExportTaskRequest{Task: &dfdaemongrpc.TaskRequest{TaskId: taskID}, Output: "/tmp/exported"})
+	assert.Nil(err, "export local task should be ok")
+	assert.Equal("peer-0", task.PeerId)
+	assert.Equal(int64(100), task.ContentLength)
+	assert.Equal([]string{"/tmp/exported"}, exported)
+
+	_, err = client.ExportTask(ctx, target, &dfdaemongrpc.ExportTaskRequest{Task: &dfdaemongrpc.TaskRequest{TaskId: "task-2"}, Output: "/tmp/exported", LocalOnly: true})
+	assert.True(dferrors.CheckError(err, base.Code_PeerTaskNotFound), "export unknown local task should fail")
+
+	_, err = client.ExportTask(ctx, target, &dfdaemongrpc.ExportTaskRequest{Task: &dfdaemongrpc.TaskRequest{TaskId: "task-2"}, Output: "/tmp/exported"})
+	assert.True(dferrors.CheckError(err, base.Code_BadRequest), "export task from other peers without url should fail")
+
+	_, err = client.ExportTask(ctx, target, &dfdaemongrpc.ExportTaskRequest{Task: &dfdaemongrpc.TaskRequest{TaskId: taskID}, Output: "exported"})
+	assert.True(dferrors.CheckError(err, base.Code_BadRequest), "export to relative path should fail")
+
+	err = client.PinTask(ctx, target, &dfdaemongrpc.PinTaskRequest{Task: &dfdaemongrpc.TaskRequest{TaskId: taskID}, Pin: true})
+	assert.Nil(err, "pin task should be ok")
+	assert.True(pinned)
+
+	err = client.DeleteTask(ctx, target, &dfdaemongrpc.TaskRequest{TaskId: "task-1"})
+	assert.True(dferrors.CheckError(err, base.Code_BadRequest), "delete running task should fail")
+
+	task, err = client.ImportTask(ctx, target, &dfdaemongrpc.ImportTaskRequest{Url: "d7y:/imported", Path: "/tmp/imported"})
+	assert.Nil(err, "import task should be ok")
+	assert.Equal(idgen.TaskID("d7y:/imported", nil), task.TaskId)
+	assert.True(task.Done)
+
+	_, err = client.ImportTask(ctx, target, &dfdaemongrpc.ImportTaskRequest{Url: "d7y:/imported", Path: "/tmp/exists"})
+	assert.True(dferrors.CheckError(err, base.Code_BadRequest), "import existing task should fail")
+
+	_, err = client.ImportTask(ctx, target, &dfdaemongrpc.ImportTaskRequest{Url: "d7y:/imported", Path: "imported"})
+	assert.True(dferrors.CheckError(err, base.Code_BadRequest), "import relative path should fail")
+
+	// grpc on tcp is rejected
+	conn, err := grpc.Dial(peerLn.Addr().String(), grpc.WithInsecure())
+	assert.Nil(err, "grpc dial should be ok")
+	defer conn.Close()
+	_, err = dfdaemongrpc.NewDaemonClient(conn).ListTasks(ctx, new(empty.Empty))
+	assert.Equal(codes.PermissionDenied, status.Code(err), "list tasks on tcp should be rejected")
+
+	// http on unix socket
+	httpClient := &http.Client{
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				return (&net.Dialer{}).DialContext(ctx, "unix", sock)
+			},
+		},
+	}
+	resp, err := httpClient.Get("http://unix" + LocalTasksHTTPPath)
+	assert.Nil(err, "http list tasks should be ok")
+	var result dfdaemongrpc.LocalTasks
+	assert.Nil(json.NewDecoder(resp.Body).Decode(&result))
+	resp.Body.Close()
+	assert.Len(result.Tasks, 2)
+
+	resp, err = httpClient.Get("http://unix" + LocalTaskHTTPPath + "?taskId=task-2")
+	assert.Nil(err)
+	resp.Body.Close()
+	assert.Equal(http.StatusNotFound, resp.StatusCode)
+
+	resp, err = httpClient.Get("http://unix" + LocalTaskHTTPPath + "?taskId=task-3&cluster=true")
+	assert.Nil(err, "http stat task in cluster should be ok")
+	result = dfdaemongrpc.LocalTasks{}
+	assert.Nil(json.NewDecoder(resp.Body).Decode(&result))
+	resp.Body.Close()
+	assert.Equal(http.StatusOK, resp.StatusCode)
+	assert.Equal("task-3", result.Cluster.Id)
+
+	resp, err = httpClient.Post("http://unix"+LocalTaskExportHTTPPath+"?taskId=task-2&localOnly=true&output=/tmp/exported", "", nil)
+	assert.Nil(err)
+	resp.Body.Close()
+	assert.Equal(http.StatusNotFound, resp.StatusCode)
+
+	req, _ := http.NewRequest(http.MethodDelete, "http://unix"+LocalTaskPinHTTPPath+"?taskId="+taskID, nil)
+	resp, err = httpClient.Do(req)
+	assert.Nil(err)
+	resp.Body.Close()
+	assert.Equal(http.StatusOK, resp.StatusCode)
+	assert.False(pinned)
+
+	req, _ = http.NewRequest(http.MethodDelete, "http://unix"+LocalTaskHTTPPath+"?url=http://localhost/test&tag=unit+test", nil)
+	resp, err = httpClient.Do(req)
+	assert.Nil(err)
+	resp.Body.Close()
+	assert.Equal(http.StatusOK, resp.StatusCode)
+
+	resp, err = httpClient.Post("http://unix"+LocalTaskImportHTTPPath+"?url=d7y:/imported&tag=unit+test&path=/tmp/imported", "", nil)
+	assert.Nil(err, "http import task should be ok")
+	result = dfdaemongrpc.LocalTasks{}
+	assert.Nil(json.NewDecoder(resp.Body).Decode(&result))
+	resp.Body.Close()
+	assert.Equal(http.StatusOK, resp.StatusCode)
+	assert.Equal(idgen.TaskID("d7y:/imported", &base.UrlMeta{Tag: "unit test"}), result.Tasks[0].TaskId)
+}
@@ -18,6 +18,7 @@ package storage
 
 import (
 	"os"
+	"time"
 
 	"github.com/pkg/errors"
 )
@@ -28,6 +29,9 @@ const (
 
 	defaultFileMode      = os.FileMode(0644)
 	defaultDirectoryMode = os.FileMode(0755)
+
+	// metadataSaveInterval limits how often the metadata of an unfinished task is saved
+	metadataSaveInterval = time.Second
 )
 
 var (
@@ -18,10 +18,13 @@ package storage
 
 import (
 	"context"
+	"crypto/sha256"
 	"encoding/json"
+	"hash"
 	"io"
 	"os"
 	"path"
+	"sort"
 	"sync"
 	"time"
 
@@ -29,6 +32,7 @@ import (
 
 	"d7y.io/dragonfly/v2/client/clientutil"
 	logger "d7y.io/dragonfly/v2/internal/dflog"
+	"d7y.io/dragonfly/v2/pkg/encryption"
 	"d7y.io/dragonfly/v2/pkg/rpc/base"
 	"d7y.io/dragonfly/v2/pkg/util/digestutils"
 )
@@ -43,6 +47,8 @@ type localTaskStore struct {
 
 	metadataFile     *os.File
 	metadataFilePath string
+	// metadataSavedAt is the unix nano time when the metadata is saved last time
+	metadataSavedAt atomic.Int64
 
 	expireTime    time.Duration
 	lastAccess    atomic.Int64
@@ -51,6 +57,22 @@ type localTaskStore struct {
 
 	// when digest not match, invalid will be set
 	invalid atomic.Bool
+
+	// cipher is set when the data file is encrypted
+	cipher *encryption.Cipher
+
+	// piecesChanged is closed and reset when pieces or metadata are changed, guarded by RWMutex
+	piecesChanged chan struct{}
+
+	// writingPieces holds the locks of pieces in writing, the same piece may be downloaded from
+	// multiple parents in endgame, only one writer writes the data file at the same time, guarded by RWMutex
+	writingPieces map[int32]*pieceWriteLock
+}
+
+// pieceWriteLock excludes the concurrent writes of a piece
+type pieceWriteLock struct {
+	sem  chan struct{}
+	refs int
 }
 
 var _ TaskStorageDriver = (*localTaskStore)(nil)
@@ -61,6 +83,71 @@ func (t *localTaskStore) touch() {
 	t.lastAccess.Store(access)
 }
 
+// watchPieces returns a channel which will be closed when pieces or metadata of the task are changed
+func (t *localTaskStore) watchPieces() <-chan struct{} {
+	t.Lock()
+	defer t.Unlock()
+	if t.piecesChanged == nil {
+		t.piecesChanged = make(chan struct{})
+	}
+	return t.piecesChanged
+}
+
+// notifyPiecesChanged wakes up all piece watchers, caller must hold the lock
+func (t *localTaskStore) notifyPiecesChanged() {
+	if t.piecesChanged != nil {
+		close(t.piecesChanged)
+		t.piecesChanged = nil
+	}
+}
+
+// lockPiece waits other writers of the piece, the returned function releases the lock
+func (t *localTaskStore) lockPiece(ctx context.Context, num int32) (func(), error) {
+	t.Lock()
+	if t.writingPieces == nil {
+		t.writingPieces = map[int32]*pieceWriteLock{}
+	}
+	l, ok := t.writingPieces[num]
+	if !ok {
+		l = &pieceWriteLock{sem: make(chan struct{}, 1)}
+		t.writingPieces[num] = l
+	}
+	l.refs++
+	t.Unlock()
+
+	release := func() {
+		t.Lock()
+		l.refs--
+		if l.refs == 0 {
+			delete(t.writingPieces, num)
+		}
+		t.Unlock()
+	}
+	select {
+	case l.sem <- struct{}{}:
+		return func() {
+			<-l.sem
+			release()
+		}, nil
+	case <-ctx.Done():
+		release()
+		return nil, ctx.Err()
+	}
+}
+
+// discardPiece drains the data of piece which already exists
+func (t *localTaskStore) discardPiece(req *WritePieceRequest, piece PieceMetadata) (int64, error) {
+	// discard data for back source
+	n, err := io.Copy(io.Discard, io.LimitReader(req.Reader, req.Range.Length))
+	if err != nil && err != io.EOF {
+		return n, err
+	}
+	if n != piece.Range.Length {
+		return n, ErrShortRead
+	}
+	return piece.Range.Length, nil
+}
+
 func (t *localTaskStore) WritePiece(ctx context.Context, req *WritePieceRequest) (int64, error) {
 	t.touch()
 
@@ -68,15 +155,21 @@ func (t *localTaskStore) WritePiece(ctx context.Context, req *WritePieceRequest)
 	t.RLock()
 	if piece, ok := t.Pieces[req.Num]; ok {
 		t.RUnlock()
-		// discard data for back source
-		n, err := io.Copy(io.Discard, io.LimitReader(req.Reader, req.Range.Length))
-		if err != nil && err != io.EOF {
-			return n, err
-		}
-		if n != piece.Range.Length {
-			return n, ErrShortRead
-		}
-		return piece.Range.Length, nil
+		return t.discardPiece(req, piece)
+	}
+	t.RUnlock()
+
+	// the data of a piece which is written by another writer must not be overwritten,
+	// the writer may fail in verifying, so wait it and check the piece again
+	unlock, err := t.lockPiece(ctx, req.Num)
+	if err != nil {
+		return 0, err
+	}
+	defer unlock()
+	t.RLock()
+	if piece, ok := t.Pieces[req.Num]; ok {
+		t.RUnlock()
+		return t.discardPiece(req, piece)
 	}
 	t.RUnlock()
 
@@ -91,9 +184,22 @@ func (t *localTaskStore) WritePiece(ctx context.Context, req *WritePieceRequest)
 	var (
 		r  *io.LimitedReader
 		ok bool
-		bn int64 // copied bytes from BufferedReader.B
+		bn int64     // copied bytes from BufferedReader.B
+		w  io.Writer = file
+		ew *encryption.Writer
+		dh hash.Hash // piece digest hash
 	)
-	if r, ok = req.Reader.(*io.LimitedReader); ok {
+	if req.GenPieceDigest && req.PieceMetadata.Digest == "" {
+		dh = sha256.New()
+	}
+	if t.cipher != nil {
+		// encrypted data can not be spliced, always copy it with the encryption writer
+		if ew, err = t.cipher.NewWriter(file, req.Range.Start); err != nil {
+			return 0, err
+		}
+		w = ew
+		r = io.LimitReader(req.Reader, req.Range.Length).(*io.LimitedReader)
+	} else if r, ok = req.Reader.(*io.LimitedReader); ok && dh == nil {
 		// by jim: drain buffer and use raw reader(normally tcp connection) for using optimised operator, like splice
 		if br, bok := r.R.(*clientutil.BufferedReader); bok {
 			bn, err := io.CopyN(file, br.B, int64(br.B.Buffered()))
@@ -105,7 +211,10 @@ func (t *localTaskStore) WritePiece(ctx context.Context, req *WritePieceRequest)
 	} else {
 		r = io.LimitReader(req.Reader, req.Range.Length).(*io.LimitedReader)
 	}
-	n, err := io.Copy(file, r)
+	if dh != nil {
+		w = io.MultiWriter(w, dh)
+	}
+	n, err := io.Copy(w, r)
 	if err != nil {
 		return 0, err
 	}
@@ -127,6 +236,13 @@ func (t *localTaskStore) WritePiece(ctx context.Context, req *WritePieceRequest)
 			return n, ErrShortRead
 		}
 	}
+	if ew != nil {
+		req.PieceMetadata.MAC = ew.Sum()
+		req.PieceMetadata.Nonce = ew.Nonce()
+	}
+	if dh != nil {
+		req.PieceMetadata.Digest = digestutils.Sha256Hash.String() + ":" + digestutils.ToHashString(dh)
+	}
 	// when Md5 is empty, try to get md5 from reader, it's useful for back source
 	if req.PieceMetadata.Md5 == "" {
 		t.Warnf("piece md5 not found in metadata, read from reader")
@@ -146,6 +262,9 @@ func (t *localTaskStore) WritePiece(ctx context.Context, req *WritePieceRequest)
 		return n, nil
 	}
 	t.Pieces[req.Num] = req.PieceMetadata
+	t.notifyPiecesChanged()
+	// keep the pieces on disk, the unfinished task can be resumed after daemon restart
+	t.saveMetadataThrottled()
 	return n, nil
 }
 
@@ -153,6 +272,7 @@ func (t *localTaskStore) UpdateTask(ctx context.Context, req *UpdateTaskRequest)
 	t.touch()
 	t.Lock()
 	defer t.Unlock()
+	defer t.notifyPiecesChanged()
 	t.persistentMetadata.ContentLength = req.ContentLength
 	if req.TotalPieces > 0 {
 		t.TotalPieces = req.TotalPieces
@@ -162,6 +282,11 @@ func (t *localTaskStore) UpdateTask(ctx context.Context, req *UpdateTaskRequest)
 		t.PieceMd5Sign = req.PieceMd5Sign
 		t.Debugf("update piece md5 sign: %s", t.PieceMd5Sign)
 	}
+	if len(t.PieceDigestRoot) == 0 && len(req.PieceDigestRoot) > 0 {
+		t.PieceDigestRoot = req.PieceDigestRoot
+		t.PieceDigestSignature = req.PieceDigestSignature
+		t.Debugf("update piece digest root: %s", t.PieceDigestRoot)
+	}
 	if req.GenPieceDigest {
 		var pieceDigests []string
 		for i := int32(0); i < t.TotalPieces; i++ {
@@ -171,13 +296,54 @@ func (t *localTaskStore) UpdateTask(ctx context.Context, req *UpdateTaskRequest)
 		digest := digestutils.Sha256(pieceDigests...)
 		t.PieceMd5Sign = digest
 		t.Infof("generated digest: %s", digest)
+
+		if tree := t.pieceDigestTree(); tree != nil {
+			t.PieceDigestRoot = tree.Root()
+			t.updatePieceProofs(tree)
+			t.Infof("generated piece digest root: %s", t.PieceDigestRoot)
+		}
+		// generated digests are required for validating, do not lose them
+		if err := t.saveMetadataLocked(); err != nil {
+			t.Warnf("save metadata error: %s", err)
+		}
+		return nil
 	}
+	t.saveMetadataThrottled()
 	return nil
 }
 
+// pieceDigestTree returns the merkle tree of all piece digests, it returns nil when any piece digest is unknown.
+// caller should hold the lock.
+func (t *localTaskStore) pieceDigestTree() *digestutils.MerkleTree {
+	if t.TotalPieces <= 0 {
+		return nil
+	}
+	leaves := make([]string, 0, t.TotalPieces)
+	for i := int32(0); i < t.TotalPieces; i++ {
+		piece, ok := t.Pieces[i]
+		if !ok || piece.Digest == "" {
+			return nil
+		}
+		leaves = append(leaves, piece.Digest)
+	}
+	return digestutils.NewMerkleTree(leaves)
+}
+
+// updatePieceProofs fills the proofs of pieces which are downloaded before piece digest root is known.
+// caller should hold the lock.
+func (t *localTaskStore) updatePieceProofs(tree *digestutils.MerkleTree) {
+	for num, piece := range t.Pieces {
+		if len(piece.Proof) == 0 {
+			piece.Proof = tree.Proof(int(num))
+			t.Pieces[num] = piece
+		}
+	}
+}
+
 func (t *localTaskStore) ValidateDigest(*PeerTaskMetadata) error {
 	t.Lock()
 	defer t.Unlock()
+	defer t.notifyPiecesChanged()
 	if t.persistentMetadata.PieceMd5Sign == "" {
 		t.invalid.Store(true)
 		return ErrDigestNotSet
@@ -199,6 +365,17 @@ func (t *localTaskStore) ValidateDigest(*PeerTaskMetadata) error {
 		t.invalid.Store(true)
 		return ErrInvalidDigest
 	}
+
+	// pieces downloaded before the piece digest root is known are verified here
+	if t.PieceDigestRoot != "" {
+		tree := t.pieceDigestTree()
+		if tree == nil || tree.Root() != t.PieceDigestRoot {
+			t.Errorf("invalid piece digest root, desired: %s", t.PieceDigestRoot)
+			t.invalid.Store(true)
+			return ErrInvalidDigest
+		}
+		t.updatePieceProofs(tree)
+	}
 	return nil
 }
 
@@ -233,6 +410,10 @@ func (t *localTaskStore) ReadPiece(ctx context.Context, req *ReadPieceRequest) (
 		}
 	}
 
+	if t.cipher != nil {
+		return t.cipher.NewReader(file, t.extents(), req.Range.Start, req.Range.Length), file, nil
+	}
+
 	if _, err = file.Seek(req.Range.Start, io.SeekStart); err != nil {
 		file.Close()
 		t.Errorf("file seek failed: %v", err)
@@ -253,6 +434,19 @@ func (t *localTaskStore) ReadAllPieces(ctx context.Context, req *PeerTaskMetadat
 	if err != nil {
 		return nil, err
 	}
+	if t.cipher != nil {
+		extents := t.extents()
+		var length int64
+		for _, e := range extents {
+			if e.Offset+e.Length > length {
+				length = e.Offset + e.Length
+			}
+		}
+		return &readCloser{
+			Reader: t.cipher.NewReader(file, extents, 0, length),
+			Closer: file,
+		}, nil
+	}
 	if _, err = file.Seek(0, io.SeekStart); err != nil {
 		file.Close()
 		t.Errorf("file seek failed: %v", err)
@@ -266,11 +460,12 @@ func (t *localTaskStore) Store(ctx context.Context, req *StoreRequest) error {
 	// Store is be called in callback.Done, mark local task store done, for fast search
 	t.Done = true
 	t.touch()
+	t.Lock()
 	if req.TotalPieces > 0 {
-		t.Lock()
 		t.TotalPieces = req.TotalPieces
-		t.Unlock()
 	}
+	t.notifyPiecesChanged()
+	t.Unlock()
 	if !req.StoreOnly {
 		err := t.saveMetadata()
 		if err != nil {
@@ -287,6 +482,10 @@ func (t *localTaskStore) Store(ctx context.Context, req *StoreRequest) error {
 		t.Infof("destination file %q exists, purge it first", req.Destination)
 		os.Remove(req.Destination)
 	}
+	// encrypted data must be decrypted to destination
+	if t.cipher != nil {
+		return t.storeDecrypted(ctx, req)
+	}
 	// 1. try to link
 	err = os.Link(t.DataFilePath, req.Destination)
 	if err == nil {
@@ -320,6 +519,49 @@ func (t *localTaskStore) Store(ctx context.Context, req *StoreRequest) error {
 	return err
 }
 
+func (t *localTaskStore) storeDecrypted(ctx context.Context, req *StoreRequest) error {
+	rc, err := t.ReadAllPieces(ctx, &PeerTaskMetadata{
+		PeerID: req.PeerID,
+		TaskID: req.TaskID,
+	})
+	if err != nil {
+		t.Errorf("read encrypted tasks data error: %s", err)
+		return err
+	}
+	defer rc.Close()
+
+	dstFile, err := os.OpenFile(req.Destination, os.O_CREATE|os.O_RDWR|os.O_TRUNC, defaultFileMode)
+	if err != nil {
+		t.Errorf("open tasks destination file error: %s", err)
+		return err
+	}
+	defer dstFile.Close()
+	n, err := io.Copy(dstFile, rc)
+	t.Debugf("decrypted tasks data %d bytes to %s", n, req.Destination)
+	return err
+}
+
+// extents returns the authenticated extents of all written pieces
+func (t *localTaskStore) extents() []encryption.Extent {
+	t.RLock()
+	defer t.RUnlock()
+	extents := make([]encryption.Extent, 0, len(t.Pieces))
+	for _, piece := range t.Pieces {
+		extents = append(extents, encryption.Extent{
+			Offset: piece.Range.Start,
+			Length: piece.Range.Length,
+			Nonce:  piece.Nonce,
+			MAC:    piece.MAC,
+		})
+	}
+	return extents
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
 func (t *localTaskStore) GetPieces(ctx context.Context, req *base.PieceTaskRequest) (*base.PiecePacket, error) {
 	if t.invalid.Load() {
 		t.Errorf("invalid digest, refuse to get pieces")
@@ -335,6 +577,9 @@ func (t *localTaskStore) GetPieces(ctx context.Context, req *base.PieceTaskReque
 		TotalPiece:    t.TotalPieces,
 		ContentLength: t.ContentLength,
 		PieceMd5Sign:  t.PieceMd5Sign,
+		// pieces downloaded before the root is known have no proof until the task is validated
+		PieceDigestRoot:      t.PieceDigestRoot,
+		PieceDigestSignature: t.PieceDigestSignature,
 	}
 	if t.TotalPieces > -1 && int32(req.StartNum) >= t.TotalPieces {
 		t.Warnf("invalid start num: %d", req.StartNum)
@@ -348,6 +593,8 @@ func (t *localTaskStore) GetPieces(ctx context.Context, req *base.PieceTaskReque
 				PieceMd5:    piece.Md5,
 				PieceOffset: piece.Offset,
 				PieceStyle:  piece.Style,
+				PieceDigest: piece.Digest,
+				PieceProof:  piece.Proof,
 			})
 		}
 	}
@@ -355,6 +602,10 @@ func (t *localTaskStore) GetPieces(ctx context.Context, req *base.PieceTaskReque
 }
 
 func (t *localTaskStore) CanReclaim() bool {
+	if t.isPinned() {
+		t.Debugf("task is pinned, skip reclaim")
+		return false
+	}
 	access := time.Unix(0, t.lastAccess.Load())
 	reclaim := access.Add(t.expireTime).Before(time.Now())
 	t.Debugf("reclaim check, last access: %v, reclaim: %v", access, reclaim)
@@ -377,6 +628,10 @@ func (t *localTaskStore) MarkReclaim() {
 
 func (t *localTaskStore) Reclaim() error {
 	t.Infof("start gc task data")
+	// wake up piece watchers, they will find the task is gone
+	t.Lock()
+	t.notifyPiecesChanged()
+	t.Unlock()
 	err := t.reclaimData()
 	if err != nil && !os.IsNotExist(err) {
 		return err
@@ -456,9 +711,146 @@ func (t *localTaskStore) reclaimMeta() error {
 	return nil
 }
 
+// taskInfo returns the summary of the task
+func (t *localTaskStore) taskInfo() *TaskInfo {
+	t.RLock()
+	defer t.RUnlock()
+	var completedLength int64
+	for _, piece := range t.Pieces {
+		completedLength += piece.Range.Length
+	}
+	info := &TaskInfo{
+		PeerTaskMetadata: PeerTaskMetadata{
+			PeerID: t.PeerID,
+			TaskID: t.TaskID,
+		},
+		ContentLength:   t.ContentLength,
+		TotalPieces:     t.TotalPieces,
+		CompletedLength: completedLength,
+		Done:            t.Done,
+		Pinned:          t.Pinned,
+		LastAccessTime:  time.Unix(0, t.lastAccess.Load()),
+	}
+	if t.CreateTime > 0 {
+		info.CreateTime = time.Unix(0, t.CreateTime)
+	}
+	info.URL, info.URLMeta = parseTaskMeta(t.TaskMeta)
+	return info
+}
+
+// completedPieces returns the count of downloaded pieces
+func (t *localTaskStore) completedPieces() int {
+	t.RLock()
+	defer t.RUnlock()
+	return len(t.Pieces)
+}
+
+// validatePieces checks all downloaded pieces with their digest, pieces without digest or with mismatched
+// digest are dropped, it returns the valid pieces sorted by piece number.
+func (t *localTaskStore) validatePieces(ctx context.Context) []PieceMetadata {
+	t.RLock()
+	var pieces []PieceMetadata
+	for _, piece := range t.Pieces {
+		pieces = append(pieces, piece)
+	}
+	t.RUnlock()
+	sort.Slice(pieces, func(i, j int) bool {
+		return pieces[i].Num < pieces[j].Num
+	})
+
+	var (
+		valid   []PieceMetadata
+		invalid []int32
+	)
+	for _, piece := range pieces {
+		if err := t.validatePiece(ctx, piece); err != nil {
+			t.Warnf("drop piece %d: %s", piece.Num, err)
+			invalid = append(invalid, piece.Num)
+			continue
+		}
+		valid = append(valid, piece)
+	}
+	if len(invalid) == 0 {
+		return valid
+	}
+
+	t.Lock()
+	defer t.Unlock()
+	for _, num := range invalid {
+		delete(t.Pieces, num)
+	}
+	if err := t.saveMetadataLocked(); err != nil {
+		t.Warnf("save metadata after validating pieces error: %s", err)
+	}
+	return valid
+}
+
+func (t *localTaskStore) validatePiece(ctx context.Context, piece PieceMetadata) error {
+	digest := piece.Digest
+	if digest == "" {
+		digest = piece.Md5
+	}
+	if digest == "" {
+		return ErrDigestNotSet
+	}
+	r, c, err := t.ReadPiece(ctx, &ReadPieceRequest{
+		PeerTaskMetadata: PeerTaskMetadata{
+			PeerID: t.PeerID,
+			TaskID: t.TaskID,
+		},
+		PieceMetadata: PieceMetadata{
+			Num:   piece.Num,
+			Range: piece.Range,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+	n, err := io.Copy(io.Discard, digestutils.NewDigestReader(t.SugaredLoggerOnWith, r, digest))
+	if err != nil {
+		return err
+	}
+	if n != piece.Range.Length {
+		return ErrShortRead
+	}
+	return nil
+}
+
+func (t *localTaskStore) isPinned() bool {
+	t.RLock()
+	defer t.RUnlock()
+	return t.Pinned
+}
+
+// pin pins or unpins the task and saves it to metadata, pinned task will not be reclaimed by gc
+func (t *localTaskStore) pin(pin bool) error {
+	t.Lock()
+	t.Pinned = pin
+	t.Unlock()
+	t.Infof("update task pinned: %v", pin)
+	return t.saveMetadata()
+}
+
 func (t *localTaskStore) saveMetadata() error {
 	t.Lock()
 	defer t.Unlock()
+	return t.saveMetadataLocked()
+}
+
+// saveMetadataThrottled saves metadata at most once in metadataSaveInterval, caller must hold the lock
+func (t *localTaskStore) saveMetadataThrottled() {
+	now := time.Now().UnixNano()
+	if time.Duration(now-t.metadataSavedAt.Load()) < metadataSaveInterval {
+		return
+	}
+	if err := t.saveMetadataLocked(); err != nil {
+		t.Warnf("save unfinished task metadata error: %s", err)
+	}
+}
+
+// saveMetadataLocked saves metadata to disk, caller must hold the lock
+func (t *localTaskStore) saveMetadataLocked() error {
 	data, err := json.Marshal(t.persistentMetadata)
 	if err != nil {
 		return err
@@ -470,6 +862,9 @@ func (t *localTaskStore) saveMetadata() error {
 	_, err = t.metadataFile.Write(data)
 	if err != nil {
 		t.Errorf("save metadata error: %s", err)
+		return err
 	}
-	return err
+	t.metadataSavedAt.Store(time.Now().UnixNano())
+	// metadata may be shorter than the saved one
+	return t.metadataFile.Truncate(int64(len(data)))
 }
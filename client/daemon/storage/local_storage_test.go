@@ -21,6 +21,7 @@ import (
 	"context"
 	"crypto/md5"
 	"encoding/hex"
+	"encoding/json"
 	"io"
 	"math/rand"
 	"os"
@@ -35,8 +36,10 @@ import (
 	"d7y.io/dragonfly/v2/client/config"
 	"d7y.io/dragonfly/v2/client/daemon/test"
 	logger "d7y.io/dragonfly/v2/internal/dflog"
+	"d7y.io/dragonfly/v2/pkg/encryption"
 	"d7y.io/dragonfly/v2/pkg/rpc/base"
 	_ "d7y.io/dragonfly/v2/pkg/rpc/dfdaemon/server"
+	"d7y.io/dragonfly/v2/pkg/util/digestutils"
 )
 
 func TestMain(m *testing.M) {
@@ -169,6 +172,250 @@ func TestLocalTaskStore_PutAndGetPiece_Simple(t *testing.T) {
 	assert.Nil(err, "task gc")
 }
 
+func TestLocalTaskStore_PutAndGetPiece_Encrypted(t *testing.T) {
+	assert := testifyassert.New(t)
+	testBytes, err := os.ReadFile(test.File)
+	assert.Nil(err, "load test file")
+
+	keyFile := path.Join(t.TempDir(), "storage.key")
+	assert.Nil(os.WriteFile(keyFile, []byte(hex.EncodeToString(bytes.Repeat([]byte{0x5a}, encryption.KeySize))), defaultFileMode))
+	encryptor, err := encryption.New(encryption.NewFileKeySource(keyFile))
+	assert.Nil(err, "init encryptor")
+
+	dst := path.Join(test.DataDir, taskData+".copy")
+	defer os.Remove(dst)
+
+	var (
+		taskID    = "task-encrypted-d4bb1c273a9889fea14abd4651994fe8"
+		peerID    = "peer-encrypted-d4bb1c273a9889fea14abd4651994fe8"
+		pieceSize = 512
+	)
+	sm, err := NewStorageManager(config.AdvanceLocalTaskStoreStrategy,
+		&config.StorageOption{
+			DataPath: test.DataDir,
+			TaskExpireTime: clientutil.Duration{
+				Duration: time.Minute,
+			},
+		}, func(request CommonTaskRequest) {
+		}, WithEncryptor(encryptor))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var s = sm.(*storageManager)
+	err = s.CreateTask(
+		RegisterTaskRequest{
+			CommonTaskRequest: CommonTaskRequest{
+				PeerID:      peerID,
+				TaskID:      taskID,
+				Destination: dst,
+			},
+			ContentLength: int64(len(testBytes)),
+		})
+	assert.Nil(err, "create task storage")
+	ts, ok := s.LoadTask(PeerTaskMetadata{
+		PeerID: peerID,
+		TaskID: taskID,
+	})
+	assert.True(ok, "")
+	assert.Equal(string(config.SimpleLocalTaskStoreStrategy), ts.(*localTaskStore).StoreStrategy,
+		"encrypted task must fall back to simple strategy")
+
+	var pieceCount int32
+	for i := 0; i*pieceSize < len(testBytes); i++ {
+		start := i * pieceSize
+		end := start + pieceSize
+		if end > len(testBytes) {
+			end = len(testBytes)
+		}
+		_, err = ts.WritePiece(context.Background(), &WritePieceRequest{
+			PeerTaskMetadata: PeerTaskMetadata{
+				TaskID: taskID,
+			},
+			PieceMetadata: PieceMetadata{
+				Num:    int32(i),
+				Offset: uint64(start),
+				Range: clientutil.Range{
+					Start:  int64(start),
+					Length: int64(end - start),
+				},
+				Style: base.PieceStyle_PLAIN,
+			},
+			Reader: bytes.NewBuffer(testBytes[start:end]),
+		})
+		assert.Nil(err, "put piece")
+		pieceCount++
+	}
+
+	cipherData, err := os.ReadFile(ts.(*localTaskStore).DataFilePath)
+	assert.Nil(err, "read task data")
+	assert.Equal(len(testBytes), len(cipherData), "encrypted data keeps length")
+	assert.NotEqual(testBytes, cipherData, "task data must be encrypted")
+
+	// piece read
+	rd, cl, err := ts.ReadPiece(context.Background(), &ReadPieceRequest{
+		PieceMetadata: PieceMetadata{Num: 1},
+	})
+	assert.Nil(err, "get piece should be ok")
+	data, err := io.ReadAll(rd)
+	cl.Close()
+	assert.Nil(err, "read piece should be ok")
+	assert.Equal(testBytes[pieceSize:2*pieceSize], data, "piece data should match")
+
+	// range read across pieces, like upload
+	rd, cl, err = ts.ReadPiece(context.Background(), &ReadPieceRequest{
+		PieceMetadata: PieceMetadata{
+			Num:   -1,
+			Range: clientutil.Range{Start: 100, Length: int64(2 * pieceSize)},
+		},
+	})
+	assert.Nil(err, "get range should be ok")
+	data, err = io.ReadAll(rd)
+	cl.Close()
+	assert.Nil(err, "read range should be ok")
+	assert.Equal(testBytes[100:100+2*pieceSize], data, "range data should match")
+
+	err = ts.Store(context.Background(), &StoreRequest{
+		CommonTaskRequest: CommonTaskRequest{
+			PeerID:      peerID,
+			TaskID:      taskID,
+			Destination: dst,
+		},
+		TotalPieces: pieceCount,
+	})
+	assert.Nil(err, "store task data")
+	output, err := os.ReadFile(dst)
+	assert.Nil(err, "read output")
+	assert.Equal(testBytes, output, "output must be decrypted")
+
+	// tamper the data file
+	cipherData[0] ^= 0xff
+	assert.Nil(os.WriteFile(ts.(*localTaskStore).DataFilePath, cipherData, defaultFileMode))
+	rd, cl, err = ts.ReadPiece(context.Background(), &ReadPieceRequest{
+		PieceMetadata: PieceMetadata{Num: 0},
+	})
+	assert.Nil(err, "get piece should be ok")
+	_, err = io.ReadAll(rd)
+	cl.Close()
+	assert.Equal(encryption.ErrInvalidMAC, err, "tampered piece must be rejected")
+
+	// clean up test data
+	ts.(*localTaskStore).lastAccess.Store(time.Now().Add(-1 * time.Hour).UnixNano())
+	assert.Nil(ts.(Reclaimer).Reclaim(), "task gc")
+}
+
+func TestLocalTaskStore_PieceDigestRoot(t *testing.T) {
+	assert := testifyassert.New(t)
+	testBytes, err := os.ReadFile(test.File)
+	assert.Nil(err, "load test file")
+
+	sm, err := NewStorageManager(config.SimpleLocalTaskStoreStrategy,
+		&config.StorageOption{
+			DataPath: test.DataDir,
+			TaskExpireTime: clientutil.Duration{
+				Duration: time.Minute,
+			},
+		}, func(request CommonTaskRequest) {
+		})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var s = sm.(*storageManager)
+
+	var pieceSize = 512
+	pieceCount := int32((len(testBytes) + pieceSize - 1) / pieceSize)
+	writePieces := func(taskID, peerID string, pieces []*base.PieceInfo) TaskStorageDriver {
+		assert.Nil(s.CreateTask(
+			RegisterTaskRequest{
+				CommonTaskRequest: CommonTaskRequest{
+					PeerID: peerID,
+					TaskID: taskID,
+				},
+				ContentLength: int64(len(testBytes)),
+				TotalPieces:   pieceCount,
+			}), "create task storage")
+		ts, ok := s.LoadTask(PeerTaskMetadata{PeerID: peerID, TaskID: taskID})
+		assert.True(ok)
+		for i := int32(0); i < pieceCount; i++ {
+			start := int(i) * pieceSize
+			end := start + pieceSize
+			if end > len(testBytes) {
+				end = len(testBytes)
+			}
+			req := &WritePieceRequest{
+				PeerTaskMetadata: PeerTaskMetadata{TaskID: taskID},
+				PieceMetadata: PieceMetadata{
+					Num:    i,
+					Md5:    digestutils.Md5Bytes(testBytes[start:end]),
+					Offset: uint64(start),
+					Range: clientutil.Range{
+						Start:  int64(start),
+						Length: int64(end - start),
+					},
+				},
+				GenPieceDigest: true,
+				Reader:         bytes.NewBuffer(testBytes[start:end]),
+			}
+			if pieces != nil {
+				req.Digest = pieces[i].PieceDigest
+			}
+			_, err := ts.WritePiece(context.Background(), req)
+			assert.Nil(err, "put piece")
+		}
+		return ts
+	}
+
+	// back source task generates piece digests and root
+	seed := writePieces("task-digest-root", "peer-seed", nil)
+	assert.Nil(seed.UpdateTask(context.Background(), &UpdateTaskRequest{
+		PeerTaskMetadata: PeerTaskMetadata{TaskID: "task-digest-root", PeerID: "peer-seed"},
+		ContentLength:    int64(len(testBytes)),
+		TotalPieces:      pieceCount,
+		GenPieceDigest:   true,
+	}))
+	packet, err := seed.GetPieces(context.Background(), &base.PieceTaskRequest{
+		TaskId:   "task-digest-root",
+		StartNum: 0,
+		Limit:    uint32(pieceCount),
+	})
+	assert.Nil(err)
+	assert.NotEmpty(packet.PieceDigestRoot)
+	assert.Len(packet.PieceInfos, int(pieceCount))
+	for _, piece := range packet.PieceInfos {
+		assert.Equal("sha256:"+digestutils.Sha256(string(testBytes[piece.RangeStart:piece.RangeStart+uint64(piece.RangeSize)])), piece.PieceDigest)
+		assert.True(digestutils.VerifyMerkleProof(packet.PieceDigestRoot, piece.PieceDigest, int(piece.PieceNum), int(pieceCount), piece.PieceProof))
+	}
+
+	// pieces downloaded before the root is known are verified when validating digest
+	peer := writePieces("task-digest-root", "peer-child", packet.PieceInfos)
+	assert.Nil(peer.UpdateTask(context.Background(), &UpdateTaskRequest{
+		PeerTaskMetadata: PeerTaskMetadata{TaskID: "task-digest-root", PeerID: "peer-child"},
+		ContentLength:    int64(len(testBytes)),
+		TotalPieces:      pieceCount,
+		PieceMd5Sign:     seed.(*localTaskStore).PieceMd5Sign,
+		PieceDigestRoot:  packet.PieceDigestRoot,
+	}))
+	assert.Nil(peer.ValidateDigest(nil))
+	childPacket, err := peer.GetPieces(context.Background(), &base.PieceTaskRequest{
+		TaskId:   "task-digest-root",
+		StartNum: 0,
+		Limit:    uint32(pieceCount),
+	})
+	assert.Nil(err)
+	assert.Equal(packet.PieceDigestRoot, childPacket.PieceDigestRoot)
+	assert.Equal(packet.PieceInfos[0].PieceProof, childPacket.PieceInfos[0].PieceProof)
+
+	// a different root is rejected
+	bad := writePieces("task-digest-root", "peer-bad", packet.PieceInfos)
+	assert.Nil(bad.UpdateTask(context.Background(), &UpdateTaskRequest{
+		PeerTaskMetadata: PeerTaskMetadata{TaskID: "task-digest-root", PeerID: "peer-bad"},
+		TotalPieces:      pieceCount,
+		PieceMd5Sign:     seed.(*localTaskStore).PieceMd5Sign,
+		PieceDigestRoot:  digestutils.Sha256("bad"),
+	}))
+	assert.Equal(ErrInvalidDigest, bad.ValidateDigest(nil))
+}
+
 func TestLocalTaskStore_StoreTaskData_Simple(t *testing.T) {
 	assert := testifyassert.New(t)
 	src := path.Join(test.DataDir, taskData)
@@ -366,3 +613,336 @@ func calcFileMd5(filePath string) (string, error) {
 	md5String = hex.EncodeToString(hashInBytes)
 	return md5String, nil
 }
+
+func TestLocalTaskStore_WatchPieces(t *testing.T) {
+	assert := testifyassert.New(t)
+	var (
+		taskID = "task-watch-d4bb1c273a9889fea14abd4651994fe8"
+		peerID = "peer-watch-d4bb1c273a9889fea14abd4651994fe8"
+		meta   = &PeerTaskMetadata{TaskID: taskID, PeerID: peerID}
+	)
+	sm, err := NewStorageManager(config.SimpleLocalTaskStoreStrategy,
+		&config.StorageOption{
+			DataPath: test.DataDir,
+			TaskExpireTime: clientutil.Duration{
+				Duration: time.Minute,
+			},
+		}, func(request CommonTaskRequest) {
+		})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var s = sm.(*storageManager)
+
+	_, err = s.WatchPieces(meta)
+	assert.Equal(ErrTaskNotFound, err, "watch unknown task should fail")
+
+	err = s.CreateTask(
+		RegisterTaskRequest{
+			CommonTaskRequest: CommonTaskRequest{
+				PeerID: peerID,
+				TaskID: taskID,
+			},
+			ContentLength: 10,
+		})
+	assert.Nil(err, "create task storage")
+
+	changed, err := s.WatchPieces(meta)
+	assert.Nil(err, "watch task should be ok")
+	select {
+	case <-changed:
+		t.Fatal("channel should not be closed before pieces changed")
+	default:
+	}
+
+	_, err = s.WritePiece(context.Background(), &WritePieceRequest{
+		PeerTaskMetadata: *meta,
+		PieceMetadata: PieceMetadata{
+			Num:   0,
+			Range: clientutil.Range{Start: 0, Length: 10},
+			Style: base.PieceStyle_PLAIN,
+		},
+		Reader: bytes.NewBuffer(make([]byte, 10)),
+	})
+	assert.Nil(err, "put piece")
+	select {
+	case <-changed:
+	default:
+		t.Fatal("channel should be closed after piece written")
+	}
+
+	// a new channel is returned after notified
+	changed, err = s.WatchPieces(meta)
+	assert.Nil(err, "watch task should be ok")
+	err = s.UpdateTask(context.Background(), &UpdateTaskRequest{
+		PeerTaskMetadata: *meta,
+		ContentLength:    10,
+		TotalPieces:      1,
+	})
+	assert.Nil(err, "update task")
+	select {
+	case <-changed:
+	default:
+		t.Fatal("channel should be closed after task updated")
+	}
+
+	changed, _ = s.WatchPieces(meta)
+	s.CleanUp()
+	select {
+	case <-changed:
+	default:
+		t.Fatal("channel should be closed after task reclaimed")
+	}
+}
+
+func TestLocalTaskStore_WritePiece_Concurrent(t *testing.T) {
+	assert := testifyassert.New(t)
+	var (
+		taskID = "task-concurrent-d4bb1c273a9889fea14abd4651994fe8"
+		peerID = "peer-concurrent-d4bb1c273a9889fea14abd4651994fe8"
+		meta   = PeerTaskMetadata{TaskID: taskID, PeerID: peerID}
+		good   = bytes.Repeat([]byte("a"), 1024)
+		bad    = bytes.Repeat([]byte("b"), 1024)
+		log    = logger.With("test", t.Name())
+	)
+	sm, err := NewStorageManager(config.SimpleLocalTaskStoreStrategy,
+		&config.StorageOption{
+			DataPath: t.TempDir(),
+			TaskExpireTime: clientutil.Duration{
+				Duration: time.Minute,
+			},
+		}, func(request CommonTaskRequest) {
+		})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sm.CleanUp()
+	err = sm.(*storageManager).CreateTask(
+		RegisterTaskRequest{
+			CommonTaskRequest: CommonTaskRequest{
+				PeerID: peerID,
+				TaskID: taskID,
+			},
+			ContentLength: int64(len(good)),
+		})
+	assert.Nil(err, "create task storage")
+
+	newRequest := func(reader io.Reader) *WritePieceRequest {
+		return &WritePieceRequest{
+			PeerTaskMetadata: meta,
+			PieceMetadata: PieceMetadata{
+				Num:   0,
+				Md5:   digestutils.Md5Bytes(good),
+				Range: clientutil.Range{Start: 0, Length: int64(len(good))},
+				Style: base.PieceStyle_PLAIN,
+			},
+			Reader: digestutils.NewDigestReader(log, io.LimitReader(reader, int64(len(good))), digestutils.Md5Bytes(good)),
+		}
+	}
+
+	// the slow parent serves a corrupt piece, it starts writing before the good one
+	pr, pw := io.Pipe()
+	slowErr := make(chan error)
+	go func() {
+		_, err := sm.WritePiece(context.Background(), newRequest(pr))
+		slowErr <- err
+	}()
+	_, err = pw.Write(bad[:512])
+	assert.Nil(err)
+
+	goodErr := make(chan error)
+	go func() {
+		_, err := sm.WritePiece(context.Background(), newRequest(bytes.NewBuffer(good)))
+		goodErr <- err
+	}()
+	select {
+	case err = <-goodErr:
+		t.Fatalf("good piece should wait the slow writer, error: %v", err)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	_, err = pw.Write(bad[512:])
+	assert.Nil(err)
+	assert.Nil(pw.Close())
+	assert.NotNil(<-slowErr, "corrupt piece should fail")
+	assert.Nil(<-goodErr, "good piece should be written")
+
+	r, c, err := sm.ReadPiece(context.Background(), &ReadPieceRequest{
+		PeerTaskMetadata: meta,
+		PieceMetadata:    PieceMetadata{Num: 0},
+	})
+	assert.Nil(err, "read piece")
+	data, err := io.ReadAll(r)
+	c.Close()
+	assert.Nil(err)
+	assert.Equal(good, data, "corrupt data must not overwrite the written piece")
+}
+
+func TestStorageManager_ManageTasks(t *testing.T) {
+	assert := testifyassert.New(t)
+	var (
+		taskID = "task-manage-d4bb1c273a9889fea14abd4651994fe8"
+		peerID = "peer-manage-d4bb1c273a9889fea14abd4651994fe8"
+	)
+	sm, err := NewStorageManager(config.SimpleLocalTaskStoreStrategy,
+		&config.StorageOption{
+			DataPath: test.DataDir,
+			TaskExpireTime: clientutil.Duration{
+				Duration: time.Minute,
+			},
+		}, func(request CommonTaskRequest) {
+		})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var s = sm.(*storageManager)
+
+	err = s.CreateTask(
+		RegisterTaskRequest{
+			CommonTaskRequest: CommonTaskRequest{
+				PeerID: peerID,
+				TaskID: taskID,
+			},
+			ContentLength: 10,
+			TotalPieces:   1,
+		})
+	assert.Nil(err, "create task storage")
+	_, err = s.WritePiece(context.Background(), &WritePieceRequest{
+		PeerTaskMetadata: PeerTaskMetadata{TaskID: taskID, PeerID: peerID},
+		PieceMetadata: PieceMetadata{
+			Num:   0,
+			Range: clientutil.Range{Start: 0, Length: 10},
+			Style: base.PieceStyle_PLAIN,
+		},
+		Reader: bytes.NewBuffer(make([]byte, 10)),
+	})
+	assert.Nil(err, "put piece")
+
+	tasks := s.ListTasks()
+	assert.Len(tasks, 1)
+	assert.Equal(taskID, tasks[0].TaskID)
+	assert.Equal(int64(10), tasks[0].CompletedLength)
+	assert.False(tasks[0].CreateTime.IsZero())
+
+	_, err = s.StatTask("unknown")
+	assert.Equal(ErrTaskNotFound, err)
+
+	// pinned task is not reclaimed even it is expired
+	assert.Nil(s.PinTask(taskID, true), "pin task")
+	ts, _ := s.LoadTask(PeerTaskMetadata{TaskID: taskID, PeerID: peerID})
+	ts.(*localTaskStore).lastAccess.Store(time.Now().Add(-1 * time.Hour).UnixNano())
+	assert.False(ts.(Reclaimer).CanReclaim(), "pinned task should not be reclaimed")
+	data, err := os.ReadFile(ts.(*localTaskStore).metadataFilePath)
+	assert.Nil(err)
+	var meta persistentMetadata
+	assert.Nil(json.Unmarshal(data, &meta), "metadata should be valid")
+	assert.True(meta.Pinned, "pinned should be saved")
+
+	tasks, err = s.StatTask(taskID)
+	assert.Nil(err)
+	assert.True(tasks[0].Pinned)
+
+	// unpin saves shorter metadata
+	assert.Nil(s.PinTask(taskID, false), "unpin task")
+	data, err = os.ReadFile(ts.(*localTaskStore).metadataFilePath)
+	assert.Nil(err)
+	meta = persistentMetadata{}
+	assert.Nil(json.Unmarshal(data, &meta), "metadata should be valid after unpinned")
+	assert.False(meta.Pinned, "unpinned should be saved")
+	assert.True(ts.(Reclaimer).CanReclaim(), "unpinned task should be reclaimed")
+
+	assert.Nil(s.DeleteTask(taskID), "delete task")
+	assert.Len(s.ListTasks(), 0)
+	assert.Equal(ErrTaskNotFound, s.DeleteTask(taskID))
+	_, err = os.Stat(ts.(*localTaskStore).dataDir)
+	assert.True(os.IsNotExist(err), "task data should be removed")
+}
+
+func TestStorageManager_FindUnfinishedTask(t *testing.T) {
+	assert := testifyassert.New(t)
+	var (
+		taskID = "task-unfinished-d4bb1c273a9889fea14abd4651994fe8"
+		peerID = "peer-unfinished-d4bb1c273a9889fea14abd4651994fe8"
+		meta   = PeerTaskMetadata{TaskID: taskID, PeerID: peerID}
+		option = &config.StorageOption{
+			DataPath: test.DataDir,
+			TaskExpireTime: clientutil.Duration{
+				Duration: time.Minute,
+			},
+		}
+		ctx = context.Background()
+	)
+	sm, err := NewStorageManager(config.SimpleLocalTaskStoreStrategy, option, func(request CommonTaskRequest) {})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var s = sm.(*storageManager)
+
+	err = s.CreateTask(
+		RegisterTaskRequest{
+			CommonTaskRequest: CommonTaskRequest{
+				PeerID: peerID,
+				TaskID: taskID,
+			},
+			ContentLength: 30,
+			TotalPieces:   3,
+		})
+	assert.Nil(err, "create task storage")
+	assert.Nil(s.FindUnfinishedTask(ctx, taskID), "task without pieces should not be resumed")
+
+	for i := 0; i < 3; i++ {
+		data := bytes.Repeat([]byte{byte('a' + i)}, 10)
+		piece := PieceMetadata{
+			Num:   int32(i),
+			Range: clientutil.Range{Start: int64(i * 10), Length: 10},
+			Style: base.PieceStyle_PLAIN,
+		}
+		// the last piece has no digest
+		if i < 2 {
+			piece.Md5 = digestutils.Md5Bytes(data)
+		}
+		_, err = s.WritePiece(ctx, &WritePieceRequest{
+			PeerTaskMetadata: meta,
+			PieceMetadata:    piece,
+			Reader:           bytes.NewBuffer(data),
+		})
+		assert.Nil(err, "put piece")
+	}
+	ts, _ := s.LoadTask(meta)
+	// corrupt the second piece
+	data, err := os.OpenFile(ts.(*localTaskStore).DataFilePath, os.O_WRONLY, 0)
+	assert.Nil(err)
+	_, err = data.WriteAt([]byte("x"), 15)
+	assert.Nil(err)
+	data.Close()
+
+	unfinished := s.FindUnfinishedTask(ctx, taskID)
+	if assert.NotNil(unfinished, "unfinished task should be found") {
+		assert.Equal(peerID, unfinished.PeerID)
+		assert.Equal(int64(30), unfinished.ContentLength)
+		assert.Equal(int32(3), unfinished.TotalPieces)
+		if assert.Len(unfinished.Pieces, 1, "invalid pieces should be dropped") {
+			assert.Equal(int32(0), unfinished.Pieces[0].Num)
+		}
+	}
+	assert.Nil(s.FindUnfinishedTask(ctx, taskID, peerID), "skipped peer should not be resumed")
+
+	// pieces of the unfinished task are kept after reload
+	reloaded, err := NewStorageManager(config.SimpleLocalTaskStoreStrategy, option, func(request CommonTaskRequest) {})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reloaded.CleanUp()
+	unfinished = reloaded.FindUnfinishedTask(ctx, taskID)
+	if assert.NotNil(unfinished, "unfinished task should be reloaded") {
+		assert.Equal(peerID, unfinished.PeerID)
+		assert.Len(unfinished.Pieces, 1)
+	}
+
+	// completed task is not unfinished
+	assert.Nil(reloaded.Store(ctx, &StoreRequest{
+		CommonTaskRequest: CommonTaskRequest{PeerID: peerID, TaskID: taskID},
+		MetadataOnly:      true,
+	}))
+	assert.Nil(reloaded.FindUnfinishedTask(ctx, taskID))
+}
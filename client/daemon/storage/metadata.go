@@ -18,6 +18,7 @@ package storage
 
 import (
 	"io"
+	"time"
 
 	"d7y.io/dragonfly/v2/client/clientutil"
 	"d7y.io/dragonfly/v2/pkg/rpc/base"
@@ -34,6 +35,76 @@ type persistentMetadata struct {
 	PieceMd5Sign  string                  `json:"pieceMd5Sign"`
 	DataFilePath  string                  `json:"dataFilePath"`
 	Done          bool                    `json:"done"`
+	// PieceDigestRoot is the merkle root of all piece digests
+	PieceDigestRoot string `json:"pieceDigestRoot,omitempty"`
+	// PieceDigestSignature is the cdn cluster signature of PieceDigestRoot
+	PieceDigestSignature []byte `json:"pieceDigestSignature,omitempty"`
+	// EncryptionSalt is set when the data file is encrypted
+	EncryptionSalt []byte `json:"encryptionSalt,omitempty"`
+	// CreateTime is the unix nano time when the task is created
+	CreateTime int64 `json:"createTime,omitempty"`
+	// Pinned task will not be reclaimed by gc
+	Pinned bool `json:"pinned,omitempty"`
+}
+
+const (
+	taskMetaURL    = "url"
+	taskMetaTag    = "tag"
+	taskMetaFilter = "filter"
+	taskMetaDigest = "digest"
+	taskMetaRange  = "range"
+)
+
+// newTaskMeta converts url and url meta to task meta, url headers are not saved
+func newTaskMeta(url string, meta *base.UrlMeta) map[string]string {
+	taskMeta := map[string]string{}
+	if url == "" {
+		return taskMeta
+	}
+	taskMeta[taskMetaURL] = url
+	if meta != nil {
+		for k, v := range map[string]string{
+			taskMetaTag:    meta.Tag,
+			taskMetaFilter: meta.Filter,
+			taskMetaDigest: meta.Digest,
+			taskMetaRange:  meta.Range,
+		} {
+			if v != "" {
+				taskMeta[k] = v
+			}
+		}
+	}
+	return taskMeta
+}
+
+// parseTaskMeta converts task meta back to url and url meta
+func parseTaskMeta(taskMeta map[string]string) (string, *base.UrlMeta) {
+	url := taskMeta[taskMetaURL]
+	if url == "" {
+		return "", nil
+	}
+	return url, &base.UrlMeta{
+		Tag:    taskMeta[taskMetaTag],
+		Filter: taskMeta[taskMetaFilter],
+		Digest: taskMeta[taskMetaDigest],
+		Range:  taskMeta[taskMetaRange],
+	}
+}
+
+// TaskInfo is the summary of a peer task in storage
+type TaskInfo struct {
+	PeerTaskMetadata
+	ContentLength int64
+	TotalPieces   int32
+	// URL and URLMeta are the origin of the task, URL is empty when the task is not created from url
+	URL     string
+	URLMeta *base.UrlMeta
+	// CompletedLength is the total length of downloaded pieces
+	CompletedLength int64
+	Done            bool
+	Pinned          bool
+	CreateTime      time.Time
+	LastAccessTime  time.Time
 }
 
 type PeerTaskMetadata struct {
@@ -47,6 +118,15 @@ type PieceMetadata struct {
 	Offset uint64           `json:"offset,omitempty"`
 	Range  clientutil.Range `json:"range,omitempty"`
 	Style  base.PieceStyle  `json:"style,omitempty"`
+	// MAC authenticates the encrypted piece data, it is empty when the data file is not encrypted
+	MAC []byte `json:"mac,omitempty"`
+	// Nonce is the encryption nonce of the piece data, every write of the piece gets a new one
+	Nonce []byte `json:"nonce,omitempty"`
+	// Digest is the piece content digest with algorithm, like sha256:xxx,
+	// it is only set when it is calculated from or verified with the piece content
+	Digest string `json:"digest,omitempty"`
+	// Proof is the merkle proof of Digest to the piece digest root
+	Proof []string `json:"proof,omitempty"`
 }
 
 type CommonTaskRequest struct {
@@ -60,13 +140,19 @@ type RegisterTaskRequest struct {
 	ContentLength int64
 	TotalPieces   int32
 	PieceMd5Sign  string
+	// URL and URLMeta are saved in task meta, they are used to announce the task to scheduler after reloading
+	URL     string
+	URLMeta *base.UrlMeta
 }
 
 type WritePieceRequest struct {
 	PeerTaskMetadata
 	PieceMetadata
 	UnknownLength bool
-	Reader        io.Reader
+	// GenPieceDigest calculates sha256 digest of piece when Digest is empty,
+	// a non-empty Digest is stored as is, so it must be verified with the piece content by caller
+	GenPieceDigest bool
+	Reader         io.Reader
 }
 
 type StoreRequest struct {
@@ -86,8 +172,21 @@ type UpdateTaskRequest struct {
 	ContentLength int64
 	TotalPieces   int32
 	PieceMd5Sign  string
+	// PieceDigestRoot is the merkle root of piece digests from parent
+	PieceDigestRoot string
+	// PieceDigestSignature is the cdn cluster signature of PieceDigestRoot
+	PieceDigestSignature []byte
 	// GenPieceDigest is used when back source
 	GenPieceDigest bool
 }
 
 type ReusePeerTask = UpdateTaskRequest
+
+// UnfinishedPeerTask is an unfinished peer task in storage which can be resumed
+type UnfinishedPeerTask struct {
+	PeerTaskMetadata
+	ContentLength int64
+	TotalPieces   int32
+	// Pieces are the downloaded pieces which pass digest validation, sorted by piece number
+	Pieces []PieceMetadata
+}
@@ -40,7 +40,10 @@ import (
 	"d7y.io/dragonfly/v2/client/config"
 	"d7y.io/dragonfly/v2/client/daemon/gc"
 	logger "d7y.io/dragonfly/v2/internal/dflog"
+	"d7y.io/dragonfly/v2/pkg/encryption"
 	"d7y.io/dragonfly/v2/pkg/rpc/base"
+	"d7y.io/dragonfly/v2/pkg/signature"
+	"d7y.io/dragonfly/v2/pkg/util/stringutils"
 )
 
 type TaskStorageDriver interface {
@@ -86,6 +89,21 @@ type Manager interface {
 	RegisterTask(ctx context.Context, req RegisterTaskRequest) error
 	// FindCompletedTask try to find a completed task for fast path
 	FindCompletedTask(taskID string) *ReusePeerTask
+	// FindUnfinishedTask try to find the unfinished task with the most downloaded pieces for resuming,
+	// the downloaded pieces are validated by their digest, it returns nil when no valid piece is found,
+	// peer tasks in skipPeers are ignored
+	FindUnfinishedTask(ctx context.Context, taskID string, skipPeers ...string) *UnfinishedPeerTask
+	// WatchPieces returns a channel which will be closed when pieces or metadata of the task are changed,
+	// caller should get pieces again and watch with a new channel after it is closed
+	WatchPieces(req *PeerTaskMetadata) (<-chan struct{}, error)
+	// ListTasks lists all peer tasks in storage
+	ListTasks() []*TaskInfo
+	// StatTask returns all peer tasks of the task
+	StatTask(taskID string) ([]*TaskInfo, error)
+	// DeleteTask reclaims all peer tasks of the task immediately
+	DeleteTask(taskID string) error
+	// PinTask pins or unpins all peer tasks of the task, pinned tasks will not be reclaimed by gc
+	PinTask(taskID string, pin bool) error
 	// CleanUp cleans all storage data
 	CleanUp()
 }
@@ -120,6 +138,9 @@ type storageManager struct {
 	gcInterval         time.Duration
 	indexRWMutex       sync.RWMutex
 	indexTask2PeerTask map[string][]*localTaskStore // key: task id, value: slice of localTaskStore
+	encryptor          *encryption.Encryptor
+	// pieceVerifier verifies the piece digest root before publishing it to peers
+	pieceVerifier *signature.Verifier
 }
 
 var _ gc.GC = (*storageManager)(nil)
@@ -191,6 +212,22 @@ func WithGCInterval(gcInterval time.Duration) func(*storageManager) error {
 	}
 }
 
+// WithEncryptor enables at-rest encryption for data files of new tasks
+// WithPieceVerifier sets the verifier of piece digest root, untrusted roots are not published to peers
+func WithPieceVerifier(verifier *signature.Verifier) func(*storageManager) error {
+	return func(manager *storageManager) error {
+		manager.pieceVerifier = verifier
+		return nil
+	}
+}
+
+func WithEncryptor(encryptor *encryption.Encryptor) func(*storageManager) error {
+	return func(manager *storageManager) error {
+		manager.encryptor = encryptor
+		return nil
+	}
+}
+
 func (s *storageManager) RegisterTask(ctx context.Context, req RegisterTaskRequest) error {
 	if _, ok := s.LoadTask(
 		PeerTaskMetadata{
@@ -274,7 +311,25 @@ func (s *storageManager) GetPieces(ctx context.Context, req *base.PieceTaskReque
 	if !ok {
 		return nil, ErrTaskNotFound
 	}
-	return t.(TaskStorageDriver).GetPieces(ctx, req)
+	packet, err := t.(TaskStorageDriver).GetPieces(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	// the root generated locally, like imported tasks, is not signed, peers reject the packet
+	// with it when the verifier is enabled, publish the pieces without root instead
+	if packet.PieceDigestRoot != "" && s.pieceVerifier.Enabled() &&
+		!s.pieceVerifier.Verify(packet.TaskId, packet.PieceDigestRoot, packet.PieceDigestSignature) {
+		packet.PieceDigestRoot, packet.PieceDigestSignature = "", nil
+	}
+	return packet, nil
+}
+
+func (s *storageManager) WatchPieces(req *PeerTaskMetadata) (<-chan struct{}, error) {
+	t, ok := s.LoadTask(*req)
+	if !ok {
+		return nil, ErrTaskNotFound
+	}
+	return t.(*localTaskStore).watchPieces(), nil
 }
 
 func (s *storageManager) LoadTask(meta PeerTaskMetadata) (TaskStorageDriver, bool) {
@@ -307,12 +362,13 @@ func (s *storageManager) CreateTask(req RegisterTaskRequest) error {
 		persistentMetadata: persistentMetadata{
 			StoreStrategy: string(s.storeStrategy),
 			TaskID:        req.TaskID,
-			TaskMeta:      map[string]string{},
+			TaskMeta:      newTaskMeta(req.URL, req.URLMeta),
 			ContentLength: req.ContentLength,
 			TotalPieces:   req.TotalPieces,
 			PieceMd5Sign:  req.PieceMd5Sign,
 			PeerID:        req.PeerID,
 			Pieces:        map[int32]PieceMetadata{},
+			CreateTime:    time.Now().UnixNano(),
 		},
 		gcCallback:       s.gcCallback,
 		dataDir:          dataDir,
@@ -335,6 +391,18 @@ func (s *storageManager) CreateTask(req RegisterTaskRequest) error {
 	if req.Destination == "" {
 		t.StoreStrategy = string(config.SimpleLocalTaskStoreStrategy)
 	}
+	// encrypted data file must not be placed beside destination, it will be decrypted to destination in Store
+	if s.encryptor != nil {
+		t.StoreStrategy = string(config.SimpleLocalTaskStoreStrategy)
+		salt, err := encryption.NewSalt()
+		if err != nil {
+			return err
+		}
+		if t.cipher, err = s.encryptor.Cipher(encryptionID(req.TaskID, req.PeerID), salt); err != nil {
+			return err
+		}
+		t.EncryptionSalt = salt
+	}
 	data := path.Join(dataDir, taskData)
 	switch t.StoreStrategy {
 	case string(config.SimpleLocalTaskStoreStrategy):
@@ -396,6 +464,10 @@ func (s *storageManager) CreateTask(req RegisterTaskRequest) error {
 	return nil
 }
 
+func encryptionID(taskID, peerID string) string {
+	return taskID + "/" + peerID
+}
+
 func (s *storageManager) FindCompletedTask(taskID string) *ReusePeerTask {
 	s.indexRWMutex.RLock()
 	defer s.indexRWMutex.RUnlock()
@@ -429,6 +501,112 @@ func (s *storageManager) FindCompletedTask(taskID string) *ReusePeerTask {
 	return nil
 }
 
+func (s *storageManager) FindUnfinishedTask(ctx context.Context, taskID string, skipPeers ...string) *UnfinishedPeerTask {
+	var candidate *localTaskStore
+	s.indexRWMutex.RLock()
+	for _, t := range s.indexTask2PeerTask[taskID] {
+		if t.invalid.Load() || t.reclaimMarked.Load() || t.Done {
+			continue
+		}
+		if stringutils.Contains(skipPeers, t.PeerID) {
+			continue
+		}
+		if t.completedPieces() == 0 {
+			continue
+		}
+		if candidate == nil || t.completedPieces() > candidate.completedPieces() {
+			candidate = t
+		}
+	}
+	s.indexRWMutex.RUnlock()
+	if candidate == nil {
+		return nil
+	}
+
+	// touch it to avoid reclaiming during validating
+	candidate.touch()
+	pieces := candidate.validatePieces(ctx)
+	if len(pieces) == 0 {
+		return nil
+	}
+	candidate.RLock()
+	defer candidate.RUnlock()
+	return &UnfinishedPeerTask{
+		PeerTaskMetadata: PeerTaskMetadata{
+			PeerID: candidate.PeerID,
+			TaskID: taskID,
+		},
+		ContentLength: candidate.ContentLength,
+		TotalPieces:   candidate.TotalPieces,
+		Pieces:        pieces,
+	}
+}
+
+func (s *storageManager) ListTasks() []*TaskInfo {
+	var tasks []*TaskInfo
+	s.tasks.Range(func(key, task interface{}) bool {
+		tasks = append(tasks, task.(*localTaskStore).taskInfo())
+		return true
+	})
+	sort.SliceStable(tasks, func(i, j int) bool {
+		return tasks[i].CreateTime.Before(tasks[j].CreateTime)
+	})
+	return tasks
+}
+
+func (s *storageManager) StatTask(taskID string) ([]*TaskInfo, error) {
+	ts := s.findTasks(taskID)
+	if len(ts) == 0 {
+		return nil, ErrTaskNotFound
+	}
+	var tasks []*TaskInfo
+	for _, t := range ts {
+		tasks = append(tasks, t.taskInfo())
+	}
+	return tasks, nil
+}
+
+func (s *storageManager) DeleteTask(taskID string) error {
+	ts := s.findTasks(taskID)
+	if len(ts) == 0 {
+		return ErrTaskNotFound
+	}
+	for _, t := range ts {
+		s.tasks.Delete(PeerTaskMetadata{
+			PeerID: t.PeerID,
+			TaskID: t.TaskID,
+		})
+		s.cleanIndex(t.TaskID, t.PeerID)
+		t.MarkReclaim()
+		if err := t.Reclaim(); err != nil {
+			logger.Errorf("delete task %s/%s error: %s", t.TaskID, t.PeerID, err)
+			return err
+		}
+		logger.Infof("task %s/%s deleted", t.TaskID, t.PeerID)
+	}
+	return nil
+}
+
+func (s *storageManager) PinTask(taskID string, pin bool) error {
+	ts := s.findTasks(taskID)
+	if len(ts) == 0 {
+		return ErrTaskNotFound
+	}
+	for _, t := range ts {
+		if err := t.pin(pin); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// findTasks returns a copy of all peer tasks of the task in index
+func (s *storageManager) findTasks(taskID string) []*localTaskStore {
+	s.indexRWMutex.RLock()
+	defer s.indexRWMutex.RUnlock()
+	return append([]*localTaskStore(nil), s.indexTask2PeerTask[taskID]...)
+}
+
 func (s *storageManager) cleanIndex(taskID, peerID string) {
 	s.indexRWMutex.Lock()
 	defer s.indexRWMutex.Unlock()
@@ -516,7 +694,8 @@ func (s *storageManager) ReloadPersistentTask(gcCallback GCCallback) error {
 			}
 			t.touch()
 
-			if t.metadataFile, err = os.Open(t.metadataFilePath); err != nil {
+			// metadata is opened for writing, it will be saved when the task is pinned or resumed
+			if t.metadataFile, err = os.OpenFile(t.metadataFilePath, os.O_RDWR, defaultFileMode); err != nil {
 				loadErrs = append(loadErrs, err)
 				loadErrDirs = append(loadErrDirs, dataDir)
 				logger.With("action", "reload", "stage", "read metadata", "taskID", taskID, "peerID", peerID).
@@ -539,6 +718,20 @@ func (s *storageManager) ReloadPersistentTask(gcCallback GCCallback) error {
 					Warnf("load task from disk error: %s", err0)
 				continue
 			}
+			if len(t.EncryptionSalt) > 0 {
+				if s.encryptor == nil {
+					err0 = errors.New("task data is encrypted, but encryption is disabled")
+				} else {
+					t.cipher, err0 = s.encryptor.Cipher(encryptionID(taskID, peerID), t.EncryptionSalt)
+				}
+				if err0 != nil {
+					loadErrs = append(loadErrs, err0)
+					loadErrDirs = append(loadErrDirs, dataDir)
+					logger.With("action", "reload", "stage", "init cipher", "taskID", taskID, "peerID", peerID).
+						Warnf("load task from disk error: %s", err0)
+					continue
+				}
+			}
 			logger.Debugf("load task %s/%s from disk, metadata %s, last access: %s, expire time: %s",
 				t.persistentMetadata.TaskID, t.persistentMetadata.PeerID, t.metadataFilePath, t.lastAccess, t.expireTime)
 			s.tasks.Store(PeerTaskMetadata{
@@ -631,7 +824,7 @@ func (s *storageManager) TryGC() (bool, error) {
 		s.tasks.Range(func(key, val interface{}) bool {
 			// skip reclaimed task
 			task := val.(*localTaskStore)
-			if task.reclaimMarked.Load() {
+			if task.reclaimMarked.Load() || task.isPinned() {
 				return true
 			}
 			// task is not done, and is active in s.gcInterval
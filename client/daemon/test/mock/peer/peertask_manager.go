@@ -11,10 +11,12 @@ import (
 	time "time"
 
 	peer "d7y.io/dragonfly/v2/client/daemon/peer"
+	storage "d7y.io/dragonfly/v2/client/daemon/storage"
 	logger "d7y.io/dragonfly/v2/internal/dflog"
 	base "d7y.io/dragonfly/v2/pkg/rpc/base"
 	scheduler "d7y.io/dragonfly/v2/pkg/rpc/scheduler"
 	gomock "github.com/golang/mock/gomock"
+	rate "golang.org/x/time/rate"
 )
 
 // MockTaskManager is a mock of TaskManager interface.
@@ -40,6 +42,33 @@ func (m *MockTaskManager) EXPECT() *MockTaskManagerMockRecorder {
 	return m.recorder
 }
 
+// AnnounceTasks mocks base method.
+func (m *MockTaskManager) AnnounceTasks(ctx context.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "AnnounceTasks", ctx)
+}
+
+// AnnounceTasks indicates an expected call of AnnounceTasks.
+func (mr *MockTaskManagerMockRecorder) AnnounceTasks(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AnnounceTasks", reflect.TypeOf((*MockTaskManager)(nil).AnnounceTasks), ctx)
+}
+
+// ImportTask mocks base method.
+func (m *MockTaskManager) ImportTask(ctx context.Context, req *peer.ImportTaskRequest) (*storage.TaskInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportTask", ctx, req)
+	ret0, _ := ret[0].(*storage.TaskInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImportTask indicates an expected call of ImportTask.
+func (mr *MockTaskManagerMockRecorder) ImportTask(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportTask", reflect.TypeOf((*MockTaskManager)(nil).ImportTask), ctx, req)
+}
+
 // IsPeerTaskRunning mocks base method.
 func (m *MockTaskManager) IsPeerTaskRunning(pid string) bool {
 	m.ctrl.T.Helper()
@@ -54,6 +83,18 @@ func (mr *MockTaskManagerMockRecorder) IsPeerTaskRunning(pid interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsPeerTaskRunning", reflect.TypeOf((*MockTaskManager)(nil).IsPeerTaskRunning), pid)
 }
 
+// SetPerPeerRateLimit mocks base method.
+func (m *MockTaskManager) SetPerPeerRateLimit(limit rate.Limit) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetPerPeerRateLimit", limit)
+}
+
+// SetPerPeerRateLimit indicates an expected call of SetPerPeerRateLimit.
+func (mr *MockTaskManagerMockRecorder) SetPerPeerRateLimit(limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetPerPeerRateLimit", reflect.TypeOf((*MockTaskManager)(nil).SetPerPeerRateLimit), limit)
+}
+
 // StartFilePeerTask mocks base method.
 func (m *MockTaskManager) StartFilePeerTask(ctx context.Context, req *peer.FilePeerTaskRequest) (chan *peer.FilePeerTaskProgress, *peer.TinyData, error) {
 	m.ctrl.T.Helper()
@@ -70,6 +111,22 @@ func (mr *MockTaskManagerMockRecorder) StartFilePeerTask(ctx, req interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartFilePeerTask", reflect.TypeOf((*MockTaskManager)(nil).StartFilePeerTask), ctx, req)
 }
 
+// StartSeekableStreamPeerTask mocks base method.
+func (m *MockTaskManager) StartSeekableStreamPeerTask(ctx context.Context, req *scheduler.PeerTaskRequest) (peer.SeekableReader, map[string]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StartSeekableStreamPeerTask", ctx, req)
+	ret0, _ := ret[0].(peer.SeekableReader)
+	ret1, _ := ret[1].(map[string]string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// StartSeekableStreamPeerTask indicates an expected call of StartSeekableStreamPeerTask.
+func (mr *MockTaskManagerMockRecorder) StartSeekableStreamPeerTask(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartSeekableStreamPeerTask", reflect.TypeOf((*MockTaskManager)(nil).StartSeekableStreamPeerTask), ctx, req)
+}
+
 // StartStreamPeerTask mocks base method.
 func (m *MockTaskManager) StartStreamPeerTask(ctx context.Context, req *scheduler.PeerTaskRequest) (io.ReadCloser, map[string]string, error) {
 	m.ctrl.T.Helper()
@@ -86,6 +143,21 @@ func (mr *MockTaskManagerMockRecorder) StartStreamPeerTask(ctx, req interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartStreamPeerTask", reflect.TypeOf((*MockTaskManager)(nil).StartStreamPeerTask), ctx, req)
 }
 
+// StatTask mocks base method.
+func (m *MockTaskManager) StatTask(ctx context.Context, taskID string) (*scheduler.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StatTask", ctx, taskID)
+	ret0, _ := ret[0].(*scheduler.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StatTask indicates an expected call of StatTask.
+func (mr *MockTaskManagerMockRecorder) StatTask(ctx, taskID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StatTask", reflect.TypeOf((*MockTaskManager)(nil).StatTask), ctx, taskID)
+}
+
 // Stop mocks base method.
 func (m *MockTaskManager) Stop(ctx context.Context) error {
 	m.ctrl.T.Helper()
@@ -268,6 +268,20 @@ func (mr *MockManagerMockRecorder) CleanUp() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CleanUp", reflect.TypeOf((*MockManager)(nil).CleanUp))
 }
 
+// DeleteTask mocks base method.
+func (m *MockManager) DeleteTask(taskID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTask", taskID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteTask indicates an expected call of DeleteTask.
+func (mr *MockManagerMockRecorder) DeleteTask(taskID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTask", reflect.TypeOf((*MockManager)(nil).DeleteTask), taskID)
+}
+
 // FindCompletedTask mocks base method.
 func (m *MockManager) FindCompletedTask(taskID string) *storage.ReusePeerTask {
 	m.ctrl.T.Helper()
@@ -282,6 +296,25 @@ func (mr *MockManagerMockRecorder) FindCompletedTask(taskID interface{}) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindCompletedTask", reflect.TypeOf((*MockManager)(nil).FindCompletedTask), taskID)
 }
 
+// FindUnfinishedTask mocks base method.
+func (m *MockManager) FindUnfinishedTask(ctx context.Context, taskID string, skipPeers ...string) *storage.UnfinishedPeerTask {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, taskID}
+	for _, a := range skipPeers {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "FindUnfinishedTask", varargs...)
+	ret0, _ := ret[0].(*storage.UnfinishedPeerTask)
+	return ret0
+}
+
+// FindUnfinishedTask indicates an expected call of FindUnfinishedTask.
+func (mr *MockManagerMockRecorder) FindUnfinishedTask(ctx, taskID interface{}, skipPeers ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, taskID}, skipPeers...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindUnfinishedTask", reflect.TypeOf((*MockManager)(nil).FindUnfinishedTask), varargs...)
+}
+
 // GetPieces mocks base method.
 func (m *MockManager) GetPieces(ctx context.Context, req *base.PieceTaskRequest) (*base.PiecePacket, error) {
 	m.ctrl.T.Helper()
@@ -324,6 +357,34 @@ func (mr *MockManagerMockRecorder) Keep() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Keep", reflect.TypeOf((*MockManager)(nil).Keep))
 }
 
+// ListTasks mocks base method.
+func (m *MockManager) ListTasks() []*storage.TaskInfo {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTasks")
+	ret0, _ := ret[0].([]*storage.TaskInfo)
+	return ret0
+}
+
+// ListTasks indicates an expected call of ListTasks.
+func (mr *MockManagerMockRecorder) ListTasks() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTasks", reflect.TypeOf((*MockManager)(nil).ListTasks))
+}
+
+// PinTask mocks base method.
+func (m *MockManager) PinTask(taskID string, pin bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PinTask", taskID, pin)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PinTask indicates an expected call of PinTask.
+func (mr *MockManagerMockRecorder) PinTask(taskID, pin interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PinTask", reflect.TypeOf((*MockManager)(nil).PinTask), taskID, pin)
+}
+
 // ReadAllPieces mocks base method.
 func (m *MockManager) ReadAllPieces(ctx context.Context, req *storage.PeerTaskMetadata) (io.ReadCloser, error) {
 	m.ctrl.T.Helper()
@@ -369,6 +430,21 @@ func (mr *MockManagerMockRecorder) RegisterTask(ctx, req interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterTask", reflect.TypeOf((*MockManager)(nil).RegisterTask), ctx, req)
 }
 
+// StatTask mocks base method.
+func (m *MockManager) StatTask(taskID string) ([]*storage.TaskInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StatTask", taskID)
+	ret0, _ := ret[0].([]*storage.TaskInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StatTask indicates an expected call of StatTask.
+func (mr *MockManagerMockRecorder) StatTask(taskID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StatTask", reflect.TypeOf((*MockManager)(nil).StatTask), taskID)
+}
+
 // Store mocks base method.
 func (m *MockManager) Store(ctx context.Context, req *storage.StoreRequest) error {
 	m.ctrl.T.Helper()
@@ -411,6 +487,21 @@ func (mr *MockManagerMockRecorder) ValidateDigest(req interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateDigest", reflect.TypeOf((*MockManager)(nil).ValidateDigest), req)
 }
 
+// WatchPieces mocks base method.
+func (m *MockManager) WatchPieces(req *storage.PeerTaskMetadata) (<-chan struct{}, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WatchPieces", req)
+	ret0, _ := ret[0].(<-chan struct{})
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WatchPieces indicates an expected call of WatchPieces.
+func (mr *MockManagerMockRecorder) WatchPieces(req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WatchPieces", reflect.TypeOf((*MockManager)(nil).WatchPieces), req)
+}
+
 // WritePiece mocks base method.
 func (m *MockManager) WritePiece(ctx context.Context, req *storage.WritePieceRequest) (int64, error) {
 	m.ctrl.T.Helper()
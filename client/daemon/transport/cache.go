@@ -0,0 +1,414 @@
+/*
+ *     Copyright 2020 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package transport
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-http-utils/headers"
+	"github.com/golang/groupcache/lru"
+	"github.com/pkg/errors"
+
+	logger "d7y.io/dragonfly/v2/internal/dflog"
+	"d7y.io/dragonfly/v2/pkg/source"
+	"d7y.io/dragonfly/v2/pkg/util/digestutils"
+	"d7y.io/dragonfly/v2/pkg/util/net/httputils"
+)
+
+const (
+	// DefaultHTTPCacheSize is the default max resources recorded in http cache
+	DefaultHTTPCacheSize = 10000
+
+	// maxHeuristicFreshness limits the freshness calculated from Last-Modified
+	maxHeuristicFreshness = 24 * time.Hour
+
+	// maxVariants limits the variants of one resource selected by Vary
+	maxVariants = 16
+
+	// foreverFresh is the lifetime of resources which never need revalidation
+	foreverFresh time.Duration = -1
+)
+
+// HTTPCache records the cache policies of the resources downloaded with dragonfly. It decides
+// whether a request can be served by the peer task of the known version, or the resource has to be
+// revalidated with origin, or downloaded directly when it is not storable in shared caches.
+type HTTPCache struct {
+	lock sync.Mutex
+	// resources are keyed by url
+	resources *lru.Cache
+}
+
+// NewHTTPCache returns a http cache records the policies of size resources at most.
+func NewHTTPCache(size int) *HTTPCache {
+	return &HTTPCache{
+		resources: lru.New(size),
+	}
+}
+
+type cachedResource struct {
+	// vary is the request headers which select the variant, in canonical form and sorted
+	vary     []string
+	variants map[string]cachedVariant
+}
+
+type cachedVariant struct {
+	// direct indicates the response is not storable in shared caches
+	direct bool
+
+	// version identifies the content of the variant, it makes a new peer task when the content changes
+	version string
+
+	etag         string
+	lastModified string
+
+	// lifetime is the freshness lifetime, foreverFresh means no need to revalidate
+	lifetime time.Duration
+	expireAt time.Time
+}
+
+func (v cachedVariant) fresh(now time.Time) bool {
+	return v.lifetime == foreverFresh || now.Before(v.expireAt)
+}
+
+// cachePolicy is the decision of http cache for a request.
+type cachePolicy struct {
+	// direct indicates to download the request without dragonfly
+	direct bool
+	// observe indicates to update the cache policy with the direct response
+	observe bool
+	// version is appended to the tag of peer task
+	version string
+}
+
+// lookup returns the variant of the request.
+func (c *HTTPCache) lookup(req *http.Request) (cachedVariant, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	value, ok := c.resources.Get(req.URL.String())
+	if !ok {
+		return cachedVariant{}, false
+	}
+	resource := value.(*cachedResource)
+	variant, ok := resource.variants[variantKey(req.Header, resource.vary)]
+	return variant, ok
+}
+
+// refresh renews the freshness of the variant after it is revalidated with origin.
+func (c *HTTPCache) refresh(req *http.Request, now time.Time) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	value, ok := c.resources.Get(req.URL.String())
+	if !ok {
+		return
+	}
+	resource := value.(*cachedResource)
+	key := variantKey(req.Header, resource.vary)
+	if variant, ok := resource.variants[key]; ok {
+		variant.expireAt = now.Add(variant.lifetime)
+		resource.variants[key] = variant
+	}
+}
+
+// store records the cache policy of the response from origin.
+func (c *HTTPCache) store(req *http.Request, resp *http.Response, now time.Time) cachedVariant {
+	cc := parseCacheControl(resp.Header)
+	vary, varyAll := parseVary(resp.Header)
+	key := variantKey(req.Header, vary)
+
+	variant := cachedVariant{
+		etag:         resp.Header.Get(headers.ETag),
+		lastModified: resp.Header.Get(headers.LastModified),
+	}
+	if varyAll || cc.has("no-store") || cc.has("private") {
+		variant.direct = true
+	} else {
+		variant.lifetime = freshnessLifetime(resp.Header, cc, now)
+		variant.expireAt = now.Add(variant.lifetime)
+		switch {
+		case variant.etag != "" || variant.lastModified != "":
+			variant.version = digestutils.Sha256(variant.etag, variant.lastModified, key)[:16]
+		case variant.lifetime != foreverFresh:
+			// content can not be revalidated, download it again when it is stale, without a version
+			// peers agree on, it is downloaded directly
+			variant.version, variant.direct = sharedVersion(resp.Header, cc, variant.lifetime, key)
+		}
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	var resource *cachedResource
+	if value, ok := c.resources.Get(req.URL.String()); ok {
+		resource = value.(*cachedResource)
+	}
+	if resource == nil || !equalStrings(resource.vary, vary) || len(resource.variants) >= maxVariants {
+		resource = &cachedResource{vary: vary, variants: map[string]cachedVariant{}}
+		c.resources.Add(req.URL.String(), resource)
+	}
+	resource.variants[key] = variant
+	return variant
+}
+
+// checkCache returns the cache policy of the request, the resource is revalidated with origin when
+// it is stale, and is probed by a HEAD request when it is unknown.
+func (rt *transport) checkCache(req *http.Request) cachePolicy {
+	// image layers are addressed by content, they never change
+	if rt.cache == nil || layerReg.MatchString(req.URL.Path) {
+		return cachePolicy{}
+	}
+
+	cc := parseCacheControl(req.Header)
+	if cc.has("no-store") {
+		return cachePolicy{direct: true}
+	}
+	revalidate := cc.has("no-cache") || cc["max-age"] == "0" ||
+		(len(cc) == 0 && strings.EqualFold(req.Header.Get(headers.Pragma), "no-cache"))
+
+	log := logger.With("url", req.URL.String(), "component", "httpCache")
+	now := time.Now()
+	if variant, ok := rt.cache.lookup(req); ok {
+		if variant.direct {
+			return cachePolicy{direct: true, observe: true}
+		}
+		if variant.fresh(now) && !revalidate {
+			return cachePolicy{version: variant.version}
+		}
+		if variant.etag != "" || variant.lastModified != "" {
+			expired, err := rt.isExpired(req, variant)
+			if err == nil && !expired {
+				log.Debugf("revalidated version %s", variant.version)
+				rt.cache.refresh(req, now)
+				return cachePolicy{version: variant.version}
+			}
+			if err != nil {
+				log.Warnf("revalidate error: %s", err)
+			}
+		}
+	}
+
+	variant, err := rt.probe(req, now)
+	if err != nil {
+		// keep downloading with dragonfly as before when origin can not be probed
+		log.Warnf("probe cache policy error: %s", err)
+		return cachePolicy{}
+	}
+	log.Debugf("probed cache policy, direct: %t, version: %s, lifetime: %s", variant.direct, variant.version, variant.lifetime)
+	return cachePolicy{direct: variant.direct, observe: variant.direct, version: variant.version}
+}
+
+// isExpired sends a conditional request with the validators of the variant to origin.
+func (rt *transport) isExpired(req *http.Request, variant cachedVariant) (bool, error) {
+	hdr := req.Header.Clone()
+	delHopHeaders(hdr)
+	request, err := source.NewRequestWithContext(req.Context(), req.URL.String(), httputils.HeaderToMap(hdr))
+	if err != nil {
+		return false, err
+	}
+	request.Header.Del(headers.Range)
+	return source.IsExpired(request, &source.ExpireInfo{
+		LastModified: variant.lastModified,
+		ETag:         variant.etag,
+	})
+}
+
+// probe gets the cache policy of the resource with a HEAD request.
+func (rt *transport) probe(req *http.Request, now time.Time) (cachedVariant, error) {
+	head := req.Clone(req.Context())
+	head.Method = http.MethodHead
+	head.Body = nil
+	head.ContentLength = 0
+	head.Host = head.URL.Host
+	head.Header.Del(headers.Range)
+	delHopHeaders(head.Header)
+
+	resp, err := rt.baseRoundTripper.RoundTrip(head)
+	if err != nil {
+		return cachedVariant{}, err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return cachedVariant{}, errors.Errorf("unexpected probe status %s", resp.Status)
+	}
+	return rt.cache.store(req, resp, now), nil
+}
+
+// cacheControl is the directives of header Cache-Control, directive names are in lower case.
+type cacheControl map[string]string
+
+func parseCacheControl(header http.Header) cacheControl {
+	cc := cacheControl{}
+	for _, value := range header.Values(headers.CacheControl) {
+		for _, directive := range strings.Split(value, ",") {
+			directive = strings.TrimSpace(directive)
+			if directive == "" {
+				continue
+			}
+			if i := strings.IndexByte(directive, '='); i >= 0 {
+				cc[strings.ToLower(strings.TrimSpace(directive[:i]))] = strings.Trim(strings.TrimSpace(directive[i+1:]), `"`)
+			} else {
+				cc[strings.ToLower(directive)] = ""
+			}
+		}
+	}
+	return cc
+}
+
+func (cc cacheControl) has(directive string) bool {
+	_, ok := cc[directive]
+	return ok
+}
+
+func (cc cacheControl) seconds(directive string) (time.Duration, bool) {
+	value, ok := cc[directive]
+	if !ok {
+		return 0, false
+	}
+	seconds, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || seconds < 0 {
+		return 0, true
+	}
+	return time.Duration(seconds) * time.Second, true
+}
+
+// freshnessLifetime calculates the freshness lifetime of a response for shared caches, see RFC 7234 section 4.2.1.
+func freshnessLifetime(header http.Header, cc cacheControl, now time.Time) time.Duration {
+	if cc.has("no-cache") {
+		return 0
+	}
+	if cc.has("immutable") {
+		return foreverFresh
+	}
+
+	var age time.Duration
+	if seconds, err := strconv.ParseInt(header.Get("Age"), 10, 64); err == nil && seconds > 0 {
+		age = time.Duration(seconds) * time.Second
+	}
+	if lifetime, ok := cc.seconds("s-maxage"); ok {
+		return nonNegative(lifetime - age)
+	}
+	if lifetime, ok := cc.seconds("max-age"); ok {
+		return nonNegative(lifetime - age)
+	}
+
+	date := now
+	if t, err := http.ParseTime(header.Get("Date")); err == nil {
+		date = t
+	}
+	if expires := header.Get(headers.Expires); expires != "" {
+		t, err := http.ParseTime(expires)
+		if err != nil {
+			return 0
+		}
+		return nonNegative(t.Sub(date))
+	}
+	if t, err := http.ParseTime(header.Get(headers.LastModified)); err == nil {
+		lifetime := nonNegative(date.Sub(t) / 10)
+		if lifetime > maxHeuristicFreshness {
+			lifetime = maxHeuristicFreshness
+		}
+		return lifetime
+	}
+	if header.Get(headers.ETag) != "" {
+		return 0
+	}
+	// no way to know whether the resource changes, treat it as immutable
+	return foreverFresh
+}
+
+// sharedVersion returns the version of the resource without validators, peers fetching it in the same
+// freshness window of origin's clock share it. direct is true when the window can not be determined.
+func sharedVersion(header http.Header, cc cacheControl, freshness time.Duration, key string) (version string, direct bool) {
+	// stale content is never shared
+	if freshness <= 0 {
+		return "", true
+	}
+	lifetime, ok := cc.seconds("s-maxage")
+	if !ok {
+		lifetime, ok = cc.seconds("max-age")
+	}
+	if !ok {
+		// the expiration of origin is the same for all peers
+		if expires := header.Get(headers.Expires); expires != "" {
+			return digestutils.Sha256(expires, key)[:16], false
+		}
+		return "", true
+	}
+
+	date, err := http.ParseTime(header.Get("Date"))
+	if err != nil || lifetime <= 0 {
+		return "", true
+	}
+	// responses from intermediate caches carry Age, use the time the response was generated by origin
+	if seconds, err := strconv.ParseInt(header.Get("Age"), 10, 64); err == nil && seconds > 0 {
+		date = date.Add(-time.Duration(seconds) * time.Second)
+	}
+	window := date.Truncate(lifetime).Unix()
+	return digestutils.Sha256(strconv.FormatInt(window, 10), key)[:16], false
+}
+
+func nonNegative(d time.Duration) time.Duration {
+	if d < 0 {
+		return 0
+	}
+	return d
+}
+
+// parseVary returns the request headers in Vary, and whether the response varies by all.
+func parseVary(header http.Header) ([]string, bool) {
+	var vary []string
+	for _, value := range header.Values(headers.Vary) {
+		for _, name := range strings.Split(value, ",") {
+			name = strings.TrimSpace(name)
+			if name == "" {
+				continue
+			}
+			if name == "*" {
+				return nil, true
+			}
+			vary = append(vary, http.CanonicalHeaderKey(name))
+		}
+	}
+	sort.Strings(vary)
+	return vary, false
+}
+
+func variantKey(header http.Header, vary []string) string {
+	var values []string
+	for _, name := range vary {
+		values = append(values, name+":"+strings.Join(header.Values(name), ","))
+	}
+	return strings.Join(values, "\n")
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
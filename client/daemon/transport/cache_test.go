@@ -0,0 +1,258 @@
+/*
+ *     Copyright 2020 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package transport
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	testifyassert "github.com/stretchr/testify/assert"
+
+	mock_peer "d7y.io/dragonfly/v2/client/daemon/test/mock/peer"
+	"d7y.io/dragonfly/v2/pkg/rpc/scheduler"
+	_ "d7y.io/dragonfly/v2/pkg/source/httpprotocol" // register http client for revalidation
+)
+
+func TestFreshnessLifetime(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name   string
+		header map[string]string
+		expect time.Duration
+	}{
+		{name: "max-age", header: map[string]string{"Cache-Control": "public, max-age=60"}, expect: time.Minute},
+		{name: "s-maxage first", header: map[string]string{"Cache-Control": "max-age=60, s-maxage=10"}, expect: 10 * time.Second},
+		{name: "age", header: map[string]string{"Cache-Control": "max-age=60", "Age": "50"}, expect: 10 * time.Second},
+		{name: "no-cache", header: map[string]string{"Cache-Control": "no-cache, max-age=60"}, expect: 0},
+		{name: "immutable", header: map[string]string{"Cache-Control": "immutable"}, expect: foreverFresh},
+		{
+			name: "expires",
+			header: map[string]string{
+				"Date":    now.UTC().Format(http.TimeFormat),
+				"Expires": now.Add(time.Hour).UTC().Format(http.TimeFormat),
+			},
+			expect: time.Hour,
+		},
+		{name: "invalid expires", header: map[string]string{"Expires": "0"}, expect: 0},
+		{
+			name: "heuristic",
+			header: map[string]string{
+				"Date":          now.UTC().Format(http.TimeFormat),
+				"Last-Modified": now.Add(-10 * time.Hour).UTC().Format(http.TimeFormat),
+			},
+			expect: time.Hour,
+		},
+		{name: "etag only", header: map[string]string{"ETag": `"a"`}, expect: 0},
+		{name: "no validator", header: map[string]string{}, expect: foreverFresh},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			header := http.Header{}
+			for k, v := range tc.header {
+				header.Set(k, v)
+			}
+			testifyassert.Equal(t, tc.expect, freshnessLifetime(header, parseCacheControl(header), now))
+		})
+	}
+}
+
+// testOrigin is a http server whose cache headers can be changed during test.
+type testOrigin struct {
+	sync.Mutex
+	header     http.Header
+	heads      int
+	gets       int
+	conditions int
+}
+
+func (o *testOrigin) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	o.Lock()
+	defer o.Unlock()
+	for k, v := range o.header {
+		w.Header()[k] = v
+	}
+	switch {
+	case r.Method == http.MethodHead:
+		o.heads++
+	case r.Header.Get("If-None-Match") != "":
+		o.conditions++
+		if r.Header.Get("If-None-Match") == o.header.Get("ETag") {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+	default:
+		o.gets++
+	}
+	w.Write([]byte("origin"))
+}
+
+func (o *testOrigin) set(key, value string) {
+	o.Lock()
+	defer o.Unlock()
+	o.header.Set(key, value)
+}
+
+func (o *testOrigin) counts() (int, int, int) {
+	o.Lock()
+	defer o.Unlock()
+	return o.heads, o.gets, o.conditions
+}
+
+func TestHTTPCache_SharedVersion(t *testing.T) {
+	assert := testifyassert.New(t)
+
+	date := time.Date(2022, 1, 1, 0, 0, 10, 0, time.UTC)
+	store := func(header map[string]string, now time.Time) cachedVariant {
+		req, _ := http.NewRequest(http.MethodGet, "http://origin/resource", nil)
+		resp := &http.Response{Header: http.Header{}}
+		for k, v := range header {
+			resp.Header.Set(k, v)
+		}
+		// every daemon has its own cache
+		return NewHTTPCache(DefaultHTTPCacheSize).store(req, resp, now)
+	}
+
+	// peers probing in the same max-age window of origin share the version
+	a := store(map[string]string{"Cache-Control": "max-age=60", "Date": date.Format(http.TimeFormat)}, date)
+	b := store(map[string]string{"Cache-Control": "max-age=60", "Date": date.Add(20 * time.Second).Format(http.TimeFormat)}, date.Add(time.Second))
+	c := store(map[string]string{"Cache-Control": "max-age=60", "Date": date.Add(40 * time.Second).Format(http.TimeFormat), "Age": "30"}, date.Add(time.Second))
+	assert.False(a.direct)
+	assert.NotEmpty(a.version)
+	assert.Equal(a.version, b.version)
+	assert.Equal(a.version, c.version)
+
+	// next window is a new version
+	d := store(map[string]string{"Cache-Control": "max-age=60", "Date": date.Add(time.Minute).Format(http.TimeFormat)}, date)
+	assert.NotEqual(a.version, d.version)
+
+	// the expiration of origin identifies the version
+	expires := map[string]string{"Date": date.Format(http.TimeFormat), "Expires": date.Add(time.Hour).Format(http.TimeFormat)}
+	assert.Equal(store(expires, date).version, store(expires, date.Add(time.Second)).version)
+
+	// without Date, peers can not agree on the version
+	assert.True(store(map[string]string{"Cache-Control": "max-age=60"}, date).direct)
+	// stale content is not shared
+	assert.True(store(map[string]string{"Cache-Control": "max-age=0", "Date": date.Format(http.TimeFormat)}, date).direct)
+}
+
+func TestTransport_HTTPCache(t *testing.T) {
+	assert := testifyassert.New(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	origin := &testOrigin{header: http.Header{}}
+	server := httptest.NewServer(origin)
+	defer server.Close()
+
+	var tags []string
+	peerTaskManager := mock_peer.NewMockTaskManager(ctrl)
+	peerTaskManager.EXPECT().StartStreamPeerTask(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, req *scheduler.PeerTaskRequest) (io.ReadCloser, map[string]string, error) {
+			tags = append(tags, req.UrlMeta.Tag)
+			return io.NopCloser(bytes.NewBufferString("p2p")), nil, nil
+		},
+	).AnyTimes()
+
+	rt, _ := New(
+		WithPeerHost(&scheduler.PeerHost{}),
+		WithPeerTaskManager(peerTaskManager),
+		WithDefaultBiz("biz"),
+		WithCondition(func(r *http.Request) bool {
+			return true
+		}),
+		WithHTTPCache(NewHTTPCache(DefaultHTTPCacheSize)),
+	)
+
+	get := func(path string, header map[string]string) string {
+		req, _ := http.NewRequestWithContext(context.Background(), http.MethodGet, server.URL+path, nil)
+		for k, v := range header {
+			req.Header.Set(k, v)
+		}
+		resp, err := rt.RoundTrip(req)
+		assert.Nil(err)
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return string(body)
+	}
+
+	// fresh resource is probed once and served by the same task
+	origin.set("Cache-Control", "max-age=60")
+	origin.set("ETag", `"v1"`)
+	assert.Equal("p2p", get("/fresh", nil))
+	assert.Equal("p2p", get("/fresh", nil))
+	heads, gets, conditions := origin.counts()
+	assert.Equal([]int{1, 0, 0}, []int{heads, gets, conditions})
+	assert.Len(tags, 2)
+	assert.Equal(tags[0], tags[1])
+	assert.Contains(tags[0], "biz@")
+
+	// request no-cache revalidates with origin, unchanged resource keeps the task
+	assert.Equal("p2p", get("/fresh", map[string]string{"Cache-Control": "no-cache"}))
+	heads, gets, conditions = origin.counts()
+	assert.Equal([]int{1, 0, 1}, []int{heads, gets, conditions})
+	assert.Equal(tags[0], tags[2])
+
+	// changed resource is a new task
+	origin.set("ETag", `"v2"`)
+	assert.Equal("p2p", get("/fresh", map[string]string{"Cache-Control": "no-cache"}))
+	heads, gets, conditions = origin.counts()
+	assert.Equal([]int{2, 0, 2}, []int{heads, gets, conditions})
+	assert.NotEqual(tags[0], tags[3])
+
+	// no-store resource is downloaded directly, and is not probed again
+	origin.set("Cache-Control", "no-store")
+	assert.Equal("origin", get("/private", nil))
+	assert.Equal("origin", get("/private", nil))
+	heads, gets, _ = origin.counts()
+	assert.Equal([]int{3, 2}, []int{heads, gets})
+
+	// becomes cacheable again after the direct response allows
+	origin.set("Cache-Control", "max-age=60")
+	assert.Equal("origin", get("/private", nil))
+	assert.Equal("p2p", get("/private", nil))
+
+	// request no-store is always downloaded directly
+	assert.Equal("origin", get("/fresh", map[string]string{"Cache-Control": "no-store"}))
+
+	// variants selected by Vary are different tasks
+	origin.set("Vary", "X-Variant")
+	count := len(tags)
+	assert.Equal("p2p", get("/vary", map[string]string{"X-Variant": "a"}))
+	assert.Equal("p2p", get("/vary", map[string]string{"X-Variant": "b"}))
+	assert.Equal("p2p", get("/vary", map[string]string{"X-Variant": "a"}))
+	assert.NotEqual(tags[count], tags[count+1])
+	assert.Equal(tags[count], tags[count+2])
+
+	// vary by all is not storable
+	origin.set("Vary", "*")
+	assert.Equal("origin", get("/vary-all", nil))
+
+	// image layers never change
+	heads, _, _ = origin.counts()
+	assert.Equal("p2p", get("/v2/a/blobs/sha256:abc", nil))
+	newHeads, _, _ := origin.counts()
+	assert.Equal(heads, newHeads)
+	assert.Equal("biz", tags[len(tags)-1])
+}
@@ -0,0 +1,177 @@
+/*
+ *     Copyright 2020 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package transport
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/go-http-utils/headers"
+	"github.com/opencontainers/go-digest"
+	"github.com/pkg/errors"
+
+	"d7y.io/dragonfly/v2/client/clientutil"
+	"d7y.io/dragonfly/v2/client/daemon/metrics"
+	logger "d7y.io/dragonfly/v2/internal/dflog"
+)
+
+// blobDigestReg the regex to get the digest of image layer
+var blobDigestReg = regexp.MustCompile("/blobs/(sha256:[a-f0-9]{64})$")
+
+// fallbackReader reads the body of stream peer task, when peer to peer downloading fails
+// after the response header has been sent, it resumes the remaining bytes from the source
+// with a range request and splices them into the same body.
+type fallbackReader struct {
+	rt  *transport
+	req *http.Request
+	log *logger.SugaredLoggerOnWith
+
+	body io.ReadCloser
+	// fallback indicates body is read from the source
+	fallback bool
+
+	// start is the offset of the first byte of body in the resource
+	start int64
+	// end is the offset of the last byte of body in the resource, -1 means the end of resource
+	end int64
+	// read is the number of the bytes read
+	read int64
+
+	// verifier verifies the digest of the whole body, nil means the digest is unknown
+	verifier digest.Verifier
+	expected digest.Digest
+}
+
+// newFallbackReader wraps body of req to resume from the source, contentLength is the length of body,
+// -1 means unknown. It returns body itself when the range of req can not be resumed.
+func newFallbackReader(rt *transport, req *http.Request, body io.ReadCloser, contentLength int64, log *logger.SugaredLoggerOnWith) io.ReadCloser {
+	r := &fallbackReader{
+		rt:    rt,
+		req:   req,
+		log:   log,
+		body:  body,
+		start: 0,
+		end:   -1,
+	}
+
+	if rg := req.Header.Get(headers.Range); rg != "" {
+		// suffix range and multiple ranges can not be resumed
+		if strings.HasPrefix(rg, "bytes=-") {
+			return body
+		}
+		ranges, err := clientutil.ParseRange(rg, math.MaxInt64)
+		if err != nil || len(ranges) != 1 {
+			return body
+		}
+		r.start = ranges[0].Start
+		if contentLength < 0 && !strings.HasSuffix(rg, "-") {
+			r.end = ranges[0].Start + ranges[0].Length - 1
+		}
+	} else if matches := blobDigestReg.FindStringSubmatch(req.URL.Path); len(matches) == 2 {
+		// the bytes read from peers can not be digested after falling back,
+		// so digest the whole body from the beginning
+		if d, err := digest.Parse(matches[1]); err == nil {
+			r.expected = d
+			r.verifier = d.Verifier()
+		}
+	}
+	if contentLength >= 0 {
+		r.end = r.start + contentLength - 1
+	}
+	return r
+}
+
+func (r *fallbackReader) Read(p []byte) (int, error) {
+	n, err := r.body.Read(p)
+	r.read += int64(n)
+	if r.verifier != nil {
+		_, _ = r.verifier.Write(p[:n])
+	}
+
+	switch {
+	case err == nil:
+		return n, nil
+	case err == io.EOF:
+		return n, r.verify()
+	case r.fallback || r.req.Context().Err() != nil:
+		return n, err
+	}
+
+	r.log.Warnf("read stream peer task failed after %d bytes, resume from the source: %s", r.read, err)
+	if ferr := r.resume(); ferr != nil {
+		metrics.ProxyFallbackFailureCount.Inc()
+		r.log.Errorf("resume from the source failed: %s", ferr)
+		return n, err
+	}
+	metrics.ProxyFallbackCount.Inc()
+	return n, nil
+}
+
+// resume replaces body with the remaining bytes of the source.
+func (r *fallbackReader) resume() error {
+	offset := r.start + r.read
+	rg := fmt.Sprintf("bytes=%d-", offset)
+	if r.end >= 0 {
+		if offset > r.end {
+			// all bytes have been read
+			r.body.Close()
+			r.body, r.fallback = http.NoBody, true
+			return nil
+		}
+		rg = fmt.Sprintf("bytes=%d-%d", offset, r.end)
+	}
+
+	req := r.req.Clone(r.req.Context())
+	req.Header.Set(headers.Range, rg)
+	resp, err := r.rt.roundTripDirect(req)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusPartialContent {
+		resp.Body.Close()
+		return errors.Errorf("source does not support range request, status: %s", resp.Status)
+	}
+	if cr := resp.Header.Get(headers.ContentRange); !strings.HasPrefix(cr, fmt.Sprintf("bytes %d-", offset)) {
+		resp.Body.Close()
+		return errors.Errorf("unexpected content range %q of range %q", cr, rg)
+	}
+
+	r.log.Infof("resume from the source with range %s", rg)
+	r.body.Close()
+	r.body, r.fallback = resp.Body, true
+	return nil
+}
+
+// verify checks the length and digest of body at the end of reading.
+func (r *fallbackReader) verify() error {
+	if r.end >= 0 && r.start+r.read != r.end+1 {
+		return io.ErrUnexpectedEOF
+	}
+	if r.verifier != nil && !r.verifier.Verified() {
+		r.log.Errorf("digest mismatch, expected: %s, fallback: %t", r.expected, r.fallback)
+		return errors.Errorf("digest mismatch, expected: %s", r.expected)
+	}
+	return io.EOF
+}
+
+func (r *fallbackReader) Close() error {
+	return r.body.Close()
+}
@@ -0,0 +1,189 @@
+/*
+ *     Copyright 2020 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package transport
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/go-http-utils/headers"
+	"github.com/golang/mock/gomock"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	testifyassert "github.com/stretchr/testify/assert"
+
+	"d7y.io/dragonfly/v2/client/daemon/metrics"
+	mock_peer "d7y.io/dragonfly/v2/client/daemon/test/mock/peer"
+	"d7y.io/dragonfly/v2/pkg/rpc/scheduler"
+)
+
+// brokenReader returns err after reading data.
+type brokenReader struct {
+	data io.Reader
+	err  error
+}
+
+func (r *brokenReader) Read(p []byte) (int, error) {
+	n, err := r.data.Read(p)
+	if err == io.EOF {
+		return n, r.err
+	}
+	return n, err
+}
+
+func TestTransport_Fallback(t *testing.T) {
+	data := make([]byte, 4096)
+	for i := range data {
+		data[i] = byte(i % 251)
+	}
+	blob := fmt.Sprintf("/v2/library/alpine/blobs/sha256:%x", sha256.Sum256(data))
+
+	tests := []struct {
+		name        string
+		path        string
+		rangeHeader string
+		// p2p is the content returned by peers before failing
+		p2p           []byte
+		contentLength int64
+		noRange       bool
+		expectRange   string
+		expect        []byte
+		expectErr     bool
+		expectResumed float64
+	}{
+		{
+			name:          "resume blob",
+			path:          blob,
+			p2p:           data[:1000],
+			contentLength: int64(len(data)),
+			expectRange:   "bytes=1000-4095",
+			expect:        data,
+			expectResumed: 1,
+		},
+		{
+			name:          "resume blob with unknown length",
+			path:          blob,
+			p2p:           data[:1000],
+			contentLength: -1,
+			expectRange:   "bytes=1000-",
+			expect:        data,
+			expectResumed: 1,
+		},
+		{
+			name:          "resume range",
+			path:          "/files/foo",
+			rangeHeader:   "bytes=100-1099",
+			p2p:           data[100:600],
+			contentLength: 1000,
+			expectRange:   "bytes=600-1099",
+			expect:        data[100:1100],
+			expectResumed: 1,
+		},
+		{
+			name:          "digest mismatch",
+			path:          blob,
+			p2p:           append([]byte{0xff}, data[1:1000]...),
+			contentLength: int64(len(data)),
+			expectRange:   "bytes=1000-4095",
+			expectErr:     true,
+			expectResumed: 1,
+		},
+		{
+			name:          "source does not support range",
+			path:          blob,
+			p2p:           data[:1000],
+			contentLength: int64(len(data)),
+			noRange:       true,
+			expectRange:   "bytes=1000-4095",
+			expectErr:     true,
+		},
+		{
+			name:          "suffix range is not resumed",
+			path:          "/files/foo",
+			rangeHeader:   "bytes=-1000",
+			p2p:           data[3096:3500],
+			contentLength: 1000,
+			expectErr:     true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := testifyassert.New(t)
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			var sourceRange string
+			source := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				sourceRange = r.Header.Get(headers.Range)
+				if tc.noRange {
+					w.Write(data)
+					return
+				}
+				http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(data))
+			}))
+			defer source.Close()
+
+			peerTaskManager := mock_peer.NewMockTaskManager(ctrl)
+			peerTaskManager.EXPECT().StartStreamPeerTask(gomock.Any(), gomock.Any()).DoAndReturn(
+				func(ctx context.Context, req *scheduler.PeerTaskRequest) (io.ReadCloser, map[string]string, error) {
+					attr := map[string]string{}
+					if tc.contentLength >= 0 {
+						attr[headers.ContentLength] = strconv.FormatInt(tc.contentLength, 10)
+					}
+					return io.NopCloser(&brokenReader{
+						data: bytes.NewReader(tc.p2p),
+						err:  errors.New("peer task failed"),
+					}), attr, nil
+				},
+			)
+			rt, _ := New(
+				WithPeerHost(&scheduler.PeerHost{}),
+				WithPeerTaskManager(peerTaskManager),
+				WithCondition(func(r *http.Request) bool {
+					return true
+				}))
+
+			resumed := testutil.ToFloat64(metrics.ProxyFallbackCount)
+			req, _ := http.NewRequestWithContext(context.Background(), http.MethodGet, source.URL+tc.path, nil)
+			if tc.rangeHeader != "" {
+				req.Header.Set(headers.Range, tc.rangeHeader)
+			}
+			resp, err := rt.RoundTrip(req)
+			assert.Nil(err)
+			defer resp.Body.Close()
+
+			output, err := io.ReadAll(resp.Body)
+			assert.Equal(tc.expectRange, sourceRange)
+			assert.Equal(tc.expectResumed, testutil.ToFloat64(metrics.ProxyFallbackCount)-resumed)
+			if tc.expectErr {
+				assert.Error(err)
+				return
+			}
+			assert.Nil(err)
+			assert.Equal(tc.expect, output)
+		})
+	}
+}
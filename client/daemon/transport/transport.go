@@ -68,6 +68,9 @@ type transport struct {
 
 	// dumpHTTPContent indicates to dump http request header and response header
 	dumpHTTPContent bool
+
+	// cache honors the cache semantics of resources, nil means resources never change
+	cache *HTTPCache
 }
 
 // Option is functional config for transport.
@@ -128,6 +131,14 @@ func WithDumpHTTPContent(b bool) Option {
 	}
 }
 
+// WithHTTPCache sets the http cache shared by transports to honor Cache-Control, validators and Vary
+func WithHTTPCache(c *HTTPCache) Option {
+	return func(rt *transport) *transport {
+		rt.cache = c
+		return rt
+	}
+}
+
 // New constructs a new instance of a RoundTripper with additional options.
 func New(options ...Option) (http.RoundTripper, error) {
 	rt := &transport{
@@ -149,13 +160,19 @@ func (rt *transport) RoundTrip(req *http.Request) (resp *http.Response, err erro
 		// delete the Accept-Encoding header to avoid returning the same cached
 		// result for different requests
 		req.Header.Del("Accept-Encoding")
-		logger.Debugf("round trip with dragonfly: %s", req.URL.String())
-		resp, err = rt.download(req)
+		if policy := rt.checkCache(req); policy.direct {
+			logger.Debugf("round trip directly by cache policy, url: %s", req.URL.String())
+			resp, err = rt.roundTripDirect(req)
+			if err == nil && policy.observe && resp.StatusCode == http.StatusOK {
+				rt.cache.store(req, resp, time.Now())
+			}
+		} else {
+			logger.Debugf("round trip with dragonfly: %s", req.URL.String())
+			resp, err = rt.download(req, policy.version)
+		}
 	} else {
 		logger.Debugf("round trip directly, method: %s, url: %s", req.Method, req.URL.String())
-		req.Host = req.URL.Host
-		req.Header.Set("Host", req.Host)
-		resp, err = rt.baseRoundTripper.RoundTrip(req)
+		resp, err = rt.roundTripDirect(req)
 	}
 	if err != nil {
 		logger.With("method", req.Method, "url", req.URL.String()).
@@ -165,14 +182,20 @@ func (rt *transport) RoundTrip(req *http.Request) (resp *http.Response, err erro
 	return resp, err
 }
 
+func (rt *transport) roundTripDirect(req *http.Request) (*http.Response, error) {
+	req.Host = req.URL.Host
+	req.Header.Set("Host", req.Host)
+	return rt.baseRoundTripper.RoundTrip(req)
+}
+
 // NeedUseDragonfly is the default value for shouldUseDragonfly, which downloads all
 // images layers with dragonfly.
 func NeedUseDragonfly(req *http.Request) bool {
 	return req.Method == http.MethodGet && layerReg.MatchString(req.URL.Path)
 }
 
-// download uses dragonfly to download.
-func (rt *transport) download(req *http.Request) (*http.Response, error) {
+// download uses dragonfly to download, version identifies the content of the resource in http cache.
+func (rt *transport) download(req *http.Request, version string) (*http.Response, error) {
 	url := req.URL.String()
 	peerID := idgen.PeerID(rt.peerHost.Ip)
 	log := logger.With("peer", peerID, "component", "transport")
@@ -190,16 +213,25 @@ func (rt *transport) download(req *http.Request) (*http.Response, error) {
 	// Pick header's parameters
 	filter := httputils.PickHeader(req.Header, config.HeaderDragonflyFilter, rt.defaultFilter)
 	tag := httputils.PickHeader(req.Header, config.HeaderDragonflyBiz, rt.defaultBiz)
+	pieceSelection := httputils.PickHeader(req.Header, config.HeaderDragonflyPieceSelection, "")
 
 	// Delete hop-by-hop headers
 	delHopHeaders(req.Header)
 
 	meta.Header = httputils.HeaderToMap(req.Header)
 	meta.Tag = tag
+	if version != "" {
+		// different versions of the same url are different tasks
+		meta.Tag = tag + "@" + version
+	}
 	meta.Filter = filter
 
+	ctx := req.Context()
+	if pieceSelection != "" {
+		ctx = peer.WithPieceSelection(ctx, pieceSelection)
+	}
 	body, attr, err := rt.peerTaskManager.StartStreamPeerTask(
-		req.Context(),
+		ctx,
 		&scheduler.PeerTaskRequest{
 			Url:         url,
 			UrlMeta:     meta,
@@ -231,7 +263,7 @@ func (rt *transport) download(req *http.Request) (*http.Response, error) {
 
 	resp := &http.Response{
 		StatusCode:    http.StatusOK,
-		Body:          body,
+		Body:          newFallbackReader(rt, req, body, contentLength, log),
 		Header:        hdr,
 		ContentLength: contentLength,
 
@@ -0,0 +1,95 @@
+/*
+ *     Copyright 2020 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package upload
+
+import (
+	"sync"
+)
+
+// Admission limits the concurrent uploads of the host,
+// and shares the concurrency fairly between requesting peers.
+// It is shared by http and grpc piece transport.
+type Admission struct {
+	lock   sync.Mutex
+	limit  int
+	active int
+	// peers holds the active uploads of every requesting peer
+	peers map[string]int
+}
+
+// NewAdmission returns an admission with the concurrency limit, limit <= 0 means no limit
+func NewAdmission(limit int) *Admission {
+	return &Admission{
+		limit: limit,
+		peers: map[string]int{},
+	}
+}
+
+// Acquire admits an upload for the requesting peer, it returns false when the host is busy,
+// release must be called after the upload is done.
+// A requesting peer can use at most its fair share, which is the limit divided by requesting peers.
+func (a *Admission) Acquire(peer string) (release func(), ok bool) {
+	if a == nil {
+		return func() {}, true
+	}
+
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	if a.limit <= 0 {
+		return func() {}, true
+	}
+	if a.active >= a.limit {
+		return nil, false
+	}
+
+	peers := len(a.peers)
+	if _, ok := a.peers[peer]; !ok {
+		peers++
+	}
+	share := a.limit / peers
+	if share < 1 {
+		share = 1
+	}
+	if a.peers[peer] >= share {
+		return nil, false
+	}
+
+	a.active++
+	a.peers[peer]++
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			a.release(peer)
+		})
+	}, true
+}
+
+// SetLimit updates the concurrency limit, active uploads are not affected
+func (a *Admission) SetLimit(limit int) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	a.limit = limit
+}
+
+func (a *Admission) release(peer string) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	a.active--
+	if a.peers[peer]--; a.peers[peer] <= 0 {
+		delete(a.peers, peer)
+	}
+}
@@ -0,0 +1,75 @@
+/*
+ *     Copyright 2020 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package upload
+
+import (
+	"testing"
+
+	testifyassert "github.com/stretchr/testify/assert"
+)
+
+func TestAdmission_Acquire(t *testing.T) {
+	assert := testifyassert.New(t)
+
+	// no limit
+	var releases []func()
+	admission := NewAdmission(0)
+	for i := 0; i < 10; i++ {
+		release, ok := admission.Acquire("peer-0")
+		assert.True(ok)
+		releases = append(releases, release)
+	}
+	for _, release := range releases {
+		release()
+	}
+
+	// single peer can use all concurrency
+	admission.SetLimit(4)
+	releases = nil
+	for i := 0; i < 4; i++ {
+		release, ok := admission.Acquire("peer-0")
+		assert.True(ok)
+		releases = append(releases, release)
+	}
+	_, ok := admission.Acquire("peer-1")
+	assert.False(ok, "host should be busy")
+
+	// release twice takes effect once
+	releases[0]()
+	releases[0]()
+	releases = releases[1:]
+
+	// fair share is 2 for 2 peers
+	release1, ok := admission.Acquire("peer-1")
+	assert.True(ok)
+	releases[0]()
+	releases = releases[1:]
+	_, ok = admission.Acquire("peer-0")
+	assert.False(ok, "peer-0 should be limited by fair share")
+	release2, ok := admission.Acquire("peer-1")
+	assert.True(ok)
+	_, ok = admission.Acquire("peer-1")
+	assert.False(ok, "host should be busy")
+
+	release1()
+	release2()
+	for _, release := range releases {
+		release()
+	}
+	assert.Equal(0, admission.active)
+	assert.Equal(0, len(admission.peers))
+}
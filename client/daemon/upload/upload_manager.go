@@ -21,8 +21,10 @@ import (
 	"fmt"
 	"io"
 	"math"
+	"mime/multipart"
 	"net"
 	"net/http"
+	"net/textproto"
 
 	"github.com/go-http-utils/headers"
 	"github.com/gorilla/mux"
@@ -44,12 +46,21 @@ type uploadManager struct {
 	*http.Server
 	*rate.Limiter
 	StorageManager storage.Manager
+	admission      *Admission
 }
 
 var _ Manager = (*uploadManager)(nil)
 
 const (
 	PeerDownloadHTTPPathPrefix = "/download/"
+
+	// PeerDownloadHTTPSrcPeerQuery is the query of the requesting peer id, it is used for upload admission
+	PeerDownloadHTTPSrcPeerQuery = "srcPeerId"
+
+	// maxUploadRanges is the max ranges in one upload request
+	maxUploadRanges = 64
+
+	octetStreamContentType = "application/octet-stream"
 )
 
 func NewUploadManager(s storage.Manager, opts ...func(*uploadManager)) (Manager, error) {
@@ -71,6 +82,13 @@ func WithLimiter(limiter *rate.Limiter) func(*uploadManager) {
 	}
 }
 
+// WithAdmission sets upload admission which limits concurrent uploads
+func WithAdmission(admission *Admission) func(*uploadManager) {
+	return func(manager *uploadManager) {
+		manager.admission = admission
+	}
+}
+
 func (um *uploadManager) initRouter() {
 	r := mux.NewRouter()
 	r.HandleFunc(PeerDownloadHTTPPathPrefix+"{taskPrefix:.*}/"+"{task:.*}", um.handleUpload).Queries("peerId", "{.*}").Methods("GET")
@@ -86,6 +104,8 @@ func (um *uploadManager) Stop() error {
 }
 
 // handleUpload uses to upload a task file when other peers download from it.
+// A request with multiple ranges is served with a multipart/byteranges response,
+// so that peers can download several pieces in one request.
 func (um *uploadManager) handleUpload(w http.ResponseWriter, r *http.Request) {
 	var (
 		task = mux.Vars(r)["task"]
@@ -101,47 +121,140 @@ func (um *uploadManager) handleUpload(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
-	if len(rg) != 1 {
-		sLogger.Error("multi range parsed, not support")
+	if len(rg) == 0 {
+		sLogger.Error("no range parsed")
 		http.Error(w, "invalid range", http.StatusBadRequest)
 		return
 	}
+	if len(rg) > maxUploadRanges {
+		sLogger.Errorf("too many ranges: %d", len(rg))
+		http.Error(w, "too many ranges", http.StatusRequestedRangeNotSatisfiable)
+		return
+	}
 
-	// add header "Content-Length" to avoid chunked body in http client
-	w.Header().Add(headers.ContentLength, fmt.Sprintf("%d", rg[0].Length))
-	reader, closer, err := um.StorageManager.ReadPiece(r.Context(),
-		&storage.ReadPieceRequest{
-			PeerTaskMetadata: storage.PeerTaskMetadata{
-				TaskID: task,
-				PeerID: peer,
-			},
-			PieceMetadata: storage.PieceMetadata{
-				Num:   -1,
-				Range: rg[0],
-			},
-		})
-	if err != nil {
-		sLogger.Errorf("get task data failed: %s", err)
-		http.Error(w, fmt.Sprintf("get piece data error: %s", err), http.StatusInternalServerError)
+	// requests from old peers have no src peer id, use remote address instead
+	srcPeer := r.FormValue(PeerDownloadHTTPSrcPeerQuery)
+	if srcPeer == "" {
+		srcPeer, _, _ = net.SplitHostPort(r.RemoteAddr)
+	}
+	release, ok := um.admission.Acquire(srcPeer)
+	if !ok {
+		sLogger.Warnf("upload is busy, reject request from %s", srcPeer)
+		http.Error(w, "upload is busy", http.StatusServiceUnavailable)
 		return
 	}
-	defer closer.Close()
-	if um.Limiter != nil {
-		if err = um.Limiter.WaitN(r.Context(), int(rg[0].Length)); err != nil {
+	defer release()
+
+	// open all ranges before writing header, so that errors can be responded
+	readers := make([]io.Reader, len(rg))
+	for i := range rg {
+		reader, closer, err := um.StorageManager.ReadPiece(r.Context(),
+			&storage.ReadPieceRequest{
+				PeerTaskMetadata: storage.PeerTaskMetadata{
+					TaskID: task,
+					PeerID: peer,
+				},
+				PieceMetadata: storage.PieceMetadata{
+					Num:   -1,
+					Range: rg[i],
+				},
+			})
+		if err != nil {
+			sLogger.Errorf("get task data failed: %s", err)
+			http.Error(w, fmt.Sprintf("get piece data error: %s", err), http.StatusInternalServerError)
+			return
+		}
+		defer closer.Close()
+		readers[i] = reader
+	}
+
+	if len(rg) == 1 {
+		if err = um.waitLimit(r.Context(), rg[0].Length); err != nil {
 			sLogger.Errorf("get limit failed: %s", err)
 			http.Error(w, fmt.Sprintf("get limit error: %s", err), http.StatusInternalServerError)
 			return
 		}
+		// add header "Content-Length" to avoid chunked body in http client,
+		// add header "Content-Type" to avoid content sniffing, then the whole data can be transferred by sendfile
+		w.Header().Add(headers.ContentLength, fmt.Sprintf("%d", rg[0].Length))
+		w.Header().Set(headers.ContentType, octetStreamContentType)
+		if err = transfer(w, readers[0], rg[0].Length); err != nil {
+			sLogger.Errorf("transfer data failed: %s", err)
+		}
+		return
 	}
 
-	// if w is a socket, golang will use sendfile or splice syscall for zero copy feature
-	// when start to transfer data, we could not call http.Error with header
-	if n, err := io.Copy(w, reader); err != nil {
-		sLogger.Errorf("transfer data failed: %s", err)
-		return
-	} else if n != rg[0].Length {
-		sLogger.Errorf("transferred data length not match request, request: %d, transferred: %d",
-			rg[0].Length, n)
-		return
+	mw := multipart.NewWriter(w)
+	w.Header().Set(headers.ContentLength, fmt.Sprintf("%d", multipartSize(rg, mw.Boundary())))
+	w.Header().Set(headers.ContentType, "multipart/byteranges; boundary="+mw.Boundary())
+	w.WriteHeader(http.StatusPartialContent)
+	for i := range rg {
+		if err = um.waitLimit(r.Context(), rg[i].Length); err != nil {
+			sLogger.Errorf("get limit failed: %s", err)
+			return
+		}
+		if _, err = mw.CreatePart(partHeader(rg[i])); err != nil {
+			sLogger.Errorf("create part failed: %s", err)
+			return
+		}
+		// part writer just writes to w, transfer to w directly for sendfile
+		if err = transfer(w, readers[i], rg[i].Length); err != nil {
+			sLogger.Errorf("transfer data of range %d-%d failed: %s", rg[i].Start, rg[i].Start+rg[i].Length-1, err)
+			return
+		}
+	}
+	if err = mw.Close(); err != nil {
+		sLogger.Errorf("close multipart failed: %s", err)
+	}
+}
+
+// waitLimit waits upload rate limiter for length bytes, it does nothing when rate limit is not active
+func (um *uploadManager) waitLimit(ctx context.Context, length int64) error {
+	if um.Limiter == nil || um.Limiter.Limit() == rate.Inf {
+		return nil
+	}
+	return um.Limiter.WaitN(ctx, int(length))
+}
+
+// transfer copies the range data to w.
+// if w is a socket and reader is a plain file, golang will use sendfile or splice syscall for zero copy feature,
+// when start to transfer data, we could not call http.Error with header
+func transfer(w io.Writer, reader io.Reader, length int64) error {
+	n, err := io.Copy(w, reader)
+	if err != nil {
+		return err
+	}
+	if n != length {
+		return fmt.Errorf("transferred data length not match request, request: %d, transferred: %d", length, n)
+	}
+	return nil
+}
+
+func partHeader(rg clientutil.Range) textproto.MIMEHeader {
+	return textproto.MIMEHeader{
+		headers.ContentType:  {octetStreamContentType},
+		headers.ContentRange: {fmt.Sprintf("bytes %d-%d/*", rg.Start, rg.Start+rg.Length-1)},
 	}
 }
+
+// multipartSize returns the size of multipart/byteranges body
+func multipartSize(rg []clientutil.Range, boundary string) int64 {
+	var w countingWriter
+	mw := multipart.NewWriter(&w)
+	// boundary is valid, it is generated by multipart writer
+	_ = mw.SetBoundary(boundary)
+	var size int64
+	for i := range rg {
+		_, _ = mw.CreatePart(partHeader(rg[i]))
+		size += rg[i].Length
+	}
+	_ = mw.Close()
+	return size + int64(w)
+}
+
+type countingWriter int64
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	*w += countingWriter(len(p))
+	return len(p), nil
+}
@@ -21,11 +21,14 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"mime"
+	"mime/multipart"
 	"net"
 	"net/http"
 	"os"
 	"testing"
 
+	"github.com/go-http-utils/headers"
 	"github.com/golang/mock/gomock"
 	testifyassert "github.com/stretchr/testify/assert"
 	"golang.org/x/time/rate"
@@ -107,3 +110,80 @@ func TestUploadManager_Serve(t *testing.T) {
 		assert.Equal(tt.targetPieceData, data)
 	}
 }
+
+func TestUploadManager_ServeMultiRange(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	assert := testifyassert.New(t)
+	testData, err := os.ReadFile(test.File)
+	assert.Nil(err, "load test file")
+
+	mockStorageManager := mock_storage.NewMockManager(ctrl)
+	mockStorageManager.EXPECT().ReadPiece(gomock.Any(), gomock.Any()).AnyTimes().
+		DoAndReturn(func(ctx context.Context, req *storage.ReadPieceRequest) (io.Reader, io.Closer, error) {
+			return bytes.NewBuffer(testData[req.Range.Start : req.Range.Start+req.Range.Length]),
+				io.NopCloser(nil), nil
+		})
+
+	// without limiter, data is transferred by sendfile when possible
+	um, err := NewUploadManager(mockStorageManager)
+	assert.Nil(err, "NewUploadManager")
+
+	listen, err := net.Listen("tcp4", "127.0.0.1:0")
+	assert.Nil(err, "Listen")
+	addr := listen.Addr().String()
+
+	go func() {
+		if err := um.Serve(listen); err != nil {
+			t.Error(err)
+		}
+	}()
+
+	tests := []struct {
+		pieceRange       string
+		targetPieceDatas [][]byte
+		contentRanges    []string
+	}{
+		{
+			pieceRange:       "bytes=0-9,100-199",
+			targetPieceDatas: [][]byte{testData[0:10], testData[100:200]},
+			contentRanges:    []string{"bytes 0-9/*", "bytes 100-199/*"},
+		},
+		{
+			pieceRange:       fmt.Sprintf("bytes=512-1023,10-19,1024-%d", len(testData)-1),
+			targetPieceDatas: [][]byte{testData[512:1024], testData[10:20], testData[1024:]},
+			contentRanges:    []string{"bytes 512-1023/*", "bytes 10-19/*", fmt.Sprintf("bytes 1024-%d/*", len(testData)-1)},
+		},
+	}
+
+	for _, tt := range tests {
+		req, _ := http.NewRequest(http.MethodGet,
+			fmt.Sprintf("http://%s%s%s/%s?peerId=%s", addr, PeerDownloadHTTPPathPrefix, "666", "task-0", "peer-0"), nil)
+		req.Header.Add("Range", tt.pieceRange)
+
+		resp, err := http.DefaultClient.Do(req)
+		assert.Nil(err, "get piece data")
+		assert.Equal(http.StatusPartialContent, resp.StatusCode)
+
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		assert.Nil(err, "read body")
+		assert.Equal(int64(len(body)), resp.ContentLength, "content length should match body")
+
+		mediaType, params, err := mime.ParseMediaType(resp.Header.Get(headers.ContentType))
+		assert.Nil(err, "parse content type")
+		assert.Equal("multipart/byteranges", mediaType)
+
+		mr := multipart.NewReader(bytes.NewReader(body), params["boundary"])
+		for i := range tt.targetPieceDatas {
+			part, err := mr.NextPart()
+			assert.Nil(err, "next part")
+			assert.Equal(tt.contentRanges[i], part.Header.Get(headers.ContentRange))
+			data, _ := io.ReadAll(part)
+			assert.Equal(tt.targetPieceDatas[i], data)
+		}
+		_, err = mr.NextPart()
+		assert.Equal(io.EOF, err)
+	}
+}
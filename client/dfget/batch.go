@@ -0,0 +1,334 @@
+/*
+ *     Copyright 2020 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dfget
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/schollz/progressbar/v3"
+	"golang.org/x/sync/semaphore"
+	"gopkg.in/yaml.v3"
+
+	"d7y.io/dragonfly/v2/client/config"
+	logger "d7y.io/dragonfly/v2/internal/dflog"
+	daemonclient "d7y.io/dragonfly/v2/pkg/rpc/dfdaemon/client"
+	"d7y.io/dragonfly/v2/pkg/source"
+	"d7y.io/dragonfly/v2/pkg/util/digestutils"
+)
+
+// BatchEntry is one file to download in the manifest of input file.
+type BatchEntry struct {
+	URL    string   `yaml:"url"`
+	Output string   `yaml:"output,omitempty"`
+	Digest string   `yaml:"digest,omitempty"`
+	Tag    string   `yaml:"tag,omitempty"`
+	Header []string `yaml:"header,omitempty"`
+}
+
+// batchTask is one file to download in batch, its result is filled after running.
+type batchTask struct {
+	url     string
+	cfg     *config.DfgetConfig
+	cost    time.Duration
+	skipped bool
+	err     error
+}
+
+// parseInputFile loads the entries of the manifest, a file with .yaml or .yml extension is a list of BatchEntry,
+// otherwise every line is "url [output] [digest=xxx] [tag=xxx] [header=key:value]...",
+// blank lines and lines started with '#' are ignored.
+func parseInputFile(name string) ([]*BatchEntry, error) {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []*BatchEntry
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".yaml", ".yml":
+		if err := yaml.Unmarshal(data, &entries); err != nil {
+			return nil, errors.Wrapf(err, "unmarshal input file %s", name)
+		}
+	default:
+		scanner := bufio.NewScanner(strings.NewReader(string(data)))
+		for line := 1; scanner.Scan(); line++ {
+			text := strings.TrimSpace(scanner.Text())
+			if text == "" || strings.HasPrefix(text, "#") {
+				continue
+			}
+
+			entry, err := parseBatchLine(text)
+			if err != nil {
+				return nil, errors.Wrapf(err, "input file %s line %d", name, line)
+			}
+			entries = append(entries, entry)
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	for i, entry := range entries {
+		if entry == nil || entry.URL == "" {
+			return nil, errors.Errorf("input file %s entry %d: url is empty", name, i)
+		}
+	}
+	return entries, nil
+}
+
+func parseBatchLine(text string) (*BatchEntry, error) {
+	fields := strings.Fields(text)
+	entry := &BatchEntry{URL: fields[0]}
+	for _, field := range fields[1:] {
+		kv := strings.SplitN(field, "=", 2)
+		if len(kv) != 2 {
+			if entry.Output != "" {
+				return nil, errors.Errorf("duplicated output %s", field)
+			}
+			entry.Output = field
+			continue
+		}
+
+		switch kv[0] {
+		case "output":
+			entry.Output = kv[1]
+		case "digest":
+			entry.Digest = kv[1]
+		case "tag":
+			entry.Tag = kv[1]
+		case "header":
+			entry.Header = append(entry.Header, kv[1])
+		default:
+			return nil, errors.Errorf("unknown field %s", kv[0])
+		}
+	}
+	return entry, nil
+}
+
+// newBatchConfig reuses the dfget config for the entry, relative output is based on the output directory.
+func newBatchConfig(cfg *config.DfgetConfig, entry *BatchEntry) *config.DfgetConfig {
+	c := *cfg
+	c.InputFile, c.URL, c.ShowProgress = "", entry.URL, false
+	c.Header = append(append([]string{}, cfg.Header...), entry.Header...)
+
+	c.Output = entry.Output
+	if c.Output == "" {
+		c.Output = path.Base(strings.TrimRight(entry.URL, "/"))
+	}
+	if !filepath.IsAbs(c.Output) {
+		c.Output = filepath.Join(cfg.Output, c.Output)
+	}
+
+	if entry.Digest != "" {
+		c.Digest, c.Tag = entry.Digest, ""
+	} else if entry.Tag != "" {
+		c.Tag = entry.Tag
+	}
+	return &c
+}
+
+// batchDownload downloads all entries in the input file concurrently through the same daemon client,
+// it returns error when any entry fails.
+func batchDownload(ctx context.Context, client daemonclient.DaemonClient, cfg *config.DfgetConfig) error {
+	entries, err := parseInputFile(cfg.InputFile)
+	if err != nil {
+		return err
+	}
+
+	tasks := make([]*batchTask, 0, len(entries))
+	for _, entry := range entries {
+		tasks = append(tasks, &batchTask{url: entry.URL, cfg: newBatchConfig(cfg, entry)})
+	}
+	return runBatch(ctx, client, cfg, tasks)
+}
+
+// runBatch downloads the tasks with a worker pool of cfg.Parallelism and prints a summary,
+// a failed task does not stop the others.
+func runBatch(ctx context.Context, client daemonclient.DaemonClient, cfg *config.DfgetConfig, tasks []*batchTask) error {
+	var (
+		sema = semaphore.NewWeighted(int64(cfg.Parallelism))
+		wg   sync.WaitGroup
+		pb   *progressbar.ProgressBar
+	)
+
+	if cfg.ShowProgress {
+		pb = newProgressBar(int64(len(tasks)), progressbar.OptionShowBytes(false),
+			progressbar.OptionSetDescription(fmt.Sprintf("[cyan]Downloading %d files...[reset]", len(tasks))))
+	}
+
+	for _, task := range tasks {
+		if err := sema.Acquire(ctx, 1); err != nil {
+			task.err = err
+			continue
+		}
+
+		wg.Add(1)
+		go func(task *batchTask) {
+			defer func() {
+				if pb != nil {
+					_ = pb.Add(1)
+				}
+				sema.Release(1)
+				wg.Done()
+			}()
+
+			start := time.Now()
+			task.skipped, task.err = runBatchTask(ctx, client, task.cfg)
+			task.cost = time.Now().Sub(start)
+		}(task)
+	}
+	wg.Wait()
+
+	if pb != nil {
+		pb.Describe("Downloaded")
+		_ = pb.Close()
+	}
+
+	var (
+		failed int
+		out    = MessageWriter(cfg)
+	)
+	fmt.Fprintf(out, "\ndownload summary:\n")
+	for _, task := range tasks {
+		switch {
+		case task.err != nil:
+			failed++
+			logger.With("url", task.url).Errorf("batch download error: %v", task.err)
+			fmt.Fprintf(out, "[FAIL] %s: %v\n", task.url, task.err)
+		case task.skipped:
+			fmt.Fprintf(out, "[SKIP] %s is up to date\n", task.url)
+		default:
+			fmt.Fprintf(out, "[OK]   %s cost: %d ms\n", task.url, task.cost.Milliseconds())
+		}
+	}
+
+	if failed > 0 {
+		return &batchError{failed: failed, total: len(tasks)}
+	}
+	return nil
+}
+
+// runBatchTask downloads one task, it is skipped in sync mode when the local file is up to date.
+func runBatchTask(ctx context.Context, client daemonclient.DaemonClient, cfg *config.DfgetConfig) (bool, error) {
+	if err := cfg.Validate(); err != nil {
+		return false, err
+	}
+
+	if !cfg.RecursiveSync {
+		return false, download(ctx, client, cfg, logger.With("url", cfg.URL))
+	}
+
+	upToDate, lastModified := checkUpToDate(ctx, cfg)
+	if upToDate {
+		return true, nil
+	}
+
+	if err := download(ctx, client, cfg, logger.With("url", cfg.URL)); err != nil {
+		return false, err
+	}
+
+	// keep the modification time of source, it is compared in the next sync
+	if lastModified > 0 {
+		mtime := time.Unix(0, lastModified*int64(time.Millisecond))
+		if err := os.Chtimes(cfg.Output, time.Now(), mtime); err != nil {
+			logger.With("url", cfg.URL).Warnf("change modification time of %s error: %v", cfg.Output, err)
+		}
+	}
+	return false, nil
+}
+
+// checkUpToDate checks whether the local file matches the source, by digest when it is given,
+// otherwise by content length and last modified time. It also returns the last modified time of source.
+func checkUpToDate(ctx context.Context, cfg *config.DfgetConfig) (bool, int64) {
+	wLog := logger.With("url", cfg.URL)
+	info, statErr := os.Stat(cfg.Output)
+	exist := statErr == nil && info.Mode().IsRegular()
+
+	if parsedHash := digestutils.Parse(cfg.Digest); exist && len(parsedHash) == 2 {
+		realHash := digestutils.HashFile(cfg.Output, digestutils.Algorithms[parsedHash[0]])
+		if realHash != "" && realHash == parsedHash[1] {
+			return true, -1
+		}
+	}
+
+	request, err := source.NewRequestWithContext(ctx, cfg.URL, parseHeader(cfg.Header))
+	if err != nil {
+		return false, -1
+	}
+
+	lastModified, err := source.GetLastModified(request)
+	if err != nil {
+		wLog.Warnf("get last modified error: %v", err)
+		lastModified = -1
+	}
+
+	// without last modified time of source, the same length is not enough
+	if !exist || cfg.Digest != "" || lastModified <= 0 {
+		return false, lastModified
+	}
+
+	contentLength, err := source.GetContentLength(request)
+	if err != nil {
+		wLog.Warnf("get content length error: %v", err)
+		return false, lastModified
+	}
+
+	if contentLength != info.Size() {
+		return false, lastModified
+	}
+	return info.ModTime().UnixNano()/int64(time.Millisecond) >= lastModified, lastModified
+}
+
+// deleteExtraneous removes the local files under dir which are not in expected,
+// only the files which could be produced by the listing are removed, others are kept as is.
+func deleteExtraneous(dir string, expected map[string]struct{}, listed func(name string) bool, out io.Writer) error {
+	return filepath.Walk(dir, func(name string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		if _, ok := expected[name]; ok {
+			return nil
+		}
+		if !listed(name) {
+			logger.Debugf("keep %s which is not listed from source", name)
+			return nil
+		}
+
+		if err := os.Remove(name); err != nil {
+			return err
+		}
+		logger.Infof("delete %s which is not in source", name)
+		fmt.Fprintf(out, "[DELETE] %s\n", name)
+		return nil
+	})
+}
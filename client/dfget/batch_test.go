@@ -0,0 +1,280 @@
+/*
+ *     Copyright 2020 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dfget
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"d7y.io/dragonfly/v2/client/config"
+	"d7y.io/dragonfly/v2/pkg/source"
+	sourcemock "d7y.io/dragonfly/v2/pkg/source/mock"
+)
+
+func Test_parseInputFile(t *testing.T) {
+	dir := t.TempDir()
+
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		expect  []*BatchEntry
+		wantErr bool
+	}{
+		{
+			name: "lines",
+			file: "manifest",
+			content: `# comment
+http://a.b.c/x
+
+http://a.b.c/y y.bin digest=sha256:abc header=X-A:1 header=X-B:2
+http://a.b.c/z tag=t output=sub/z
+`,
+			expect: []*BatchEntry{
+				{URL: "http://a.b.c/x"},
+				{URL: "http://a.b.c/y", Output: "y.bin", Digest: "sha256:abc", Header: []string{"X-A:1", "X-B:2"}},
+				{URL: "http://a.b.c/z", Output: "sub/z", Tag: "t"},
+			},
+		},
+		{
+			name: "yaml",
+			file: "manifest.yaml",
+			content: `- url: http://a.b.c/x
+- url: http://a.b.c/y
+  output: y.bin
+  header:
+  - "X-A: 1"
+`,
+			expect: []*BatchEntry{
+				{URL: "http://a.b.c/x"},
+				{URL: "http://a.b.c/y", Output: "y.bin", Header: []string{"X-A: 1"}},
+			},
+		},
+		{
+			name:    "unknown field",
+			file:    "unknown",
+			content: "http://a.b.c/x foo=bar\n",
+			wantErr: true,
+		},
+		{
+			name:    "empty url",
+			file:    "empty.yml",
+			content: "- output: x\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name := filepath.Join(dir, tt.file)
+			require.Nil(t, os.WriteFile(name, []byte(tt.content), 0644))
+
+			entries, err := parseInputFile(name)
+			if tt.wantErr {
+				assert.NotNil(t, err)
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, tt.expect, entries)
+		})
+	}
+}
+
+func Test_newBatchConfig(t *testing.T) {
+	cfg := &config.DfgetConfig{
+		InputFile: "manifest",
+		Output:    "/data",
+		Tag:       "global",
+		Header:    []string{"X-A: 1"},
+	}
+
+	c := newBatchConfig(cfg, &BatchEntry{URL: "http://a.b.c/x/", Header: []string{"X-B: 2"}})
+	assert.Equal(t, "", c.InputFile)
+	assert.Equal(t, "/data/x", c.Output)
+	assert.Equal(t, "global", c.Tag)
+	assert.Equal(t, []string{"X-A: 1", "X-B: 2"}, c.Header)
+	assert.Equal(t, []string{"X-A: 1"}, cfg.Header)
+
+	c = newBatchConfig(cfg, &BatchEntry{URL: "http://a.b.c/x", Output: "/tmp/y", Digest: "md5:abc"})
+	assert.Equal(t, "/tmp/y", c.Output)
+	assert.Equal(t, "md5:abc", c.Digest)
+	assert.Equal(t, "", c.Tag)
+}
+
+func Test_batchDownload(t *testing.T) {
+	dir := t.TempDir()
+
+	sourceClient := sourcemock.NewMockResourceClient(gomock.NewController(t))
+	require.Nil(t, source.Register("http", sourceClient, func(request *source.Request) *source.Request {
+		return request
+	}))
+	defer source.UnRegister("http")
+
+	sourceClient.EXPECT().Download(gomock.Any()).DoAndReturn(func(request *source.Request) (*source.Response, error) {
+		if strings.HasSuffix(request.URL.Path, "bad") {
+			return nil, errors.New("not found")
+		}
+		return source.NewResponse(io.NopCloser(strings.NewReader(request.URL.Path))), nil
+	}).Times(3)
+
+	manifest := filepath.Join(dir, "manifest")
+	require.Nil(t, os.WriteFile(manifest, []byte("http://a.b.c/x\nhttp://a.b.c/y sub/y\nhttp://a.b.c/bad\n"), 0644))
+
+	cfg := &config.DfgetConfig{
+		InputFile:   manifest,
+		Output:      dir,
+		Parallelism: 2,
+	}
+	err := batchDownload(context.Background(), nil, cfg)
+	assert.EqualError(t, err, "1 of 3 downloads failed")
+
+	data, err := os.ReadFile(filepath.Join(dir, "x"))
+	assert.Nil(t, err)
+	assert.Equal(t, "/x", string(data))
+
+	data, err = os.ReadFile(filepath.Join(dir, "sub", "y"))
+	assert.Nil(t, err)
+	assert.Equal(t, "/y", string(data))
+}
+
+type mockResourceLister struct {
+	*sourcemock.MockResourceClient
+	urls []*url.URL
+}
+
+func (m *mockResourceLister) List(request *source.Request) ([]*url.URL, error) {
+	return m.urls, nil
+}
+
+func Test_recursiveDownloadSync(t *testing.T) {
+	dir := t.TempDir()
+	lastModified := time.Now().Add(-time.Hour).Truncate(time.Second)
+
+	var urls []*url.URL
+	for _, name := range []string{"dir/fresh", "dir/stale", "dir/new"} {
+		u, err := url.Parse("http://a.b.c/" + name)
+		require.Nil(t, err)
+		urls = append(urls, u)
+	}
+	sourceClient := &mockResourceLister{
+		MockResourceClient: sourcemock.NewMockResourceClient(gomock.NewController(t)),
+		urls:               urls,
+	}
+	require.Nil(t, source.Register("http", sourceClient, func(request *source.Request) *source.Request {
+		return request
+	}))
+	defer source.UnRegister("http")
+
+	// content of every file is its url path
+	sourceClient.EXPECT().GetContentLength(gomock.Any()).DoAndReturn(func(request *source.Request) (int64, error) {
+		return int64(len(request.URL.Path)), nil
+	}).AnyTimes()
+	sourceClient.EXPECT().GetLastModified(gomock.Any()).Return(lastModified.UnixNano()/int64(time.Millisecond), nil).AnyTimes()
+	sourceClient.EXPECT().Download(gomock.Any()).DoAndReturn(func(request *source.Request) (*source.Response, error) {
+		if strings.HasSuffix(request.URL.Path, "fresh") {
+			t.Errorf("up to date file %s is downloaded", request.URL.Path)
+		}
+		return source.NewResponse(io.NopCloser(strings.NewReader(request.URL.Path))), nil
+	}).Times(2)
+
+	// fresh has the same length and modification time, stale has a different length
+	require.Nil(t, os.WriteFile(filepath.Join(dir, "fresh"), []byte("/dir/fresh"), 0644))
+	require.Nil(t, os.Chtimes(filepath.Join(dir, "fresh"), lastModified, lastModified))
+	require.Nil(t, os.WriteFile(filepath.Join(dir, "stale"), []byte("old"), 0644))
+	require.Nil(t, os.WriteFile(filepath.Join(dir, "extra"), []byte("extra"), 0644))
+
+	cfg := &config.DfgetConfig{
+		URL:             "http://a.b.c/dir",
+		Output:          dir,
+		Recursive:       true,
+		RecursiveSync:   true,
+		RecursiveDelete: true,
+		Parallelism:     2,
+	}
+	assert.Nil(t, recursiveDownload(context.Background(), nil, cfg))
+
+	for _, name := range []string{"fresh", "stale", "new"} {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		assert.Nil(t, err)
+		assert.Equal(t, "/dir/"+name, string(data))
+
+		info, err := os.Stat(filepath.Join(dir, name))
+		assert.Nil(t, err)
+		assert.True(t, info.ModTime().Equal(lastModified), name)
+	}
+
+	_, err := os.Stat(filepath.Join(dir, "extra"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func Test_recursiveDownloadDeleteFiltered(t *testing.T) {
+	dir := t.TempDir()
+
+	u, err := url.Parse("http://a.b.c/dir/keep.txt")
+	require.Nil(t, err)
+	sourceClient := &mockResourceLister{
+		MockResourceClient: sourcemock.NewMockResourceClient(gomock.NewController(t)),
+		urls:               []*url.URL{u},
+	}
+	require.Nil(t, source.Register("http", sourceClient, func(request *source.Request) *source.Request {
+		return request
+	}))
+	defer source.UnRegister("http")
+
+	sourceClient.EXPECT().GetContentLength(gomock.Any()).Return(int64(-1), nil).AnyTimes()
+	sourceClient.EXPECT().Download(gomock.Any()).DoAndReturn(func(request *source.Request) (*source.Response, error) {
+		return source.NewResponse(io.NopCloser(strings.NewReader(request.URL.Path))), nil
+	}).Times(1)
+
+	// extra.txt can be listed from source, the others are filtered out by level or regex
+	require.Nil(t, os.MkdirAll(filepath.Join(dir, "sub"), 0755))
+	for _, name := range []string{"extra.txt", "local.bin", "sub/deep.txt", "skip.txt"} {
+		require.Nil(t, os.WriteFile(filepath.Join(dir, name), []byte(name), 0644))
+	}
+
+	cfg := &config.DfgetConfig{
+		URL:                  "http://a.b.c/dir",
+		Output:               dir,
+		Recursive:            true,
+		RecursiveDelete:      true,
+		RecursiveLevel:       1,
+		RecursiveAcceptRegex: `\.txt$`,
+		RecursiveRejectRegex: `skip`,
+		Parallelism:          1,
+	}
+	assert.Nil(t, recursiveDownload(context.Background(), nil, cfg))
+
+	_, err = os.Stat(filepath.Join(dir, "keep.txt"))
+	assert.Nil(t, err)
+	_, err = os.Stat(filepath.Join(dir, "extra.txt"))
+	assert.True(t, os.IsNotExist(err))
+	for _, name := range []string{"local.bin", "sub/deep.txt", "skip.txt"} {
+		_, err = os.Stat(filepath.Join(dir, name))
+		assert.Nil(t, err, "filtered out file %s should survive", name)
+	}
+}
@@ -52,7 +52,7 @@ func Download(cfg *config.DfgetConfig, client daemonclient.DaemonClient) error {
 	)
 
 	wLog.Info("init success and start to download")
-	fmt.Println("init success and start to download")
+	fmt.Fprintln(MessageWriter(cfg), "init success and start to download")
 
 	if cfg.Timeout > 0 {
 		ctx, cancel = context.WithTimeout(ctx, cfg.Timeout)
@@ -68,23 +68,33 @@ func Download(cfg *config.DfgetConfig, client daemonclient.DaemonClient) error {
 	<-ctx.Done()
 
 	if ctx.Err() == context.DeadlineExceeded {
-		return errors.Errorf("download timeout(%s)", cfg.Timeout)
+		return errors.Wrapf(errDownloadTimeout, "timeout %s", cfg.Timeout)
 	}
 	return downError
 }
 
 func download(ctx context.Context, client daemonclient.DaemonClient, cfg *config.DfgetConfig, wLog *logger.SugaredLoggerOnWith) error {
+	if cfg.InputFile != "" {
+		return batchDownload(ctx, client, cfg)
+	}
 	if cfg.Recursive {
 		return recursiveDownload(ctx, client, cfg)
 	}
+	if cfg.Stdout {
+		return streamDownload(ctx, client, cfg, os.Stdout, wLog)
+	}
 	return singleDownload(ctx, client, cfg, wLog)
 }
 
-func singleDownload(ctx context.Context, client daemonclient.DaemonClient, cfg *config.DfgetConfig, wLog *logger.SugaredLoggerOnWith) error {
+func singleDownload(ctx context.Context, client daemonclient.DaemonClient, cfg *config.DfgetConfig, wLog *logger.SugaredLoggerOnWith) (err error) {
 	hdr := parseHeader(cfg.Header)
+	jr := newJSONReporter(cfg)
+	defer func() {
+		jr.result(err)
+	}()
 
 	if client == nil {
-		return downloadFromSource(ctx, cfg, hdr)
+		return downloadFromSource(ctx, cfg, hdr, jr)
 	}
 
 	var (
@@ -105,6 +115,7 @@ func singleDownload(ctx context.Context, client daemonclient.DaemonClient, cfg *
 			if result, downError = stream.Recv(); downError != nil {
 				break
 			}
+			jr.progress(result)
 
 			if result.CompletedLength > 0 && pb != nil {
 				_ = pb.Set64(int64(result.CompletedLength))
@@ -118,7 +129,7 @@ func singleDownload(ctx context.Context, client daemonclient.DaemonClient, cfg *
 				}
 
 				wLog.Infof("download from daemon success, length: %d bytes cost: %d ms", result.CompletedLength, time.Now().Sub(start).Milliseconds())
-				fmt.Printf("finish total length %d bytes\n", result.CompletedLength)
+				fmt.Fprintf(MessageWriter(cfg), "finish total length %d bytes\n", result.CompletedLength)
 
 				break
 			}
@@ -127,14 +138,17 @@ func singleDownload(ctx context.Context, client daemonclient.DaemonClient, cfg *
 
 	if downError != nil {
 		wLog.Warnf("daemon downloads file error: %v", downError)
-		fmt.Printf("daemon downloads file error: %v\n", downError)
-		downError = downloadFromSource(ctx, cfg, hdr)
+		fmt.Fprintf(MessageWriter(cfg), "daemon downloads file error: %v\n", downError)
+		if cfg.DisableBackSource {
+			return errors.Wrap(downError, "back source is disabled")
+		}
+		downError = downloadFromSource(ctx, cfg, hdr, jr)
 	}
 
 	return downError
 }
 
-func downloadFromSource(ctx context.Context, cfg *config.DfgetConfig, hdr map[string]string) error {
+func downloadFromSource(ctx context.Context, cfg *config.DfgetConfig, hdr map[string]string, jr *jsonReporter) error {
 	if cfg.DisableBackSource {
 		return errors.New("try to download from source but back source is disabled")
 	}
@@ -149,7 +163,7 @@ func downloadFromSource(ctx context.Context, cfg *config.DfgetConfig, hdr map[st
 	)
 
 	wLog.Info("try to download from source and ignore rate limit")
-	fmt.Println("try to download from source and ignore rate limit")
+	fmt.Fprintln(MessageWriter(cfg), "try to download from source and ignore rate limit")
 
 	if target, err = os.CreateTemp(filepath.Dir(cfg.Output), ".df_"); err != nil {
 		return err
@@ -189,7 +203,130 @@ func downloadFromSource(ctx context.Context, cfg *config.DfgetConfig, hdr map[st
 	}
 
 	wLog.Infof("download from source success, length: %d bytes cost: %d ms", written, time.Now().Sub(start).Milliseconds())
-	fmt.Printf("finish total length %d bytes\n", written)
+	fmt.Fprintf(MessageWriter(cfg), "finish total length %d bytes\n", written)
+	jr.backSource(written)
+
+	return nil
+}
+
+// MessageWriter returns the writer for messages, stdout is reserved for the content when writing to stdout
+// and for the events when the output format is json
+func MessageWriter(cfg *config.DfgetConfig) io.Writer {
+	if cfg.Stdout || cfg.OutputFormat == config.OutputFormatJSON {
+		return os.Stderr
+	}
+	return os.Stdout
+}
+
+// streamDownload writes the content to w through the stream peer task of daemon,
+// it falls back to source only when nothing has been written yet.
+func streamDownload(ctx context.Context, client daemonclient.DaemonClient, cfg *config.DfgetConfig, w io.Writer, wLog *logger.SugaredLoggerOnWith) error {
+	hdr := parseHeader(cfg.Header)
+
+	if client == nil {
+		return streamFromSource(ctx, cfg, hdr, w, wLog)
+	}
+
+	var (
+		start     = time.Now()
+		stream    dfdaemon.Daemon_DownloadStreamClient
+		result    *dfdaemon.StreamDownResult
+		pb        *progressbar.ProgressBar
+		written   int64
+		downError error
+		// content length is unknown until the first result is received
+		contentLength = int64(-1)
+	)
+
+	if stream, downError = client.DownloadStream(ctx, newStreamDownRequest(cfg, hdr)); downError == nil {
+		for {
+			if result, downError = stream.Recv(); downError != nil {
+				break
+			}
+
+			// the first result carries the task meta
+			if result.TaskId != "" {
+				wLog.Infof("stream task %s, peer %s, content length %d", result.TaskId, result.PeerId, result.ContentLength)
+				contentLength = result.ContentLength
+				if cfg.ShowProgress && pb == nil {
+					pb = newProgressBar(result.ContentLength, progressbar.OptionSetWriter(os.Stderr))
+				}
+			}
+
+			if len(result.Content) == 0 {
+				continue
+			}
+
+			n, err := w.Write(result.Content)
+			written += int64(n)
+			if err != nil {
+				return errors.Wrap(err, "write content")
+			}
+
+			if pb != nil {
+				_ = pb.Set64(written)
+			}
+		}
+	}
+
+	if downError == io.EOF && contentLength >= 0 && written != contentLength {
+		downError = errors.Errorf("stream ended with %d bytes, but content length is %d", written, contentLength)
+	}
+
+	if downError == io.EOF {
+		if pb != nil {
+			pb.Describe("Downloaded")
+			_ = pb.Close()
+		}
+
+		wLog.Infof("stream from daemon success, length: %d bytes cost: %d ms", written, time.Now().Sub(start).Milliseconds())
+		fmt.Fprintf(os.Stderr, "finish total length %d bytes\n", written)
+		return nil
+	}
+
+	wLog.Warnf("daemon streams file error: %v", downError)
+	fmt.Fprintf(os.Stderr, "daemon streams file error: %v\n", downError)
+
+	// part of the content has been written, it can not be recovered from source
+	if written > 0 {
+		return errors.Wrapf(downError, "stream interrupted after %d bytes", written)
+	}
+
+	return streamFromSource(ctx, cfg, hdr, w, wLog)
+}
+
+func streamFromSource(ctx context.Context, cfg *config.DfgetConfig, hdr map[string]string, w io.Writer, wLog *logger.SugaredLoggerOnWith) error {
+	if cfg.DisableBackSource {
+		return errors.New("try to download from source but back source is disabled")
+	}
+
+	var (
+		start    = time.Now()
+		response *source.Response
+		err      error
+		written  int64
+	)
+
+	wLog.Info("try to stream from source and ignore rate limit")
+	fmt.Fprintln(os.Stderr, "try to stream from source and ignore rate limit")
+
+	downloadRequest, err := source.NewRequestWithContext(ctx, cfg.URL, hdr)
+	if err != nil {
+		return err
+	}
+	if response, err = source.Download(downloadRequest); err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	// digest is checked after all the content is written, a mismatch only fails the command
+	reader := digestutils.NewDigestReader(wLog, response.Body, cfg.Digest)
+	if written, err = io.Copy(w, reader); err != nil {
+		return err
+	}
+
+	wLog.Infof("stream from source success, length: %d bytes cost: %d ms", written, time.Now().Sub(start).Milliseconds())
+	fmt.Fprintf(os.Stderr, "finish total length %d bytes\n", written)
 
 	return nil
 }
@@ -227,11 +364,25 @@ func newDownRequest(cfg *config.DfgetConfig, hdr map[string]string) *dfdaemon.Do
 		Callsystem: cfg.CallSystem,
 		Uid:        int64(basic.UserID),
 		Gid:        int64(basic.UserGroup),
+		Resume:     cfg.Continue,
+	}
+}
+
+func newStreamDownRequest(cfg *config.DfgetConfig, hdr map[string]string) *dfdaemon.StreamDownRequest {
+	return &dfdaemon.StreamDownRequest{
+		Url: cfg.URL,
+		UrlMeta: &base.UrlMeta{
+			Digest: cfg.Digest,
+			Tag:    cfg.Tag,
+			Range:  hdr[dfheaders.Range],
+			Filter: cfg.Filter,
+			Header: hdr,
+		},
 	}
 }
 
-func newProgressBar(max int64) *progressbar.ProgressBar {
-	return progressbar.NewOptions64(max,
+func newProgressBar(max int64, opts ...progressbar.Option) *progressbar.ProgressBar {
+	opts = append([]progressbar.Option{
 		progressbar.OptionShowBytes(true),
 		progressbar.OptionShowIts(),
 		progressbar.OptionSetPredictTime(true),
@@ -246,7 +397,9 @@ func newProgressBar(max int64) *progressbar.ProgressBar {
 			SaucerPadding: " ",
 			BarStart:      "[",
 			BarEnd:        "]",
-		}))
+		}),
+	}, opts...)
+	return progressbar.NewOptions64(max, opts...)
 }
 
 func accept(u string, parent, sub string, level uint, accept, reject string) bool {
@@ -302,11 +455,18 @@ func recursiveDownload(ctx context.Context, client daemonclient.DaemonClient, cf
 	if err != nil {
 		return err
 	}
+
+	var (
+		tasks    []*batchTask
+		expected = make(map[string]struct{}, len(urls))
+	)
 	for _, u := range urls {
 		// reuse dfget config
 		c := *cfg
 		// update some attributes
 		c.Recursive, c.URL, c.Output = false, u.String(), path.Join(cfg.Output, strings.TrimPrefix(u.Path, dirURL.Path))
+		// files in source are kept even if they are not accepted
+		expected[c.Output] = struct{}{}
 		if !accept(c.URL, dirURL.Path, u.Path, cfg.RecursiveLevel, cfg.RecursiveAcceptRegex, cfg.RecursiveRejectRegex) {
 			logger.Debugf("url %s is not accepted, skip", c.URL)
 			continue
@@ -315,17 +475,33 @@ func recursiveDownload(ctx context.Context, client daemonclient.DaemonClient, cf
 			fmt.Printf("%s\n", u.String())
 			continue
 		}
-		// validate new dfget config
-		if err = c.Validate(); err != nil {
-			logger.Errorf("validate failed: %s", err)
-			return err
-		}
 
 		logger.Debugf("download %s to %s", c.URL, c.Output)
-		err = download(ctx, client, &c, logger.With("url", c.URL))
-		if err != nil {
-			return err
+		tasks = append(tasks, &batchTask{url: c.URL, cfg: &c})
+	}
+
+	if cfg.RecursiveList {
+		return nil
+	}
+
+	err = runBatch(ctx, client, cfg, tasks)
+	if cfg.RecursiveDelete {
+		// a local file is listed when its url passes the same filters as the source files
+		listed := func(name string) bool {
+			rel, err := filepath.Rel(cfg.Output, name)
+			if err != nil {
+				return false
+			}
+			u := *dirURL
+			u.Path = path.Join(dirURL.Path, filepath.ToSlash(rel))
+			return accept(u.String(), dirURL.Path, u.Path, cfg.RecursiveLevel, cfg.RecursiveAcceptRegex, cfg.RecursiveRejectRegex)
+		}
+		if deleteErr := deleteExtraneous(cfg.Output, expected, listed, MessageWriter(cfg)); deleteErr != nil {
+			logger.Errorf("delete extraneous files error: %v", deleteErr)
+			if err == nil {
+				err = deleteErr
+			}
 		}
 	}
-	return nil
+	return err
 }
@@ -17,6 +17,7 @@
 package dfget
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"io"
@@ -28,9 +29,13 @@ import (
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
 
 	"d7y.io/dragonfly/v2/client/config"
+	logger "d7y.io/dragonfly/v2/internal/dflog"
 	"d7y.io/dragonfly/v2/pkg/idgen"
+	"d7y.io/dragonfly/v2/pkg/rpc/dfdaemon"
+	daemonclient "d7y.io/dragonfly/v2/pkg/rpc/dfdaemon/client"
 	"d7y.io/dragonfly/v2/pkg/source"
 	sourcemock "d7y.io/dragonfly/v2/pkg/source/mock"
 	"d7y.io/dragonfly/v2/pkg/util/digestutils"
@@ -59,10 +64,124 @@ func Test_downloadFromSource(t *testing.T) {
 	assert.Nil(t, err)
 	sourceClient.EXPECT().Download(request).Return(source.NewResponse(io.NopCloser(strings.NewReader(content))), nil)
 
-	err = downloadFromSource(context.Background(), cfg, nil)
+	err = downloadFromSource(context.Background(), cfg, nil, nil)
 	assert.Nil(t, err)
 }
 
+func Test_streamFromSource(t *testing.T) {
+	content := idgen.UUIDString()
+
+	sourceClient := sourcemock.NewMockResourceClient(gomock.NewController(t))
+	require.Nil(t, source.Register("http", sourceClient, func(request *source.Request) *source.Request {
+		return request
+	}))
+	defer source.UnRegister("http")
+
+	tests := []struct {
+		name   string
+		digest string
+		ok     bool
+	}{
+		{
+			name:   "digest matched",
+			digest: strings.Join([]string{digestutils.Sha256Hash.String(), digestutils.Sha256(content)}, ":"),
+			ok:     true,
+		},
+		{
+			name:   "digest not matched",
+			digest: strings.Join([]string{digestutils.Sha256Hash.String(), digestutils.Sha256("x")}, ":"),
+			ok:     false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.DfgetConfig{
+				URL:    "http://a.b.c/xx",
+				Stdout: true,
+				Digest: tt.digest,
+			}
+			request, err := source.NewRequest(cfg.URL)
+			assert.Nil(t, err)
+			sourceClient.EXPECT().Download(request).Return(source.NewResponse(io.NopCloser(strings.NewReader(content))), nil)
+
+			buf := &bytes.Buffer{}
+			err = streamDownload(context.Background(), nil, cfg, buf, logger.With("url", cfg.URL))
+			assert.Equal(t, tt.ok, err == nil)
+			assert.Equal(t, content, buf.String())
+		})
+	}
+}
+
+// testStreamClient streams the results to dfget
+type testStreamClient struct {
+	daemonclient.DaemonClient
+	grpc.ClientStream
+	results []*dfdaemon.StreamDownResult
+}
+
+func (c *testStreamClient) DownloadStream(ctx context.Context, req *dfdaemon.StreamDownRequest, opts ...grpc.CallOption) (dfdaemon.Daemon_DownloadStreamClient, error) {
+	return c, nil
+}
+
+func (c *testStreamClient) Recv() (*dfdaemon.StreamDownResult, error) {
+	if len(c.results) == 0 {
+		return nil, io.EOF
+	}
+	result := c.results[0]
+	c.results = c.results[1:]
+	return result, nil
+}
+
+func Test_streamDownload(t *testing.T) {
+	tests := []struct {
+		name          string
+		contentLength int64
+		content       string
+		ok            bool
+	}{
+		{
+			name:          "content length matched",
+			contentLength: 5,
+			content:       "hello",
+			ok:            true,
+		},
+		{
+			name:          "stream truncated",
+			contentLength: 10,
+			content:       "hello",
+			ok:            false,
+		},
+		{
+			name:          "unknown content length",
+			contentLength: -1,
+			content:       "hello",
+			ok:            true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.DfgetConfig{
+				URL:               "http://a.b.c/xx",
+				Stdout:            true,
+				DisableBackSource: true,
+			}
+			client := &testStreamClient{
+				results: []*dfdaemon.StreamDownResult{
+					{TaskId: "task", PeerId: "peer", ContentLength: tt.contentLength},
+					{Content: []byte(tt.content)},
+				},
+			}
+
+			buf := &bytes.Buffer{}
+			err := streamDownload(context.Background(), client, cfg, buf, logger.With("url", cfg.URL))
+			assert.Equal(t, tt.ok, err == nil)
+			assert.Equal(t, tt.content, buf.String())
+		})
+	}
+}
+
 func Test_checkDirectoryLevel(t *testing.T) {
 	testCases := []struct {
 		parent string
@@ -0,0 +1,117 @@
+/*
+ *     Copyright 2020 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dfget
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"d7y.io/dragonfly/v2/internal/dferrors"
+	"d7y.io/dragonfly/v2/pkg/rpc/base"
+)
+
+// exit codes of dfget, every failure class of base.Code has its own exit code
+const (
+	ExitCodeSuccess         = 0
+	ExitCodeUnknownError    = 1
+	ExitCodeInvalidArgument = 2
+	ExitCodeTimeout         = 3
+	// ExitCodeCommonError is for common response errors 1000-1999
+	ExitCodeCommonError = 10
+	// ExitCodeClientError is for client response errors 4000-4999
+	ExitCodeClientError = 14
+	// ExitCodeSchedulerError is for scheduler response errors 5000-5999
+	ExitCodeSchedulerError = 15
+	// ExitCodeCDNError is for cdn response errors 6000-6999
+	ExitCodeCDNError = 16
+	// ExitCodeManagerError is for manager response errors 7000-7999
+	ExitCodeManagerError = 17
+	// ExitCodePartialFailure is for batch downloads which some of the files failed
+	ExitCodePartialFailure = 20
+)
+
+// errDownloadTimeout is returned when the download exceeds the timeout of dfget
+var errDownloadTimeout = errors.New("download timeout")
+
+// batchError is returned when some of the batch downloads failed
+type batchError struct {
+	failed int
+	total  int
+}
+
+func (e *batchError) Error() string {
+	return fmt.Sprintf("%d of %d downloads failed", e.failed, e.total)
+}
+
+// ErrorCode returns the base.Code carried by err, base.Code_X_UNSPECIFIED when there is none.
+func ErrorCode(err error) base.Code {
+	var dfError *dferrors.DfError
+	if errors.As(err, &dfError) {
+		return dfError.Code
+	}
+
+	// status error from daemon without dferror details
+	for ; err != nil; err = errors.Unwrap(err) {
+		if s, ok := status.FromError(err); ok {
+			switch s.Code() {
+			case codes.DeadlineExceeded:
+				return base.Code_RequestTimeOut
+			case codes.Canceled:
+				return base.Code_ClientContextCanceled
+			}
+		}
+	}
+	return base.Code_X_UNSPECIFIED
+}
+
+// ExitCode returns the process exit code for the error of download.
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitCodeSuccess
+	}
+
+	var be *batchError
+	switch {
+	case errors.As(err, &be):
+		return ExitCodePartialFailure
+	case errors.Is(err, dferrors.ErrInvalidArgument):
+		return ExitCodeInvalidArgument
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(err, errDownloadTimeout):
+		return ExitCodeTimeout
+	}
+
+	code := ErrorCode(err)
+	switch {
+	case code == base.Code_RequestTimeOut || code == base.Code_ClientScheduleTimeout:
+		return ExitCodeTimeout
+	case code >= 1000 && code < 2000:
+		return ExitCodeCommonError
+	case code >= 4000 && code < 5000:
+		return ExitCodeClientError
+	case code >= 5000 && code < 6000:
+		return ExitCodeSchedulerError
+	case code >= 6000 && code < 7000:
+		return ExitCodeCDNError
+	case code >= 7000 && code < 8000:
+		return ExitCodeManagerError
+	}
+	return ExitCodeUnknownError
+}
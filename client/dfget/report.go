@@ -0,0 +1,181 @@
+/*
+ *     Copyright 2020 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dfget
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"d7y.io/dragonfly/v2/client/config"
+	logger "d7y.io/dragonfly/v2/internal/dflog"
+	"d7y.io/dragonfly/v2/pkg/rpc/dfdaemon"
+)
+
+// types of json events
+const (
+	EventTypeProgress = "progress"
+	EventTypeResult   = "result"
+)
+
+// sourceBackSource is the source of the content which is downloaded from source by dfget itself,
+// it is the same as the one reported by daemon.
+const sourceBackSource = "back_source"
+
+// ProgressEvent is the json event of download progress.
+type ProgressEvent struct {
+	Type            string   `json:"type"`
+	URL             string   `json:"url"`
+	TaskID          string   `json:"taskId,omitempty"`
+	PeerID          string   `json:"peerId,omitempty"`
+	ContentLength   int64    `json:"contentLength"`
+	CompletedLength uint64   `json:"completedLength"`
+	TotalPieces     int32    `json:"totalPieces"`
+	FinishedPieces  int32    `json:"finishedPieces"`
+	Source          string   `json:"source,omitempty"`
+	Parents         []string `json:"parents,omitempty"`
+}
+
+// Traffic is the traffic split by where the content comes from.
+type Traffic struct {
+	Peer   uint64 `json:"peer"`
+	CDN    uint64 `json:"cdn"`
+	Source uint64 `json:"source"`
+}
+
+// ResultEvent is the json event of download result, it is the last event of one download.
+type ResultEvent struct {
+	Type          string  `json:"type"`
+	URL           string  `json:"url"`
+	Output        string  `json:"output"`
+	Success       bool    `json:"success"`
+	TaskID        string  `json:"taskId,omitempty"`
+	PeerID        string  `json:"peerId,omitempty"`
+	Source        string  `json:"source,omitempty"`
+	Digest        string  `json:"digest,omitempty"`
+	PieceMd5Sign  string  `json:"pieceMd5Sign,omitempty"`
+	ContentLength int64   `json:"contentLength"`
+	CostMs        int64   `json:"costMs"`
+	Traffic       Traffic `json:"traffic"`
+	Code          int32   `json:"code,omitempty"`
+	ExitCode      int     `json:"exitCode"`
+	Error         string  `json:"error,omitempty"`
+}
+
+// reportLock serializes events of concurrent downloads, every event is one line.
+var reportLock sync.Mutex
+
+// jsonReporter writes newline-delimited json events of one download,
+// methods of nil reporter do nothing, so it is used without checking output format.
+type jsonReporter struct {
+	cfg   *config.DfgetConfig
+	w     io.Writer
+	start time.Time
+	last  *dfdaemon.DownResult
+}
+
+func newJSONReporter(cfg *config.DfgetConfig) *jsonReporter {
+	if cfg.OutputFormat != config.OutputFormatJSON {
+		return nil
+	}
+	return &jsonReporter{
+		cfg:   cfg,
+		w:     os.Stdout,
+		start: time.Now(),
+	}
+}
+
+// progress reports the download result from daemon.
+func (r *jsonReporter) progress(result *dfdaemon.DownResult) {
+	if r == nil {
+		return
+	}
+	r.last = result
+	r.write(&ProgressEvent{
+		Type:            EventTypeProgress,
+		URL:             r.cfg.URL,
+		TaskID:          result.TaskId,
+		PeerID:          result.PeerId,
+		ContentLength:   result.ContentLength,
+		CompletedLength: result.CompletedLength,
+		TotalPieces:     result.TotalPieces,
+		FinishedPieces:  result.FinishedPieces,
+		Source:          result.Source,
+		Parents:         result.Parents,
+	})
+}
+
+// backSource records the download from source by dfget itself.
+func (r *jsonReporter) backSource(written int64) {
+	if r == nil {
+		return
+	}
+	r.last = &dfdaemon.DownResult{
+		CompletedLength: uint64(written),
+		Done:            true,
+		ContentLength:   written,
+		Source:          sourceBackSource,
+		SourceTraffic:   uint64(written),
+	}
+}
+
+// result reports the final result of the download.
+func (r *jsonReporter) result(err error) {
+	if r == nil {
+		return
+	}
+	event := &ResultEvent{
+		Type:     EventTypeResult,
+		URL:      r.cfg.URL,
+		Output:   r.cfg.Output,
+		Success:  err == nil,
+		Digest:   r.cfg.Digest,
+		CostMs:   time.Now().Sub(r.start).Milliseconds(),
+		ExitCode: ExitCode(err),
+	}
+	if last := r.last; last != nil {
+		event.TaskID = last.TaskId
+		event.PeerID = last.PeerId
+		event.Source = last.Source
+		event.PieceMd5Sign = last.PieceMd5Sign
+		event.ContentLength = last.ContentLength
+		event.Traffic = Traffic{
+			Peer:   last.PeerTraffic,
+			CDN:    last.CdnTraffic,
+			Source: last.SourceTraffic,
+		}
+	}
+	if err != nil {
+		event.Code = int32(ErrorCode(err))
+		event.Error = err.Error()
+	}
+	r.write(event)
+}
+
+func (r *jsonReporter) write(event interface{}) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		logger.Errorf("marshal event error: %v", err)
+		return
+	}
+
+	reportLock.Lock()
+	defer reportLock.Unlock()
+	_, _ = r.w.Write(append(data, '\n'))
+}
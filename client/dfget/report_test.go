@@ -0,0 +1,153 @@
+/*
+ *     Copyright 2020 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dfget
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	pkgerrors "github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"d7y.io/dragonfly/v2/client/config"
+	"d7y.io/dragonfly/v2/internal/dferrors"
+	"d7y.io/dragonfly/v2/pkg/rpc/base"
+	"d7y.io/dragonfly/v2/pkg/rpc/dfdaemon"
+)
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		code int
+	}{
+		{name: "success", err: nil, code: ExitCodeSuccess},
+		{name: "unknown", err: fmt.Errorf("unknown"), code: ExitCodeUnknownError},
+		{name: "invalid argument", err: pkgerrors.Wrap(dferrors.ErrInvalidArgument, "url"), code: ExitCodeInvalidArgument},
+		{name: "timeout", err: pkgerrors.Wrapf(errDownloadTimeout, "timeout %s", "1s"), code: ExitCodeTimeout},
+		{name: "deadline exceeded", err: status.Error(codes.DeadlineExceeded, "deadline"), code: ExitCodeTimeout},
+		{name: "schedule timeout", err: dferrors.New(base.Code_ClientScheduleTimeout, "schedule"), code: ExitCodeTimeout},
+		{name: "common error", err: pkgerrors.Wrap(dferrors.New(base.Code_PeerTaskNotFound, "not found"), "download"), code: ExitCodeCommonError},
+		{name: "client error", err: dferrors.New(base.Code_ClientPieceDownloadFail, "piece"), code: ExitCodeClientError},
+		{name: "scheduler error", err: dferrors.New(base.Code_SchedTaskStatusError, "task"), code: ExitCodeSchedulerError},
+		{name: "cdn error", err: dferrors.New(base.Code_CDNTaskDownloadFail, "cdn"), code: ExitCodeCDNError},
+		{name: "manager error", err: dferrors.New(base.Code_InvalidResourceType, "manager"), code: ExitCodeManagerError},
+		{name: "partial failure", err: &batchError{failed: 1, total: 2}, code: ExitCodePartialFailure},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.code, ExitCode(tt.err))
+		})
+	}
+}
+
+func TestJSONReporter(t *testing.T) {
+	assert := assert.New(t)
+	cfg := &config.DfgetConfig{
+		URL:          "http://a.b.c/xx",
+		Output:       "/tmp/xx",
+		Digest:       "md5:abc",
+		OutputFormat: config.OutputFormatJSON,
+	}
+
+	// nil reporter for text format
+	assert.Nil(newJSONReporter(&config.DfgetConfig{OutputFormat: config.OutputFormatText}))
+
+	buf := &bytes.Buffer{}
+	jr := newJSONReporter(cfg)
+	jr.w = buf
+	jr.progress(&dfdaemon.DownResult{
+		TaskId:          "task",
+		PeerId:          "peer",
+		CompletedLength: 10,
+		ContentLength:   20,
+		TotalPieces:     2,
+		FinishedPieces:  1,
+		Source:          "p2p",
+		Parents:         []string{"parent"},
+	})
+	jr.progress(&dfdaemon.DownResult{
+		TaskId:          "task",
+		PeerId:          "peer",
+		CompletedLength: 20,
+		ContentLength:   20,
+		Done:            true,
+		Source:          "p2p",
+		PeerTraffic:     15,
+		CdnTraffic:      5,
+		PieceMd5Sign:    "sign",
+	})
+	jr.result(pkgerrors.Wrap(dferrors.New(base.Code_ClientError, "failed"), "download"))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 3)
+
+	var progress ProgressEvent
+	assert.Nil(json.Unmarshal([]byte(lines[0]), &progress))
+	assert.Equal(ProgressEvent{
+		Type:            EventTypeProgress,
+		URL:             cfg.URL,
+		TaskID:          "task",
+		PeerID:          "peer",
+		ContentLength:   20,
+		CompletedLength: 10,
+		TotalPieces:     2,
+		FinishedPieces:  1,
+		Source:          "p2p",
+		Parents:         []string{"parent"},
+	}, progress)
+
+	var result ResultEvent
+	assert.Nil(json.Unmarshal([]byte(lines[2]), &result))
+	assert.Equal(EventTypeResult, result.Type)
+	assert.False(result.Success)
+	assert.Equal("task", result.TaskID)
+	assert.Equal("md5:abc", result.Digest)
+	assert.Equal("sign", result.PieceMd5Sign)
+	assert.Equal(Traffic{Peer: 15, CDN: 5}, result.Traffic)
+	assert.Equal(int32(base.Code_ClientError), result.Code)
+	assert.Equal(ExitCodeClientError, result.ExitCode)
+}
+
+func TestJSONReporter_BackSource(t *testing.T) {
+	assert := assert.New(t)
+	cfg := &config.DfgetConfig{
+		URL:          "http://a.b.c/xx",
+		OutputFormat: config.OutputFormatJSON,
+	}
+
+	buf := &bytes.Buffer{}
+	jr := newJSONReporter(cfg)
+	jr.w = buf
+	jr.backSource(100)
+	jr.result(nil)
+
+	var result ResultEvent
+	assert.Nil(json.Unmarshal(buf.Bytes(), &result))
+	assert.True(result.Success)
+	assert.Equal(sourceBackSource, result.Source)
+	assert.Equal(int64(100), result.ContentLength)
+	assert.Equal(Traffic{Source: 100}, result.Traffic)
+	assert.Equal(ExitCodeSuccess, result.ExitCode)
+}
@@ -63,6 +63,9 @@ from remote source repeatedly.`,
 
 		// update plugin directory
 		source.UpdatePluginDir(d.PluginDir())
+		if cfg.Storage.Encryption.PluginDir == "" {
+			cfg.Storage.Encryption.PluginDir = d.PluginDir()
+		}
 
 		return runCdnSystem()
 	},
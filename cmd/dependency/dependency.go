@@ -162,6 +162,68 @@ func SetupQuitSignalHandler(handler func()) {
 	}()
 }
 
+// SetupReloadSignalHandler calls handler when receiving SIGHUP or the config file is changed,
+// the config file is checked every interval and handler is never called concurrently.
+func SetupReloadSignalHandler(interval time.Duration, handler func()) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGHUP)
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		stat := configFileStat()
+		for {
+			select {
+			case sig := <-signals:
+				logger.Infof("receive signal: %v, reload config", sig)
+				stat = configFileStat()
+			case <-ticker.C:
+				current := configFileStat()
+				if current == stat {
+					continue
+				}
+				logger.Infof("config file %s is changed, reload config", viper.ConfigFileUsed())
+				stat = current
+			}
+			handler()
+		}
+	}()
+}
+
+type fileStat struct {
+	modTime time.Time
+	size    int64
+}
+
+// configFileStat returns the stat of the config file used by viper, it is empty when there is no config file.
+func configFileStat() fileStat {
+	cfgFile := viper.ConfigFileUsed()
+	if cfgFile == "" {
+		return fileStat{}
+	}
+
+	info, err := os.Stat(cfgFile)
+	if err != nil {
+		return fileStat{}
+	}
+	return fileStat{modTime: info.ModTime(), size: info.Size()}
+}
+
+// ReloadConfig reads the config file used by viper again and unmarshals it with flags and ENV variables to config.
+// config is a pointer to configuration struct.
+func ReloadConfig(config interface{}) error {
+	if viper.ConfigFileUsed() != "" {
+		if err := viper.ReadInConfig(); err != nil {
+			return errors.Wrap(err, "viper read config")
+		}
+	}
+	if err := viper.Unmarshal(config, initDecoderConfig); err != nil {
+		return errors.Wrap(err, "unmarshal config to struct")
+	}
+	return nil
+}
+
 func GetConfigPath(name string) string {
 	cfgFile := viper.GetString("config")
 	if cfgFile != "" {
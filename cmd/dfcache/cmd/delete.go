@@ -0,0 +1,58 @@
+/*
+ *     Copyright 2020 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"d7y.io/dragonfly/v2/client/config"
+	"d7y.io/dragonfly/v2/internal/dfnet"
+	"d7y.io/dragonfly/v2/pkg/rpc/dfdaemon/client"
+)
+
+// deleteCmd represents the delete command
+var deleteCmd = &cobra.Command{
+	Use:   "delete <cid>|--url url",
+	Short: "delete a cache from local storage",
+	Long: `delete a cache from local storage of the daemon, then the daemon does not seed it anymore,
+the caches of other peers in the P2P network are not affected.`,
+	Args:              cobra.MaximumNArgs(1),
+	DisableAutoGenTag: true,
+	SilenceUsage:      true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runDfcache(config.CmdDelete, args, runDelete)
+	},
+}
+
+func init() {
+	// Add the command to parent
+	rootCmd.AddCommand(deleteCmd)
+}
+
+func runDelete(ctx context.Context, daemonClient client.DaemonClient, target dfnet.NetAddr) error {
+	req := newTaskRequest(dfcacheConfig)
+	if err := daemonClient.DeleteTask(ctx, target, req); err != nil {
+		return errors.Wrap(err, "delete cache")
+	}
+
+	fmt.Println("delete success")
+	return nil
+}
@@ -0,0 +1,76 @@
+/*
+ *     Copyright 2020 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"d7y.io/dragonfly/v2/client/config"
+	"d7y.io/dragonfly/v2/internal/dfnet"
+	"d7y.io/dragonfly/v2/pkg/basic"
+	"d7y.io/dragonfly/v2/pkg/rpc/dfdaemon"
+	"d7y.io/dragonfly/v2/pkg/rpc/dfdaemon/client"
+)
+
+// exportCmd represents the export command
+var exportCmd = &cobra.Command{
+	Use:   "export <cid>|--url url -O path",
+	Short: "export a cache from the P2P network to a local file",
+	Long: `export a cache from local storage of the daemon to a local file, when it is not found locally,
+the cache is downloaded from other peers without back source, unless --local is set.
+Only --url identifies a cache in the P2P network, cid is only used to export the local cache.`,
+	Args:              cobra.MaximumNArgs(1),
+	DisableAutoGenTag: true,
+	SilenceUsage:      true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runDfcache(config.CmdExport, args, runExport)
+	},
+}
+
+func init() {
+	// Add the command to parent
+	rootCmd.AddCommand(exportCmd)
+
+	flags := exportCmd.Flags()
+	flags.StringVarP(&dfcacheConfig.Output, "output", "O", dfcacheConfig.Output,
+		"Destination path which is used to store the exported file")
+	flags.BoolVarP(&dfcacheConfig.LocalOnly, "local", "l", dfcacheConfig.LocalOnly,
+		"Only export the cache in local storage, but not download it from the P2P network")
+}
+
+func runExport(ctx context.Context, daemonClient client.DaemonClient, target dfnet.NetAddr) error {
+	start := time.Now()
+	task, err := daemonClient.ExportTask(ctx, target, &dfdaemon.ExportTaskRequest{
+		Task:      newTaskRequest(dfcacheConfig),
+		Output:    dfcacheConfig.Output,
+		LocalOnly: dfcacheConfig.LocalOnly,
+		Uid:       int64(basic.UserID),
+		Gid:       int64(basic.UserGroup),
+	})
+	if err != nil {
+		return errors.Wrapf(err, "export to %s", dfcacheConfig.Output)
+	}
+
+	fmt.Printf("export success, cid: %s, output: %s, content length: %d, cost: %d ms\n",
+		task.TaskId, dfcacheConfig.Output, task.ContentLength, time.Now().Sub(start).Milliseconds())
+	return nil
+}
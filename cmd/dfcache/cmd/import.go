@@ -0,0 +1,67 @@
+/*
+ *     Copyright 2020 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"d7y.io/dragonfly/v2/client/config"
+	"d7y.io/dragonfly/v2/internal/dfnet"
+	"d7y.io/dragonfly/v2/pkg/rpc/dfdaemon"
+	"d7y.io/dragonfly/v2/pkg/rpc/dfdaemon/client"
+)
+
+// importCmd represents the import command
+var importCmd = &cobra.Command{
+	Use:   "import <path> --url url",
+	Short: "import a local file into the P2P network as a cache",
+	Long: `import a local file into local storage of the daemon as a completed cache of the url,
+the daemon seeds it to other peers, and they can export or download it with the same url,
+tag, digest and filter without back source.`,
+	Args:              cobra.ExactArgs(1),
+	DisableAutoGenTag: true,
+	SilenceUsage:      true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runDfcache(config.CmdImport, args, runImport)
+	},
+}
+
+func init() {
+	// Add the command to parent
+	rootCmd.AddCommand(importCmd)
+}
+
+func runImport(ctx context.Context, daemonClient client.DaemonClient, target dfnet.NetAddr) error {
+	start := time.Now()
+	task, err := daemonClient.ImportTask(ctx, target, &dfdaemon.ImportTaskRequest{
+		Url:     dfcacheConfig.URL,
+		UrlMeta: newURLMeta(dfcacheConfig),
+		Path:    dfcacheConfig.Path,
+	})
+	if err != nil {
+		return errors.Wrapf(err, "import %s", dfcacheConfig.Path)
+	}
+
+	fmt.Printf("import success, cid: %s, content length: %d, total pieces: %d, cost: %d ms\n",
+		task.TaskId, task.ContentLength, task.TotalPiece, time.Now().Sub(start).Milliseconds())
+	return nil
+}
@@ -0,0 +1,179 @@
+/*
+ *     Copyright 2020 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
+
+	"d7y.io/dragonfly/v2/client/config"
+	"d7y.io/dragonfly/v2/cmd/dependency"
+	logger "d7y.io/dragonfly/v2/internal/dflog"
+	"d7y.io/dragonfly/v2/internal/dflog/logcore"
+	"d7y.io/dragonfly/v2/internal/dfnet"
+	"d7y.io/dragonfly/v2/pkg/dfpath"
+	"d7y.io/dragonfly/v2/pkg/rpc/base"
+	"d7y.io/dragonfly/v2/pkg/rpc/dfdaemon"
+	"d7y.io/dragonfly/v2/pkg/rpc/dfdaemon/client"
+	"d7y.io/dragonfly/v2/version"
+)
+
+var (
+	// dfcacheConfig is initialized in declaration, sub commands bind their flags to it in init
+	dfcacheConfig = config.NewDfcacheConfig()
+)
+
+var dfcacheDescription = `dfcache is the cache client of dragonfly, it talks to the local dfdaemon
+to check, import, export and delete caches in the P2P network. A cache is identified by
+its cid, which is the task id, or by the url with tag, filter and digest like dfget.
+The dfdaemon must be running before using dfcache, it can be started by dfget daemon.`
+
+// rootCmd represents the base command when called without any subcommands
+var rootCmd = &cobra.Command{
+	Use:               "dfcache <command> [flags]",
+	Short:             "the P2P cache client of dragonfly",
+	Long:              dfcacheDescription,
+	Args:              cobra.NoArgs,
+	DisableAutoGenTag: true,
+	SilenceUsage:      true,
+}
+
+// Execute adds all child commands to the root command and sets flags appropriately.
+// This is called by main.main(). It only needs to happen once to the rootCmd.
+func Execute() {
+	if err := rootCmd.Execute(); err != nil {
+		logger.Error(err)
+		os.Exit(1)
+	}
+}
+
+func init() {
+	// Initialize cobra
+	dependency.InitCobra(rootCmd, false, dfcacheConfig)
+
+	// Add flags shared by all sub commands
+	flagSet := rootCmd.PersistentFlags()
+
+	flagSet.StringP("url", "u", dfcacheConfig.URL,
+		"The url of the cache, it identifies the cache with tag, filter and digest when cid is not given")
+
+	flagSet.String("tag", dfcacheConfig.Tag,
+		"Different tags for the same url will be divided into different P2P overlay, it conflicts with --digest")
+
+	flagSet.String("filter", dfcacheConfig.Filter,
+		"Filter the query parameters of the url, P2P overlay is the same one if the filtered url is same, "+
+			"in format of key&sign, which will filter 'key' and 'sign' query parameters")
+
+	flagSet.String("digest", dfcacheConfig.Digest,
+		"The digest of the cache, in format of md5:xxx or sha256:yyy")
+
+	flagSet.Duration("timeout", dfcacheConfig.Timeout, "Timeout for the cache operation, 0 is infinite")
+
+	flagSet.String("workhome", dfcacheConfig.WorkHome, "Dfcache working directory, it must be the same one of dfdaemon")
+
+	flagSet.String("logdir", dfcacheConfig.LogDir, "Dfcache log directory")
+
+	// Bind cmd flags
+	if err := viper.BindPFlags(flagSet); err != nil {
+		panic(errors.Wrap(err, "bind dfcache flags to viper"))
+	}
+}
+
+func initDfcacheDfpath(cfg *config.CacheOption) (dfpath.Dfpath, error) {
+	options := []dfpath.Option{}
+	if cfg.WorkHome != "" {
+		options = append(options, dfpath.WithWorkHome(cfg.WorkHome))
+	}
+
+	if cfg.LogDir != "" {
+		options = append(options, dfpath.WithLogDir(cfg.LogDir))
+	}
+
+	return dfpath.New(options...)
+}
+
+// runDfcache checks the config, initializes logger and connects to the local daemon, then runs the sub command.
+func runDfcache(cmd string, args []string,
+	run func(ctx context.Context, daemonClient client.DaemonClient, target dfnet.NetAddr) error) error {
+	// Convert config
+	if err := dfcacheConfig.Convert(cmd, args); err != nil {
+		return err
+	}
+
+	// Validate config
+	if err := dfcacheConfig.Validate(cmd); err != nil {
+		return err
+	}
+
+	// Initialize daemon dfpath
+	d, err := initDfcacheDfpath(dfcacheConfig)
+	if err != nil {
+		return err
+	}
+
+	// Initialize logger
+	if err := logcore.InitDfcache(dfcacheConfig.Console, d.LogDir()); err != nil {
+		return errors.Wrap(err, "init client dfcache logger")
+	}
+	logger.Infof("Version:\n%s", version.Version())
+
+	// Dfcache config values
+	s, _ := yaml.Marshal(dfcacheConfig)
+	logger.Infof("client dfcache %s configuration:\n%s", cmd, string(s))
+
+	ff := dependency.InitMonitor(dfcacheConfig.Verbose, dfcacheConfig.PProfPort, dfcacheConfig.Telemetry)
+	defer ff()
+
+	target := dfnet.NetAddr{Type: dfnet.UNIX, Addr: d.DaemonSockPath()}
+	daemonClient, err := client.GetClientByAddr([]dfnet.NetAddr{target})
+	if err != nil {
+		return err
+	}
+	if err = daemonClient.CheckHealth(context.Background(), target); err != nil {
+		return errors.Wrap(err, "the daemon is unhealthy, start it by dfget daemon first")
+	}
+
+	ctx := context.Background()
+	if dfcacheConfig.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, dfcacheConfig.Timeout)
+		defer cancel()
+	}
+	return run(ctx, daemonClient, target)
+}
+
+// newTaskRequest returns the task request of the cache, cid takes precedence over url
+func newTaskRequest(cfg *config.CacheOption) *dfdaemon.TaskRequest {
+	return &dfdaemon.TaskRequest{
+		TaskId:  cfg.Cid,
+		Url:     cfg.URL,
+		UrlMeta: newURLMeta(cfg),
+	}
+}
+
+func newURLMeta(cfg *config.CacheOption) *base.UrlMeta {
+	return &base.UrlMeta{
+		Digest: cfg.Digest,
+		Tag:    cfg.Tag,
+		Filter: cfg.Filter,
+	}
+}
@@ -0,0 +1,79 @@
+/*
+ *     Copyright 2020 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"d7y.io/dragonfly/v2/client/config"
+	"d7y.io/dragonfly/v2/internal/dferrors"
+	"d7y.io/dragonfly/v2/internal/dfnet"
+	"d7y.io/dragonfly/v2/pkg/rpc/base"
+	"d7y.io/dragonfly/v2/pkg/rpc/dfdaemon/client"
+)
+
+// statCmd represents the stat command
+var statCmd = &cobra.Command{
+	Use:   "stat <cid>|--url url",
+	Short: "stat checks if a cache exists in the P2P network",
+	Long: `stat checks if a cache exists in local storage of the daemon, when it is not found locally,
+the scheduler is asked whether any peer in the cluster has the cache, unless --local is set.
+It exits with non-zero code when the cache is not found.`,
+	Args:              cobra.MaximumNArgs(1),
+	DisableAutoGenTag: true,
+	SilenceUsage:      true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runDfcache(config.CmdStat, args, runStat)
+	},
+}
+
+func init() {
+	// Add the command to parent
+	rootCmd.AddCommand(statCmd)
+
+	flags := statCmd.Flags()
+	flags.BoolVarP(&dfcacheConfig.LocalOnly, "local", "l", dfcacheConfig.LocalOnly,
+		"Only check the cache in local storage, but not in the P2P network")
+}
+
+func runStat(ctx context.Context, daemonClient client.DaemonClient, target dfnet.NetAddr) error {
+	req := newTaskRequest(dfcacheConfig)
+	req.Cluster = !dfcacheConfig.LocalOnly
+	tasks, err := daemonClient.StatTask(ctx, target, req)
+	if err != nil {
+		if dferrors.CheckError(err, base.Code_PeerTaskNotFound) {
+			return errors.Errorf("cache not found: %s", err)
+		}
+		return errors.Wrap(err, "stat cache")
+	}
+
+	for _, t := range tasks.Tasks {
+		fmt.Printf("cache found in local storage, task id: %s, peer id: %s, content length: %d, completed length: %d, "+
+			"done: %t, pinned: %t, last access: %s\n", t.TaskId, t.PeerId, t.ContentLength, t.CompletedLength,
+			t.Done, t.Pinned, time.Unix(0, t.LastAccessTime).Format(config.DefaultTimestampFormat))
+	}
+	if c := tasks.Cluster; c != nil {
+		fmt.Printf("cache found in cluster, task id: %s, content length: %d, total pieces: %d, state: %s, peers: %d\n",
+			c.Id, c.ContentLength, c.TotalPieceCount, c.State, c.PeerCount)
+	}
+	return nil
+}
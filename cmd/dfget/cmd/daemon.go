@@ -38,6 +38,9 @@ import (
 	"d7y.io/dragonfly/v2/version"
 )
 
+// reloadCheckInterval is the interval of checking whether the config file is changed
+const reloadCheckInterval = 10 * time.Second
+
 var (
 	cfg *config.DaemonConfig
 )
@@ -179,5 +182,31 @@ func runDaemon(d dfpath.Dfpath) error {
 		return err
 	}
 	dependency.SetupQuitSignalHandler(func() { svr.Stop() })
+	dependency.SetupReloadSignalHandler(reloadCheckInterval, func() {
+		if err := reloadDaemon(svr); err != nil {
+			logger.Errorf("reload client daemon configuration failed: %v", err)
+			return
+		}
+		logger.Info("client daemon configuration is reloaded")
+	})
 	return svr.Serve()
 }
+
+// reloadDaemon loads the daemon config again and applies the reloadable part,
+// such as proxy rules, white list, hijack hosts, registry mirrors and rate limits.
+func reloadDaemon(svr server.Daemon) error {
+	newCfg := config.NewDaemonConfig()
+	if err := dependency.ReloadConfig(newCfg); err != nil {
+		return err
+	}
+
+	if err := newCfg.Convert(); err != nil {
+		return err
+	}
+
+	if err := newCfg.Validate(); err != nil {
+		return err
+	}
+
+	return svr.Reload(newCfg)
+}
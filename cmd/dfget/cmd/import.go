@@ -0,0 +1,125 @@
+/*
+ *     Copyright 2020 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"d7y.io/dragonfly/v2/internal/dflog/logcore"
+	"d7y.io/dragonfly/v2/internal/dfnet"
+	"d7y.io/dragonfly/v2/pkg/rpc/base"
+	"d7y.io/dragonfly/v2/pkg/rpc/dfdaemon"
+)
+
+// importOption holds the flags of import command
+var importOption struct {
+	url     string
+	tag     string
+	digest  string
+	filter  string
+	timeout time.Duration
+}
+
+// importCmd represents the import command
+var importCmd = &cobra.Command{
+	Use:   "import path --url url",
+	Short: "import a local file into the P2P network",
+	Long: `import a local file into the local daemon as a completed task of the url,
+the daemon seeds it to other peers, and they can download it with the same url, tag,
+digest and filter without back source.`,
+	Args:              cobra.ExactArgs(1),
+	DisableAutoGenTag: true,
+	SilenceUsage:      true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		start := time.Now()
+
+		// Initialize daemon dfpath
+		d, err := initDfgetDfpath(dfgetConfig)
+		if err != nil {
+			return err
+		}
+
+		// Initialize logger
+		if err := logcore.InitDfget(dfgetConfig.Console, d.LogDir()); err != nil {
+			return errors.Wrap(err, "init client dfget logger")
+		}
+
+		if importOption.url == "" {
+			return errors.New("url is required")
+		}
+		path, err := filepath.Abs(args[0])
+		if err != nil {
+			return err
+		}
+
+		daemonClient, err := checkAndSpawnDaemon(d.DfgetLockPath(), d.DaemonSockPath())
+		if err != nil {
+			return errors.Wrap(err, "check and spawn daemon")
+		}
+
+		ctx := context.Background()
+		if importOption.timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, importOption.timeout)
+			defer cancel()
+		}
+
+		req := &dfdaemon.ImportTaskRequest{
+			Url: importOption.url,
+			UrlMeta: &base.UrlMeta{
+				Digest: importOption.digest,
+				Filter: importOption.filter,
+			},
+			Path: path,
+		}
+		// tag conflicts with digest
+		if importOption.digest == "" {
+			req.UrlMeta.Tag = importOption.tag
+		}
+		task, err := daemonClient.ImportTask(ctx, dfnet.NetAddr{Type: dfnet.UNIX, Addr: d.DaemonSockPath()}, req)
+		if err != nil {
+			return errors.Wrapf(err, "import %s", path)
+		}
+
+		fmt.Printf("import success, task id: %s, peer id: %s, content length: %d, total pieces: %d, cost: %d ms\n",
+			task.TaskId, task.PeerId, task.ContentLength, task.TotalPiece, time.Now().Sub(start).Milliseconds())
+		return nil
+	},
+}
+
+func init() {
+	// Add the command to parent
+	rootCmd.AddCommand(importCmd)
+
+	flags := importCmd.Flags()
+	flags.StringVarP(&importOption.url, "url", "u", "",
+		"The url of the imported task, other peers download the file with the url")
+	flags.StringVar(&importOption.tag, "tag", "",
+		"Different tags for the same url will be divided into different P2P overlay, it conflicts with --digest")
+	flags.StringVar(&importOption.digest, "digest", "",
+		"Check the integrity of the imported file with digest, in format of md5:xxx or sha256:yyy")
+	flags.StringVar(&importOption.filter, "filter", "",
+		"Filter the query parameters of the url, P2P overlay is the same one if the filtered url is same, "+
+			"in format of key&sign, which will filter 'key' and 'sign' query parameters")
+	flags.DurationVar(&importOption.timeout, "timeout", 0, "Timeout for the importing task, 0 is infinite")
+}
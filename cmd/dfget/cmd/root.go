@@ -94,10 +94,18 @@ var rootCmd = &cobra.Command{
 			return err
 		}
 
-		fmt.Printf("--%s--  %s\n", start.Format("2006-01-02 15:04:05"), dfgetConfig.URL)
-		fmt.Printf("dfget version: %s\n", version.GitVersion)
-		fmt.Printf("current user: %s, default peer ip: %s\n", basic.Username, iputils.IPv4)
-		fmt.Printf("output path: %s\n", dfgetConfig.Output)
+		// stdout is reserved for the content when writing to stdout
+		out := dfget.MessageWriter(dfgetConfig)
+		target := dfgetConfig.URL
+		if dfgetConfig.InputFile != "" {
+			target = dfgetConfig.InputFile
+		}
+		fmt.Fprintf(out, "--%s--  %s\n", start.Format("2006-01-02 15:04:05"), target)
+		fmt.Fprintf(out, "dfget version: %s\n", version.GitVersion)
+		fmt.Fprintf(out, "current user: %s, default peer ip: %s\n", basic.Username, iputils.IPv4)
+		if !dfgetConfig.Stdout {
+			fmt.Fprintf(out, "output path: %s\n", dfgetConfig.Output)
+		}
 
 		//  do get file
 		var errInfo string
@@ -107,10 +115,10 @@ var rootCmd = &cobra.Command{
 		}
 
 		msg := fmt.Sprintf("download success: %t cost: %d ms %s", err == nil, time.Now().Sub(start).Milliseconds(), errInfo)
-		logger.With("url", dfgetConfig.URL).Info(msg)
-		fmt.Println(msg)
+		logger.With("url", target).Info(msg)
+		fmt.Fprintln(out, msg)
 
-		return errors.Wrapf(err, "download url: %s", dfgetConfig.URL)
+		return errors.Wrapf(err, "download url: %s", target)
 	},
 }
 
@@ -119,7 +127,7 @@ var rootCmd = &cobra.Command{
 func Execute() {
 	if err := rootCmd.Execute(); err != nil {
 		logger.Error(err)
-		os.Exit(1)
+		os.Exit(dfget.ExitCode(err))
 	}
 }
 
@@ -136,7 +144,7 @@ func init() {
 		"Download one file from the url, equivalent to the command's first position argument")
 
 	flagSet.StringP("output", "O", dfgetConfig.Output,
-		"Destination path which is used to store the downloaded file, it must be a full path")
+		"Destination path which is used to store the downloaded file, it must be a full path, '-' writes to stdout")
 
 	flagSet.Duration("timeout", dfgetConfig.Timeout, "Timeout for the downloading task, 0 is infinite")
 
@@ -158,10 +166,19 @@ func init() {
 	flagSet.Bool("disable-back-source", dfgetConfig.DisableBackSource,
 		"Disable downloading directly from source when the daemon fails to download file")
 
+	flagSet.Bool("stdout", dfgetConfig.Stdout,
+		"Write the downloaded content to stdout instead of a file, messages are written to stderr")
+
+	flagSet.BoolP("continue", "c", dfgetConfig.Continue,
+		"Resume the interrupted download of the same url, the downloaded pieces in daemon are reused")
+
 	flagSet.StringP("pattern", "p", dfgetConfig.Pattern, "The downloading pattern: p2p/cdn/source")
 
 	flagSet.BoolP("show-progress", "b", dfgetConfig.ShowProgress, "Show progress bar, it conflicts with --console")
 
+	flagSet.String("output-format", dfgetConfig.OutputFormat,
+		"The format of progress and result: text/json, json writes newline-delimited events to stdout and other messages to stderr")
+
 	flagSet.String("callsystem", dfgetConfig.CallSystem, "The caller name which is mainly used for statistics and access control")
 
 	flagSet.String("workhome", dfgetConfig.WorkHome, "Dfget working directory")
@@ -183,6 +200,18 @@ func init() {
 	flagSet.String("reject-regex", dfgetConfig.RecursiveRejectRegex,
 		`Recursively download only. Specify a regular expression to reject the complete URL. In this case, you have to enclose the pattern into quotes to prevent your shell from expanding it`)
 
+	flagSet.Bool("sync", dfgetConfig.RecursiveSync,
+		"Skip the files whose local copy is up to date with the source, which is checked by digest, or by content length and last modified time")
+
+	flagSet.Bool("delete", dfgetConfig.RecursiveDelete,
+		"Recursively download only. Delete the local files which pass --level, --accept-regex and --reject-regex but are not in the source, the output can not be the filesystem root or home directory")
+
+	flagSet.StringP("input-file", "i", dfgetConfig.InputFile,
+		"Download all files listed in the manifest file, every line is 'url [output] [digest=xxx] [tag=xxx] [header=key:value]' "+
+			"or a yaml list with .yaml extension, relative outputs are based on the --output directory")
+
+	flagSet.Int("parallelism", dfgetConfig.Parallelism, "The maximum number of files downloaded concurrently with --input-file or --recursive")
+
 	// Bind cmd flags
 	if err := viper.BindPFlags(flagSet); err != nil {
 		panic(errors.Wrap(err, "bind dfget flags to viper"))
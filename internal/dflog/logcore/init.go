@@ -197,3 +197,25 @@ func InitDfget(console bool, dir string) error {
 
 	return nil
 }
+
+func InitDfcache(console bool, dir string) error {
+	if console {
+		return nil
+	}
+
+	logDir := filepath.Join(dir, "dfcache")
+
+	coreLogger, err := CreateLogger(path.Join(logDir, CoreLogFileName), false, false)
+	if err != nil {
+		return err
+	}
+	logger.SetCoreLogger(coreLogger.Sugar())
+
+	grpcLogger, err := CreateLogger(path.Join(logDir, GrpcLogFileName), false, false)
+	if err != nil {
+		return err
+	}
+	logger.SetGrpcLogger(grpcLogger.Sugar())
+
+	return nil
+}
@@ -31,14 +31,14 @@ const (
 	// PluginInitFuncName indicates the function `DragonflyPluginInit` must be implemented in plugin
 	PluginInitFuncName = "DragonflyPluginInit"
 
-	// PluginMetaKeyType indicates the type of plugin, currently support: resource
+	// PluginMetaKeyType indicates the type of plugin, currently support: resource, manager, scheduler and kms
 	PluginMetaKeyType = "type"
 
 	// PluginMetaKeyName indicates the name of a plugin
 	PluginMetaKeyName = "name"
 )
 
-var PluginFormatExpr = regexp.MustCompile("d7y-(resource|manager|scheduler)-plugin-([a-z0-9]+).so")
+var PluginFormatExpr = regexp.MustCompile("d7y-(resource|manager|scheduler|kms)-plugin-([a-z0-9]+).so")
 
 type PluginType string
 
@@ -46,6 +46,7 @@ const (
 	PluginTypeResource  = PluginType("resource")
 	PluginTypeManager   = PluginType("manager")
 	PluginTypeScheduler = PluginType("scheduler")
+	PluginTypeKMS       = PluginType("kms")
 )
 
 type PluginInitFunc func(option map[string]string) (plugin interface{}, meta map[string]string, err error)
@@ -29,6 +29,7 @@ type Config struct {
 	Database     *DatabaseConfig `yaml:"database" mapstructure:"database"`
 	Cache        *CacheConfig    `yaml:"cache" mapstructure:"cache"`
 	Metrics      *RestConfig     `yaml:"metrics" mapstructure:"metrics"`
+	Preheat      *PreheatConfig  `yaml:"preheat" mapstructure:"preheat"`
 }
 
 type ServerConfig struct {
@@ -80,6 +81,20 @@ type RestConfig struct {
 	Addr string `yaml:"addr" mapstructure:"addr"`
 }
 
+type PreheatConfig struct {
+	// Registries are the credentials to request the token of registries when resolving image manifests
+	Registries []*RegistryConfig `yaml:"registries" mapstructure:"registries"`
+}
+
+type RegistryConfig struct {
+	// Host is the registry host, like ghcr.io
+	Host     string `yaml:"host" mapstructure:"host"`
+	Username string `yaml:"username" mapstructure:"username"`
+	Password string `yaml:"password" mapstructure:"password"`
+	// Insecure skips the tls verification of the registry and its token server
+	Insecure bool `yaml:"insecure" mapstructure:"insecure"`
+}
+
 type TCPListenConfig struct {
 	// Listen stands listen interface, like: 0.0.0.0, 192.168.0.1
 	Listen string `mapstructure:"listen" yaml:"listen"`
@@ -184,5 +199,13 @@ func (cfg *Config) Validate() error {
 		}
 	}
 
+	if cfg.Preheat != nil {
+		for _, registry := range cfg.Preheat.Registries {
+			if registry.Host == "" {
+				return errors.New("empty preheat registry host is not specified")
+			}
+		}
+	}
+
 	return nil
 }
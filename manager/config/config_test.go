@@ -73,6 +73,16 @@ func TestManagerConfig_Load(t *testing.T) {
 		Metrics: &RestConfig{
 			Addr: ":8000",
 		},
+		Preheat: &PreheatConfig{
+			Registries: []*RegistryConfig{
+				{
+					Host:     "ghcr.io",
+					Username: "foo",
+					Password: "bar",
+					Insecure: true,
+				},
+			},
+		},
 	}
 
 	managerConfigYAML := &Config{}
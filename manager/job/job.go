@@ -38,7 +38,12 @@ func New(cfg *config.Config) (*Job, error) {
 		return nil, err
 	}
 
-	p, err := newPreheat(j, cfg.Server.Name)
+	var registries []*config.RegistryConfig
+	if cfg.Preheat != nil {
+		registries = cfg.Preheat.Registries
+	}
+
+	p, err := newPreheat(j, cfg.Server.Name, registries)
 	if err != nil {
 		return nil, err
 	}
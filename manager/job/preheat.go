@@ -19,18 +19,17 @@ package job
 import (
 	"context"
 	"crypto/tls"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"regexp"
-	"strings"
 	"time"
 
 	machineryv1tasks "github.com/RichardKnop/machinery/v1/tasks"
 	"github.com/distribution/distribution/v3"
 	"github.com/distribution/distribution/v3/manifest/schema2"
+	"github.com/go-http-utils/headers"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/trace"
 
@@ -39,6 +38,7 @@ import (
 	"d7y.io/dragonfly/v2/manager/config"
 	"d7y.io/dragonfly/v2/manager/model"
 	"d7y.io/dragonfly/v2/manager/types"
+	"d7y.io/dragonfly/v2/pkg/registry"
 	"d7y.io/dragonfly/v2/pkg/util/net/httputils"
 )
 
@@ -62,8 +62,11 @@ type Preheat interface {
 }
 
 type preheat struct {
-	job    *internaljob.Job
-	bizTag string
+	job          *internaljob.Job
+	bizTag       string
+	registryAuth *registry.Authenticator
+	// verifiedRegistries are the hosts with credentials, their tls certificates are verified
+	verifiedRegistries map[string]bool
 }
 
 type preheatImage struct {
@@ -73,10 +76,26 @@ type preheatImage struct {
 	tag      string
 }
 
-func newPreheat(job *internaljob.Job, bizTag string) (Preheat, error) {
+func newPreheat(job *internaljob.Job, bizTag string, registries []*config.RegistryConfig) (Preheat, error) {
+	options := []registry.AuthenticatorOption{
+		registry.WithHTTPClient(newRegistryClient(false)),
+		registry.WithInsecureHTTPClient(newRegistryClient(true)),
+	}
+	verifiedRegistries := map[string]bool{}
+	for _, r := range registries {
+		options = append(options, registry.WithCredentials(r.Host, &registry.Credentials{
+			Username: r.Username,
+			Password: r.Password,
+			Insecure: r.Insecure,
+		}))
+		verifiedRegistries[r.Host] = !r.Insecure
+	}
+
 	return &preheat{
-		job:    job,
-		bizTag: bizTag,
+		job:                job,
+		bizTag:             bizTag,
+		registryAuth:       registry.NewAuthenticator(options...),
+		verifiedRegistries: verifiedRegistries,
 	}, nil
 }
 
@@ -178,23 +197,24 @@ func (p *preheat) getLayers(ctx context.Context, url string, filter string, head
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode/100 != 2 {
-		if resp.StatusCode == http.StatusUnauthorized {
-			token, err := getAuthToken(ctx, resp.Header)
-			if err != nil {
-				return nil, err
-			}
-
-			bearer := "Bearer " + token
-			header.Add("Authorization", bearer)
+	if resp.StatusCode == http.StatusUnauthorized {
+		authorization, err := p.authorize(ctx, resp)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
 
-			resp, err = p.getManifests(ctx, url, header)
-			if err != nil {
-				return nil, err
-			}
-		} else {
-			return nil, fmt.Errorf("request registry %d", resp.StatusCode)
+		// layers are downloaded with the same authorization
+		header.Set(headers.Authorization, authorization)
+		resp, err = p.getManifests(ctx, url, header)
+		if err != nil {
+			return nil, err
 		}
+		defer resp.Body.Close()
+	}
+
+	if resp.StatusCode/100 != 2 {
+		return nil, fmt.Errorf("request registry %d", resp.StatusCode)
 	}
 
 	layers, err := p.parseLayers(resp, url, filter, header, image)
@@ -212,16 +232,17 @@ func (p *preheat) getManifests(ctx context.Context, url string, header http.Head
 	}
 
 	req.Header = header
-	req.Header.Add("Accept", schema2.MediaTypeManifest)
+	req.Header.Set("Accept", schema2.MediaTypeManifest)
 
-	client := &http.Client{
-		Timeout: timeout,
-		Transport: &http.Transport{
-			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
-		},
+	// use the cached token of the repository when caller supplies no authorization
+	if req.Header.Get(headers.Authorization) == "" {
+		if authorization := p.registryAuth.Authorization(req); authorization != "" {
+			req.Header.Set(headers.Authorization, authorization)
+		}
 	}
 
-	resp, err := client.Do(req)
+	// the authorization of configured credentials is only sent to the verified registry
+	resp, err := newRegistryClient(!p.verifiedRegistries[req.URL.Host]).Do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -229,6 +250,23 @@ func (p *preheat) getManifests(ctx context.Context, url string, header http.Head
 	return resp, nil
 }
 
+// authorize resolves the challenge of the unauthorized response with the credentials of the registry.
+func (p *preheat) authorize(ctx context.Context, resp *http.Response) (string, error) {
+	ctx, span := tracer.Start(ctx, config.SpanAuthWithRegistry, trace.WithSpanKind(trace.SpanKindProducer))
+	defer span.End()
+
+	return p.registryAuth.Authorize(ctx, resp.Request, resp)
+}
+
+func newRegistryClient(insecure bool) *http.Client {
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: insecure},
+		},
+	}
+}
+
 func (p *preheat) parseLayers(resp *http.Response, url, filter string, header http.Header, image *preheatImage) ([]*internaljob.PreheatRequest, error) {
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
@@ -257,63 +295,6 @@ func (p *preheat) parseLayers(resp *http.Response, url, filter string, header ht
 	return layers, nil
 }
 
-func getAuthToken(ctx context.Context, header http.Header) (string, error) {
-	ctx, span := tracer.Start(ctx, config.SpanAuthWithRegistry, trace.WithSpanKind(trace.SpanKindProducer))
-	defer span.End()
-
-	authURL := authURL(header.Values("WWW-Authenticate"))
-	if len(authURL) == 0 {
-		return "", errors.New("authURL is empty")
-	}
-
-	req, err := http.NewRequestWithContext(ctx, "GET", authURL, nil)
-	if err != nil {
-		return "", err
-	}
-
-	client := &http.Client{
-		Timeout: timeout,
-		Transport: &http.Transport{
-			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
-		},
-	}
-
-	resp, err := client.Do(req)
-	if err != nil {
-		return "", err
-	}
-	defer resp.Body.Close()
-
-	body, _ := io.ReadAll(resp.Body)
-	var result map[string]interface{}
-	if err := json.Unmarshal(body, &result); err != nil {
-		return "", err
-	}
-
-	if result["token"] == nil {
-		return "", errors.New("token is empty")
-	}
-
-	token := fmt.Sprintf("%v", result["token"])
-	return token, nil
-
-}
-
-func authURL(wwwAuth []string) string {
-	// Bearer realm="<auth-service-url>",service="<service>",scope="repository:<name>:pull"
-	if len(wwwAuth) == 0 {
-		return ""
-	}
-	polished := make([]string, 0)
-	for _, it := range wwwAuth {
-		polished = append(polished, strings.ReplaceAll(it, "\"", ""))
-	}
-	fileds := strings.Split(polished[0], ",")
-	host := strings.Split(fileds[0], "=")[1]
-	query := strings.Join(fileds[1:], "&")
-	return fmt.Sprintf("%s?%s", host, query)
-}
-
 func layerURL(protocol string, domain string, name string, digest string) string {
 	return fmt.Sprintf("%s://%s/v2/%s/blobs/%s", protocol, domain, name, digest)
 }
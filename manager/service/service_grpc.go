@@ -392,6 +392,11 @@ func (s *GRPC) ListSchedulers(ctx context.Context, req *manager.ListSchedulersRe
 	}
 	log.Infof("find matching scheduler cluster %v", getSchedulerClusterNames(schedulerClusters))
 
+	schedulerClusterClientConfig, err := schedulerCluster.ClientConfig.MarshalJSON()
+	if err != nil {
+		return nil, status.Error(codes.DataLoss, err.Error())
+	}
+
 	schedulers := []model.Scheduler{}
 	if err := s.db.WithContext(ctx).Find(&schedulers, &model.Scheduler{
 		State:              model.SchedulerStateActive,
@@ -416,7 +421,13 @@ func (s *GRPC) ListSchedulers(ctx context.Context, req *manager.ListSchedulersRe
 			Ip:                 scheduler.IP,
 			Port:               scheduler.Port,
 			SchedulerClusterId: uint64(scheduler.SchedulerClusterID),
-			State:              scheduler.State,
+			SchedulerCluster: &manager.SchedulerCluster{
+				Id:           uint64(schedulerCluster.ID),
+				Name:         schedulerCluster.Name,
+				Bio:          schedulerCluster.BIO,
+				ClientConfig: schedulerClusterClientConfig,
+			},
+			State: scheduler.State,
 		})
 	}
 
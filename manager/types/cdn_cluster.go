@@ -53,4 +53,6 @@ type GetCDNClustersQuery struct {
 type CDNClusterConfig struct {
 	LoadLimit   uint32 `yaml:"loadLimit" mapstructure:"loadLimit" json:"load_limit" binding:"omitempty,gte=1,lte=5000"`
 	NetTopology string `yaml:"netTopology" mapstructure:"netTopology" json:"net_topology"`
+	// PieceSigningKey is the base64 encoded ed25519 private key which signs piece digests of tasks
+	PieceSigningKey string `yaml:"pieceSigningKey" mapstructure:"pieceSigningKey" json:"piece_signing_key" binding:"omitempty,base64"`
 }
@@ -56,6 +56,43 @@ type SchedulerClusterConfig struct {
 
 type SchedulerClusterClientConfig struct {
 	LoadLimit uint32 `yaml:"loadLimit" mapstructure:"loadLimit" json:"load_limit" binding:"omitempty,gte=1,lte=5000"`
+	// PieceVerifyKey is the base64 encoded ed25519 public key of the cdn cluster piece signing key
+	PieceVerifyKey string `yaml:"pieceVerifyKey" mapstructure:"pieceVerifyKey" json:"piece_verify_key" binding:"omitempty,base64"`
+	// PieceTransport is the transport of pieces between clients, http or grpc
+	PieceTransport string `yaml:"pieceTransport" mapstructure:"pieceTransport" json:"piece_transport" binding:"omitempty,oneof=http grpc"`
+	// TotalDownloadRateLimit overrides the total download rate limit of clients in bytes per second, 0 means no override
+	TotalDownloadRateLimit uint64 `yaml:"totalDownloadRateLimit" mapstructure:"totalDownloadRateLimit" json:"total_download_rate_limit" binding:"omitempty"`
+	// PerPeerDownloadRateLimit overrides the download rate limit of each task in bytes per second, 0 means no override
+	PerPeerDownloadRateLimit uint64 `yaml:"perPeerDownloadRateLimit" mapstructure:"perPeerDownloadRateLimit" json:"per_peer_download_rate_limit" binding:"omitempty"`
+	// UploadRateLimit overrides the upload rate limit of clients in bytes per second, 0 means no override
+	UploadRateLimit uint64 `yaml:"uploadRateLimit" mapstructure:"uploadRateLimit" json:"upload_rate_limit" binding:"omitempty"`
+	// Proxy overrides the proxy rules of clients
+	Proxy *SchedulerClusterClientProxyConfig `yaml:"proxy" mapstructure:"proxy" json:"proxy" binding:"omitempty"`
+}
+
+// SchedulerClusterClientProxyConfig overrides the proxy of clients, nil fields keep the local config of clients.
+type SchedulerClusterClientProxyConfig struct {
+	Rules       []*ProxyRule       `yaml:"rules" mapstructure:"rules" json:"rules" binding:"omitempty,dive"`
+	WhiteList   []*ProxyWhiteList  `yaml:"whiteList" mapstructure:"whiteList" json:"white_list" binding:"omitempty,dive"`
+	HijackHosts []*ProxyHijackHost `yaml:"hijackHosts" mapstructure:"hijackHosts" json:"hijack_hosts" binding:"omitempty,dive"`
+}
+
+type ProxyRule struct {
+	Regx     string `yaml:"regx" mapstructure:"regx" json:"regx" binding:"required"`
+	UseHTTPS bool   `yaml:"useHTTPS" mapstructure:"useHTTPS" json:"use_https" binding:"omitempty"`
+	Direct   bool   `yaml:"direct" mapstructure:"direct" json:"direct" binding:"omitempty"`
+	Redirect string `yaml:"redirect" mapstructure:"redirect" json:"redirect" binding:"omitempty"`
+}
+
+type ProxyWhiteList struct {
+	Host  string   `yaml:"host" mapstructure:"host" json:"host" binding:"omitempty"`
+	Regx  string   `yaml:"regx" mapstructure:"regx" json:"regx" binding:"omitempty"`
+	Ports []string `yaml:"ports" mapstructure:"ports" json:"ports" binding:"omitempty"`
+}
+
+type ProxyHijackHost struct {
+	Regx     string `yaml:"regx" mapstructure:"regx" json:"regx" binding:"required"`
+	Insecure bool   `yaml:"insecure" mapstructure:"insecure" json:"insecure" binding:"omitempty"`
 }
 
 type SchedulerClusterScopes struct {
@@ -0,0 +1,85 @@
+/*
+ *     Copyright 2020 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package encryption
+
+import (
+	"github.com/pkg/errors"
+)
+
+// Config is the at-rest encryption configuration shared by dfdaemon and cdn.
+type Config struct {
+	// Enable encrypts cached task data on disk
+	Enable bool `mapstructure:"enable" yaml:"enable"`
+
+	// KeySource indicates where the master key comes from, support "file" and "plugin"
+	KeySource string `mapstructure:"keySource" yaml:"keySource"`
+
+	// KeyFile is the master key file for file key source
+	KeyFile string `mapstructure:"keyFile" yaml:"keyFile"`
+
+	// PluginName is the kms plugin name for plugin key source,
+	// the plugin file is d7y-kms-plugin-<name>.so in PluginDir
+	PluginName string `mapstructure:"pluginName" yaml:"pluginName"`
+
+	// PluginDir is the kms plugin directory, default is the dragonfly plugin directory
+	PluginDir string `mapstructure:"pluginDir" yaml:"pluginDir"`
+
+	// PluginOption is passed to the kms plugin when it is initialized
+	PluginOption map[string]string `mapstructure:"pluginOption" yaml:"pluginOption"`
+}
+
+func (c Config) Validate() error {
+	if !c.Enable {
+		return nil
+	}
+	switch c.KeySource {
+	case KeySourceFile:
+		if c.KeyFile == "" {
+			return errors.New("encryption key file can't be empty")
+		}
+	case KeySourcePlugin:
+		if c.PluginName == "" {
+			return errors.New("encryption kms plugin name can't be empty")
+		}
+	default:
+		return errors.Errorf("not support encryption key source: %q", c.KeySource)
+	}
+	return nil
+}
+
+// NewFromConfig returns an Encryptor according to cfg, it returns nil when encryption is disabled.
+func NewFromConfig(cfg Config) (*Encryptor, error) {
+	if !cfg.Enable {
+		return nil, nil
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	var keySource KeySource
+	switch cfg.KeySource {
+	case KeySourceFile:
+		keySource = NewFileKeySource(cfg.KeyFile)
+	case KeySourcePlugin:
+		ks, err := NewPluginKeySource(cfg.PluginDir, cfg.PluginName, cfg.PluginOption)
+		if err != nil {
+			return nil, errors.Wrap(err, "load kms plugin")
+		}
+		keySource = ks
+	}
+	return New(keySource)
+}
@@ -0,0 +1,307 @@
+/*
+ *     Copyright 2020 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package encryption implements at-rest encryption of cached task data.
+//
+// Every encrypted file gets its own keys, derived with HKDF from the master key,
+// a random salt and the file id. Data is encrypted with AES-256-CTR, so the
+// ciphertext keeps the plaintext offsets and any range can be decrypted without
+// reading the file from the beginning. Each written extent (normally a piece) gets
+// a random nonce which is mixed into the counter, so rewriting an extent never
+// reuses the keystream. Extents are authenticated with HMAC-SHA256 over the nonce,
+// offset and ciphertext, and readers verify every extent before returning any
+// plaintext of it.
+package encryption
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"hash"
+	"io"
+	"sort"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/hkdf"
+)
+
+const (
+	// KeySize is the minimal size of the master key and the size of derived keys.
+	KeySize = 32
+
+	// SaltSize is the size of the random salt generated for every encrypted file.
+	SaltSize = 16
+
+	// NonceSize is the size of the random nonce generated for every written extent.
+	NonceSize = aes.BlockSize
+
+	// writeChunkSize limits the buffer used by Writer for one encryption round.
+	writeChunkSize = 32 * 1024
+)
+
+var (
+	ErrInvalidMAC     = errors.New("encrypted data authentication failed")
+	ErrMissingExtent  = errors.New("encrypted data is not covered by any written extent")
+	ErrMasterKeyShort = errors.Errorf("master key must be at least %d bytes", KeySize)
+)
+
+// Encryptor derives file ciphers from the master key.
+type Encryptor struct {
+	masterKey []byte
+}
+
+// New returns an Encryptor with the master key loaded from key source.
+func New(keySource KeySource) (*Encryptor, error) {
+	key, err := keySource.MasterKey()
+	if err != nil {
+		return nil, errors.Wrap(err, "load master key")
+	}
+	if len(key) < KeySize {
+		return nil, ErrMasterKeyShort
+	}
+	return &Encryptor{masterKey: key}, nil
+}
+
+// NewSalt generates a random salt for a new encrypted file.
+func NewSalt() ([]byte, error) {
+	salt := make([]byte, SaltSize)
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return nil, err
+	}
+	return salt, nil
+}
+
+// Cipher returns the cipher of the file identified by id and salt.
+func (e *Encryptor) Cipher(id string, salt []byte) (*Cipher, error) {
+	if len(salt) != SaltSize {
+		return nil, errors.Errorf("invalid salt size %d", len(salt))
+	}
+	material := make([]byte, 2*KeySize+aes.BlockSize)
+	kdf := hkdf.New(sha256.New, e.masterKey, salt, []byte("dragonfly-encryption:"+id))
+	if _, err := io.ReadFull(kdf, material); err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(material[:KeySize])
+	if err != nil {
+		return nil, err
+	}
+	c := &Cipher{
+		block:  block,
+		macKey: material[KeySize : 2*KeySize],
+	}
+	copy(c.iv[:], material[2*KeySize:])
+	return c, nil
+}
+
+// Cipher encrypts and authenticates the content of one file.
+type Cipher struct {
+	block  cipher.Block
+	iv     [aes.BlockSize]byte
+	macKey []byte
+}
+
+// XORKeyStreamAt encrypts or decrypts src which locates at offset of the file and is written with nonce into dst.
+// The empty nonce is the keystream of extents written without nonce.
+func (c *Cipher) XORKeyStreamAt(dst, src, nonce []byte, offset int64) {
+	c.streamAt(nonce, offset).XORKeyStream(dst, src)
+}
+
+// Sum returns the authentication code of ciphertext written with nonce at offset.
+func (c *Cipher) Sum(nonce []byte, offset int64, ciphertext []byte) []byte {
+	mac := c.newMAC(nonce, offset)
+	mac.Write(ciphertext)
+	return mac.Sum(nil)
+}
+
+// Verify checks the authentication code of ciphertext written with nonce at offset.
+func (c *Cipher) Verify(nonce []byte, offset int64, ciphertext, sum []byte) bool {
+	if len(nonce) != 0 && len(nonce) != NonceSize {
+		return false
+	}
+	return hmac.Equal(c.Sum(nonce, offset, ciphertext), sum)
+}
+
+func (c *Cipher) newMAC(nonce []byte, offset int64) hash.Hash {
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], uint64(offset))
+	mac := hmac.New(sha256.New, c.macKey)
+	mac.Write(nonce)
+	mac.Write(buf[:])
+	return mac
+}
+
+// streamAt returns a CTR stream of nonce positioned at offset of the file.
+func (c *Cipher) streamAt(nonce []byte, offset int64) cipher.Stream {
+	var iv, base [aes.BlockSize]byte
+	copy(base[:], c.iv[:])
+	for i := 0; i < len(nonce) && i < len(base); i++ {
+		base[i] ^= nonce[i]
+	}
+	hi := binary.BigEndian.Uint64(base[:8])
+	lo := binary.BigEndian.Uint64(base[8:])
+	blocks := uint64(offset / aes.BlockSize)
+	if lo+blocks < lo {
+		hi++
+	}
+	binary.BigEndian.PutUint64(iv[:8], hi)
+	binary.BigEndian.PutUint64(iv[8:], lo+blocks)
+
+	stream := cipher.NewCTR(c.block, iv[:])
+	if skip := offset % aes.BlockSize; skip > 0 {
+		var discard [aes.BlockSize]byte
+		stream.XORKeyStream(discard[:skip], discard[:skip])
+	}
+	return stream
+}
+
+// Writer encrypts everything written to it and computes the authentication code of the ciphertext.
+type Writer struct {
+	w      io.Writer
+	nonce  []byte
+	stream cipher.Stream
+	mac    hash.Hash
+	buf    []byte
+}
+
+// NewWriter returns a Writer which writes ciphertext of data located at offset of the file into w,
+// every Writer encrypts with a new random nonce.
+func (c *Cipher) NewWriter(w io.Writer, offset int64) (*Writer, error) {
+	nonce := make([]byte, NonceSize)
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	return &Writer{
+		w:      w,
+		nonce:  nonce,
+		stream: c.streamAt(nonce, offset),
+		mac:    c.newMAC(nonce, offset),
+	}, nil
+}
+
+func (w *Writer) Write(p []byte) (int, error) {
+	var written int
+	for len(p) > 0 {
+		size := len(p)
+		if size > writeChunkSize {
+			size = writeChunkSize
+		}
+		if cap(w.buf) < size {
+			w.buf = make([]byte, size)
+		}
+		buf := w.buf[:size]
+		w.stream.XORKeyStream(buf, p[:size])
+		n, err := w.w.Write(buf)
+		w.mac.Write(buf[:n])
+		written += n
+		if err != nil {
+			return written, err
+		}
+		p = p[size:]
+	}
+	return written, nil
+}
+
+// Sum returns the authentication code of all ciphertext written so far.
+func (w *Writer) Sum() []byte {
+	return w.mac.Sum(nil)
+}
+
+// Nonce returns the nonce of the written ciphertext.
+func (w *Writer) Nonce() []byte {
+	return w.nonce
+}
+
+// Extent is a range of an encrypted file which is authenticated as a whole.
+type Extent struct {
+	Offset int64  `json:"offset"`
+	Length int64  `json:"length"`
+	Nonce  []byte `json:"nonce,omitempty"`
+	MAC    []byte `json:"mac"`
+}
+
+// NewReader returns a reader of the plaintext in [offset, offset+length) of the encrypted file ra.
+// Every extent overlapping the range is read and verified before any of its plaintext is returned,
+// the later one of extents at the same offset is the rewritten data.
+func (c *Cipher) NewReader(ra io.ReaderAt, extents []Extent, offset, length int64) io.Reader {
+	sorted := make([]Extent, len(extents))
+	copy(sorted, extents)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Offset < sorted[j].Offset
+	})
+	return &reader{
+		cipher:  c,
+		ra:      ra,
+		extents: sorted,
+		pos:     offset,
+		end:     offset + length,
+	}
+}
+
+type reader struct {
+	cipher  *Cipher
+	ra      io.ReaderAt
+	extents []Extent
+	pos     int64
+	end     int64
+	buf     []byte
+}
+
+func (r *reader) Read(p []byte) (int, error) {
+	if len(r.buf) == 0 {
+		if r.pos >= r.end {
+			return 0, io.EOF
+		}
+		if err := r.fill(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+	return n, nil
+}
+
+// fill loads and verifies the extent which contains r.pos.
+func (r *reader) fill() error {
+	i := sort.Search(len(r.extents), func(i int) bool {
+		return r.extents[i].Offset > r.pos
+	}) - 1
+	if i < 0 || r.extents[i].Offset+r.extents[i].Length <= r.pos {
+		return ErrMissingExtent
+	}
+	extent := r.extents[i]
+	data := make([]byte, extent.Length)
+	if n, err := r.ra.ReadAt(data, extent.Offset); n != len(data) {
+		if err == nil || err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return err
+	}
+	if !r.cipher.Verify(extent.Nonce, extent.Offset, data, extent.MAC) {
+		return ErrInvalidMAC
+	}
+	r.cipher.XORKeyStreamAt(data, data, extent.Nonce, extent.Offset)
+
+	end := extent.Offset + extent.Length
+	if end > r.end {
+		end = r.end
+	}
+	r.buf = data[r.pos-extent.Offset : end-extent.Offset]
+	r.pos = end
+	return nil
+}
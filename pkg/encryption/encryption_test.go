@@ -0,0 +1,200 @@
+/*
+ *     Copyright 2020 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package encryption
+
+import (
+	"bytes"
+	"encoding/hex"
+	"io"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type staticKeySource []byte
+
+func (s staticKeySource) MasterKey() ([]byte, error) {
+	return s, nil
+}
+
+func newTestCipher(t *testing.T, id string) *Cipher {
+	e, err := New(staticKeySource(bytes.Repeat([]byte{1}, KeySize)))
+	assert.Nil(t, err)
+	salt, err := NewSalt()
+	assert.Nil(t, err)
+	c, err := e.Cipher(id, salt)
+	assert.Nil(t, err)
+	return c
+}
+
+func TestNew(t *testing.T) {
+	_, err := New(staticKeySource([]byte("short")))
+	assert.Equal(t, ErrMasterKeyShort, err)
+
+	dir := t.TempDir()
+	key := bytes.Repeat([]byte{0xab}, KeySize)
+
+	hexFile := filepath.Join(dir, "hex.key")
+	assert.Nil(t, os.WriteFile(hexFile, []byte(hex.EncodeToString(key)+"\n"), 0600))
+	loaded, err := NewFileKeySource(hexFile).MasterKey()
+	assert.Nil(t, err)
+	assert.Equal(t, key, loaded)
+
+	rawFile := filepath.Join(dir, "raw.key")
+	assert.Nil(t, os.WriteFile(rawFile, key, 0600))
+	loaded, err = NewFileKeySource(rawFile).MasterKey()
+	assert.Nil(t, err)
+	assert.Equal(t, key, loaded)
+}
+
+func TestNewFromConfig(t *testing.T) {
+	e, err := NewFromConfig(Config{})
+	assert.Nil(t, err)
+	assert.Nil(t, e)
+
+	_, err = NewFromConfig(Config{Enable: true, KeySource: "unknown"})
+	assert.NotNil(t, err)
+
+	_, err = NewFromConfig(Config{Enable: true, KeySource: KeySourceFile})
+	assert.NotNil(t, err)
+}
+
+func TestCipher_XORKeyStreamAt(t *testing.T) {
+	c := newTestCipher(t, "task")
+	plain := make([]byte, 4096)
+	rand.Read(plain)
+	nonce := bytes.Repeat([]byte{2}, NonceSize)
+
+	whole := make([]byte, len(plain))
+	c.XORKeyStreamAt(whole, plain, nonce, 0)
+	assert.NotEqual(t, plain, whole)
+
+	// any offset must produce the same ciphertext as encrypting from the beginning
+	for _, offset := range []int64{1, 15, 16, 17, 1000, 4095} {
+		part := make([]byte, int64(len(plain))-offset)
+		c.XORKeyStreamAt(part, plain[offset:], nonce, offset)
+		assert.Equal(t, whole[offset:], part, "offset %d", offset)
+	}
+
+	// different nonces produce different keystreams
+	other := make([]byte, len(plain))
+	c.XORKeyStreamAt(other, plain, bytes.Repeat([]byte{3}, NonceSize), 0)
+	assert.NotEqual(t, whole, other)
+
+	// different file ids derive different keys
+	newTestCipher(t, "other").XORKeyStreamAt(other, plain, nonce, 0)
+	assert.NotEqual(t, whole, other)
+}
+
+// encrypt writes plain located at offset with a new Writer, it returns the ciphertext and the extent
+func encrypt(t *testing.T, c *Cipher, plain []byte, offset int64) ([]byte, Extent) {
+	buf := &bytes.Buffer{}
+	w, err := c.NewWriter(buf, offset)
+	assert.Nil(t, err)
+	n, err := w.Write(plain)
+	assert.Nil(t, err)
+	assert.Equal(t, len(plain), n)
+	assert.Len(t, w.Nonce(), NonceSize)
+	assert.Equal(t, c.Sum(w.Nonce(), offset, buf.Bytes()), w.Sum())
+	return buf.Bytes(), Extent{Offset: offset, Length: int64(len(plain)), Nonce: w.Nonce(), MAC: w.Sum()}
+}
+
+func TestCipher_WriterAndReader(t *testing.T) {
+	c := newTestCipher(t, "task")
+	plain := make([]byte, 10*1024+123)
+	rand.Read(plain)
+
+	var (
+		file      = make([]byte, len(plain))
+		extents   []Extent
+		pieceSize = 1024
+	)
+	for start := 0; start < len(plain); start += pieceSize {
+		end := start + pieceSize
+		if end > len(plain) {
+			end = len(plain)
+		}
+		ciphertext, extent := encrypt(t, c, plain[start:end], int64(start))
+		copy(file[start:], ciphertext)
+		extents = append(extents, extent)
+	}
+	rand.Shuffle(len(extents), func(i, j int) { extents[i], extents[j] = extents[j], extents[i] })
+
+	data, err := io.ReadAll(c.NewReader(bytes.NewReader(file), extents, 0, int64(len(plain))))
+	assert.Nil(t, err)
+	assert.Equal(t, plain, data)
+
+	data, err = io.ReadAll(c.NewReader(bytes.NewReader(file), extents, 1000, 3000))
+	assert.Nil(t, err)
+	assert.Equal(t, plain[1000:4000], data)
+
+	// a range without written extent
+	_, err = io.ReadAll(c.NewReader(bytes.NewReader(file), extents[:1], 0, int64(len(plain))))
+	assert.NotNil(t, err)
+
+	// tampered ciphertext
+	file[2048] ^= 1
+	_, err = io.ReadAll(c.NewReader(bytes.NewReader(file), extents, 2000, 100))
+	assert.Equal(t, ErrInvalidMAC, err)
+}
+
+func TestCipher_Rewrite(t *testing.T) {
+	c := newTestCipher(t, "task")
+	first := make([]byte, 1024)
+	second := make([]byte, 1024)
+	rand.Read(first)
+	rand.Read(second)
+
+	// rewriting the same offset must not reuse the keystream, otherwise
+	// xor of the ciphertexts is xor of the plaintexts
+	c1, e1 := encrypt(t, c, first, 1024)
+	c2, e2 := encrypt(t, c, second, 1024)
+	assert.NotEqual(t, e1.Nonce, e2.Nonce)
+	for i := range first {
+		if c1[i]^c2[i] != first[i]^second[i] {
+			break
+		}
+		assert.NotEqual(t, len(first)-1, i, "keystream is reused")
+	}
+
+	// the later extent of the same offset is the rewritten data
+	file := make([]byte, 2048)
+	copy(file[1024:], c2)
+	data, err := io.ReadAll(c.NewReader(bytes.NewReader(file), []Extent{e1, e2}, 1024, 1024))
+	assert.Nil(t, err)
+	assert.Equal(t, second, data)
+
+	// nonce is authenticated
+	e2.Nonce = e1.Nonce
+	_, err = io.ReadAll(c.NewReader(bytes.NewReader(file), []Extent{e2}, 1024, 1024))
+	assert.Equal(t, ErrInvalidMAC, err)
+
+	// extents written without nonce are still readable
+	legacy := make([]byte, 1024)
+	c.XORKeyStreamAt(legacy, first, nil, 1024)
+	copy(file[1024:], legacy)
+	data, err = io.ReadAll(c.NewReader(bytes.NewReader(file), []Extent{{
+		Offset: 1024,
+		Length: 1024,
+		MAC:    c.Sum(nil, 1024, legacy),
+	}}, 1024, 1024))
+	assert.Nil(t, err)
+	assert.Equal(t, first, data)
+}
@@ -0,0 +1,77 @@
+/*
+ *     Copyright 2020 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package encryption
+
+import (
+	"bytes"
+	"encoding/hex"
+	"os"
+
+	"github.com/pkg/errors"
+
+	"d7y.io/dragonfly/v2/internal/dfplugin"
+)
+
+const (
+	// KeySourceFile loads the master key from a local file
+	KeySourceFile = "file"
+
+	// KeySourcePlugin loads the master key from a kms plugin
+	KeySourcePlugin = "plugin"
+)
+
+// KeySource provides the master key, all file keys are derived from it.
+type KeySource interface {
+	MasterKey() ([]byte, error)
+}
+
+type fileKeySource struct {
+	path string
+}
+
+// NewFileKeySource returns a KeySource which reads the master key from path,
+// the file content is either hex encoded or the raw key bytes.
+func NewFileKeySource(path string) KeySource {
+	return &fileKeySource{path: path}
+}
+
+func (s *fileKeySource) MasterKey() ([]byte, error) {
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		return nil, err
+	}
+	trimmed := bytes.TrimSpace(data)
+	if key, err := hex.DecodeString(string(trimmed)); err == nil {
+		return key, nil
+	}
+	return data, nil
+}
+
+// NewPluginKeySource loads the kms plugin d7y-kms-plugin-<name>.so from dir,
+// the plugin must implement KeySource.
+func NewPluginKeySource(dir, name string, option map[string]string) (KeySource, error) {
+	client, _, err := dfplugin.Load(dir, dfplugin.PluginTypeKMS, name, option)
+	if err != nil {
+		return nil, err
+	}
+
+	ks, ok := client.(KeySource)
+	if !ok {
+		return nil, errors.New("invalid client, not a KeySource")
+	}
+	return ks, nil
+}
@@ -0,0 +1,371 @@
+/*
+ *     Copyright 2020 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package registry implements the token authentication of docker registry,
+// see https://docs.docker.com/registry/spec/auth/token/.
+package registry
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-http-utils/headers"
+	"github.com/pkg/errors"
+
+	logger "d7y.io/dragonfly/v2/internal/dflog"
+)
+
+const (
+	// defaultTokenExpiration is used when the token server does not return expires_in
+	defaultTokenExpiration = 60 * time.Second
+	// tokenExpirationMargin makes the cached token expire before the token server's expiration
+	tokenExpirationMargin = 10 * time.Second
+	// defaultTimeout is the timeout of token requests
+	defaultTimeout = 30 * time.Second
+)
+
+const (
+	schemeBearer = "bearer"
+	schemeBasic  = "basic"
+)
+
+// repositoryPattern matches the repository in registry api path, like /v2/library/alpine/manifests/latest
+var repositoryPattern = regexp.MustCompile(`^/v2/(.+)/(manifests|blobs|tags)/`)
+
+// Credentials is the username and password of registry.
+type Credentials struct {
+	Username string
+	Password string
+	// Insecure skips the tls verification of the token server, the credentials are exposed to
+	// the network path, it is only for registries with self-signed certificates
+	Insecure bool
+}
+
+// Challenge is an authentication challenge in header WWW-Authenticate.
+type Challenge struct {
+	// Scheme is in lower case, like bearer or basic
+	Scheme string
+	Params map[string]string
+}
+
+type token struct {
+	value    string
+	expireAt time.Time
+}
+
+// Authenticator resolves the challenges of registries, it caches the challenges of repositories
+// and the tokens until expiry, so the following requests are authorized in advance.
+type Authenticator struct {
+	client *http.Client
+	// insecureClient requests the token servers of insecure registries
+	insecureClient *http.Client
+	credentials    map[string]*Credentials
+
+	lock sync.RWMutex
+	// challenges are keyed by host and repository
+	challenges map[string]Challenge
+	// tokens are keyed by realm, service, scope and username
+	tokens map[string]*token
+}
+
+// AuthenticatorOption is a functional option for configuring the authenticator.
+type AuthenticatorOption func(a *Authenticator)
+
+// WithHTTPClient sets the http client to request the token server.
+func WithHTTPClient(client *http.Client) AuthenticatorOption {
+	return func(a *Authenticator) {
+		a.client = client
+	}
+}
+
+// WithInsecureHTTPClient sets the http client to request the token server of insecure registries.
+func WithInsecureHTTPClient(client *http.Client) AuthenticatorOption {
+	return func(a *Authenticator) {
+		a.insecureClient = client
+	}
+}
+
+// WithCredentials sets the credentials for the registry host, requests to other hosts are anonymous.
+func WithCredentials(host string, credentials *Credentials) AuthenticatorOption {
+	return func(a *Authenticator) {
+		a.credentials[host] = credentials
+	}
+}
+
+// NewAuthenticator returns a new authenticator.
+func NewAuthenticator(options ...AuthenticatorOption) *Authenticator {
+	a := &Authenticator{
+		client: &http.Client{Timeout: defaultTimeout},
+		insecureClient: &http.Client{
+			Timeout: defaultTimeout,
+			Transport: &http.Transport{
+				TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+			},
+		},
+		credentials: map[string]*Credentials{},
+		challenges:  map[string]Challenge{},
+		tokens:      map[string]*token{},
+	}
+	for _, opt := range options {
+		opt(a)
+	}
+	return a
+}
+
+// Authorization returns the cached authorization for the request, it is empty when the challenge
+// of the repository is unknown or the token is expired.
+func (a *Authenticator) Authorization(req *http.Request) string {
+	a.lock.RLock()
+	challenge, ok := a.challenges[challengeKey(req.URL)]
+	a.lock.RUnlock()
+	if !ok {
+		return ""
+	}
+
+	switch challenge.Scheme {
+	case schemeBasic:
+		return a.basicAuthorization(req.URL.Host)
+	case schemeBearer:
+		a.lock.RLock()
+		defer a.lock.RUnlock()
+		if t, ok := a.tokens[a.tokenKey(req.URL.Host, challenge)]; ok && time.Now().Before(t.expireAt) {
+			return "Bearer " + t.value
+		}
+	}
+	return ""
+}
+
+// Authorize resolves the challenge in the unauthorized response of the request,
+// and returns the authorization to retry the request.
+func (a *Authenticator) Authorize(ctx context.Context, req *http.Request, resp *http.Response) (string, error) {
+	var challenge *Challenge
+	for _, c := range ParseChallenges(resp.Header) {
+		c := c
+		if c.Scheme == schemeBearer {
+			challenge = &c
+			break
+		}
+		if c.Scheme == schemeBasic && challenge == nil {
+			challenge = &c
+		}
+	}
+	if challenge == nil {
+		return "", errors.Errorf("no supported challenge from %s", req.URL.Host)
+	}
+
+	var (
+		authorization string
+		err           error
+	)
+	switch challenge.Scheme {
+	case schemeBasic:
+		if authorization = a.basicAuthorization(req.URL.Host); authorization == "" {
+			return "", errors.Errorf("no credentials for %s", req.URL.Host)
+		}
+	case schemeBearer:
+		if authorization, err = a.fetchToken(ctx, req.URL.Host, *challenge); err != nil {
+			return "", err
+		}
+	}
+
+	a.setChallenge(req.URL, *challenge)
+	return authorization, nil
+}
+
+// resolved returns whether the challenge of the repository is known, an empty challenge
+// means the repository is anonymous.
+func (a *Authenticator) resolved(u *url.URL) bool {
+	a.lock.RLock()
+	defer a.lock.RUnlock()
+	_, ok := a.challenges[challengeKey(u)]
+	return ok
+}
+
+// invalidate removes the cached challenge of the repository and its token, which are rejected by registry.
+func (a *Authenticator) invalidate(u *url.URL) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	key := challengeKey(u)
+	if challenge, ok := a.challenges[key]; ok {
+		delete(a.tokens, a.tokenKey(u.Host, challenge))
+		delete(a.challenges, key)
+	}
+}
+
+func (a *Authenticator) setChallenge(u *url.URL, challenge Challenge) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	a.challenges[challengeKey(u)] = challenge
+}
+
+func (a *Authenticator) basicAuthorization(host string) string {
+	credentials, ok := a.credentials[host]
+	if !ok || credentials == nil {
+		return ""
+	}
+	auth := credentials.Username + ":" + credentials.Password
+	return "Basic " + base64.StdEncoding.EncodeToString([]byte(auth))
+}
+
+func (a *Authenticator) tokenKey(host string, challenge Challenge) string {
+	var username string
+	if credentials, ok := a.credentials[host]; ok && credentials != nil {
+		username = credentials.Username
+	}
+	return strings.Join([]string{challenge.Params["realm"], challenge.Params["service"], challenge.Params["scope"], username}, "|")
+}
+
+// tokenResponse is the response of token server, some servers use access_token instead of token.
+type tokenResponse struct {
+	Token       string `json:"token"`
+	AccessToken string `json:"access_token"`
+	ExpiresIn   int    `json:"expires_in"`
+}
+
+// fetchToken requests a new token from the realm of the challenge and caches it.
+func (a *Authenticator) fetchToken(ctx context.Context, host string, challenge Challenge) (string, error) {
+	realm, err := url.Parse(challenge.Params["realm"])
+	if err != nil || realm.Host == "" {
+		return "", errors.Errorf("invalid realm %q from %s", challenge.Params["realm"], host)
+	}
+
+	query := realm.Query()
+	for _, key := range []string{"service", "scope"} {
+		if value := challenge.Params[key]; value != "" {
+			query.Set(key, value)
+		}
+	}
+	realm.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, realm.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	// the token server is verified unless the registry is insecure, it receives the credentials
+	client := a.client
+	if credentials, ok := a.credentials[host]; ok && credentials != nil {
+		req.SetBasicAuth(credentials.Username, credentials.Password)
+		if credentials.Insecure {
+			client = a.insecureClient
+		}
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", errors.Wrapf(err, "request token from %s", realm.Host)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", errors.Errorf("request token from %s: %s", realm.Host, resp.Status)
+	}
+
+	var result tokenResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", errors.Wrapf(err, "decode token from %s", realm.Host)
+	}
+	if result.Token == "" {
+		result.Token = result.AccessToken
+	}
+	if result.Token == "" {
+		return "", errors.Errorf("empty token from %s", realm.Host)
+	}
+
+	expiration := defaultTokenExpiration
+	if result.ExpiresIn > 0 {
+		expiration = time.Duration(result.ExpiresIn) * time.Second
+	}
+	if expiration > 2*tokenExpirationMargin {
+		expiration -= tokenExpirationMargin
+	}
+
+	a.lock.Lock()
+	a.tokens[a.tokenKey(host, challenge)] = &token{
+		value:    result.Token,
+		expireAt: time.Now().Add(expiration),
+	}
+	a.lock.Unlock()
+	logger.Debugf("fetch registry token from %s for %s, scope: %s", realm.Host, host, challenge.Params["scope"])
+	return "Bearer " + result.Token, nil
+}
+
+// challengeKey returns the key of challenge, challenges of different repositories are different
+// because of the scope.
+func challengeKey(u *url.URL) string {
+	var repository string
+	if match := repositoryPattern.FindStringSubmatch(u.Path); len(match) > 1 {
+		repository = match[1]
+	}
+	return u.Host + "/" + repository
+}
+
+// ParseChallenges parses the challenges in header WWW-Authenticate, like
+// Bearer realm="https://auth.docker.io/token",service="registry.docker.io",scope="repository:library/alpine:pull".
+func ParseChallenges(header http.Header) []Challenge {
+	var challenges []Challenge
+	for _, value := range header.Values(headers.WWWAuthenticate) {
+		value = strings.TrimSpace(value)
+		i := strings.IndexByte(value, ' ')
+		if i < 0 {
+			challenges = append(challenges, Challenge{Scheme: strings.ToLower(value), Params: map[string]string{}})
+			continue
+		}
+		challenges = append(challenges, Challenge{
+			Scheme: strings.ToLower(value[:i]),
+			Params: parseParams(value[i+1:]),
+		})
+	}
+	return challenges
+}
+
+// parseParams parses the comma separated key=value pairs, values may be quoted and contain commas.
+func parseParams(s string) map[string]string {
+	params := map[string]string{}
+	for s = strings.TrimSpace(s); s != ""; {
+		i := strings.IndexByte(s, '=')
+		if i < 0 {
+			break
+		}
+		key := strings.ToLower(strings.TrimSpace(s[:i]))
+		s = strings.TrimSpace(s[i+1:])
+
+		var value string
+		if strings.HasPrefix(s, `"`) {
+			end := strings.IndexByte(s[1:], '"')
+			if end < 0 {
+				value, s = s[1:], ""
+			} else {
+				value, s = s[1:end+1], s[end+2:]
+			}
+		} else if end := strings.IndexByte(s, ','); end < 0 {
+			value, s = s, ""
+		} else {
+			value, s = s[:end], s[end:]
+		}
+		params[key] = strings.TrimSpace(value)
+
+		s = strings.TrimLeft(strings.TrimSpace(s), ",")
+		s = strings.TrimSpace(s)
+	}
+	return params
+}
@@ -0,0 +1,274 @@
+/*
+ *     Copyright 2020 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package registry
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseChallenges(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []string
+		expect []Challenge
+	}{
+		{
+			name:   "bearer",
+			values: []string{`Bearer realm="https://auth.docker.io/token",service="registry.docker.io",scope="repository:library/alpine:pull,push"`},
+			expect: []Challenge{{
+				Scheme: "bearer",
+				Params: map[string]string{
+					"realm":   "https://auth.docker.io/token",
+					"service": "registry.docker.io",
+					"scope":   "repository:library/alpine:pull,push",
+				},
+			}},
+		},
+		{
+			name:   "basic and unquoted",
+			values: []string{`Basic realm=registry`, `Bearer realm="https://a/token", service=b`},
+			expect: []Challenge{
+				{Scheme: "basic", Params: map[string]string{"realm": "registry"}},
+				{Scheme: "bearer", Params: map[string]string{"realm": "https://a/token", "service": "b"}},
+			},
+		},
+		{
+			name:   "scheme only",
+			values: []string{"Negotiate"},
+			expect: []Challenge{{Scheme: "negotiate", Params: map[string]string{}}},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			header := http.Header{}
+			for _, v := range tc.values {
+				header.Add("WWW-Authenticate", v)
+			}
+			assert.Equal(t, tc.expect, ParseChallenges(header))
+		})
+	}
+}
+
+// newTestRegistry returns a registry requires bearer token, the token server requires credentials user:pass.
+func newTestRegistry(t *testing.T, tokenRequests *int32) (*httptest.Server, *httptest.Server) {
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(tokenRequests, 1)
+		if user, pass, ok := r.BasicAuth(); !ok || user != "user" || pass != "pass" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		fmt.Fprintf(w, `{"token":"token-%s","expires_in":300}`, r.URL.Query().Get("scope"))
+	}))
+
+	var registry *httptest.Server
+	registry = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		repository := repositoryPattern.FindStringSubmatch(r.URL.Path)[1]
+		scope := fmt.Sprintf("repository:%s:pull", repository)
+		if r.Header.Get("Authorization") != "Bearer token-"+scope {
+			w.Header().Set("WWW-Authenticate",
+				fmt.Sprintf(`Bearer realm="%s/token",service="%s",scope="%s"`, tokenServer.URL, registry.Listener.Addr(), scope))
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		fmt.Fprint(w, repository)
+	}))
+
+	t.Cleanup(func() {
+		registry.Close()
+		tokenServer.Close()
+	})
+	return registry, tokenServer
+}
+
+func TestAuthenticator_Authorize(t *testing.T) {
+	var tokenRequests int32
+	registry, _ := newTestRegistry(t, &tokenRequests)
+	u, _ := url.Parse(registry.URL)
+
+	auth := NewAuthenticator(WithCredentials(u.Host, &Credentials{Username: "user", Password: "pass"}))
+	req, _ := http.NewRequest(http.MethodGet, registry.URL+"/v2/library/alpine/manifests/latest", nil)
+	assert.Empty(t, auth.Authorization(req))
+
+	resp, err := http.DefaultClient.Do(req)
+	require.Nil(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	authorization, err := auth.Authorize(context.Background(), req, resp)
+	require.Nil(t, err)
+	assert.Equal(t, "Bearer token-repository:library/alpine:pull", authorization)
+	assert.Equal(t, authorization, auth.Authorization(req))
+
+	// other repository has a different scope
+	other, _ := http.NewRequest(http.MethodGet, registry.URL+"/v2/library/busybox/blobs/sha256:abc", nil)
+	assert.Empty(t, auth.Authorization(other))
+	assert.EqualValues(t, 1, atomic.LoadInt32(&tokenRequests))
+
+	// anonymous is rejected by the token server
+	_, err = NewAuthenticator().Authorize(context.Background(), req, resp)
+	assert.NotNil(t, err)
+}
+
+func TestAuthenticator_InsecureTokenServer(t *testing.T) {
+	var tokenRequests int32
+	tokenServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&tokenRequests, 1)
+		fmt.Fprint(w, `{"token":"token"}`)
+	}))
+	defer tokenServer.Close()
+
+	req, _ := http.NewRequest(http.MethodGet, "http://registry/v2/library/alpine/manifests/latest", nil)
+	resp := &http.Response{
+		StatusCode: http.StatusUnauthorized,
+		Header: http.Header{
+			"Www-Authenticate": []string{fmt.Sprintf(`Bearer realm="%s/token",service="registry"`, tokenServer.URL)},
+		},
+	}
+
+	// the credentials are not sent to the token server with self-signed certificate
+	auth := NewAuthenticator(WithCredentials("registry", &Credentials{Username: "user", Password: "pass"}))
+	_, err := auth.Authorize(context.Background(), req, resp)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, 0, atomic.LoadInt32(&tokenRequests))
+
+	auth = NewAuthenticator(WithCredentials("registry", &Credentials{Username: "user", Password: "pass", Insecure: true}))
+	authorization, err := auth.Authorize(context.Background(), req, resp)
+	require.Nil(t, err)
+	assert.Equal(t, "Bearer token", authorization)
+	assert.EqualValues(t, 1, atomic.LoadInt32(&tokenRequests))
+}
+
+func TestTransport(t *testing.T) {
+	var tokenRequests int32
+	registry, _ := newTestRegistry(t, &tokenRequests)
+	u, _ := url.Parse(registry.URL)
+
+	client := &http.Client{
+		Transport: &Transport{
+			Base:          http.DefaultTransport,
+			Authenticator: NewAuthenticator(WithCredentials(u.Host, &Credentials{Username: "user", Password: "pass"})),
+		},
+	}
+
+	for i := 0; i < 3; i++ {
+		resp, err := client.Get(registry.URL + "/v2/library/alpine/blobs/sha256:abc")
+		require.Nil(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	}
+	// token is cached after the first request
+	assert.EqualValues(t, 1, atomic.LoadInt32(&tokenRequests))
+
+	// unresolved challenge returns the unauthorized response
+	anonymous := &http.Client{Transport: &Transport{Base: http.DefaultTransport, Authenticator: NewAuthenticator()}}
+	resp, err := anonymous.Get(registry.URL + "/v2/library/alpine/blobs/sha256:abc")
+	require.Nil(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	assert.NotEmpty(t, resp.Header.Get("WWW-Authenticate"))
+}
+
+// errorOnUnauthorized simulates the dragonfly transport which returns error instead of the unauthorized response.
+type errorOnUnauthorized struct{}
+
+func (errorOnUnauthorized) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := http.DefaultTransport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return resp, nil
+}
+
+func TestTransport_Challenger(t *testing.T) {
+	var tokenRequests int32
+	registry, _ := newTestRegistry(t, &tokenRequests)
+	u, _ := url.Parse(registry.URL)
+
+	client := &http.Client{
+		Transport: &Transport{
+			Base:          errorOnUnauthorized{},
+			Authenticator: NewAuthenticator(WithCredentials(u.Host, &Credentials{Username: "user", Password: "pass"})),
+			Challenger:    http.DefaultTransport,
+		},
+	}
+
+	for i := 0; i < 2; i++ {
+		resp, err := client.Get(registry.URL + "/v2/library/alpine/blobs/sha256:abc")
+		require.Nil(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	}
+	assert.EqualValues(t, 1, atomic.LoadInt32(&tokenRequests))
+}
+
+// countTransport counts the requests without authorization
+type countTransport struct {
+	anonymous int32
+}
+
+func (c *countTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Header.Get("Authorization") == "" {
+		atomic.AddInt32(&c.anonymous, 1)
+	}
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestTransport_RevokedToken(t *testing.T) {
+	var tokenRequests int32
+	registry, _ := newTestRegistry(t, &tokenRequests)
+	u, _ := url.Parse(registry.URL)
+
+	base := &countTransport{}
+	auth := NewAuthenticator(WithCredentials(u.Host, &Credentials{Username: "user", Password: "pass"}))
+	client := &http.Client{Transport: &Transport{Base: base, Authenticator: auth}}
+
+	blob := registry.URL + "/v2/library/alpine/blobs/sha256:abc"
+	resp, err := client.Get(blob)
+	require.Nil(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.EqualValues(t, 1, atomic.LoadInt32(&base.anonymous))
+
+	// registry revokes the cached token
+	auth.lock.Lock()
+	for _, token := range auth.tokens {
+		token.value = "revoked"
+	}
+	auth.lock.Unlock()
+
+	resp, err = client.Get(blob)
+	require.Nil(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.EqualValues(t, 2, atomic.LoadInt32(&tokenRequests))
+	// the unauthorized response of the revoked token is authorized directly without anonymous request
+	assert.EqualValues(t, 1, atomic.LoadInt32(&base.anonymous))
+}
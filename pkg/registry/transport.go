@@ -0,0 +1,120 @@
+/*
+ *     Copyright 2020 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package registry
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/go-http-utils/headers"
+
+	logger "d7y.io/dragonfly/v2/internal/dflog"
+)
+
+// Transport authorizes the registry requests without authorization, the unauthorized response is
+// returned as it is when the challenge can not be resolved.
+type Transport struct {
+	Base          http.RoundTripper
+	Authenticator *Authenticator
+	// Challenger is used to get the challenge of the repository in advance by a HEAD request,
+	// it is required when Base can not return the unauthorized response, like downloading with dragonfly.
+	Challenger http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Header.Get(headers.Authorization) != "" {
+		return t.Base.RoundTrip(req)
+	}
+
+	if authorization := t.Authenticator.Authorization(req); authorization != "" {
+		resp, err := t.Base.RoundTrip(withAuthorization(req, authorization))
+		if err != nil || resp.StatusCode != http.StatusUnauthorized || !canRetry(req) {
+			return resp, err
+		}
+		// token is revoked, forget it and resolve the challenge in the unauthorized response
+		t.Authenticator.invalidate(req.URL)
+		return t.authorize(req, resp)
+	} else if t.Challenger != nil && !t.Authenticator.resolved(req.URL) {
+		if authorization := t.challenge(req); authorization != "" {
+			return t.Base.RoundTrip(withAuthorization(req, authorization))
+		}
+	}
+
+	resp, err := t.Base.RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized || !canRetry(req) {
+		return resp, err
+	}
+	return t.authorize(req, resp)
+}
+
+// authorize resolves the challenge in the unauthorized response and sends the request again with
+// the authorization, the unauthorized response is returned when the challenge can not be resolved.
+func (t *Transport) authorize(req *http.Request, resp *http.Response) (*http.Response, error) {
+	authorization, err := t.Authenticator.Authorize(req.Context(), req, resp)
+	if err != nil {
+		logger.Debugf("authorize request %s error: %v", req.URL, err)
+		return resp, nil
+	}
+	drainAndClose(resp.Body)
+	return t.Base.RoundTrip(withAuthorization(req, authorization))
+}
+
+// challenge sends a HEAD request to get the challenge of the repository, returns the authorization
+// when the repository requires authentication.
+func (t *Transport) challenge(req *http.Request) string {
+	head := req.Clone(req.Context())
+	head.Method = http.MethodHead
+	head.Body = nil
+	head.ContentLength = 0
+	head.Header.Del(headers.Range)
+
+	resp, err := t.Challenger.RoundTrip(head)
+	if err != nil {
+		logger.Debugf("challenge request %s error: %v", req.URL, err)
+		return ""
+	}
+	defer drainAndClose(resp.Body)
+
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Authenticator.setChallenge(req.URL, Challenge{})
+		return ""
+	}
+
+	authorization, err := t.Authenticator.Authorize(req.Context(), head, resp)
+	if err != nil {
+		logger.Debugf("authorize request %s error: %v", req.URL, err)
+		return ""
+	}
+	return authorization
+}
+
+func withAuthorization(req *http.Request, authorization string) *http.Request {
+	r := req.Clone(req.Context())
+	r.Header.Set(headers.Authorization, authorization)
+	return r
+}
+
+// canRetry returns whether the request can be sent again, registry requests are almost GET and HEAD.
+func canRetry(req *http.Request) bool {
+	return req.Body == nil || req.Body == http.NoBody
+}
+
+func drainAndClose(body io.ReadCloser) {
+	_, _ = io.Copy(io.Discard, io.LimitReader(body, 4096))
+	_ = body.Close()
+}
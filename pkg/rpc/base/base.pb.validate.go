@@ -640,6 +640,21 @@ func (m *PieceInfo) validate(all bool) error {
 
 	// no validation rules for PieceStyle
 
+	if m.GetPieceDigest() != "" {
+
+		if !_PieceInfo_PieceDigest_Pattern.MatchString(m.GetPieceDigest()) {
+			err := PieceInfoValidationError{
+				field:  "PieceDigest",
+				reason: "value does not match regex pattern \"^(md5|sha256):[A-Fa-f0-9]+$\"",
+			}
+			if !all {
+				return err
+			}
+			errors = append(errors, err)
+		}
+
+	}
+
 	if len(errors) > 0 {
 		return PieceInfoMultiError(errors)
 	}
@@ -718,6 +733,8 @@ var _ interface {
 
 var _PieceInfo_PieceMd5_Pattern = regexp.MustCompile("([a-f\\d]{32}|[A-F\\d]{32}|[a-f\\d]{16}|[A-F\\d]{16})")
 
+var _PieceInfo_PieceDigest_Pattern = regexp.MustCompile("^(md5|sha256):[A-Fa-f0-9]+$")
+
 // Validate checks the field values on PiecePacket with the rules defined in
 // the proto definition for this message. If any rules are violated, the first
 // error encountered is returned, or nil if there are no violations.
@@ -813,6 +830,10 @@ func (m *PiecePacket) validate(all bool) error {
 
 	// no validation rules for PieceMd5Sign
 
+	// no validation rules for PieceDigestRoot
+
+	// no validation rules for PieceDigestSignature
+
 	if len(errors) > 0 {
 		return PiecePacketMultiError(errors)
 	}
@@ -271,6 +271,10 @@ func (m *PieceSeed) validate(all bool) error {
 
 	// no validation rules for TotalPieceCount
 
+	// no validation rules for PieceDigestRoot
+
+	// no validation rules for PieceDigestSignature
+
 	if len(errors) > 0 {
 		return PieceSeedMultiError(errors)
 	}
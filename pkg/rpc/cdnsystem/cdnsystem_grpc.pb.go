@@ -22,6 +22,8 @@ type SeederClient interface {
 	ObtainSeeds(ctx context.Context, in *SeedRequest, opts ...grpc.CallOption) (Seeder_ObtainSeedsClient, error)
 	// Get piece tasks from cdn
 	GetPieceTasks(ctx context.Context, in *base.PieceTaskRequest, opts ...grpc.CallOption) (*base.PiecePacket, error)
+	// Subscribe piece tasks from cdn, new pieces are pushed when they are ready
+	SyncPieceTasks(ctx context.Context, in *base.PieceTaskRequest, opts ...grpc.CallOption) (Seeder_SyncPieceTasksClient, error)
 }
 
 type seederClient struct {
@@ -73,6 +75,38 @@ func (c *seederClient) GetPieceTasks(ctx context.Context, in *base.PieceTaskRequ
 	return out, nil
 }
 
+func (c *seederClient) SyncPieceTasks(ctx context.Context, in *base.PieceTaskRequest, opts ...grpc.CallOption) (Seeder_SyncPieceTasksClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Seeder_serviceDesc.Streams[1], "/cdnsystem.Seeder/SyncPieceTasks", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &seederSyncPieceTasksClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Seeder_SyncPieceTasksClient interface {
+	Recv() (*base.PiecePacket, error)
+	grpc.ClientStream
+}
+
+type seederSyncPieceTasksClient struct {
+	grpc.ClientStream
+}
+
+func (x *seederSyncPieceTasksClient) Recv() (*base.PiecePacket, error) {
+	m := new(base.PiecePacket)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // SeederServer is the server API for Seeder service.
 // All implementations must embed UnimplementedSeederServer
 // for forward compatibility
@@ -81,6 +115,8 @@ type SeederServer interface {
 	ObtainSeeds(*SeedRequest, Seeder_ObtainSeedsServer) error
 	// Get piece tasks from cdn
 	GetPieceTasks(context.Context, *base.PieceTaskRequest) (*base.PiecePacket, error)
+	// Subscribe piece tasks from cdn, new pieces are pushed when they are ready
+	SyncPieceTasks(*base.PieceTaskRequest, Seeder_SyncPieceTasksServer) error
 	mustEmbedUnimplementedSeederServer()
 }
 
@@ -94,6 +130,9 @@ func (UnimplementedSeederServer) ObtainSeeds(*SeedRequest, Seeder_ObtainSeedsSer
 func (UnimplementedSeederServer) GetPieceTasks(context.Context, *base.PieceTaskRequest) (*base.PiecePacket, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetPieceTasks not implemented")
 }
+func (UnimplementedSeederServer) SyncPieceTasks(*base.PieceTaskRequest, Seeder_SyncPieceTasksServer) error {
+	return status.Errorf(codes.Unimplemented, "method SyncPieceTasks not implemented")
+}
 func (UnimplementedSeederServer) mustEmbedUnimplementedSeederServer() {}
 
 // UnsafeSeederServer may be embedded to opt out of forward compatibility for this service.
@@ -146,6 +185,27 @@ func _Seeder_GetPieceTasks_Handler(srv interface{}, ctx context.Context, dec fun
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Seeder_SyncPieceTasks_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(base.PieceTaskRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(SeederServer).SyncPieceTasks(m, &seederSyncPieceTasksServer{stream})
+}
+
+type Seeder_SyncPieceTasksServer interface {
+	Send(*base.PiecePacket) error
+	grpc.ServerStream
+}
+
+type seederSyncPieceTasksServer struct {
+	grpc.ServerStream
+}
+
+func (x *seederSyncPieceTasksServer) Send(m *base.PiecePacket) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 var _Seeder_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "cdnsystem.Seeder",
 	HandlerType: (*SeederServer)(nil),
@@ -161,6 +221,11 @@ var _Seeder_serviceDesc = grpc.ServiceDesc{
 			Handler:       _Seeder_ObtainSeeds_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "SyncPieceTasks",
+			Handler:       _Seeder_SyncPieceTasks_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "pkg/rpc/cdnsystem/cdnsystem.proto",
 }
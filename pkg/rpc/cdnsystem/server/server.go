@@ -41,6 +41,8 @@ type SeederServer interface {
 	ObtainSeeds(context.Context, *cdnsystem.SeedRequest, chan<- *cdnsystem.PieceSeed) error
 	// Get piece tasks from cdn
 	GetPieceTasks(context.Context, *base.PieceTaskRequest) (*base.PiecePacket, error)
+	// Subscribe piece tasks from cdn
+	SyncPieceTasks(*base.PieceTaskRequest, cdnsystem.Seeder_SyncPieceTasksServer) error
 }
 
 type proxy struct {
@@ -91,6 +93,10 @@ func (p *proxy) GetPieceTasks(ctx context.Context, ptr *base.PieceTaskRequest) (
 	return p.server.GetPieceTasks(ctx, ptr)
 }
 
+func (p *proxy) SyncPieceTasks(ptr *base.PieceTaskRequest, stream cdnsystem.Seeder_SyncPieceTasksServer) error {
+	return p.server.SyncPieceTasks(ptr, stream)
+}
+
 func send(psc chan *cdnsystem.PieceSeed, closePsc func(), stream cdnsystem.Seeder_ObtainSeedsServer, errChan chan error) {
 	err := safe.Call(func() {
 		defer closePsc()
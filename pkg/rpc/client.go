@@ -286,6 +286,15 @@ func (conn *Connection) GetServerNode(hashKey string) (string, bool) {
 	return "unknown", false
 }
 
+// GetServerNodes returns all server nodes of the connection
+func (conn *Connection) GetServerNodes() []dfnet.NetAddr {
+	conn.rwMutex.RLock()
+	defer conn.rwMutex.RUnlock()
+	nodes := make([]dfnet.NetAddr, len(conn.serverNodes))
+	copy(nodes, conn.serverNodes)
+	return nodes
+}
+
 func (conn *Connection) GetClientConnByTarget(node string) (*grpc.ClientConn, error) {
 	logger.GrpcLogger.With("conn", conn.name).Debugf("start to get client conn by target %s", node)
 	conn.rwMutex.RLock()
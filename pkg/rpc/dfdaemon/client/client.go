@@ -73,10 +73,26 @@ func GetElasticClientByAddrs(addrs []dfnet.NetAddr, opts ...grpc.DialOption) (Da
 type DaemonClient interface {
 	Download(ctx context.Context, req *dfdaemon.DownRequest, opts ...grpc.CallOption) (*DownResultStream, error)
 
+	DownloadStream(ctx context.Context, req *dfdaemon.StreamDownRequest, opts ...grpc.CallOption) (dfdaemon.Daemon_DownloadStreamClient, error)
+
 	GetPieceTasks(ctx context.Context, addr dfnet.NetAddr, ptr *base.PieceTaskRequest, opts ...grpc.CallOption) (*base.PiecePacket, error)
 
+	SyncPieceTasks(ctx context.Context, addr dfnet.NetAddr, ptr *base.PieceTaskRequest, opts ...grpc.CallOption) (dfdaemon.Daemon_SyncPieceTasksClient, error)
+
 	CheckHealth(ctx context.Context, target dfnet.NetAddr, opts ...grpc.CallOption) error
 
+	ListTasks(ctx context.Context, target dfnet.NetAddr, opts ...grpc.CallOption) (*dfdaemon.LocalTasks, error)
+
+	StatTask(ctx context.Context, target dfnet.NetAddr, req *dfdaemon.TaskRequest, opts ...grpc.CallOption) (*dfdaemon.LocalTasks, error)
+
+	DeleteTask(ctx context.Context, target dfnet.NetAddr, req *dfdaemon.TaskRequest, opts ...grpc.CallOption) error
+
+	PinTask(ctx context.Context, target dfnet.NetAddr, req *dfdaemon.PinTaskRequest, opts ...grpc.CallOption) error
+
+	ImportTask(ctx context.Context, target dfnet.NetAddr, req *dfdaemon.ImportTaskRequest, opts ...grpc.CallOption) (*dfdaemon.LocalTask, error)
+
+	ExportTask(ctx context.Context, target dfnet.NetAddr, req *dfdaemon.ExportTaskRequest, opts ...grpc.CallOption) (*dfdaemon.LocalTask, error)
+
 	Close() error
 }
 
@@ -107,6 +123,20 @@ func (dc *daemonClient) Download(ctx context.Context, req *dfdaemon.DownRequest,
 	return newDownResultStream(ctx, dc, taskID, req, opts)
 }
 
+func (dc *daemonClient) DownloadStream(ctx context.Context, req *dfdaemon.StreamDownRequest, opts ...grpc.CallOption) (dfdaemon.Daemon_DownloadStreamClient, error) {
+	taskID := idgen.TaskID(req.Url, req.UrlMeta)
+	client, target, err := dc.getDaemonClient(taskID, false)
+	if err != nil {
+		return nil, err
+	}
+	stream, err := client.DownloadStream(ctx, req, opts...)
+	if err != nil {
+		logger.WithTaskID(taskID).Infof("DownloadStream: invoke daemon node %s DownloadStream failed: %v", target, err)
+		return nil, err
+	}
+	return stream, nil
+}
+
 func (dc *daemonClient) GetPieceTasks(ctx context.Context, target dfnet.NetAddr, ptr *base.PieceTaskRequest, opts ...grpc.CallOption) (*base.PiecePacket,
 	error) {
 	res, err := rpc.ExecuteWithRetry(func() (interface{}, error) {
@@ -123,6 +153,19 @@ func (dc *daemonClient) GetPieceTasks(ctx context.Context, target dfnet.NetAddr,
 	return res.(*base.PiecePacket), nil
 }
 
+func (dc *daemonClient) SyncPieceTasks(ctx context.Context, target dfnet.NetAddr, ptr *base.PieceTaskRequest, opts ...grpc.CallOption) (dfdaemon.Daemon_SyncPieceTasksClient, error) {
+	client, err := dc.getDaemonClientWithTarget(target.GetEndpoint())
+	if err != nil {
+		return nil, err
+	}
+	stream, err := client.SyncPieceTasks(ctx, ptr, opts...)
+	if err != nil {
+		logger.WithTaskID(ptr.TaskId).Infof("SyncPieceTasks: invoke daemon node %s SyncPieceTasks failed: %v", target, err)
+		return nil, err
+	}
+	return stream, nil
+}
+
 func (dc *daemonClient) CheckHealth(ctx context.Context, target dfnet.NetAddr, opts ...grpc.CallOption) (err error) {
 	_, err = rpc.ExecuteWithRetry(func() (interface{}, error) {
 		client, err := dc.getDaemonClientWithTarget(target.GetEndpoint())
@@ -137,3 +180,91 @@ func (dc *daemonClient) CheckHealth(ctx context.Context, target dfnet.NetAddr, o
 	}
 	return
 }
+
+func (dc *daemonClient) ListTasks(ctx context.Context, target dfnet.NetAddr, opts ...grpc.CallOption) (*dfdaemon.LocalTasks, error) {
+	res, err := rpc.ExecuteWithRetry(func() (interface{}, error) {
+		client, err := dc.getDaemonClientWithTarget(target.GetEndpoint())
+		if err != nil {
+			return nil, err
+		}
+		return client.ListTasks(ctx, new(empty.Empty), opts...)
+	}, 0.2, 2.0, 3, nil)
+	if err != nil {
+		logger.Infof("ListTasks: invoke daemon node %s ListTasks failed: %v", target, err)
+		return nil, err
+	}
+	return res.(*dfdaemon.LocalTasks), nil
+}
+
+func (dc *daemonClient) StatTask(ctx context.Context, target dfnet.NetAddr, req *dfdaemon.TaskRequest, opts ...grpc.CallOption) (*dfdaemon.LocalTasks, error) {
+	res, err := rpc.ExecuteWithRetry(func() (interface{}, error) {
+		client, err := dc.getDaemonClientWithTarget(target.GetEndpoint())
+		if err != nil {
+			return nil, err
+		}
+		return client.StatTask(ctx, req, opts...)
+	}, 0.2, 2.0, 3, nil)
+	if err != nil {
+		logger.Infof("StatTask: invoke daemon node %s StatTask failed: %v", target, err)
+		return nil, err
+	}
+	return res.(*dfdaemon.LocalTasks), nil
+}
+
+func (dc *daemonClient) DeleteTask(ctx context.Context, target dfnet.NetAddr, req *dfdaemon.TaskRequest, opts ...grpc.CallOption) error {
+	_, err := rpc.ExecuteWithRetry(func() (interface{}, error) {
+		client, err := dc.getDaemonClientWithTarget(target.GetEndpoint())
+		if err != nil {
+			return nil, err
+		}
+		return client.DeleteTask(ctx, req, opts...)
+	}, 0.2, 2.0, 3, nil)
+	if err != nil {
+		logger.Infof("DeleteTask: invoke daemon node %s DeleteTask failed: %v", target, err)
+	}
+	return err
+}
+
+func (dc *daemonClient) PinTask(ctx context.Context, target dfnet.NetAddr, req *dfdaemon.PinTaskRequest, opts ...grpc.CallOption) error {
+	_, err := rpc.ExecuteWithRetry(func() (interface{}, error) {
+		client, err := dc.getDaemonClientWithTarget(target.GetEndpoint())
+		if err != nil {
+			return nil, err
+		}
+		return client.PinTask(ctx, req, opts...)
+	}, 0.2, 2.0, 3, nil)
+	if err != nil {
+		logger.Infof("PinTask: invoke daemon node %s PinTask failed: %v", target, err)
+	}
+	return err
+}
+
+func (dc *daemonClient) ImportTask(ctx context.Context, target dfnet.NetAddr, req *dfdaemon.ImportTaskRequest, opts ...grpc.CallOption) (*dfdaemon.LocalTask, error) {
+	res, err := rpc.ExecuteWithRetry(func() (interface{}, error) {
+		client, err := dc.getDaemonClientWithTarget(target.GetEndpoint())
+		if err != nil {
+			return nil, err
+		}
+		return client.ImportTask(ctx, req, opts...)
+	}, 0.2, 2.0, 3, nil)
+	if err != nil {
+		logger.Infof("ImportTask: invoke daemon node %s ImportTask failed: %v", target, err)
+		return nil, err
+	}
+	return res.(*dfdaemon.LocalTask), nil
+}
+
+func (dc *daemonClient) ExportTask(ctx context.Context, target dfnet.NetAddr, req *dfdaemon.ExportTaskRequest, opts ...grpc.CallOption) (*dfdaemon.LocalTask, error) {
+	res, err := rpc.ExecuteWithRetry(func() (interface{}, error) {
+		client, err := dc.getDaemonClientWithTarget(target.GetEndpoint())
+		if err != nil {
+			return nil, err
+		}
+		return client.ExportTask(ctx, req, opts...)
+	}, 0.2, 2.0, 3, nil)
+	if err != nil {
+		logger.Infof("ExportTask: invoke daemon node %s ExportTask failed: %v", target, err)
+		return nil, err
+	}
+	return res.(*dfdaemon.LocalTask), nil
+}
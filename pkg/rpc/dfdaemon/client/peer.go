@@ -50,6 +50,29 @@ func GetPieceTasks(ctx context.Context, destPeer *scheduler.PeerPacket_DestPeer,
 	return client.(DaemonClient).GetPieceTasks(ctx, netAddr, ptr, opts...)
 }
 
+// PieceTaskSyncClient receives piece packets pushed by dest peer
+type PieceTaskSyncClient interface {
+	Recv() (*base.PiecePacket, error)
+}
+
+// SyncPieceTasks subscribes pieces of dest peer, new pieces are pushed when they are ready
+func SyncPieceTasks(ctx context.Context, destPeer *scheduler.PeerPacket_DestPeer, ptr *base.PieceTaskRequest, opts ...grpc.CallOption) (PieceTaskSyncClient, error) {
+	destAddr := fmt.Sprintf("%s:%d", destPeer.Ip, destPeer.RpcPort)
+	toCdn := strings.HasSuffix(destPeer.PeerId, common.CdnSuffix)
+	netAddr := dfnet.NetAddr{
+		Type: dfnet.TCP,
+		Addr: destAddr,
+	}
+	client, err := getClient(netAddr, toCdn)
+	if err != nil {
+		return nil, err
+	}
+	if toCdn {
+		return client.(cdnclient.CdnClient).SyncPieceTasks(ctx, netAddr, ptr, opts...)
+	}
+	return client.(DaemonClient).SyncPieceTasks(ctx, netAddr, ptr, opts...)
+}
+
 func getClient(netAddr dfnet.NetAddr, toCdn bool) (rpc.Closer, error) {
 	if toCdn {
 		return cdnclient.GetElasticClientByAddrs([]dfnet.NetAddr{netAddr})
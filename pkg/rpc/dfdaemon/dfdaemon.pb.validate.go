@@ -176,6 +176,8 @@ func (m *DownRequest) validate(all bool) error {
 
 	// no validation rules for Gid
 
+	// no validation rules for Resume
+
 	if len(errors) > 0 {
 		return DownRequestMultiError(errors)
 	}
@@ -323,6 +325,22 @@ func (m *DownResult) validate(all bool) error {
 
 	// no validation rules for Done
 
+	// no validation rules for ContentLength
+
+	// no validation rules for TotalPieces
+
+	// no validation rules for FinishedPieces
+
+	// no validation rules for Source
+
+	// no validation rules for PeerTraffic
+
+	// no validation rules for CdnTraffic
+
+	// no validation rules for SourceTraffic
+
+	// no validation rules for PieceMd5Sign
+
 	if len(errors) > 0 {
 		return DownResultMultiError(errors)
 	}
@@ -398,3 +416,1408 @@ var _ interface {
 	Cause() error
 	ErrorName() string
 } = DownResultValidationError{}
+
+// Validate checks the field values on StreamDownRequest with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// first error encountered is returned, or nil if there are no violations.
+func (m *StreamDownRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on StreamDownRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// StreamDownRequestMultiError, or nil if none found.
+func (m *StreamDownRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *StreamDownRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if uri, err := url.Parse(m.GetUrl()); err != nil {
+		err = StreamDownRequestValidationError{
+			field:  "Url",
+			reason: "value must be a valid URI",
+			cause:  err,
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	} else if !uri.IsAbs() {
+		err := StreamDownRequestValidationError{
+			field:  "Url",
+			reason: "value must be absolute",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if all {
+		switch v := interface{}(m.GetUrlMeta()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, StreamDownRequestValidationError{
+					field:  "UrlMeta",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, StreamDownRequestValidationError{
+					field:  "UrlMeta",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetUrlMeta()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return StreamDownRequestValidationError{
+				field:  "UrlMeta",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	if m.GetOffset() < 0 {
+		err := StreamDownRequestValidationError{
+			field:  "Offset",
+			reason: "value must be greater than or equal to 0",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if m.GetLength() < 0 {
+		err := StreamDownRequestValidationError{
+			field:  "Length",
+			reason: "value must be greater than or equal to 0",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if len(errors) > 0 {
+		return StreamDownRequestMultiError(errors)
+	}
+	return nil
+}
+
+// StreamDownRequestMultiError is an error wrapping multiple validation errors
+// returned by StreamDownRequest.ValidateAll() if the designated constraints
+// aren't met.
+type StreamDownRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m StreamDownRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m StreamDownRequestMultiError) AllErrors() []error { return m }
+
+// StreamDownRequestValidationError is the validation error returned by
+// StreamDownRequest.Validate if the designated constraints aren't met.
+type StreamDownRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e StreamDownRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e StreamDownRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e StreamDownRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e StreamDownRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e StreamDownRequestValidationError) ErrorName() string {
+	return "StreamDownRequestValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e StreamDownRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sStreamDownRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = StreamDownRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = StreamDownRequestValidationError{}
+
+// Validate checks the field values on StreamDownResult with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// first error encountered is returned, or nil if there are no violations.
+func (m *StreamDownResult) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on StreamDownResult with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// StreamDownResultMultiError, or nil if none found.
+func (m *StreamDownResult) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *StreamDownResult) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for TaskId
+
+	// no validation rules for PeerId
+
+	// no validation rules for ContentLength
+
+	// no validation rules for Content
+
+	if len(errors) > 0 {
+		return StreamDownResultMultiError(errors)
+	}
+	return nil
+}
+
+// StreamDownResultMultiError is an error wrapping multiple validation errors
+// returned by StreamDownResult.ValidateAll() if the designated constraints
+// aren't met.
+type StreamDownResultMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m StreamDownResultMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m StreamDownResultMultiError) AllErrors() []error { return m }
+
+// StreamDownResultValidationError is the validation error returned by
+// StreamDownResult.Validate if the designated constraints aren't met.
+type StreamDownResultValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e StreamDownResultValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e StreamDownResultValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e StreamDownResultValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e StreamDownResultValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e StreamDownResultValidationError) ErrorName() string { return "StreamDownResultValidationError" }
+
+// Error satisfies the builtin error interface
+func (e StreamDownResultValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sStreamDownResult.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = StreamDownResultValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = StreamDownResultValidationError{}
+
+// Validate checks the field values on PieceDownloadRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *PieceDownloadRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on PieceDownloadRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// PieceDownloadRequestMultiError, or nil if none found.
+func (m *PieceDownloadRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *PieceDownloadRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if utf8.RuneCountInString(m.GetTaskId()) < 1 {
+		err := PieceDownloadRequestValidationError{
+			field:  "TaskId",
+			reason: "value length must be at least 1 runes",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	// no validation rules for SrcPid
+
+	if utf8.RuneCountInString(m.GetDstPid()) < 1 {
+		err := PieceDownloadRequestValidationError{
+			field:  "DstPid",
+			reason: "value length must be at least 1 runes",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if m.GetPieceNum() < 0 {
+		err := PieceDownloadRequestValidationError{
+			field:  "PieceNum",
+			reason: "value must be greater than or equal to 0",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	// no validation rules for RangeStart
+
+	if m.GetRangeSize() <= 0 {
+		err := PieceDownloadRequestValidationError{
+			field:  "RangeSize",
+			reason: "value must be greater than 0",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if len(errors) > 0 {
+		return PieceDownloadRequestMultiError(errors)
+	}
+	return nil
+}
+
+// PieceDownloadRequestMultiError is an error wrapping multiple validation
+// errors returned by PieceDownloadRequest.ValidateAll() if the designated
+// constraints aren't met.
+type PieceDownloadRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m PieceDownloadRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m PieceDownloadRequestMultiError) AllErrors() []error { return m }
+
+// PieceDownloadRequestValidationError is the validation error returned by
+// PieceDownloadRequest.Validate if the designated constraints aren't met.
+type PieceDownloadRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e PieceDownloadRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e PieceDownloadRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e PieceDownloadRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e PieceDownloadRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e PieceDownloadRequestValidationError) ErrorName() string {
+	return "PieceDownloadRequestValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e PieceDownloadRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sPieceDownloadRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = PieceDownloadRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = PieceDownloadRequestValidationError{}
+
+// Validate checks the field values on PieceData with the rules defined in the
+// proto definition for this message. If any rules are violated, the first
+// error encountered is returned, or nil if there are no violations.
+func (m *PieceData) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on PieceData with the rules defined in
+// the proto definition for this message. If any rules are violated, the
+// result is a list of violation errors wrapped in PieceDataMultiError, or nil
+// if none found.
+func (m *PieceData) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *PieceData) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for PieceNum
+
+	// no validation rules for Content
+
+	// no validation rules for Done
+
+	if len(errors) > 0 {
+		return PieceDataMultiError(errors)
+	}
+	return nil
+}
+
+// PieceDataMultiError is an error wrapping multiple validation errors returned
+// by PieceData.ValidateAll() if the designated constraints aren't met.
+type PieceDataMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m PieceDataMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m PieceDataMultiError) AllErrors() []error { return m }
+
+// PieceDataValidationError is the validation error returned by
+// PieceData.Validate if the designated constraints aren't met.
+type PieceDataValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e PieceDataValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e PieceDataValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e PieceDataValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e PieceDataValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e PieceDataValidationError) ErrorName() string { return "PieceDataValidationError" }
+
+// Error satisfies the builtin error interface
+func (e PieceDataValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sPieceData.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = PieceDataValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = PieceDataValidationError{}
+
+// Validate checks the field values on TaskRequest with the rules defined in
+// the proto definition for this message. If any rules are violated, the first
+// error encountered is returned, or nil if there are no violations.
+func (m *TaskRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on TaskRequest with the rules defined in
+// the proto definition for this message. If any rules are violated, the
+// result is a list of violation errors wrapped in TaskRequestMultiError, or
+// nil if none found.
+func (m *TaskRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *TaskRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for TaskId
+
+	// no validation rules for Url
+
+	if all {
+		switch v := interface{}(m.GetUrlMeta()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, TaskRequestValidationError{
+					field:  "UrlMeta",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, TaskRequestValidationError{
+					field:  "UrlMeta",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetUrlMeta()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return TaskRequestValidationError{
+				field:  "UrlMeta",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	// no validation rules for Cluster
+
+	if len(errors) > 0 {
+		return TaskRequestMultiError(errors)
+	}
+	return nil
+}
+
+// TaskRequestMultiError is an error wrapping multiple validation errors
+// returned by TaskRequest.ValidateAll() if the designated constraints aren't met.
+type TaskRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m TaskRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m TaskRequestMultiError) AllErrors() []error { return m }
+
+// TaskRequestValidationError is the validation error returned by
+// TaskRequest.Validate if the designated constraints aren't met.
+type TaskRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e TaskRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e TaskRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e TaskRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e TaskRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e TaskRequestValidationError) ErrorName() string { return "TaskRequestValidationError" }
+
+// Error satisfies the builtin error interface
+func (e TaskRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sTaskRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = TaskRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = TaskRequestValidationError{}
+
+// Validate checks the field values on PinTaskRequest with the rules defined in
+// the proto definition for this message. If any rules are violated, the first
+// error encountered is returned, or nil if there are no violations.
+func (m *PinTaskRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on PinTaskRequest with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// result is a list of violation errors wrapped in PinTaskRequestMultiError,
+// or nil if none found.
+func (m *PinTaskRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *PinTaskRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if m.GetTask() == nil {
+		err := PinTaskRequestValidationError{
+			field:  "Task",
+			reason: "value is required",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if all {
+		switch v := interface{}(m.GetTask()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, PinTaskRequestValidationError{
+					field:  "Task",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, PinTaskRequestValidationError{
+					field:  "Task",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetTask()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return PinTaskRequestValidationError{
+				field:  "Task",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	// no validation rules for Pin
+
+	if len(errors) > 0 {
+		return PinTaskRequestMultiError(errors)
+	}
+	return nil
+}
+
+// PinTaskRequestMultiError is an error wrapping multiple validation errors
+// returned by PinTaskRequest.ValidateAll() if the designated constraints
+// aren't met.
+type PinTaskRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m PinTaskRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m PinTaskRequestMultiError) AllErrors() []error { return m }
+
+// PinTaskRequestValidationError is the validation error returned by
+// PinTaskRequest.Validate if the designated constraints aren't met.
+type PinTaskRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e PinTaskRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e PinTaskRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e PinTaskRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e PinTaskRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e PinTaskRequestValidationError) ErrorName() string { return "PinTaskRequestValidationError" }
+
+// Error satisfies the builtin error interface
+func (e PinTaskRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sPinTaskRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = PinTaskRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = PinTaskRequestValidationError{}
+
+// Validate checks the field values on LocalTask with the rules defined in the
+// proto definition for this message. If any rules are violated, the first
+// error encountered is returned, or nil if there are no violations.
+func (m *LocalTask) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on LocalTask with the rules defined in
+// the proto definition for this message. If any rules are violated, the
+// result is a list of violation errors wrapped in LocalTaskMultiError, or nil
+// if none found.
+func (m *LocalTask) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *LocalTask) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for TaskId
+
+	// no validation rules for PeerId
+
+	// no validation rules for ContentLength
+
+	// no validation rules for TotalPiece
+
+	// no validation rules for CompletedLength
+
+	// no validation rules for Done
+
+	// no validation rules for Pinned
+
+	// no validation rules for CreateTime
+
+	// no validation rules for LastAccessTime
+
+	if len(errors) > 0 {
+		return LocalTaskMultiError(errors)
+	}
+	return nil
+}
+
+// LocalTaskMultiError is an error wrapping multiple validation errors returned
+// by LocalTask.ValidateAll() if the designated constraints aren't met.
+type LocalTaskMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m LocalTaskMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m LocalTaskMultiError) AllErrors() []error { return m }
+
+// LocalTaskValidationError is the validation error returned by
+// LocalTask.Validate if the designated constraints aren't met.
+type LocalTaskValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e LocalTaskValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e LocalTaskValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e LocalTaskValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e LocalTaskValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e LocalTaskValidationError) ErrorName() string { return "LocalTaskValidationError" }
+
+// Error satisfies the builtin error interface
+func (e LocalTaskValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sLocalTask.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = LocalTaskValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = LocalTaskValidationError{}
+
+// Validate checks the field values on LocalTasks with the rules defined in the
+// proto definition for this message. If any rules are violated, the first
+// error encountered is returned, or nil if there are no violations.
+func (m *LocalTasks) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on LocalTasks with the rules defined in
+// the proto definition for this message. If any rules are violated, the
+// result is a list of violation errors wrapped in LocalTasksMultiError, or
+// nil if none found.
+func (m *LocalTasks) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *LocalTasks) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	for idx, item := range m.GetTasks() {
+		_, _ = idx, item
+
+		if all {
+			switch v := interface{}(item).(type) {
+			case interface{ ValidateAll() error }:
+				if err := v.ValidateAll(); err != nil {
+					errors = append(errors, LocalTasksValidationError{
+						field:  fmt.Sprintf("Tasks[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			case interface{ Validate() error }:
+				if err := v.Validate(); err != nil {
+					errors = append(errors, LocalTasksValidationError{
+						field:  fmt.Sprintf("Tasks[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			}
+		} else if v, ok := interface{}(item).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return LocalTasksValidationError{
+					field:  fmt.Sprintf("Tasks[%v]", idx),
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	}
+
+	if all {
+		switch v := interface{}(m.GetCluster()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, LocalTasksValidationError{
+					field:  "Cluster",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, LocalTasksValidationError{
+					field:  "Cluster",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetCluster()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return LocalTasksValidationError{
+				field:  "Cluster",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	if len(errors) > 0 {
+		return LocalTasksMultiError(errors)
+	}
+	return nil
+}
+
+// LocalTasksMultiError is an error wrapping multiple validation errors
+// returned by LocalTasks.ValidateAll() if the designated constraints aren't met.
+type LocalTasksMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m LocalTasksMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m LocalTasksMultiError) AllErrors() []error { return m }
+
+// LocalTasksValidationError is the validation error returned by
+// LocalTasks.Validate if the designated constraints aren't met.
+type LocalTasksValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e LocalTasksValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e LocalTasksValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e LocalTasksValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e LocalTasksValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e LocalTasksValidationError) ErrorName() string { return "LocalTasksValidationError" }
+
+// Error satisfies the builtin error interface
+func (e LocalTasksValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sLocalTasks.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = LocalTasksValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = LocalTasksValidationError{}
+
+// Validate checks the field values on ImportTaskRequest with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// first error encountered is returned, or nil if there are no violations.
+func (m *ImportTaskRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on ImportTaskRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// ImportTaskRequestMultiError, or nil if none found.
+func (m *ImportTaskRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *ImportTaskRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if utf8.RuneCountInString(m.GetUrl()) < 1 {
+		err := ImportTaskRequestValidationError{
+			field:  "Url",
+			reason: "value length must be at least 1 runes",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if all {
+		switch v := interface{}(m.GetUrlMeta()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, ImportTaskRequestValidationError{
+					field:  "UrlMeta",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, ImportTaskRequestValidationError{
+					field:  "UrlMeta",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetUrlMeta()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return ImportTaskRequestValidationError{
+				field:  "UrlMeta",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	if utf8.RuneCountInString(m.GetPath()) < 1 {
+		err := ImportTaskRequestValidationError{
+			field:  "Path",
+			reason: "value length must be at least 1 runes",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if len(errors) > 0 {
+		return ImportTaskRequestMultiError(errors)
+	}
+	return nil
+}
+
+// ImportTaskRequestMultiError is an error wrapping multiple validation errors
+// returned by ImportTaskRequest.ValidateAll() if the designated constraints
+// aren't met.
+type ImportTaskRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m ImportTaskRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m ImportTaskRequestMultiError) AllErrors() []error { return m }
+
+// ImportTaskRequestValidationError is the validation error returned by
+// ImportTaskRequest.Validate if the designated constraints aren't met.
+type ImportTaskRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e ImportTaskRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e ImportTaskRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e ImportTaskRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e ImportTaskRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e ImportTaskRequestValidationError) ErrorName() string {
+	return "ImportTaskRequestValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e ImportTaskRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sImportTaskRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = ImportTaskRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = ImportTaskRequestValidationError{}
+
+// Validate checks the field values on ExportTaskRequest with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// first error encountered is returned, or nil if there are no violations.
+func (m *ExportTaskRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on ExportTaskRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// ExportTaskRequestMultiError, or nil if none found.
+func (m *ExportTaskRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *ExportTaskRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if m.GetTask() == nil {
+		err := ExportTaskRequestValidationError{
+			field:  "Task",
+			reason: "value is required",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if all {
+		switch v := interface{}(m.GetTask()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, ExportTaskRequestValidationError{
+					field:  "Task",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, ExportTaskRequestValidationError{
+					field:  "Task",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetTask()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return ExportTaskRequestValidationError{
+				field:  "Task",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	if utf8.RuneCountInString(m.GetOutput()) < 1 {
+		err := ExportTaskRequestValidationError{
+			field:  "Output",
+			reason: "value length must be at least 1 runes",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	// no validation rules for LocalOnly
+
+	if m.GetLimit() < 0 {
+		err := ExportTaskRequestValidationError{
+			field:  "Limit",
+			reason: "value must be greater than or equal to 0",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	// no validation rules for Uid
+
+	// no validation rules for Gid
+
+	if len(errors) > 0 {
+		return ExportTaskRequestMultiError(errors)
+	}
+	return nil
+}
+
+// ExportTaskRequestMultiError is an error wrapping multiple validation errors
+// returned by ExportTaskRequest.ValidateAll() if the designated constraints
+// aren't met.
+type ExportTaskRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m ExportTaskRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m ExportTaskRequestMultiError) AllErrors() []error { return m }
+
+// ExportTaskRequestValidationError is the validation error returned by
+// ExportTaskRequest.Validate if the designated constraints aren't met.
+type ExportTaskRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e ExportTaskRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e ExportTaskRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e ExportTaskRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e ExportTaskRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e ExportTaskRequestValidationError) ErrorName() string {
+	return "ExportTaskRequestValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e ExportTaskRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sExportTaskRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = ExportTaskRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = ExportTaskRequestValidationError{}
@@ -21,10 +21,28 @@ const _ = grpc.SupportPackageIsVersion7
 type DaemonClient interface {
 	// Trigger client to download file
 	Download(ctx context.Context, in *DownRequest, opts ...grpc.CallOption) (Daemon_DownloadClient, error)
+	// Trigger client to download file and send the content back in stream, the content is cached in daemon too
+	DownloadStream(ctx context.Context, in *StreamDownRequest, opts ...grpc.CallOption) (Daemon_DownloadStreamClient, error)
 	// Get piece tasks from other peers
 	GetPieceTasks(ctx context.Context, in *base.PieceTaskRequest, opts ...grpc.CallOption) (*base.PiecePacket, error)
 	// Check daemon health
 	CheckHealth(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// Download pieces from other peers, pieces requested in one stream are sent one by one
+	DownloadPieces(ctx context.Context, opts ...grpc.CallOption) (Daemon_DownloadPiecesClient, error)
+	// Subscribe piece tasks from other peers, new pieces are pushed when they are ready
+	SyncPieceTasks(ctx context.Context, in *base.PieceTaskRequest, opts ...grpc.CallOption) (Daemon_SyncPieceTasksClient, error)
+	// List tasks cached in local storage, only for local callers
+	ListTasks(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*LocalTasks, error)
+	// Stat peer tasks of a task in local storage, only for local callers
+	StatTask(ctx context.Context, in *TaskRequest, opts ...grpc.CallOption) (*LocalTasks, error)
+	// Delete a task from local storage, only for local callers
+	DeleteTask(ctx context.Context, in *TaskRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// Pin or unpin a task in local storage, only for local callers
+	PinTask(ctx context.Context, in *PinTaskRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// Import a local file as a completed task and seed it to other peers, only for local callers
+	ImportTask(ctx context.Context, in *ImportTaskRequest, opts ...grpc.CallOption) (*LocalTask, error)
+	// Export a cached task to a local file without back source, only for local callers
+	ExportTask(ctx context.Context, in *ExportTaskRequest, opts ...grpc.CallOption) (*LocalTask, error)
 }
 
 type daemonClient struct {
@@ -67,6 +85,38 @@ func (x *daemonDownloadClient) Recv() (*DownResult, error) {
 	return m, nil
 }
 
+func (c *daemonClient) DownloadStream(ctx context.Context, in *StreamDownRequest, opts ...grpc.CallOption) (Daemon_DownloadStreamClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Daemon_serviceDesc.Streams[1], "/dfdaemon.Daemon/DownloadStream", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &daemonDownloadStreamClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Daemon_DownloadStreamClient interface {
+	Recv() (*StreamDownResult, error)
+	grpc.ClientStream
+}
+
+type daemonDownloadStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *daemonDownloadStreamClient) Recv() (*StreamDownResult, error) {
+	m := new(StreamDownResult)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func (c *daemonClient) GetPieceTasks(ctx context.Context, in *base.PieceTaskRequest, opts ...grpc.CallOption) (*base.PiecePacket, error) {
 	out := new(base.PiecePacket)
 	err := c.cc.Invoke(ctx, "/dfdaemon.Daemon/GetPieceTasks", in, out, opts...)
@@ -85,16 +135,151 @@ func (c *daemonClient) CheckHealth(ctx context.Context, in *emptypb.Empty, opts
 	return out, nil
 }
 
+func (c *daemonClient) DownloadPieces(ctx context.Context, opts ...grpc.CallOption) (Daemon_DownloadPiecesClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Daemon_serviceDesc.Streams[2], "/dfdaemon.Daemon/DownloadPieces", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &daemonDownloadPiecesClient{stream}
+	return x, nil
+}
+
+type Daemon_DownloadPiecesClient interface {
+	Send(*PieceDownloadRequest) error
+	Recv() (*PieceData, error)
+	grpc.ClientStream
+}
+
+type daemonDownloadPiecesClient struct {
+	grpc.ClientStream
+}
+
+func (x *daemonDownloadPiecesClient) Send(m *PieceDownloadRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *daemonDownloadPiecesClient) Recv() (*PieceData, error) {
+	m := new(PieceData)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *daemonClient) SyncPieceTasks(ctx context.Context, in *base.PieceTaskRequest, opts ...grpc.CallOption) (Daemon_SyncPieceTasksClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Daemon_serviceDesc.Streams[3], "/dfdaemon.Daemon/SyncPieceTasks", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &daemonSyncPieceTasksClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Daemon_SyncPieceTasksClient interface {
+	Recv() (*base.PiecePacket, error)
+	grpc.ClientStream
+}
+
+type daemonSyncPieceTasksClient struct {
+	grpc.ClientStream
+}
+
+func (x *daemonSyncPieceTasksClient) Recv() (*base.PiecePacket, error) {
+	m := new(base.PiecePacket)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *daemonClient) ListTasks(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*LocalTasks, error) {
+	out := new(LocalTasks)
+	err := c.cc.Invoke(ctx, "/dfdaemon.Daemon/ListTasks", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *daemonClient) StatTask(ctx context.Context, in *TaskRequest, opts ...grpc.CallOption) (*LocalTasks, error) {
+	out := new(LocalTasks)
+	err := c.cc.Invoke(ctx, "/dfdaemon.Daemon/StatTask", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *daemonClient) DeleteTask(ctx context.Context, in *TaskRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/dfdaemon.Daemon/DeleteTask", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *daemonClient) PinTask(ctx context.Context, in *PinTaskRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/dfdaemon.Daemon/PinTask", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *daemonClient) ImportTask(ctx context.Context, in *ImportTaskRequest, opts ...grpc.CallOption) (*LocalTask, error) {
+	out := new(LocalTask)
+	err := c.cc.Invoke(ctx, "/dfdaemon.Daemon/ImportTask", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *daemonClient) ExportTask(ctx context.Context, in *ExportTaskRequest, opts ...grpc.CallOption) (*LocalTask, error) {
+	out := new(LocalTask)
+	err := c.cc.Invoke(ctx, "/dfdaemon.Daemon/ExportTask", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // DaemonServer is the server API for Daemon service.
 // All implementations must embed UnimplementedDaemonServer
 // for forward compatibility
 type DaemonServer interface {
 	// Trigger client to download file
 	Download(*DownRequest, Daemon_DownloadServer) error
+	// Trigger client to download file and send the content back in stream, the content is cached in daemon too
+	DownloadStream(*StreamDownRequest, Daemon_DownloadStreamServer) error
 	// Get piece tasks from other peers
 	GetPieceTasks(context.Context, *base.PieceTaskRequest) (*base.PiecePacket, error)
 	// Check daemon health
 	CheckHealth(context.Context, *emptypb.Empty) (*emptypb.Empty, error)
+	// Download pieces from other peers, pieces requested in one stream are sent one by one
+	DownloadPieces(Daemon_DownloadPiecesServer) error
+	// Subscribe piece tasks from other peers, new pieces are pushed when they are ready
+	SyncPieceTasks(*base.PieceTaskRequest, Daemon_SyncPieceTasksServer) error
+	// List tasks cached in local storage, only for local callers
+	ListTasks(context.Context, *emptypb.Empty) (*LocalTasks, error)
+	// Stat peer tasks of a task in local storage, only for local callers
+	StatTask(context.Context, *TaskRequest) (*LocalTasks, error)
+	// Delete a task from local storage, only for local callers
+	DeleteTask(context.Context, *TaskRequest) (*emptypb.Empty, error)
+	// Pin or unpin a task in local storage, only for local callers
+	PinTask(context.Context, *PinTaskRequest) (*emptypb.Empty, error)
+	// Import a local file as a completed task and seed it to other peers, only for local callers
+	ImportTask(context.Context, *ImportTaskRequest) (*LocalTask, error)
+	// Export a cached task to a local file without back source, only for local callers
+	ExportTask(context.Context, *ExportTaskRequest) (*LocalTask, error)
 	mustEmbedUnimplementedDaemonServer()
 }
 
@@ -105,12 +290,39 @@ type UnimplementedDaemonServer struct {
 func (UnimplementedDaemonServer) Download(*DownRequest, Daemon_DownloadServer) error {
 	return status.Errorf(codes.Unimplemented, "method Download not implemented")
 }
+func (UnimplementedDaemonServer) DownloadStream(*StreamDownRequest, Daemon_DownloadStreamServer) error {
+	return status.Errorf(codes.Unimplemented, "method DownloadStream not implemented")
+}
 func (UnimplementedDaemonServer) GetPieceTasks(context.Context, *base.PieceTaskRequest) (*base.PiecePacket, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetPieceTasks not implemented")
 }
 func (UnimplementedDaemonServer) CheckHealth(context.Context, *emptypb.Empty) (*emptypb.Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method CheckHealth not implemented")
 }
+func (UnimplementedDaemonServer) DownloadPieces(Daemon_DownloadPiecesServer) error {
+	return status.Errorf(codes.Unimplemented, "method DownloadPieces not implemented")
+}
+func (UnimplementedDaemonServer) SyncPieceTasks(*base.PieceTaskRequest, Daemon_SyncPieceTasksServer) error {
+	return status.Errorf(codes.Unimplemented, "method SyncPieceTasks not implemented")
+}
+func (UnimplementedDaemonServer) ListTasks(context.Context, *emptypb.Empty) (*LocalTasks, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTasks not implemented")
+}
+func (UnimplementedDaemonServer) StatTask(context.Context, *TaskRequest) (*LocalTasks, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StatTask not implemented")
+}
+func (UnimplementedDaemonServer) DeleteTask(context.Context, *TaskRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteTask not implemented")
+}
+func (UnimplementedDaemonServer) PinTask(context.Context, *PinTaskRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PinTask not implemented")
+}
+func (UnimplementedDaemonServer) ImportTask(context.Context, *ImportTaskRequest) (*LocalTask, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ImportTask not implemented")
+}
+func (UnimplementedDaemonServer) ExportTask(context.Context, *ExportTaskRequest) (*LocalTask, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExportTask not implemented")
+}
 func (UnimplementedDaemonServer) mustEmbedUnimplementedDaemonServer() {}
 
 // UnsafeDaemonServer may be embedded to opt out of forward compatibility for this service.
@@ -145,6 +357,27 @@ func (x *daemonDownloadServer) Send(m *DownResult) error {
 	return x.ServerStream.SendMsg(m)
 }
 
+func _Daemon_DownloadStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamDownRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(DaemonServer).DownloadStream(m, &daemonDownloadStreamServer{stream})
+}
+
+type Daemon_DownloadStreamServer interface {
+	Send(*StreamDownResult) error
+	grpc.ServerStream
+}
+
+type daemonDownloadStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *daemonDownloadStreamServer) Send(m *StreamDownResult) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 func _Daemon_GetPieceTasks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(base.PieceTaskRequest)
 	if err := dec(in); err != nil {
@@ -181,6 +414,161 @@ func _Daemon_CheckHealth_Handler(srv interface{}, ctx context.Context, dec func(
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Daemon_DownloadPieces_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(DaemonServer).DownloadPieces(&daemonDownloadPiecesServer{stream})
+}
+
+type Daemon_DownloadPiecesServer interface {
+	Send(*PieceData) error
+	Recv() (*PieceDownloadRequest, error)
+	grpc.ServerStream
+}
+
+type daemonDownloadPiecesServer struct {
+	grpc.ServerStream
+}
+
+func (x *daemonDownloadPiecesServer) Send(m *PieceData) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *daemonDownloadPiecesServer) Recv() (*PieceDownloadRequest, error) {
+	m := new(PieceDownloadRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _Daemon_SyncPieceTasks_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(base.PieceTaskRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(DaemonServer).SyncPieceTasks(m, &daemonSyncPieceTasksServer{stream})
+}
+
+type Daemon_SyncPieceTasksServer interface {
+	Send(*base.PiecePacket) error
+	grpc.ServerStream
+}
+
+type daemonSyncPieceTasksServer struct {
+	grpc.ServerStream
+}
+
+func (x *daemonSyncPieceTasksServer) Send(m *base.PiecePacket) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Daemon_ListTasks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DaemonServer).ListTasks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dfdaemon.Daemon/ListTasks",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DaemonServer).ListTasks(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Daemon_StatTask_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TaskRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DaemonServer).StatTask(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dfdaemon.Daemon/StatTask",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DaemonServer).StatTask(ctx, req.(*TaskRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Daemon_DeleteTask_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TaskRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DaemonServer).DeleteTask(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dfdaemon.Daemon/DeleteTask",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DaemonServer).DeleteTask(ctx, req.(*TaskRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Daemon_PinTask_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PinTaskRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DaemonServer).PinTask(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dfdaemon.Daemon/PinTask",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DaemonServer).PinTask(ctx, req.(*PinTaskRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Daemon_ImportTask_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ImportTaskRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DaemonServer).ImportTask(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dfdaemon.Daemon/ImportTask",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DaemonServer).ImportTask(ctx, req.(*ImportTaskRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Daemon_ExportTask_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExportTaskRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DaemonServer).ExportTask(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dfdaemon.Daemon/ExportTask",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DaemonServer).ExportTask(ctx, req.(*ExportTaskRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _Daemon_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "dfdaemon.Daemon",
 	HandlerType: (*DaemonServer)(nil),
@@ -193,6 +581,30 @@ var _Daemon_serviceDesc = grpc.ServiceDesc{
 			MethodName: "CheckHealth",
 			Handler:    _Daemon_CheckHealth_Handler,
 		},
+		{
+			MethodName: "ListTasks",
+			Handler:    _Daemon_ListTasks_Handler,
+		},
+		{
+			MethodName: "StatTask",
+			Handler:    _Daemon_StatTask_Handler,
+		},
+		{
+			MethodName: "DeleteTask",
+			Handler:    _Daemon_DeleteTask_Handler,
+		},
+		{
+			MethodName: "PinTask",
+			Handler:    _Daemon_PinTask_Handler,
+		},
+		{
+			MethodName: "ImportTask",
+			Handler:    _Daemon_ImportTask_Handler,
+		},
+		{
+			MethodName: "ExportTask",
+			Handler:    _Daemon_ExportTask_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -200,6 +612,22 @@ var _Daemon_serviceDesc = grpc.ServiceDesc{
 			Handler:       _Daemon_Download_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "DownloadStream",
+			Handler:       _Daemon_DownloadStream_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "DownloadPieces",
+			Handler:       _Daemon_DownloadPieces_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "SyncPieceTasks",
+			Handler:       _Daemon_SyncPieceTasks_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "pkg/rpc/dfdaemon/dfdaemon.proto",
 }
@@ -36,10 +36,28 @@ import (
 type DaemonServer interface {
 	// Trigger client to download file
 	Download(context.Context, *dfdaemon.DownRequest, chan<- *dfdaemon.DownResult) error
+	// Trigger client to download file and send the content back in stream
+	DownloadStream(*dfdaemon.StreamDownRequest, dfdaemon.Daemon_DownloadStreamServer) error
 	// Get piece tasks from other peers
 	GetPieceTasks(context.Context, *base.PieceTaskRequest) (*base.PiecePacket, error)
 	// Check daemon health
 	CheckHealth(context.Context) error
+	// Download pieces from other peers
+	DownloadPieces(dfdaemon.Daemon_DownloadPiecesServer) error
+	// Subscribe piece tasks from other peers
+	SyncPieceTasks(*base.PieceTaskRequest, dfdaemon.Daemon_SyncPieceTasksServer) error
+	// List tasks cached in local storage
+	ListTasks(context.Context) (*dfdaemon.LocalTasks, error)
+	// Stat peer tasks of a task in local storage
+	StatTask(context.Context, *dfdaemon.TaskRequest) (*dfdaemon.LocalTasks, error)
+	// Delete a task from local storage
+	DeleteTask(context.Context, *dfdaemon.TaskRequest) error
+	// Pin or unpin a task in local storage
+	PinTask(context.Context, *dfdaemon.PinTaskRequest) error
+	// Import a local file as a completed task and seed it to other peers
+	ImportTask(context.Context, *dfdaemon.ImportTaskRequest) (*dfdaemon.LocalTask, error)
+	// Export a cached task to a local file without back source
+	ExportTask(context.Context, *dfdaemon.ExportTaskRequest) (*dfdaemon.LocalTask, error)
 }
 
 type proxy struct {
@@ -85,6 +103,15 @@ func (p *proxy) Download(req *dfdaemon.DownRequest, stream dfdaemon.Daemon_Downl
 	return
 }
 
+func (p *proxy) DownloadStream(req *dfdaemon.StreamDownRequest, stream dfdaemon.Daemon_DownloadStreamServer) error {
+	peerAddr := "unknown"
+	if pe, ok := peer.FromContext(stream.Context()); ok {
+		peerAddr = pe.Addr.String()
+	}
+	logger.Infof("trigger stream download for url: %s, from: %s", req.Url, peerAddr)
+	return p.server.DownloadStream(req, stream)
+}
+
 func (p *proxy) GetPieceTasks(ctx context.Context, ptr *base.PieceTaskRequest) (*base.PiecePacket, error) {
 	return p.server.GetPieceTasks(ctx, ptr)
 }
@@ -93,6 +120,38 @@ func (p *proxy) CheckHealth(ctx context.Context, req *empty.Empty) (*empty.Empty
 	return new(empty.Empty), p.server.CheckHealth(ctx)
 }
 
+func (p *proxy) DownloadPieces(stream dfdaemon.Daemon_DownloadPiecesServer) error {
+	return p.server.DownloadPieces(stream)
+}
+
+func (p *proxy) SyncPieceTasks(ptr *base.PieceTaskRequest, stream dfdaemon.Daemon_SyncPieceTasksServer) error {
+	return p.server.SyncPieceTasks(ptr, stream)
+}
+
+func (p *proxy) ListTasks(ctx context.Context, req *empty.Empty) (*dfdaemon.LocalTasks, error) {
+	return p.server.ListTasks(ctx)
+}
+
+func (p *proxy) StatTask(ctx context.Context, req *dfdaemon.TaskRequest) (*dfdaemon.LocalTasks, error) {
+	return p.server.StatTask(ctx, req)
+}
+
+func (p *proxy) DeleteTask(ctx context.Context, req *dfdaemon.TaskRequest) (*empty.Empty, error) {
+	return new(empty.Empty), p.server.DeleteTask(ctx, req)
+}
+
+func (p *proxy) PinTask(ctx context.Context, req *dfdaemon.PinTaskRequest) (*empty.Empty, error) {
+	return new(empty.Empty), p.server.PinTask(ctx, req)
+}
+
+func (p *proxy) ImportTask(ctx context.Context, req *dfdaemon.ImportTaskRequest) (*dfdaemon.LocalTask, error) {
+	return p.server.ImportTask(ctx, req)
+}
+
+func (p *proxy) ExportTask(ctx context.Context, req *dfdaemon.ExportTaskRequest) (*dfdaemon.LocalTask, error) {
+	return p.server.ExportTask(ctx, req)
+}
+
 func send(drc chan *dfdaemon.DownResult, closeDrc func(), stream dfdaemon.Daemon_DownloadServer, errChan chan error) {
 	err := safe.Call(func() {
 		defer closeDrc()
@@ -48,6 +48,9 @@ type Client interface {
 	// Update scheduler configuration
 	UpdateScheduler(*manager.UpdateSchedulerRequest) (*manager.Scheduler, error)
 
+	// Get CDN and CDN cluster configuration
+	GetCDN(*manager.GetCDNRequest) (*manager.CDN, error)
+
 	// Update CDN configuration
 	UpdateCDN(*manager.UpdateCDNRequest) (*manager.CDN, error)
 
@@ -121,6 +124,13 @@ func (c *client) UpdateScheduler(req *manager.UpdateSchedulerRequest) (*manager.
 	return c.ManagerClient.UpdateScheduler(ctx, req)
 }
 
+func (c *client) GetCDN(req *manager.GetCDNRequest) (*manager.CDN, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), contextTimeout)
+	defer cancel()
+
+	return c.ManagerClient.GetCDN(ctx, req)
+}
+
 func (c *client) UpdateCDN(req *manager.UpdateCDNRequest) (*manager.CDN, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), contextTimeout)
 	defer cancel()
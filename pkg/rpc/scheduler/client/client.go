@@ -58,6 +58,15 @@ type SchedulerClient interface {
 
 	LeaveTask(context.Context, *scheduler.PeerTarget, ...grpc.CallOption) error
 
+	// StatTask checks whether the task exists in the scheduler and can be downloaded from peers
+	StatTask(context.Context, *scheduler.StatTaskRequest, ...grpc.CallOption) (*scheduler.Task, error)
+
+	// AnnounceTask registers the host as a finished peer of the task with its pieces
+	AnnounceTask(context.Context, *scheduler.AnnounceTaskRequest, ...grpc.CallOption) error
+
+	// LeaveHost makes all peers of the host leaving from all schedulers
+	LeaveHost(context.Context, *scheduler.LeaveHostRequest, ...grpc.CallOption) error
+
 	UpdateState(addrs []dfnet.NetAddr)
 
 	Close() error
@@ -240,4 +249,60 @@ func (sc *schedulerClient) LeaveTask(ctx context.Context, pt *scheduler.PeerTarg
 	return
 }
 
+func (sc *schedulerClient) StatTask(ctx context.Context, req *scheduler.StatTaskRequest, opts ...grpc.CallOption) (*scheduler.Task, error) {
+	var schedulerNode string
+	res, err := rpc.ExecuteWithRetry(func() (interface{}, error) {
+		client, target, err := sc.getSchedulerClient(req.TaskId, false)
+		if err != nil {
+			return nil, err
+		}
+		schedulerNode = target
+		return client.StatTask(ctx, req, opts...)
+	}, 0.2, 2.0, 3, nil)
+	if err != nil {
+		logger.WithTaskID(req.TaskId).Infof("StatTask: invoke scheduler node %s StatTask failed: %v", schedulerNode, err)
+		return nil, err
+	}
+	return res.(*scheduler.Task), nil
+}
+
+func (sc *schedulerClient) AnnounceTask(ctx context.Context, req *scheduler.AnnounceTaskRequest, opts ...grpc.CallOption) error {
+	var schedulerNode string
+	_, err := rpc.ExecuteWithRetry(func() (interface{}, error) {
+		client, target, err := sc.getSchedulerClient(req.TaskId, false)
+		if err != nil {
+			return nil, err
+		}
+		schedulerNode = target
+		return client.AnnounceTask(ctx, req, opts...)
+	}, 0.2, 2.0, 3, nil)
+	if err != nil {
+		logger.WithTaskID(req.TaskId).Infof("AnnounceTask: invoke scheduler node %s AnnounceTask failed: %v", schedulerNode, err)
+		return err
+	}
+	return nil
+}
+
+// LeaveHost notifies all schedulers, because peers of the host are scheduled by different schedulers with task id
+func (sc *schedulerClient) LeaveHost(ctx context.Context, req *scheduler.LeaveHostRequest, opts ...grpc.CallOption) error {
+	var lastErr error
+	for _, node := range sc.Connection.GetServerNodes() {
+		schedulerNode := node.GetEndpoint()
+		_, err := rpc.ExecuteWithRetry(func() (interface{}, error) {
+			clientConn, err := sc.Connection.GetClientConnByTarget(schedulerNode)
+			if err != nil {
+				return nil, err
+			}
+			return scheduler.NewSchedulerClient(clientConn).LeaveHost(ctx, req, opts...)
+		}, 0.2, 2.0, 3, nil)
+		if err != nil {
+			logger.Errorf("LeaveHost: invoke scheduler node %s LeaveHost for host %s failed: %v", schedulerNode, req.HostUuid, err)
+			lastErr = err
+			continue
+		}
+		logger.Infof("LeaveHost: host %s leaves scheduler node %s", req.HostUuid, schedulerNode)
+	}
+	return lastErr
+}
+
 var _ SchedulerClient = (*schedulerClient)(nil)
@@ -37,6 +37,12 @@ type SchedulerServer interface {
 	ReportPeerResult(context.Context, *scheduler.PeerResult) error
 	// LeaveTask makes the peer leaving from scheduling overlay for the task.
 	LeaveTask(context.Context, *scheduler.PeerTarget) error
+	// StatTask checks whether the task exists in the scheduler and can be downloaded from peers.
+	StatTask(context.Context, *scheduler.StatTaskRequest) (*scheduler.Task, error)
+	// AnnounceTask registers the host as a finished peer of the task with its pieces.
+	AnnounceTask(context.Context, *scheduler.AnnounceTaskRequest) error
+	// LeaveHost makes all peers of the host leaving from scheduling overlay.
+	LeaveHost(context.Context, *scheduler.LeaveHostRequest) error
 }
 
 type proxy struct {
@@ -84,3 +90,15 @@ func (p *proxy) ReportPeerResult(ctx context.Context, req *scheduler.PeerResult)
 func (p *proxy) LeaveTask(ctx context.Context, pt *scheduler.PeerTarget) (*empty.Empty, error) {
 	return new(empty.Empty), p.server.LeaveTask(ctx, pt)
 }
+
+func (p *proxy) StatTask(ctx context.Context, req *scheduler.StatTaskRequest) (*scheduler.Task, error) {
+	return p.server.StatTask(ctx, req)
+}
+
+func (p *proxy) AnnounceTask(ctx context.Context, req *scheduler.AnnounceTaskRequest) (*empty.Empty, error) {
+	return new(empty.Empty), p.server.AnnounceTask(ctx, req)
+}
+
+func (p *proxy) LeaveHost(ctx context.Context, req *scheduler.LeaveHostRequest) (*empty.Empty, error) {
+	return new(empty.Empty), p.server.LeaveHost(ctx, req)
+}
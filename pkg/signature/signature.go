@@ -0,0 +1,128 @@
+/*
+ *     Copyright 2020 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package signature signs and verifies piece digest roots of tasks with the cluster ed25519 key.
+//
+// The cdn cluster holds the private key and signs the piece digest root of every finished task,
+// peers hold the public key and only trust piece digests which match a signed root.
+// Keys are base64 encoded, a private key is either a 32 bytes seed or a 64 bytes ed25519 private key.
+package signature
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+const signaturePrefix = "dragonfly-piece-digest-root\x00"
+
+var (
+	ErrInvalidKey = errors.New("invalid piece signing key")
+)
+
+// message binds the piece digest root to the task,
+// the total piece count is bound by the shape of the merkle tree.
+func message(taskID string, root string) []byte {
+	msg := make([]byte, 0, len(signaturePrefix)+len(taskID)+len(root)+1)
+	msg = append(msg, signaturePrefix...)
+	msg = append(msg, taskID...)
+	msg = append(msg, 0)
+	return append(msg, root...)
+}
+
+// Signer signs piece digest roots.
+type Signer struct {
+	key ed25519.PrivateKey
+}
+
+// NewSigner returns a signer with the base64 encoded private key.
+func NewSigner(key string) (*Signer, error) {
+	data, err := base64.StdEncoding.DecodeString(key)
+	if err != nil {
+		return nil, errors.Wrap(ErrInvalidKey, err.Error())
+	}
+	switch len(data) {
+	case ed25519.SeedSize:
+		return &Signer{key: ed25519.NewKeyFromSeed(data)}, nil
+	case ed25519.PrivateKeySize:
+		return &Signer{key: ed25519.PrivateKey(data)}, nil
+	default:
+		return nil, ErrInvalidKey
+	}
+}
+
+// PublicKey returns the base64 encoded public key which is used by verifiers.
+func (s *Signer) PublicKey() string {
+	return base64.StdEncoding.EncodeToString(s.key.Public().(ed25519.PublicKey))
+}
+
+// Sign signs the piece digest root of task.
+func (s *Signer) Sign(taskID string, root string) []byte {
+	return ed25519.Sign(s.key, message(taskID, root))
+}
+
+// Verifier verifies piece digest roots, the key can be updated at runtime.
+type Verifier struct {
+	mu  sync.RWMutex
+	key ed25519.PublicKey
+}
+
+// NewVerifier returns a verifier without key, a verifier without key is disabled.
+func NewVerifier() *Verifier {
+	return &Verifier{}
+}
+
+// SetKey updates the base64 encoded public key, empty key disables the verifier.
+func (v *Verifier) SetKey(key string) error {
+	var publicKey ed25519.PublicKey
+	if key != "" {
+		data, err := base64.StdEncoding.DecodeString(key)
+		if err != nil {
+			return errors.Wrap(ErrInvalidKey, err.Error())
+		}
+		if len(data) != ed25519.PublicKeySize {
+			return ErrInvalidKey
+		}
+		publicKey = data
+	}
+	v.mu.Lock()
+	v.key = publicKey
+	v.mu.Unlock()
+	return nil
+}
+
+// Enabled reports whether piece digest roots must be signed.
+func (v *Verifier) Enabled() bool {
+	if v == nil {
+		return false
+	}
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	return v.key != nil
+}
+
+// Verify checks the signature of the piece digest root of task.
+func (v *Verifier) Verify(taskID string, root string, signature []byte) bool {
+	v.mu.RLock()
+	key := v.key
+	v.mu.RUnlock()
+	if key == nil || root == "" {
+		return false
+	}
+	return ed25519.Verify(key, message(taskID, root), signature)
+}
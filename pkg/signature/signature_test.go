@@ -0,0 +1,54 @@
+/*
+ *     Copyright 2020 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package signature
+
+import (
+	"bytes"
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSignature(t *testing.T) {
+	_, err := NewSigner("not base64")
+	assert.NotNil(t, err)
+	_, err = NewSigner(base64.StdEncoding.EncodeToString([]byte("short")))
+	assert.Equal(t, ErrInvalidKey, err)
+
+	signer, err := NewSigner(base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{1}, 32)))
+	assert.Nil(t, err)
+	sig := signer.Sign("task", "root")
+
+	verifier := NewVerifier()
+	assert.False(t, verifier.Enabled())
+	assert.False(t, verifier.Verify("task", "root", sig))
+
+	assert.Equal(t, ErrInvalidKey, verifier.SetKey(base64.StdEncoding.EncodeToString([]byte("short"))))
+	assert.Nil(t, verifier.SetKey(signer.PublicKey()))
+	assert.True(t, verifier.Enabled())
+	assert.True(t, verifier.Verify("task", "root", sig))
+	assert.False(t, verifier.Verify("other", "root", sig))
+	assert.False(t, verifier.Verify("task", "other", sig))
+
+	other, err := NewSigner(base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{2}, 32)))
+	assert.Nil(t, err)
+	assert.False(t, verifier.Verify("task", "root", other.Sign("task", "root")))
+
+	assert.Nil(t, verifier.SetKey(""))
+	assert.False(t, verifier.Enabled())
+}
@@ -165,8 +165,17 @@ func (client *httpSourceClient) IsExpired(request *source.Request, info *source.
 		return false, err
 	}
 	defer resp.Body.Close()
-	return !(resp.StatusCode == http.StatusNotModified || (resp.Header.Get(headers.ETag) == info.ETag || resp.Header.Get(headers.LastModified) == info.
-		LastModified)), nil
+	if resp.StatusCode == http.StatusNotModified {
+		return false, nil
+	}
+	// compare the validators known only, an empty validator always equals to the missing one in response
+	if info != nil && info.ETag != "" {
+		return resp.Header.Get(headers.ETag) != info.ETag, nil
+	}
+	if info != nil && info.LastModified != "" {
+		return resp.Header.Get(headers.LastModified) != info.LastModified, nil
+	}
+	return true, nil
 }
 
 func (client *httpSourceClient) Download(request *source.Request) (*source.Response, error) {
@@ -181,6 +190,8 @@ func (client *httpSourceClient) Download(request *source.Request) (*source.Respo
 	}
 	response := source.NewResponse(
 		resp.Body,
+		source.WithStatus(resp.StatusCode, resp.Status),
+		source.WithContentLength(resp.ContentLength),
 		source.WithExpireInfo(
 			source.ExpireInfo{
 				LastModified: resp.Header.Get(headers.LastModified),
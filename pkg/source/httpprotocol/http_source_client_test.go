@@ -55,6 +55,7 @@ var (
 	timeoutRawURL               = "https://timeout.com"
 	normalRawURL                = "https://normal.com"
 	expireRawURL                = "https://expired.com"
+	lastModifiedOnlyRawURL      = "https://lastmodified.com"
 	errorRawURL                 = "https://error.com"
 	forbiddenRawURL             = "https://forbidden.com"
 	notfoundRawURL              = "https://notfound.com"
@@ -127,6 +128,17 @@ func (suite *HTTPSourceClientTestSuite) SetupTest() {
 		}, nil
 	})
 
+	httpmock.RegisterResponder(http.MethodGet, lastModifiedOnlyRawURL, func(request *http.Request) (*http.Response, error) {
+		header := http.Header{}
+		header.Set(headers.LastModified, lastModified)
+		return &http.Response{
+			StatusCode:    http.StatusOK,
+			ContentLength: 14,
+			Body:          httpmock.NewRespBodyFromString(testContent),
+			Header:        header,
+		}, nil
+	})
+
 	httpmock.RegisterResponder(http.MethodGet, forbiddenRawURL, httpmock.NewStringResponder(http.StatusForbidden, "forbidden"))
 	httpmock.RegisterResponder(http.MethodGet, notfoundRawURL, httpmock.NewStringResponder(http.StatusNotFound, "not found"))
 	httpmock.RegisterResponder(http.MethodGet, normalNotSupportRangeRawURL, httpmock.NewStringResponder(http.StatusOK, testContent))
@@ -245,6 +257,7 @@ func (suite *HTTPSourceClientTestSuite) TestHttpSourceClientIsExpired() {
 	normalRequest, _ := source.NewRequest(normalRawURL)
 	errorRequest, _ := source.NewRequest(errorRawURL)
 	expireRequest, _ := source.NewRequest(expireRawURL)
+	lastModifiedOnlyRequest, _ := source.NewRequest(lastModifiedOnlyRawURL)
 	tests := []struct {
 		name       string
 		request    *source.Request
@@ -264,6 +277,9 @@ func (suite *HTTPSourceClientTestSuite) TestHttpSourceClientIsExpired() {
 			LastModified: expireLastModified,
 			ETag:         expireEtag,
 		}, want: true, wantErr: false},
+		{name: "last modified only expired", request: lastModifiedOnlyRequest, expireInfo: &source.ExpireInfo{
+			LastModified: expireLastModified,
+		}, want: true, wantErr: false},
 	}
 	for _, tt := range tests {
 		suite.Run(tt.name, func() {
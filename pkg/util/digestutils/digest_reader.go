@@ -21,6 +21,7 @@ import (
 	"encoding/hex"
 	"hash"
 	"io"
+	"strings"
 
 	"github.com/pkg/errors"
 
@@ -46,17 +47,26 @@ type DigestReader interface {
 
 // TODO add AF_ALG digest https://github.com/golang/sys/commit/e24f485414aeafb646f6fca458b0bf869c0880a1
 
+// NewDigestReader calculates md5 of contents and checks it with digest when digest is not empty,
+// digest with algorithm prefix like sha256:xxx is checked with the given algorithm.
 func NewDigestReader(log *logger.SugaredLoggerOnWith, reader io.Reader, digest ...string) io.Reader {
-	var d string
+	var (
+		d string
+		h hash.Hash = md5.New()
+	)
 	if len(digest) > 0 {
 		d = digest[0]
 	}
+	if fields := strings.SplitN(d, ":", 2); len(fields) == 2 {
+		if ah := CreateHash(fields[0]); ah != nil {
+			h, d = ah, fields[1]
+		}
+	}
 	return &digestReader{
 		SugaredLoggerOnWith: log,
 		digest:              d,
-		// TODO support more digest method like sha1, sha256
-		hash: md5.New(),
-		r:    reader,
+		hash:                h,
+		r:                   reader,
 	}
 }
 
@@ -68,7 +78,9 @@ func (dr *digestReader) Read(p []byte) (int, error) {
 	if n > 0 {
 		dr.hash.Write(p[:n])
 	}
-	if err == io.EOF && dr.digest != "" {
+	// the consumer may stop reading once the limit is reached, verify the digest without waiting EOF
+	lr, limited := dr.r.(*io.LimitedReader)
+	if (err == io.EOF || limited && lr.N <= 0) && dr.digest != "" {
 		digest := dr.Digest()
 		if digest != dr.digest {
 			dr.Warnf("digest not match, desired: %s, actual: %s", dr.digest, digest)
@@ -81,5 +93,5 @@ func (dr *digestReader) Read(p []byte) (int, error) {
 
 // Digest returns the digest of contents.
 func (dr *digestReader) Digest() string {
-	return hex.EncodeToString(dr.hash.Sum(nil)[:16])
+	return hex.EncodeToString(dr.hash.Sum(nil))
 }
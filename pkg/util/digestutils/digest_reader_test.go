@@ -48,3 +48,32 @@ func TestNewDigestReader(t *testing.T) {
 	assert.Nil(err)
 	assert.Equal(testBytes, data)
 }
+
+func TestNewDigestReader_Algorithm(t *testing.T) {
+	assert := testifyassert.New(t)
+
+	digest := "sha256:" + Sha256("hello world")
+	reader := NewDigestReader(logger.With("test", "test"), bytes.NewBufferString("hello world"), digest)
+	data, err := io.ReadAll(reader)
+	assert.Nil(err)
+	assert.Equal([]byte("hello world"), data)
+	assert.Equal(Sha256("hello world"), reader.(DigestReader).Digest())
+
+	reader = NewDigestReader(logger.With("test", "test"), bytes.NewBufferString("hello dragonfly"), digest)
+	_, err = io.ReadAll(reader)
+	assert.Equal(ErrDigestNotMatch, err)
+}
+
+func TestNewDigestReader_Limited(t *testing.T) {
+	assert := testifyassert.New(t)
+
+	digest := "sha256:" + Sha256("hello world")
+	reader := NewDigestReader(logger.With("test", "test"), io.LimitReader(bytes.NewBufferString("hello dragonfly"), 11), digest)
+	// read exactly the limit, the digest is verified without another read
+	_, err := io.Copy(io.Discard, io.LimitReader(reader, 11))
+	assert.Equal(ErrDigestNotMatch, err)
+
+	reader = NewDigestReader(logger.With("test", "test"), io.LimitReader(bytes.NewBufferString("hello world!"), 11), digest)
+	_, err = io.Copy(io.Discard, io.LimitReader(reader, 11))
+	assert.Nil(err)
+}
@@ -0,0 +1,130 @@
+/*
+ *     Copyright 2020 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package digestutils
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// The piece merkle tree is a binary sha256 tree over piece digests in piece order.
+// Leaves and inner nodes use different prefixes, and the last node of a level
+// without sibling is promoted to the upper level as is.
+const (
+	merkleLeafPrefix = 0x00
+	merkleNodePrefix = 0x01
+)
+
+func merkleLeaf(leaf string) []byte {
+	h := sha256.New()
+	h.Write([]byte{merkleLeafPrefix})
+	h.Write([]byte(leaf))
+	return h.Sum(nil)
+}
+
+func merkleNode(left, right []byte) []byte {
+	h := sha256.New()
+	h.Write([]byte{merkleNodePrefix})
+	h.Write(left)
+	h.Write(right)
+	return h.Sum(nil)
+}
+
+// MerkleTree is the merkle tree of piece digests.
+type MerkleTree struct {
+	levels [][][]byte
+}
+
+// NewMerkleTree builds the merkle tree of leaves.
+func NewMerkleTree(leaves []string) *MerkleTree {
+	level := make([][]byte, len(leaves))
+	for i, leaf := range leaves {
+		level[i] = merkleLeaf(leaf)
+	}
+	levels := [][][]byte{level}
+	for len(level) > 1 {
+		next := make([][]byte, 0, (len(level)+1)/2)
+		for i := 0; i < len(level); i += 2 {
+			if i+1 == len(level) {
+				next = append(next, level[i])
+				continue
+			}
+			next = append(next, merkleNode(level[i], level[i+1]))
+		}
+		levels = append(levels, next)
+		level = next
+	}
+	return &MerkleTree{levels: levels}
+}
+
+// Root returns the hex encoded merkle root, it returns empty string when the tree has no leaf.
+func (t *MerkleTree) Root() string {
+	top := t.levels[len(t.levels)-1]
+	if len(top) == 0 {
+		return ""
+	}
+	return hex.EncodeToString(top[0])
+}
+
+// Proof returns the hex encoded sibling hashes from the leaf at index up to the root.
+func (t *MerkleTree) Proof(index int) []string {
+	if index < 0 || index >= len(t.levels[0]) {
+		return nil
+	}
+	var proof []string
+	for _, level := range t.levels {
+		if len(level) == 1 {
+			break
+		}
+		sibling := index ^ 1
+		if sibling < len(level) {
+			proof = append(proof, hex.EncodeToString(level[sibling]))
+		}
+		index /= 2
+	}
+	return proof
+}
+
+// VerifyMerkleProof checks that leaf is the leaf at index of a tree with count leaves and the given root.
+func VerifyMerkleProof(root string, leaf string, index, count int, proof []string) bool {
+	if index < 0 || index >= count {
+		return false
+	}
+	node := merkleLeaf(leaf)
+	for size := count; size > 1; size = (size + 1) / 2 {
+		// promoted node without sibling
+		if index == size-1 && size%2 == 1 {
+			index /= 2
+			continue
+		}
+		if len(proof) == 0 {
+			return false
+		}
+		sibling, err := hex.DecodeString(proof[0])
+		if err != nil {
+			return false
+		}
+		proof = proof[1:]
+		if index%2 == 0 {
+			node = merkleNode(node, sibling)
+		} else {
+			node = merkleNode(sibling, node)
+		}
+		index /= 2
+	}
+	return len(proof) == 0 && hex.EncodeToString(node) == root
+}
@@ -0,0 +1,51 @@
+/*
+ *     Copyright 2020 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package digestutils
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMerkle(t *testing.T) {
+	assert.Equal(t, "", NewMerkleTree(nil).Root())
+
+	for count := 1; count <= 9; count++ {
+		var leaves []string
+		for i := 0; i < count; i++ {
+			leaves = append(leaves, fmt.Sprintf("sha256:%064d", i))
+		}
+		tree := NewMerkleTree(leaves)
+		root := tree.Root()
+		assert.Len(t, root, 64)
+		for i := 0; i < count; i++ {
+			proof := tree.Proof(i)
+			assert.True(t, VerifyMerkleProof(root, leaves[i], i, count, proof), "count %d, index %d", count, i)
+			// wrong leaf, index or count
+			assert.False(t, VerifyMerkleProof(root, "sha256:bad", i, count, proof))
+			if count > 1 {
+				assert.False(t, VerifyMerkleProof(root, leaves[i], (i+1)%count, count, proof))
+			}
+			if len(proof) > 0 {
+				tampered := append([]string{leaves[i][7:]}, proof[1:]...)
+				assert.False(t, VerifyMerkleProof(root, leaves[i], i, count, tampered))
+			}
+		}
+	}
+}
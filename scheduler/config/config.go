@@ -60,6 +60,8 @@ func New() *Config {
 				PeerTTI:        3 * time.Minute,
 				TaskTTL:        10 * time.Minute,
 				TaskTTI:        3 * time.Minute,
+				HostGCInterval: 1 * time.Minute,
+				HostTTL:        10 * time.Minute,
 			},
 		},
 		Server: &ServerConfig{
@@ -207,6 +209,9 @@ type GCConfig struct {
 	TaskGCInterval time.Duration `yaml:"taskGCInterval" mapstructure:"taskGCInterval"`
 	TaskTTL        time.Duration `yaml:"taskTTL" mapstructure:"taskTTL"`
 	TaskTTI        time.Duration `yaml:"taskTTI" mapstructure:"taskTTI"`
+	HostGCInterval time.Duration `yaml:"hostGCInterval" mapstructure:"hostGCInterval"`
+	// HostTTL is the keepalive timeout of host, host leaves when it neither sends requests nor uploads pieces during HostTTL
+	HostTTL time.Duration `yaml:"hostTTL" mapstructure:"hostTTL"`
 }
 
 type MetricsConfig struct {
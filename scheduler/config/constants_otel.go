@@ -39,6 +39,10 @@ const (
 	AttributeLastTriggerTime     = attribute.Key("d7y.task.last.trigger.time")
 	AttributeClientBackSource    = attribute.Key("d7y.need.client.back-source")
 	AttributeTriggerCDNError     = attribute.Key("d7y.trigger.cdn.error")
+	AttributeStatTaskID          = attribute.Key("d7y.stat.task.id")
+	AttributeAnnounceTaskID      = attribute.Key("d7y.announce.task.id")
+	AttributeAnnouncePeerID      = attribute.Key("d7y.announce.peer.id")
+	AttributeLeaveHostID         = attribute.Key("d7y.leave.host.id")
 )
 
 const (
@@ -48,6 +52,9 @@ const (
 	SpanReportPeerResult  = "report-peer-result"
 	SpanPeerLeave         = "peer-leave"
 	SpanPreheat           = "preheat"
+	SpanStatTask          = "stat-task"
+	SpanAnnounceTask      = "announce-task"
+	SpanHostLeave         = "host-leave"
 )
 
 const (
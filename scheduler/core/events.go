@@ -34,6 +34,9 @@ import (
 	"d7y.io/dragonfly/v2/scheduler/supervisor"
 )
 
+// hostBusyDuration is the duration in which busy host is not scheduled as parent
+const hostBusyDuration = 5 * time.Second
+
 type event interface {
 	hashKey() string
 	apply(s *state)
@@ -238,6 +241,17 @@ func (e peerDownloadPieceFailEvent) apply(s *state) {
 				e.peer.Log().Errorf("peerDownloadPieceFailEvent: seed task failed: %v", err)
 			}
 		}()
+	case base.Code_ClientPeerBusy:
+		// parent host rejects uploading, do not schedule it as parent for a while
+		if parent, ok := s.peerManager.Get(e.pr.DstPid); ok && parent.Host != nil {
+			e.peer.Log().Infof("peerDownloadPieceFailEvent: parent %s is busy, mark host %s busy", parent.ID, parent.Host.UUID)
+			parent.Host.SetBusy(hostBusyDuration)
+		}
+	case base.Code_ClientPieceVerifyFail:
+		// parent served piece metadata or content which does not match the signed piece digests,
+		// the report is not trusted by others, so the parent is only blocked for the reporter
+		e.peer.Log().Warnf("peerDownloadPieceFailEvent: parent %s served unverifiable pieces, block it", e.pr.DstPid)
+		e.peer.BlockParent(e.pr.DstPid)
 	default:
 		e.peer.Log().Debugf("report piece download fail message, piece result %s", e.pr.String())
 	}
@@ -373,6 +387,7 @@ func constructSuccessPeerPacket(peer *supervisor.Peer, parent *supervisor.Peer,
 		StealPeers:    stealPeers,
 		Code:          base.Code_Success,
 	}
+	peerPacket.PieceDigestRoot, peerPacket.PieceDigestSignature = peer.Task.GetPieceDigestRoot()
 	logger.Debugf("send peerPacket %#v to peer %s", peerPacket, peer.ID)
 	return peerPacket
 }
@@ -0,0 +1,61 @@
+/*
+ *     Copyright 2020 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package core
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/client-go/util/workqueue"
+
+	"d7y.io/dragonfly/v2/pkg/rpc/base"
+	schedulerRPC "d7y.io/dragonfly/v2/pkg/rpc/scheduler"
+	"d7y.io/dragonfly/v2/scheduler/supervisor"
+)
+
+func TestPeerDownloadPieceFailEvent_PieceVerifyFail(t *testing.T) {
+	assert := assert.New(t)
+	queue := workqueue.NewDelayingQueue()
+	defer queue.ShutDown()
+	s := newState(nil, nil, nil, queue)
+
+	cdn := supervisor.NewCDNHost("cdn", "127.0.0.1", "cdn", 8003, 8001, "", "", "")
+	seed := newTestPeer("seed", cdn, supervisor.PeerStatusSuccess)
+	host := supervisor.NewClientHost("host", "127.0.0.2", "client", 8080, 8081, "", "", "")
+	reporter := supervisor.NewPeer("reporter", seed.Task, host)
+	other := supervisor.NewPeer("other", seed.Task, host)
+
+	peerDownloadPieceFailEvent{
+		ctx:  context.Background(),
+		peer: reporter,
+		pr: &schedulerRPC.PieceResult{
+			TaskId: seed.Task.ID,
+			SrcPid: reporter.ID,
+			DstPid: seed.ID,
+			Code:   base.Code_ClientPieceVerifyFail,
+		},
+	}.apply(s)
+
+	// a single report must not fail the parent for every peer
+	assert.True(seed.IsSuccess())
+	assert.True(reporter.IsBlockedParent(seed.ID))
+	assert.False(other.IsBlockedParent(seed.ID))
+
+	item, _ := queue.Get()
+	assert.Equal(reporter, item.(*rsPeer).peer)
+}
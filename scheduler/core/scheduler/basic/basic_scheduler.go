@@ -176,6 +176,11 @@ func (s *Scheduler) selectCandidateChildren(peer *supervisor.Peer, limit int, bl
 			return false
 		}
 
+		if candidateNode.IsBlockedParent(peer.ID) {
+			peer.Log().Debugf("******candidate child peer %s is not selected because it blocks peer******", candidateNode.ID)
+			return false
+		}
+
 		if candidateNode.TotalPieceCount.Load() >= peer.TotalPieceCount.Load() {
 			peer.Log().Debugf("******candidate child peer %s is not selected because it finished number of download is equal to or greater than peer's"+
 				"******", candidateNode.ID)
@@ -227,6 +232,11 @@ func (s *Scheduler) selectCandidateParents(peer *supervisor.Peer, limit int, bla
 			logger.WithTaskAndPeerID(peer.Task.ID, peer.ID).Debugf("++++++candidate parent peer is not selected because it in blank parent set++++++")
 			return false
 		}
+		if peer.IsBlockedParent(candidateNode.ID) {
+			peer.Log().Debugf("++++++candidate parent peer %s is not selected because it is blocked by peer++++++",
+				candidateNode.ID)
+			return false
+		}
 		if s.evaluator.IsBadNode(candidateNode) {
 			peer.Log().Debugf("++++++candidate parent peer %s is not selected because it is badNode++++++",
 				candidateNode.ID)
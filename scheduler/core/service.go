@@ -84,7 +84,7 @@ type SchedulerService struct {
 	metricsConfig *config.MetricsConfig
 }
 
-func NewSchedulerService(cfg *config.SchedulerConfig, pluginDir string, metricsConfig *config.MetricsConfig, dynConfig config.DynconfigInterface, gc gc.GC, options ...Option) (*SchedulerService, error) {
+func NewSchedulerService(cfg *config.SchedulerConfig, pluginDir string, metricsConfig *config.MetricsConfig, dynConfig config.DynconfigInterface, gcManager gc.GC, options ...Option) (*SchedulerService, error) {
 	ops := &Options{}
 	for _, op := range options {
 		op(ops)
@@ -92,12 +92,12 @@ func NewSchedulerService(cfg *config.SchedulerConfig, pluginDir string, metricsC
 
 	hostManager := supervisor.NewHostManager()
 
-	peerManager, err := supervisor.NewPeerManager(cfg.GC, gc, hostManager)
+	peerManager, err := supervisor.NewPeerManager(cfg.GC, gcManager, hostManager)
 	if err != nil {
 		return nil, err
 	}
 
-	taskManager, err := supervisor.NewTaskManager(cfg.GC, gc, peerManager)
+	taskManager, err := supervisor.NewTaskManager(cfg.GC, gcManager, peerManager)
 	if err != nil {
 		return nil, err
 	}
@@ -142,6 +142,16 @@ func NewSchedulerService(cfg *config.SchedulerConfig, pluginDir string, metricsC
 		}
 		s.CDN = cdn
 	}
+
+	// host leaves when it lost keepalive
+	if err := gcManager.Add(gc.Task{
+		ID:       supervisor.HostGCID,
+		Interval: cfg.GC.HostGCInterval,
+		Timeout:  cfg.GC.HostGCInterval,
+		Runner:   s,
+	}); err != nil {
+		return nil, err
+	}
 	return s, nil
 }
 
@@ -222,6 +232,10 @@ func (s *SchedulerService) GetPeer(id string) (*supervisor.Peer, bool) {
 	return s.peerManager.Get(id)
 }
 
+func (s *SchedulerService) GetTask(id string) (*supervisor.Task, bool) {
+	return s.taskManager.Get(id)
+}
+
 func (s *SchedulerService) RegisterTask(req *schedulerRPC.PeerTaskRequest, task *supervisor.Task) *supervisor.Peer {
 	// get or create host
 	peerHost := req.PeerHost
@@ -238,12 +252,18 @@ func (s *SchedulerService) RegisterTask(req *schedulerRPC.PeerTaskRequest, task
 			peerHost.SecurityDomain, peerHost.Location, peerHost.Idc, options...)
 		s.hostManager.Add(host)
 	}
+	host.Touch()
 	// get or creat PeerTask
 	peer, ok := s.peerManager.Get(req.PeerId)
-	if ok {
+	if ok && !peer.IsFail() && !peer.IsLeave() {
 		logger.Warnf("peer %s has already registered", peer.ID)
 		return peer
 	}
+	if ok {
+		// the client resumes an interrupted peer task with the same peer id, start it over
+		logger.Infof("peer %s is registered again after %s, replace it", peer.ID, peer.GetStatus())
+		s.peerManager.Delete(peer.ID)
+	}
 	peer = supervisor.NewPeer(req.PeerId, task, host)
 	s.peerManager.Add(peer)
 	return peer
@@ -308,8 +328,49 @@ func (s *SchedulerService) GetOrAddTask(ctx context.Context, task *supervisor.Ta
 	return task
 }
 
+// AnnounceTask registers the peer which has finished the task in its local storage as a parent candidate.
+// The announcement is not trusted to change the task, the announced pieces are merged only when the task
+// has succeeded with the same total piece count and content length, and the piece digest root is never set by it.
+func (s *SchedulerService) AnnounceTask(ctx context.Context, req *schedulerRPC.AnnounceTaskRequest) *supervisor.Peer {
+	s.kmu.Lock(req.TaskId)
+	defer s.kmu.Unlock(req.TaskId)
+
+	packet := req.PiecePacket
+	task, ok := s.taskManager.GetOrAdd(supervisor.NewTask(req.TaskId, req.Url, req.UrlMeta))
+	if !ok {
+		task.Log().Infof("add new task %s by announcement", task.ID)
+	}
+	peer := s.RegisterTask(&schedulerRPC.PeerTaskRequest{
+		Url:      req.Url,
+		UrlMeta:  req.UrlMeta,
+		PeerId:   packet.DstPid,
+		PeerHost: req.PeerHost,
+	}, task)
+
+	if task.IsSuccess() {
+		if task.TotalPieceCount.Load() != packet.TotalPiece || task.ContentLength.Load() != packet.ContentLength {
+			peer.Log().Warnf("announced task with total piece %d and content length %d, but task has total piece %d and content length %d",
+				packet.TotalPiece, packet.ContentLength, task.TotalPieceCount.Load(), task.ContentLength.Load())
+			return peer
+		}
+		for _, piece := range packet.PieceInfos {
+			task.GetOrAddPiece(piece)
+		}
+	}
+	peer.UpdateProgress(packet.TotalPiece, 0)
+	peer.SetStatus(supervisor.PeerStatusSuccess)
+	peer.Touch()
+	return peer
+}
+
 func (s *SchedulerService) HandlePieceResult(ctx context.Context, peer *supervisor.Peer, pieceResult *schedulerRPC.PieceResult) error {
 	peer.Touch()
+	if pieceResult.Success {
+		// the host of dst peer is alive as it uploads pieces
+		if p, ok := s.peerManager.Get(pieceResult.DstPid); ok {
+			p.Host.Touch()
+		}
+	}
 	if pieceResult.Success && s.metricsConfig != nil && s.metricsConfig.EnablePeerHost {
 		// TODO parse PieceStyle
 		metrics.PeerHostTraffic.WithLabelValues("download", peer.Host.UUID, peer.Host.IP).Add(float64(pieceResult.PieceInfo.RangeSize))
@@ -354,6 +415,49 @@ func (s *SchedulerService) HandlePeerResult(ctx context.Context, peer *superviso
 	return nil
 }
 
+func (s *SchedulerService) GetHost(id string) (*supervisor.Host, bool) {
+	return s.hostManager.Get(id)
+}
+
+// HandleLeaveHost makes all peers of the host leave and deletes the host,
+// children of the peers are rescheduled when the leave events are applied.
+func (s *SchedulerService) HandleLeaveHost(ctx context.Context, host *supervisor.Host) error {
+	host.Log().Infof("host leaves with %d peers", host.GetPeersLen())
+	host.GetPeers().Range(func(_, value interface{}) bool {
+		peer := value.(*supervisor.Peer)
+		// mark peer left immediately, it must not be scheduled as parent before the leave event is applied
+		peer.Leave()
+		if !s.worker.send(peerLeaveEvent{
+			ctx:  ctx,
+			peer: peer,
+		}) {
+			logger.Errorf("send peer leave event failed")
+		}
+		return true
+	})
+	s.hostManager.Delete(host.UUID)
+	return nil
+}
+
+// RunGC makes the hosts which lost keepalive leave, the keepalive of host is
+// the last time it sent a request or uploaded a piece.
+func (s *SchedulerService) RunGC() error {
+	s.hostManager.Range(func(_, value interface{}) bool {
+		host := value.(*supervisor.Host)
+		if host.IsCDN {
+			return true
+		}
+		if elapsed := time.Since(host.GetLastAccessAt()); elapsed > s.config.GC.HostTTL {
+			host.Log().Infof("host has been more than %s since last access, it lost keepalive", s.config.GC.HostTTL)
+			if err := s.HandleLeaveHost(context.Background(), host); err != nil {
+				host.Log().Errorf("handle leave host failed: %v", err)
+			}
+		}
+		return true
+	})
+	return nil
+}
+
 func (s *SchedulerService) HandleLeaveTask(ctx context.Context, peer *supervisor.Peer) error {
 	peer.Touch()
 	if !s.worker.send(peerLeaveEvent{
@@ -0,0 +1,229 @@
+/*
+ *     Copyright 2020 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package core
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"d7y.io/dragonfly/v2/pkg/rpc/base"
+	schedulerRPC "d7y.io/dragonfly/v2/pkg/rpc/scheduler"
+	pkgsync "d7y.io/dragonfly/v2/pkg/sync"
+	"d7y.io/dragonfly/v2/scheduler/config"
+	"d7y.io/dragonfly/v2/scheduler/supervisor"
+	"d7y.io/dragonfly/v2/scheduler/supervisor/mocks"
+)
+
+// recordWorker records the events sent to workers without applying them
+type recordWorker struct {
+	sync.Mutex
+	events []event
+}
+
+func (w *recordWorker) start(*state) {}
+
+func (w *recordWorker) stop() {}
+
+func (w *recordWorker) send(e event) bool {
+	w.Lock()
+	defer w.Unlock()
+	w.events = append(w.events, e)
+	return true
+}
+
+// leftPeers returns the ids of peers in the leave events
+func (w *recordWorker) leftPeers() []string {
+	w.Lock()
+	defer w.Unlock()
+	var ids []string
+	for _, e := range w.events {
+		if e, ok := e.(peerLeaveEvent); ok {
+			ids = append(ids, e.peer.ID)
+		}
+	}
+	return ids
+}
+
+func newTestService(hostTTL time.Duration) (*SchedulerService, *recordWorker) {
+	w := &recordWorker{}
+	return &SchedulerService{
+		hostManager: supervisor.NewHostManager(),
+		worker:      w,
+		config: &config.SchedulerConfig{
+			GC: &config.GCConfig{HostTTL: hostTTL},
+		},
+	}, w
+}
+
+func newTestPeer(id string, host *supervisor.Host, status supervisor.PeerStatus) *supervisor.Peer {
+	peer := supervisor.NewPeer(id, supervisor.NewTask("task-"+id, "http://example.com/"+id, nil), host)
+	peer.SetStatus(status)
+	host.AddPeer(peer)
+	return peer
+}
+
+func TestSchedulerService_HandleLeaveHost(t *testing.T) {
+	assert := assert.New(t)
+	s, w := newTestService(time.Minute)
+
+	host := supervisor.NewClientHost("host", "127.0.0.1", "client", 8080, 8081, "", "", "")
+	s.hostManager.Add(host)
+	running := newTestPeer("running", host, supervisor.PeerStatusRunning)
+	seed := newTestPeer("seed", host, supervisor.PeerStatusSuccess)
+
+	assert.Nil(s.HandleLeaveHost(context.Background(), host))
+	// peers are marked left at once, so that they are not scheduled as parents any more
+	assert.True(running.IsLeave())
+	assert.True(seed.IsLeave())
+	assert.ElementsMatch([]string{"running", "seed"}, w.leftPeers())
+	_, ok := s.hostManager.Get("host")
+	assert.False(ok)
+}
+
+func TestSchedulerService_RunGC(t *testing.T) {
+	assert := assert.New(t)
+	s, w := newTestService(50 * time.Millisecond)
+
+	// a crashed daemon holding finished tasks
+	crashed := supervisor.NewClientHost("crashed", "127.0.0.1", "client", 8080, 8081, "", "", "")
+	s.hostManager.Add(crashed)
+	seed := newTestPeer("seed", crashed, supervisor.PeerStatusSuccess)
+
+	// a daemon which keeps uploading pieces
+	alive := supervisor.NewClientHost("alive", "127.0.0.2", "client", 8080, 8081, "", "", "")
+	s.hostManager.Add(alive)
+	uploading := newTestPeer("uploading", alive, supervisor.PeerStatusSuccess)
+
+	cdn := supervisor.NewCDNHost("cdn", "127.0.0.3", "cdn", 8003, 8001, "", "", "")
+	s.hostManager.Add(cdn)
+
+	time.Sleep(100 * time.Millisecond)
+	uploading.Touch()
+	assert.Nil(s.RunGC())
+
+	_, ok := s.hostManager.Get("crashed")
+	assert.False(ok, "host lost keepalive should leave even it has finished peers")
+	assert.True(seed.IsLeave())
+	_, ok = s.hostManager.Get("alive")
+	assert.True(ok)
+	assert.False(uploading.IsLeave())
+	_, ok = s.hostManager.Get("cdn")
+	assert.True(ok, "cdn host never leaves by gc")
+	assert.Equal([]string{"seed"}, w.leftPeers())
+}
+
+func TestSchedulerService_AnnounceTask(t *testing.T) {
+	announce := func(contentLength int64, totalPiece int32) *schedulerRPC.AnnounceTaskRequest {
+		packet := &base.PiecePacket{
+			TaskId:          "task",
+			DstPid:          "announcer",
+			TotalPiece:      totalPiece,
+			ContentLength:   contentLength,
+			PieceDigestRoot: "root",
+		}
+		for i := int32(0); i < totalPiece; i++ {
+			packet.PieceInfos = append(packet.PieceInfos, &base.PieceInfo{PieceNum: i})
+		}
+		return &schedulerRPC.AnnounceTaskRequest{
+			TaskId:      "task",
+			Url:         "http://example.com/task",
+			PeerHost:    &schedulerRPC.PeerHost{Uuid: "host"},
+			PiecePacket: packet,
+		}
+	}
+	tests := []struct {
+		name   string
+		task   func() *supervisor.Task
+		req    *schedulerRPC.AnnounceTaskRequest
+		expect func(t *testing.T, task *supervisor.Task, peer *supervisor.Peer)
+	}{
+		{
+			name: "unknown task",
+			task: func() *supervisor.Task {
+				return supervisor.NewTask("task", "http://example.com/task", nil)
+			},
+			req: announce(1024, 2),
+			expect: func(t *testing.T, task *supervisor.Task, peer *supervisor.Peer) {
+				assert := assert.New(t)
+				assert.True(task.IsWaiting(), "announcement can not make task success")
+				_, ok := task.GetPiece(0)
+				assert.False(ok)
+				root, _ := task.GetPieceDigestRoot()
+				assert.Empty(root)
+				assert.True(peer.IsSuccess())
+				assert.Equal(int32(2), peer.TotalPieceCount.Load())
+			},
+		},
+		{
+			name: "success task",
+			task: func() *supervisor.Task {
+				task := supervisor.NewTask("task", "http://example.com/task", nil)
+				task.UpdateSuccess(2, 1024)
+				return task
+			},
+			req: announce(1024, 2),
+			expect: func(t *testing.T, task *supervisor.Task, peer *supervisor.Peer) {
+				assert := assert.New(t)
+				_, ok := task.GetPiece(1)
+				assert.True(ok)
+				root, _ := task.GetPieceDigestRoot()
+				assert.Empty(root, "announcement can not set piece digest root")
+				assert.True(peer.IsSuccess())
+			},
+		},
+		{
+			name: "success task with different content length",
+			task: func() *supervisor.Task {
+				task := supervisor.NewTask("task", "http://example.com/task", nil)
+				task.UpdateSuccess(2, 2048)
+				return task
+			},
+			req: announce(1024, 2),
+			expect: func(t *testing.T, task *supervisor.Task, peer *supervisor.Peer) {
+				assert := assert.New(t)
+				_, ok := task.GetPiece(0)
+				assert.False(ok)
+				assert.Equal(int64(2048), task.ContentLength.Load())
+				assert.False(peer.IsSuccess())
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			task := tc.task()
+			taskManager := mocks.NewMockTaskManager(ctrl)
+			taskManager.EXPECT().GetOrAdd(gomock.Any()).Return(task, true)
+			peerManager := mocks.NewMockPeerManager(ctrl)
+			peerManager.EXPECT().Get("announcer").Return(nil, false)
+			peerManager.EXPECT().Add(gomock.Any())
+
+			s, _ := newTestService(time.Minute)
+			s.kmu = pkgsync.NewKrwmutex()
+			s.taskManager = taskManager
+			s.peerManager = peerManager
+			s.hostManager.Add(supervisor.NewClientHost("host", "127.0.0.1", "client", 8080, 8081, "", "", ""))
+			tc.expect(t, task, s.AnnounceTask(context.Background(), tc.req))
+		})
+	}
+}
@@ -80,6 +80,15 @@ func (s *server) RegisterPeerTask(ctx context.Context, req *scheduler.PeerTaskRe
 		span.SetAttributes(config.AttributeTaskSizeScope.String(sizeScope.String()))
 		switch sizeScope {
 		case base.SizeScope_TINY:
+			// tasks announced by peers have no direct piece, they are downloaded from peers like normal tasks
+			if int64(len(task.DirectPiece)) != task.ContentLength.Load() {
+				log.Info("task size scope is tiny but piece content is not available, register peer as normal")
+				s.service.RegisterTask(req, task)
+				return &scheduler.RegisterResult{
+					TaskId:    taskID,
+					SizeScope: base.SizeScope_NORMAL,
+				}, nil
+			}
 			log.Info("task size scope is tiny and return piece content directly")
 			return &scheduler.RegisterResult{
 				TaskId:    taskID,
@@ -120,6 +129,7 @@ func (s *server) RegisterPeerTask(ctx context.Context, req *scheduler.PeerTaskRe
 					PieceMd5:    firstPiece.PieceMd5,
 					PieceOffset: firstPiece.PieceOffset,
 					PieceStyle:  firstPiece.PieceStyle,
+					PieceDigest: firstPiece.PieceDigest,
 				},
 			}
 			log.Infof("task size scope is small and return single piece %#v", sizeScope)
@@ -241,3 +251,58 @@ func (s *server) LeaveTask(ctx context.Context, target *scheduler.PeerTarget) (e
 	}
 	return s.service.HandleLeaveTask(ctx, peer)
 }
+
+func (s *server) StatTask(ctx context.Context, req *scheduler.StatTaskRequest) (*scheduler.Task, error) {
+	logger.Debugf("stat task %v", req)
+	var span trace.Span
+	_, span = tracer.Start(ctx, config.SpanStatTask, trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+	span.SetAttributes(config.AttributeStatTaskID.String(req.TaskId))
+	task, ok := s.service.GetTask(req.TaskId)
+	if !ok {
+		logger.Debugf("stat task: task %s is not exists", req.TaskId)
+		err := dferrors.Newf(base.Code_PeerTaskNotFound, "task %s not found", req.TaskId)
+		span.RecordError(err)
+		return nil, err
+	}
+	return &scheduler.Task{
+		Id:               task.ID,
+		ContentLength:    task.ContentLength.Load(),
+		TotalPieceCount:  task.TotalPieceCount.Load(),
+		State:            task.GetStatus().String(),
+		PeerCount:        int32(task.GetPeers().Len()),
+		HasAvailablePeer: task.HasAvailablePeer(),
+	}, nil
+}
+
+func (s *server) AnnounceTask(ctx context.Context, req *scheduler.AnnounceTaskRequest) error {
+	logger.Debugf("announce task %s", req.TaskId)
+	var span trace.Span
+	_, span = tracer.Start(ctx, config.SpanAnnounceTask, trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+	span.SetAttributes(config.AttributeAnnounceTaskID.String(req.TaskId))
+	span.SetAttributes(config.AttributeAnnouncePeerID.String(req.PiecePacket.DstPid))
+	if req.PiecePacket.TotalPiece <= 0 || int(req.PiecePacket.TotalPiece) != len(req.PiecePacket.PieceInfos) {
+		err := dferrors.Newf(base.Code_BadRequest, "announced task %s must contain all %d pieces, but got %d",
+			req.TaskId, req.PiecePacket.TotalPiece, len(req.PiecePacket.PieceInfos))
+		span.RecordError(err)
+		return err
+	}
+	peer := s.service.AnnounceTask(ctx, req)
+	logger.WithTaskAndPeerID(req.TaskId, peer.ID).Infof("task is announced by host %s", peer.Host.IP)
+	return nil
+}
+
+func (s *server) LeaveHost(ctx context.Context, req *scheduler.LeaveHostRequest) error {
+	logger.Debugf("leave host %v", req)
+	var span trace.Span
+	ctx, span = tracer.Start(ctx, config.SpanHostLeave, trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+	span.SetAttributes(config.AttributeLeaveHostID.String(req.HostUuid))
+	host, ok := s.service.GetHost(req.HostUuid)
+	if !ok {
+		logger.Warnf("leave host: host %s is not exists", req.HostUuid)
+		return nil
+	}
+	return s.service.HandleLeaveHost(ctx, host)
+}
@@ -176,6 +176,7 @@ func (c *cdn) receivePiece(ctx context.Context, task *Task, stream *cdnclient.Pi
 				logger.Infof("task %s receive pieces finish", task.ID)
 				task.TotalPieceCount.Store(piece.TotalPieceCount)
 				task.ContentLength.Store(piece.ContentLength)
+				task.SetPieceDigestRoot(piece.PieceDigestRoot, piece.PieceDigestSignature)
 				task.SetStatus(TaskStatusSuccess)
 				cdnPeer.SetStatus(PeerStatusSuccess)
 				if task.ContentLength.Load() <= TinyFileSize {
@@ -20,6 +20,7 @@ package supervisor
 
 import (
 	"sync"
+	"time"
 
 	"go.uber.org/atomic"
 
@@ -29,6 +30,8 @@ import (
 const (
 	// When using the manager configuration parameter, limit the maximum load number to 5000
 	HostMaxLoad = 5 * 1000
+
+	HostGCID = "host"
 )
 
 type HostManager interface {
@@ -38,6 +41,8 @@ type HostManager interface {
 	Get(string) (*Host, bool)
 	// Delete host
 	Delete(string)
+	// Range calls f sequentially for each host
+	Range(f func(key, value interface{}) bool)
 }
 
 type hostManager struct {
@@ -109,8 +114,13 @@ type Host struct {
 	TotalUploadLoad uint32
 	// CurrentUploadLoad is current upload load number
 	CurrentUploadLoad atomic.Uint32
+	// busyUntil is the unix nano time until which host is busy to upload,
+	// it is set when host rejects uploading as its upload concurrency is full
+	busyUntil atomic.Int64
 	// peers info map
 	peers *sync.Map
+	// lastAccessAt is host last access time, it is the keepalive of host
+	lastAccessAt *atomic.Time
 	// host logger
 	logger *logger.SugaredLoggerOnWith
 }
@@ -137,6 +147,7 @@ func newHost(uuid, ip, hostname string, rpcPort, downloadPort int32, isCDN bool,
 		NetTopology:     "",
 		TotalUploadLoad: 100,
 		peers:           &sync.Map{},
+		lastAccessAt:    atomic.NewTime(time.Now()),
 		logger:          logger.With("hostUUID", uuid),
 	}
 
@@ -164,6 +175,21 @@ func (h *Host) GetPeer(id string) (*Peer, bool) {
 	return peer.(*Peer), ok
 }
 
+// GetPeers returns peers of the host, the value is *Peer
+func (h *Host) GetPeers() *sync.Map {
+	return h.peers
+}
+
+// Touch updates the keepalive of host
+func (h *Host) Touch() {
+	h.lastAccessAt.Store(time.Now())
+}
+
+// GetLastAccessAt returns the last access time of host
+func (h *Host) GetLastAccessAt() time.Time {
+	return h.lastAccessAt.Load()
+}
+
 func (h *Host) GetPeersLen() int {
 	length := 0
 	h.peers.Range(func(_, _ interface{}) bool {
@@ -175,9 +201,22 @@ func (h *Host) GetPeersLen() int {
 }
 
 func (h *Host) GetFreeUploadLoad() int32 {
+	if h.IsBusy() {
+		return 0
+	}
 	return int32(h.TotalUploadLoad - h.CurrentUploadLoad.Load())
 }
 
+// SetBusy marks host busy to upload for a duration
+func (h *Host) SetBusy(d time.Duration) {
+	h.busyUntil.Store(time.Now().Add(d).UnixNano())
+}
+
+// IsBusy returns whether host is busy to upload
+func (h *Host) IsBusy() bool {
+	return time.Now().UnixNano() < h.busyUntil.Load()
+}
+
 func (h *Host) Log() *logger.SugaredLoggerOnWith {
 	return h.logger
 }
@@ -19,6 +19,7 @@ package supervisor_test
 import (
 	"strconv"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
@@ -98,6 +99,81 @@ func TestHost_New(t *testing.T) {
 	}
 }
 
+func TestHost_SetBusy(t *testing.T) {
+	tests := []struct {
+		name     string
+		duration time.Duration
+		expect   func(t *testing.T, host *supervisor.Host)
+	}{
+		{
+			name:     "busy host has no free upload load",
+			duration: time.Minute,
+			expect: func(t *testing.T, host *supervisor.Host) {
+				assert := assert.New(t)
+				assert.True(host.IsBusy())
+				assert.Equal(int32(0), host.GetFreeUploadLoad())
+			},
+		},
+		{
+			name:     "busy duration expired",
+			duration: -time.Second,
+			expect: func(t *testing.T, host *supervisor.Host) {
+				assert := assert.New(t)
+				assert.False(host.IsBusy())
+				assert.Equal(int32(100), host.GetFreeUploadLoad())
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			host := supervisor.NewClientHost("host", "127.0.0.1", "Client", 8080, 8081, "", "", "")
+			host.SetBusy(tc.duration)
+			tc.expect(t, host)
+		})
+	}
+}
+
+func TestHost_Touch(t *testing.T) {
+	tests := []struct {
+		name   string
+		touch  func(host *supervisor.Host)
+		expect func(t *testing.T, host *supervisor.Host, before time.Time)
+	}{
+		{
+			name:  "touch host",
+			touch: func(host *supervisor.Host) { host.Touch() },
+			expect: func(t *testing.T, host *supervisor.Host, before time.Time) {
+				assert := assert.New(t)
+				assert.True(host.GetLastAccessAt().After(before))
+			},
+		},
+		{
+			name: "touch peer of host",
+			touch: func(host *supervisor.Host) {
+				task := supervisor.NewTask("task", "http://example.com/file", nil)
+				peer := supervisor.NewPeer("peer", task, host)
+				host.AddPeer(peer)
+				peer.Touch()
+			},
+			expect: func(t *testing.T, host *supervisor.Host, before time.Time) {
+				assert := assert.New(t)
+				assert.True(host.GetLastAccessAt().After(before))
+				_, ok := host.GetPeers().Load("peer")
+				assert.True(ok)
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			host := supervisor.NewClientHost("host", "127.0.0.1", "Client", 8080, 8081, "", "", "")
+			before := host.GetLastAccessAt()
+			time.Sleep(time.Millisecond)
+			tc.touch(host)
+			tc.expect(t, host, before)
+		})
+	}
+}
+
 func TestHostManager_New(t *testing.T) {
 	tests := []struct {
 		name   string
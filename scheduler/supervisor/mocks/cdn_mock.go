@@ -122,6 +122,26 @@ func (mr *MockCDNDynmaicClientMockRecorder) OnNotify(arg0 interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OnNotify", reflect.TypeOf((*MockCDNDynmaicClient)(nil).OnNotify), arg0)
 }
 
+// SyncPieceTasks mocks base method.
+func (m *MockCDNDynmaicClient) SyncPieceTasks(arg0 context.Context, arg1 dfnet.NetAddr, arg2 *base.PieceTaskRequest, arg3 ...grpc.CallOption) (cdnsystem.Seeder_SyncPieceTasksClient, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1, arg2}
+	for _, a := range arg3 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "SyncPieceTasks", varargs...)
+	ret0, _ := ret[0].(cdnsystem.Seeder_SyncPieceTasksClient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SyncPieceTasks indicates an expected call of SyncPieceTasks.
+func (mr *MockCDNDynmaicClientMockRecorder) SyncPieceTasks(arg0, arg1, arg2 interface{}, arg3 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1, arg2}, arg3...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SyncPieceTasks", reflect.TypeOf((*MockCDNDynmaicClient)(nil).SyncPieceTasks), varargs...)
+}
+
 // UpdateState mocks base method.
 func (m *MockCDNDynmaicClient) UpdateState(arg0 []dfnet.NetAddr) {
 	m.ctrl.T.Helper()
@@ -72,3 +72,15 @@ func (mr *MockHostManagerMockRecorder) Get(arg0 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockHostManager)(nil).Get), arg0)
 }
+
+// Range mocks base method.
+func (m *MockHostManager) Range(arg0 func(interface{}, interface{}) bool) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Range", arg0)
+}
+
+// Range indicates an expected call of Range.
+func (mr *MockHostManagerMockRecorder) Range(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Range", reflect.TypeOf((*MockHostManager)(nil).Range), arg0)
+}
@@ -212,6 +212,8 @@ type Peer struct {
 	parent atomic.Value
 	// children is peer children map
 	children *sync.Map
+	// blockedParents holds the ids of parents which served unverifiable pieces to the peer
+	blockedParents *sync.Map
 	// status is peer status and type is PeerStatus
 	status atomic.Value
 	// pieceCosts is piece historical download time
@@ -228,13 +230,14 @@ type Peer struct {
 
 func NewPeer(id string, task *Task, host *Host) *Peer {
 	peer := &Peer{
-		ID:           id,
-		Task:         task,
-		Host:         host,
-		CreateAt:     atomic.NewTime(time.Now()),
-		lastAccessAt: atomic.NewTime(time.Now()),
-		children:     &sync.Map{},
-		logger:       logger.WithTaskAndPeerID(task.ID, id),
+		ID:             id,
+		Task:           task,
+		Host:           host,
+		CreateAt:       atomic.NewTime(time.Now()),
+		lastAccessAt:   atomic.NewTime(time.Now()),
+		children:       &sync.Map{},
+		blockedParents: &sync.Map{},
+		logger:         logger.WithTaskAndPeerID(task.ID, id),
 	}
 
 	peer.status.Store(PeerStatusWaiting)
@@ -361,6 +364,7 @@ func (peer *Peer) Touch() {
 	if peer.GetStatus() == PeerStatusZombie && !peer.leave.Load() {
 		peer.SetStatus(PeerStatusRunning)
 	}
+	peer.Host.Touch()
 	peer.Task.Touch()
 }
 
@@ -419,6 +423,18 @@ func (peer *Peer) Leave() {
 	peer.leave.Store(true)
 }
 
+// BlockParent prevents the parent from being scheduled to the peer again,
+// the parent is only blocked for the peer which reports it.
+func (peer *Peer) BlockParent(id string) {
+	peer.blockedParents.Store(id, struct{}{})
+}
+
+// IsBlockedParent returns whether the parent is blocked by the peer
+func (peer *Peer) IsBlockedParent(id string) bool {
+	_, ok := peer.blockedParents.Load(id)
+	return ok
+}
+
 func (peer *Peer) IsLeave() bool {
 	return peer.leave.Load()
 }
@@ -315,6 +315,21 @@ func TestPeer_Status(t *testing.T) {
 	}
 }
 
+func TestPeer_BlockParent(t *testing.T) {
+	assert := assert.New(t)
+	task := mockATask("task")
+	host := mockAHost("host")
+	parent := supervisor.NewPeer("parent", task, host)
+	child := supervisor.NewPeer("child", task, host)
+	other := supervisor.NewPeer("other", task, host)
+
+	child.BlockParent(parent.ID)
+	assert.True(child.IsBlockedParent(parent.ID))
+	// the parent is only blocked for the peer which blocks it
+	assert.False(other.IsBlockedParent(parent.ID))
+	assert.False(parent.IsFail())
+}
+
 func TestPeerManager_New(t *testing.T) {
 	tests := []struct {
 		name   string
@@ -188,6 +188,10 @@ type Task struct {
 	pieces *sync.Map
 	// TotalPieceCount is total piece count
 	TotalPieceCount atomic.Int32
+	// pieceDigestRoot is the merkle root of piece digests reported by cdn
+	pieceDigestRoot string
+	// pieceDigestSignature is the cdn cluster signature of pieceDigestRoot
+	pieceDigestSignature []byte
 	// task logger
 	logger *logger.SugaredLoggerOnWith
 	// task lock
@@ -292,6 +296,21 @@ func (task *Task) GetOrAddPiece(p *base.PieceInfo) (*base.PieceInfo, bool) {
 	return piece.(*base.PieceInfo), ok
 }
 
+func (task *Task) SetPieceDigestRoot(root string, signature []byte) {
+	task.lock.Lock()
+	defer task.lock.Unlock()
+
+	task.pieceDigestRoot = root
+	task.pieceDigestSignature = signature
+}
+
+func (task *Task) GetPieceDigestRoot() (string, []byte) {
+	task.lock.RLock()
+	defer task.lock.RUnlock()
+
+	return task.pieceDigestRoot, task.pieceDigestSignature
+}
+
 func (task *Task) GetSizeScope() base.SizeScope {
 	if task.ContentLength.Load() <= TinyFileSize {
 		return base.SizeScope_TINY
@@ -385,6 +404,14 @@ func (task *Task) PickReverse(limit int, pickFn func(peer *Peer) bool) []*Peer {
 	return peers
 }
 
+// HasAvailablePeer determines whether any peer finished the task and is not left,
+// then the task can be downloaded from the peer without back source.
+func (task *Task) HasAvailablePeer() bool {
+	return len(task.Pick(1, func(peer *Peer) bool {
+		return peer.IsSuccess() && !peer.IsLeave()
+	})) > 0
+}
+
 func (task *Task) Log() *logger.SugaredLoggerOnWith {
 	return task.logger
 }
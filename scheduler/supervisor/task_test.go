@@ -348,6 +348,28 @@ func TestTask_Pick(t *testing.T) {
 	}
 }
 
+func TestTask_HasAvailablePeer(t *testing.T) {
+	assert := assert.New(t)
+	task := mockATask("task")
+	assert.False(task.HasAvailablePeer())
+
+	running := mockAPeer("running", task)
+	running.SetStatus(supervisor.PeerStatusRunning)
+	task.AddPeer(running)
+	assert.False(task.HasAvailablePeer())
+
+	left := mockAPeer("left", task)
+	left.SetStatus(supervisor.PeerStatusSuccess)
+	left.Leave()
+	task.AddPeer(left)
+	assert.False(task.HasAvailablePeer())
+
+	success := mockAPeer("success", task)
+	success.SetStatus(supervisor.PeerStatusSuccess)
+	task.AddPeer(success)
+	assert.True(task.HasAvailablePeer())
+}
+
 func TestTaskManager_New(t *testing.T) {
 	tests := []struct {
 		name   string